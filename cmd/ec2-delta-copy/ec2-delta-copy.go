@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/kubev2v/forklift/pkg/provider/ec2/ebs"
+	"k8s.io/klog/v2"
+)
+
+type AppConfig struct {
+	volumePath     string
+	region         string
+	firstSnapshot  string
+	secondSnapshot string
+	workers        int
+}
+
+func main() {
+	appConfig := &AppConfig{}
+	flag.StringVar(&appConfig.volumePath, "volume-path", "", "Path of the volume the changed blocks are written to")
+	flag.StringVar(&appConfig.region, "region", os.Getenv("AWS_REGION"), "AWS region of the snapshots")
+	flag.StringVar(&appConfig.firstSnapshot, "first-snapshot", "", "ID of the snapshot already copied to the volume (empty to copy all the blocks)")
+	flag.StringVar(&appConfig.secondSnapshot, "second-snapshot", "", "ID of the snapshot to copy to the volume")
+	flag.IntVar(&appConfig.workers, "workers", ebs.DefaultWorkers, "Number of blocks fetched and written at once")
+	flag.Parse()
+
+	if appConfig.volumePath == "" {
+		klog.Fatal("volume-path is required")
+	}
+	if appConfig.secondSnapshot == "" {
+		klog.Fatal("second-snapshot is required")
+	}
+
+	err := copyChangedBlocks(context.Background(), appConfig)
+	if err != nil {
+		klog.Fatal(err)
+	}
+}
+
+// Write the blocks that changed between the snapshots to the volume.
+// The AWS credentials are read from the standard environment variables.
+func copyChangedBlocks(ctx context.Context, appConfig *AppConfig) (err error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(appConfig.region))
+	if err != nil {
+		return
+	}
+	volume, err := os.OpenFile(appConfig.volumePath, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	defer volume.Close()
+
+	klog.Info(
+		"Copying changed blocks. first: ", appConfig.firstSnapshot,
+		" second: ", appConfig.secondSnapshot,
+		" volume: ", appConfig.volumePath)
+	written, err := ebs.CopyChangedBlocks(ctx, ebs.NewFromConfig(cfg), appConfig.firstSnapshot, appConfig.secondSnapshot, volume, appConfig.workers)
+	if err != nil {
+		return
+	}
+	err = volume.Sync()
+	if err != nil {
+		return
+	}
+	klog.Info("Copied changed blocks. bytes: ", written)
+	return
+}
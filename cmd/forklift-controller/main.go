@@ -21,6 +21,7 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/go-logr/logr"
 	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
@@ -1,7 +1,13 @@
 package main
 
 import (
+	"compress/bzip2"
+	"compress/zlib"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -23,6 +29,34 @@ type AppConfig struct {
 	ownerUID         string
 	pvcSize          int64
 	volumePath       string
+	backupID         string
+	parentBackupID   string
+}
+
+// Object storage holding the volume backups.
+type objectStorage interface {
+	ListObjects(container, prefix string) ([]string, error)
+	DownloadObject(container, name string) (io.ReadCloser, error)
+}
+
+// Metadata of a backup written by the Cinder chunked backup
+// drivers. Each object holds an extent of the volume, an
+// incremental backup holds only the extents changed since
+// its parent.
+type backupMetadata struct {
+	BackupID string                    `json:"backup_id"`
+	VolumeID string                    `json:"volume_id"`
+	ParentID string                    `json:"parent_id"`
+	Objects  []map[string]backupExtent `json:"objects"`
+}
+
+// Extent of the volume stored in a backup object.
+type backupExtent struct {
+	Object      string `json:"-"`
+	Offset      int64  `json:"offset"`
+	Length      int64  `json:"length"`
+	MD5         string `json:"md5"`
+	Compression string `json:"compression"`
 }
 
 func main() {
@@ -35,6 +69,8 @@ func main() {
 	flag.StringVar(&config.crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
 	flag.StringVar(&config.ownerUID, "owner-uid", "", "Owner UID (usually PVC UID)")
 	flag.Int64Var(&config.pvcSize, "pvc-size", 0, "Size of pvc (in bytes)")
+	flag.StringVar(&config.backupID, "backup-id", "", "Incremental volume backup ID, only the extents of the backup are written")
+	flag.StringVar(&config.parentBackupID, "parent-backup-id", "", "ID of the volume backup the volume content matches")
 	flag.Parse()
 
 	if config.pvcSize <= 0 {
@@ -53,6 +89,10 @@ func main() {
 
 func populate(config *AppConfig) {
 	client := createClient(config)
+	if config.backupID != "" {
+		applyBackup(client, config)
+		return
+	}
 	downloadAndSaveImage(client, config)
 }
 
@@ -127,6 +167,151 @@ func writeData(reader io.ReadCloser, file *os.File, config *AppConfig, progress
 	done <- true
 }
 
+// Write the extents of the volume stored in an incremental backup.
+// The volume holds the content of the parent backup, taken from
+// the previous precopy snapshot, so only the extents changed since
+// then are transferred.
+func applyBackup(client *libclient.Client, config *AppConfig) {
+	klog.Info("Applying the backup: ", config.backupID)
+	backup := &libclient.Backup{}
+	err := client.Get(backup, config.backupID)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if backup.Status != libclient.BackupStatusAvailable {
+		klog.Fatalf("The backup %s is not available, status: %s", backup.ID, backup.Status)
+	}
+	metadata, err := readBackupMetadata(client, backup)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if metadata.ParentID != config.parentBackupID {
+		klog.Fatalf("The backup %s is based on the backup '%s' instead of '%s'",
+			backup.ID, metadata.ParentID, config.parentBackupID)
+	}
+
+	file := openFile(config.volumePath)
+	defer file.Close()
+
+	extents := metadata.extents()
+	countingReader := &CountingReader{read: new(int64)}
+	for _, extent := range extents {
+		countingReader.total += extent.Length
+	}
+	progressVec := createProgressCounter()
+	done := make(chan bool)
+
+	go reportProgress(done, countingReader, progressVec, config)
+
+	if err = writeExtents(client, backup.Container, extents, file, countingReader); err != nil {
+		klog.Fatal(err)
+	}
+	done <- true
+	klog.Info("Applied the backup, extents: ", len(extents), " changed bytes: ", countingReader.total)
+}
+
+// Find and read the metadata object of a backup. The objects of a
+// backup are named volume_<volume>/<timestamp>/az_<zone>_backup_<backup>.
+func readBackupMetadata(storage objectStorage, backup *libclient.Backup) (metadata *backupMetadata, err error) {
+	names, err := storage.ListObjects(backup.Container, fmt.Sprintf("volume_%s/", backup.VolumeID))
+	if err != nil {
+		return
+	}
+	suffix := fmt.Sprintf("_backup_%s_metadata", backup.ID)
+	for _, name := range names {
+		if !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		var reader io.ReadCloser
+		reader, err = storage.DownloadObject(backup.Container, name)
+		if err != nil {
+			return
+		}
+		defer reader.Close()
+		metadata = &backupMetadata{}
+		err = json.NewDecoder(reader).Decode(metadata)
+		if err != nil {
+			err = fmt.Errorf("failed to read the metadata of the backup %s: %w", backup.ID, err)
+			return
+		}
+		if metadata.BackupID != backup.ID {
+			err = fmt.Errorf("the metadata object %s belongs to the backup %s", name, metadata.BackupID)
+		}
+		return
+	}
+	err = fmt.Errorf("the metadata of the backup %s was not found in the container %s", backup.ID, backup.Container)
+	return
+}
+
+// The extents of the backup in the order they were written.
+func (r *backupMetadata) extents() (extents []backupExtent) {
+	for _, object := range r.Objects {
+		for name, extent := range object {
+			extent.Object = name
+			extents = append(extents, extent)
+		}
+	}
+	return
+}
+
+// Write the extents to the volume.
+func writeExtents(storage objectStorage, container string, extents []backupExtent, file io.WriterAt, countingReader *CountingReader) (err error) {
+	for _, extent := range extents {
+		err = writeExtent(storage, container, extent, file, countingReader)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Write an extent to the volume and verify its length and checksum.
+func writeExtent(storage objectStorage, container string, extent backupExtent, file io.WriterAt, countingReader *CountingReader) (err error) {
+	object, err := storage.DownloadObject(container, extent.Object)
+	if err != nil {
+		return
+	}
+	defer object.Close()
+	var reader io.Reader
+	switch extent.Compression {
+	case "", "none", "off", "no":
+		reader = object
+	case "zlib", "gzip":
+		var zlibReader io.ReadCloser
+		zlibReader, err = zlib.NewReader(object)
+		if err != nil {
+			err = fmt.Errorf("failed to decompress the backup object %s: %w", extent.Object, err)
+			return
+		}
+		defer zlibReader.Close()
+		reader = zlibReader
+	case "bz2", "bzip2":
+		reader = bzip2.NewReader(object)
+	default:
+		err = fmt.Errorf("unsupported compression %s of the backup object %s", extent.Compression, extent.Object)
+		return
+	}
+	hash := md5.New()
+	extentReader := &CountingReader{
+		reader: io.NopCloser(io.TeeReader(io.LimitReader(reader, extent.Length), hash)),
+		read:   countingReader.read,
+		total:  countingReader.total,
+	}
+	written, err := io.Copy(io.NewOffsetWriter(file, extent.Offset), extentReader)
+	if err != nil {
+		err = fmt.Errorf("failed to write the backup object %s: %w", extent.Object, err)
+		return
+	}
+	if written != extent.Length {
+		err = fmt.Errorf("the backup object %s holds %d bytes instead of %d", extent.Object, written, extent.Length)
+		return
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != extent.MD5 {
+		err = fmt.Errorf("the checksum of the backup object %s is %s instead of %s", extent.Object, sum, extent.MD5)
+	}
+	return
+}
+
 func reportProgress(done chan bool, countingReader *CountingReader, progress *prometheus.CounterVec, config *AppConfig) {
 	for {
 		select {
@@ -1,13 +1,21 @@
 package main
 
 import (
+	"bytes"
+	"compress/zlib"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/openstack"
 )
 
 func setupMockServer() (*httptest.Server, string, int, error) {
@@ -179,3 +187,125 @@ func TestPopulate(t *testing.T) {
 
 	os.Remove(fileName)
 }
+
+// Object storage holding the objects in memory.
+type fakeStorage map[string][]byte
+
+func (r fakeStorage) ListObjects(container, prefix string) (names []string, err error) {
+	for name := range r {
+		if strings.HasPrefix(name, container+"/"+prefix) {
+			names = append(names, strings.TrimPrefix(name, container+"/"))
+		}
+	}
+	return
+}
+
+func (r fakeStorage) DownloadObject(container, name string) (io.ReadCloser, error) {
+	data, found := r[container+"/"+name]
+	if !found {
+		return nil, fmt.Errorf("object %s not found", name)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Store an extent of a backup compressed with zlib.
+func (r fakeStorage) addExtent(t *testing.T, metadata *backupMetadata, name string, offset int64, data []byte) {
+	compressed := &bytes.Buffer{}
+	writer := zlib.NewWriter(compressed)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	writer.Close()
+	r["backups/"+name] = compressed.Bytes()
+	sum := md5.Sum(data)
+	metadata.Objects = append(metadata.Objects, map[string]backupExtent{
+		name: {
+			Offset:      offset,
+			Length:      int64(len(data)),
+			MD5:         hex.EncodeToString(sum[:]),
+			Compression: "zlib",
+		},
+	})
+}
+
+func TestApplyBackupExtents(t *testing.T) {
+	backup := &libclient.Backup{ID: "backup-2", VolumeID: "volume-1", Container: "backups"}
+	prefix := "volume_volume-1/20261017000000/az_nova_backup_backup-2"
+	storage := fakeStorage{}
+	metadata := &backupMetadata{BackupID: "backup-2", VolumeID: "volume-1", ParentID: "backup-1"}
+	storage.addExtent(t, metadata, prefix+"-00001", 10, []byte("changed"))
+	storage.addExtent(t, metadata, prefix+"-00002", 100, bytes.Repeat([]byte{2}, 50))
+	data, _ := json.Marshal(metadata)
+	storage["backups/"+prefix+"_metadata"] = data
+	storage["backups/volume_volume-1/20261016000000/az_nova_backup_backup-1_metadata"] = []byte("{}")
+
+	read, err := readBackupMetadata(storage, backup)
+	if err != nil {
+		t.Fatalf("Failed to read the backup metadata: %v", err)
+	}
+	if read.ParentID != "backup-1" {
+		t.Errorf("Expected the parent backup-1, got %s", read.ParentID)
+	}
+	extents := read.extents()
+	if len(extents) != 2 || extents[0].Object != prefix+"-00001" {
+		t.Fatalf("Unexpected extents: %v", extents)
+	}
+
+	file, err := os.CreateTemp("", "disk.img")
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	previous := bytes.Repeat([]byte{1}, 200)
+	if _, err = file.Write(previous); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	countingReader := &CountingReader{read: new(int64), total: 57}
+	err = writeExtents(storage, backup.Container, extents, file, countingReader)
+	if err != nil {
+		t.Fatalf("Failed to write the extents: %v", err)
+	}
+	if *countingReader.read != 57 {
+		t.Errorf("Expected 57 bytes read, got %d", *countingReader.read)
+	}
+	expected := bytes.Clone(previous)
+	copy(expected[10:], "changed")
+	copy(expected[100:], bytes.Repeat([]byte{2}, 50))
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if !bytes.Equal(content, expected) {
+		t.Errorf("Expected only the extents of the backup to be written")
+	}
+}
+
+func TestApplyBackupChecksumMismatch(t *testing.T) {
+	storage := fakeStorage{}
+	metadata := &backupMetadata{BackupID: "backup-2"}
+	storage.addExtent(t, metadata, "extent", 0, []byte("changed"))
+	extents := metadata.extents()
+	extents[0].MD5 = "invalid"
+
+	file, err := os.CreateTemp("", "disk.img")
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	err = writeExtents(storage, "backups", extents, file, &CountingReader{read: new(int64)})
+	if err == nil {
+		t.Errorf("Expected the checksum mismatch to fail")
+	}
+}
+
+func TestReadBackupMetadataNotFound(t *testing.T) {
+	backup := &libclient.Backup{ID: "backup-2", VolumeID: "volume-1", Container: "backups"}
+	_, err := readBackupMetadata(fakeStorage{}, backup)
+	if err == nil {
+		t.Errorf("Expected the missing metadata to fail")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	_ "embed"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 
 	"github.com/kubev2v/forklift/pkg/virt-v2v/config"
 	"github.com/kubev2v/forklift/pkg/virt-v2v/conversion"
@@ -26,6 +28,10 @@ func main() {
 		fmt.Println("Failed to create v2v output dir", err)
 		os.Exit(1)
 	}
+	if err = throttleTransfer(env); err != nil {
+		fmt.Println("Failed to limit the transfer rate", err)
+		os.Exit(1)
+	}
 	convert, err := conversion.NewConversion(env)
 	if err != nil {
 		fmt.Println("Failed prepare conversion", err)
@@ -142,3 +148,48 @@ func createV2vOutputDir(env *config.AppConfig) (err error) {
 	}
 	return nil
 }
+
+// Limit the disk transfer rate.
+// virt-v2v copies the disks with nbdcopy whatever the input, including
+// the OVA disks read from the NFS share, so a shim, found first in the
+// PATH, serves the source of the copy through an nbdkit with the rate
+// filter (bits/s) that nbdcopy runs with socket activation.
+func throttleTransfer(env *config.AppConfig) (err error) {
+	if env.TransferRateLimit <= 0 {
+		return
+	}
+	nbdcopy, err := exec.LookPath("nbdcopy")
+	if err != nil {
+		return fmt.Errorf("nbdcopy not found: %v", err)
+	}
+	nbdkit, err := exec.LookPath("nbdkit")
+	if err != nil {
+		return fmt.Errorf("nbdkit not found: %v", err)
+	}
+	dir := filepath.Join(env.Workdir, "bin")
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating directory: %v", err)
+	}
+	script := fmt.Sprintf(
+		`#!/bin/sh
+throttled=
+for arg; do
+  shift
+  case "$throttled$arg" in
+  nbd://*|nbd+unix://*|nbds://*|nbds+unix://*)
+    throttled=1
+    set -- "$@" [ %[2]s --exit-with-parent --filter=rate nbd uri="$arg" rate=%[3]dM ] ;;
+  *)
+    set -- "$@" "$arg" ;;
+  esac
+done
+exec %[1]s "$@"
+`,
+		nbdcopy,
+		nbdkit,
+		env.TransferRateLimit*8)
+	if err = os.WriteFile(filepath.Join(dir, "nbdcopy"), []byte(script), 0755); err != nil {
+		return fmt.Errorf("error writing the nbdcopy shim: %v", err)
+	}
+	return os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
@@ -148,10 +148,36 @@ func (p *RemoteEsxcliPopulator) Populate(vmId string, sourceVMDKFile string, pv
 		}
 	}
 
+	// storage exposing the volume as an NVMe namespace is mapped to a host
+	// subsystem using the ESX host NQN instead of the HBA UIDs
+	var mapper StorageMapper = p.StorageApi
+	var lun LUN
+	lunResolved := false
+	isNVMe := false
+	nvmeAdapters := []string{}
+	if nvmeMapper, ok := p.StorageApi.(NVMeMapper); ok {
+		lun, err = p.StorageApi.ResolvePVToLUN(pv)
+		if err != nil {
+			return err
+		}
+		lunResolved = true
+		if lun.Protocol == ProtocolNVMe {
+			hostNQN, adapters, err := nvmeHost(p.VSphereClient, host)
+			if err != nil {
+				return err
+			}
+			klog.Infof("NVMe host NQN %s found with adapters %+v", hostNQN, adapters)
+			isNVMe = true
+			mapper = nvmeStorageMapper{nvmeMapper}
+			hbaUIDs = append(hbaUIDs, hostNQN)
+			nvmeAdapters = adapters
+		}
+	}
+
 	// powerflex handling - scini is the powerflex kernel module and is not
 	// using any iqn/wwn to identity the host. Instead extract the SdcGuid
 	// as the possible clonner identifier
-	if isSciniRequired {
+	if !isNVMe && isSciniRequired {
 		klog.Infof("scini is required for the storage api")
 		sciModule, err := p.VSphereClient.RunEsxCommand(context.Background(), host, []string{"system", "module", "parameters", "list", "-m", "scini"})
 		if err != nil {
@@ -170,7 +196,7 @@ func (p *RemoteEsxcliPopulator) Populate(vmId string, sourceVMDKFile string, pv
 		}
 	}
 
-	if !isSciniRequired {
+	if !isNVMe && !isSciniRequired {
 		klog.Infof("scini is not required for the storage api")
 		for _, a := range r {
 			hbaName, hasHbaName := a["HBAName"]
@@ -215,17 +241,19 @@ func (p *RemoteEsxcliPopulator) Populate(vmId string, sourceVMDKFile string, pv
 		klog.Infof("no valid HBA UIDs found for host %s", host)
 		return fmt.Errorf("no valid HBA UIDs found for host %s", host)
 	}
-	mappingContext, err := p.StorageApi.EnsureClonnerIgroup(xcopyInitiatorGroup, hbaUIDs)
+	mappingContext, err := mapper.EnsureClonnerIgroup(xcopyInitiatorGroup, hbaUIDs)
 	if err != nil {
 		return fmt.Errorf("failed to add the ESX HBA UID %s to the initiator group %w", hbaUIDs, err)
 	}
 
-	lun, err := p.StorageApi.ResolvePVToLUN(pv)
-	if err != nil {
-		return err
+	if !lunResolved {
+		lun, err = p.StorageApi.ResolvePVToLUN(pv)
+		if err != nil {
+			return err
+		}
 	}
 
-	originalInitiatorGroups, err := p.StorageApi.CurrentMappedGroups(lun, mappingContext)
+	originalInitiatorGroups, err := mapper.CurrentMappedGroups(lun, mappingContext)
 	if err != nil {
 		return fmt.Errorf("failed to fetch the current initiator groups of the lun %s: %w", lun.Name, err)
 	}
@@ -254,7 +282,7 @@ func (p *RemoteEsxcliPopulator) Populate(vmId string, sourceVMDKFile string, pv
 				if mappingContext != nil {
 					mappingContext["UnmapAllSdc"] = false
 				}
-				errUnmap := p.StorageApi.UnMap(xcopyInitiatorGroup, lun, mappingContext)
+				errUnmap := mapper.UnMap(xcopyInitiatorGroup, lun, mappingContext)
 				if errUnmap != nil {
 					klog.Infof("failed to unmap all initiator groups during partial cleanup: %s", errUnmap)
 				}
@@ -264,7 +292,7 @@ func (p *RemoteEsxcliPopulator) Populate(vmId string, sourceVMDKFile string, pv
 		}
 	}()
 
-	lun, err = p.StorageApi.Map(xcopyInitiatorGroup, lun, mappingContext)
+	lun, err = mapper.Map(xcopyInitiatorGroup, lun, mappingContext)
 	if err != nil {
 		return fmt.Errorf("failed to map lun %s to initiator group %s: %w", lun, xcopyInitiatorGroup, err)
 	}
@@ -275,7 +303,7 @@ func (p *RemoteEsxcliPopulator) Populate(vmId string, sourceVMDKFile string, pv
 	leaseHostID := strings.ReplaceAll(strings.ToLower(host.String()), ":", "-")
 	err = hostLocker.WithLock(context.Background(), leaseHostID,
 		func(ctx context.Context) error {
-			return rescan(ctx, p.VSphereClient, host, lun.NAA)
+			return rescan(ctx, p.VSphereClient, host, lun.NAA, nvmeAdapters)
 		},
 	)
 
@@ -308,7 +336,7 @@ func (p *RemoteEsxcliPopulator) Populate(vmId string, sourceVMDKFile string, pv
 			klog.Errorf("failed to remove device from detached list %s: %s", lun.Name, err)
 		}
 		// finaly after the kernel have it detached and not having any i/o we can unmap
-		errUnmap := p.StorageApi.UnMap(xcopyInitiatorGroup, lun, mappingContext)
+		errUnmap := mapper.UnMap(xcopyInitiatorGroup, lun, mappingContext)
 		if errUnmap != nil {
 			klog.Errorf("failed in unmap during cleanup, lun %s: %s", lun.Name, errUnmap)
 		}
@@ -316,7 +344,7 @@ func (p *RemoteEsxcliPopulator) Populate(vmId string, sourceVMDKFile string, pv
 		// map the LUN back to the original OCP worker
 		klog.Infof("about to map the volume back to the originalInitiatorGroups, which are: %s", originalInitiatorGroups)
 		for _, group := range originalInitiatorGroups {
-			_, errMap := p.StorageApi.Map(group, lun, mappingContext)
+			_, errMap := mapper.Map(group, lun, mappingContext)
 			if errMap != nil {
 				klog.Warningf("failed to map the volume back the original holder - this may cause problems: %v", errMap)
 			}
@@ -326,7 +354,11 @@ func (p *RemoteEsxcliPopulator) Populate(vmId string, sourceVMDKFile string, pv
 		klog.Infof("about to delete dead devices")
 		klog.Infof("taking a short nap to let the ESX settle down")
 		time.Sleep(5 * time.Second)
-		deleteDeadDevices(p.VSphereClient, host, hbaUIDs, hbaUIDsNamesMap)
+		if isNVMe {
+			deleteDeadDevices(p.VSphereClient, host, nvmeAdapters, hbaUIDsNamesMap)
+		} else {
+			deleteDeadDevices(p.VSphereClient, host, hbaUIDs, hbaUIDsNamesMap)
+		}
 	}()
 
 	// Execute the clone using the unified task handling approach
@@ -407,8 +439,10 @@ func waitForDeviceStateOff(client vmware.Client, host *object.HostSystem, device
 	})
 }
 
-// After mapping a volume the ESX needs a rescan to see the device. ESXs can opt-in to do it automatically
-func rescan(ctx context.Context, client vmware.Client, host *object.HostSystem, targetLUN string) error {
+// After mapping a volume the ESX needs a rescan to see the device. ESXs can opt-in to do it automatically.
+// An NVMe namespace is discovered by rescanning the NVMe over fabrics adapters, the SCSI
+// devices by rescanning all the adapters.
+func rescan(ctx context.Context, client vmware.Client, host *object.HostSystem, targetLUN string, nvmeAdapters []string) error {
 	for i := 1; i <= rescanRetries; i++ {
 		// Check if we should abort (lease was lost)
 		if ctx.Err() != nil {
@@ -429,10 +463,20 @@ func rescan(ctx context.Context, client vmware.Client, host *object.HostSystem,
 			}
 			return nil
 		} else {
-			_, err = client.RunEsxCommand(
-				context.Background(), host, []string{"storage", "core", "adapter", "rescan", "-t", "add", "-a", "1"})
-			if err != nil {
-				klog.Errorf("failed to rescan for adapters, attempt %d/%d due to: %s", i, rescanRetries, err)
+			if len(nvmeAdapters) > 0 {
+				for _, adapter := range nvmeAdapters {
+					_, err = client.RunEsxCommand(
+						context.Background(), host, []string{"storage", "core", "adapter", "rescan", "-t", "add", "-A", adapter})
+					if err != nil {
+						klog.Errorf("failed to rescan NVMe adapter %s, attempt %d/%d due to: %s", adapter, i, rescanRetries, err)
+					}
+				}
+			} else {
+				_, err = client.RunEsxCommand(
+					context.Background(), host, []string{"storage", "core", "adapter", "rescan", "-t", "add", "-a", "1"})
+				if err != nil {
+					klog.Errorf("failed to rescan for adapters, attempt %d/%d due to: %s", i, rescanRetries, err)
+				}
 			}
 
 			// Sleep but respect context cancellation
@@ -460,6 +504,43 @@ func rescan(ctx context.Context, client vmware.Client, host *object.HostSystem,
 	}
 }
 
+// nvmeHost returns the host NQN of the ESX and the names of its NVMe over fabrics adapters
+func nvmeHost(client vmware.Client, host *object.HostSystem) (string, []string, error) {
+	info, err := client.RunEsxCommand(context.Background(), host, []string{"nvme", "info", "get"})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch the NVMe info of host %s: %w", host, err)
+	}
+	hostNQN := ""
+	if len(info) > 0 && len(info[0]["HostNQN"]) > 0 {
+		hostNQN = strings.TrimSpace(info[0]["HostNQN"][0])
+	}
+	if hostNQN == "" {
+		return "", nil, fmt.Errorf("no NVMe host NQN found for host %s", host)
+	}
+
+	r, err := client.RunEsxCommand(context.Background(), host, []string{"nvme", "adapter", "list"})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list the NVMe adapters of host %s: %w", host, err)
+	}
+	adapters := []string{}
+	for _, a := range r {
+		name, hasName := a["Adapter"]
+		if !hasName || len(name) == 0 {
+			continue
+		}
+		// local PCIe drives can't reach the storage, only TCP, FC and RDMA adapters do
+		transport := a["TransportType"]
+		if len(transport) > 0 && strings.EqualFold(transport[0], "PCIe") {
+			continue
+		}
+		adapters = append(adapters, name[0])
+	}
+	if len(adapters) == 0 {
+		return "", nil, fmt.Errorf("no NVMe over fabrics adapters found for host %s", host)
+	}
+	return hostNQN, adapters, nil
+}
+
 func deleteDeadDevices(client vmware.Client, host *object.HostSystem, hbaUIDs []string, hbaUIDsNamesMap map[string]string) error {
 	failedDevices := []string{}
 	for _, adapter := range hbaUIDs {
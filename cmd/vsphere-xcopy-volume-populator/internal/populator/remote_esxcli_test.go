@@ -8,6 +8,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/cli/esx"
 	"github.com/vmware/govmomi/object"
 	"go.uber.org/mock/gomock"
 
@@ -43,7 +44,7 @@ var _ = Describe("rescan", func() {
 			listCmd := []string{"storage", "core", "device", "list", "-d", targetLUN}
 			mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(listCmd)).Return(nil, nil)
 
-			err := rescan(context.Background(), mockClient, host, targetLUN)
+			err := rescan(context.Background(), mockClient, host, targetLUN, nil)
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
@@ -59,7 +60,7 @@ var _ = Describe("rescan", func() {
 				mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(listCmd)).Return(nil, nil),
 			)
 
-			err := rescan(context.Background(), mockClient, host, targetLUN)
+			err := rescan(context.Background(), mockClient, host, targetLUN, nil)
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
@@ -72,7 +73,7 @@ var _ = Describe("rescan", func() {
 			mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(listCmd)).Return(nil, errors.New("device not found")).Times(rescanRetries + 1)
 			mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(rescanCmd)).Return(nil, nil).Times(rescanRetries)
 
-			err := rescan(context.Background(), mockClient, host, targetLUN)
+			err := rescan(context.Background(), mockClient, host, targetLUN, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to find device"))
 		})
@@ -86,7 +87,7 @@ var _ = Describe("rescan", func() {
 			mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(listCmd)).Return(nil, errors.New("device not found")).Times(rescanRetries + 1)
 			mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(rescanCmd)).Return(nil, errors.New("rescan failed")).Times(rescanRetries)
 
-			err := rescan(context.Background(), mockClient, host, targetLUN)
+			err := rescan(context.Background(), mockClient, host, targetLUN, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to find device"))
 		})
@@ -102,7 +103,7 @@ var _ = Describe("rescan", func() {
 			mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(rescanCmd)).Return(nil, errors.New("rescan failed")).Times(2)
 			mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(rescanCmd)).Return(nil, nil).Times(1)
 
-			err := rescan(context.Background(), mockClient, host, targetLUN)
+			err := rescan(context.Background(), mockClient, host, targetLUN, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("should retry even when scan fails and eventually succeed if device found", func() {
@@ -113,10 +114,84 @@ var _ = Describe("rescan", func() {
 			mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(listCmd)).Return(nil, nil).Times(1)
 			mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(rescanCmd)).Return(nil, errors.New("rescan failed")).Times(3)
 
-			err := rescan(context.Background(), mockClient, host, targetLUN)
+			err := rescan(context.Background(), mockClient, host, targetLUN, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	Context("when the device is an NVMe namespace", func() {
+		It("should rescan the NVMe adapters until the namespace is visible", func() {
+			nvmeLUN := "eui.0123456789abcdef"
+			listCmd := []string{"storage", "core", "device", "list", "-d", nvmeLUN}
+			rescanTCP := []string{"storage", "core", "adapter", "rescan", "-t", "add", "-A", "vmhba65"}
+			rescanFC := []string{"storage", "core", "adapter", "rescan", "-t", "add", "-A", "vmhba66"}
+
+			gomock.InOrder(
+				mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(listCmd)).Return(nil, errors.New("device not found")),
+				mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(rescanTCP)).Return(nil, nil),
+				mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(rescanFC)).Return(nil, nil),
+				mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(listCmd)).Return(
+					[]esx.Values{{"Status": []string{"on"}}}, nil),
+			)
+
+			err := rescan(context.Background(), mockClient, host, nvmeLUN, []string{"vmhba65", "vmhba66"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("nvmeHost", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockClient *vmware_mocks.MockClient
+		host       *object.HostSystem
+		infoCmd    = []string{"nvme", "info", "get"}
+		adapterCmd = []string{"nvme", "adapter", "list"}
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = vmware_mocks.NewMockClient(ctrl)
+		host = &object.HostSystem{}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should return the host NQN and the fabrics adapters", func() {
+		mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(infoCmd)).Return(
+			[]esx.Values{{"HostNQN": {"nqn.2014-08.com.vmware:nvme:esx-1"}}}, nil)
+		mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(adapterCmd)).Return([]esx.Values{
+			{"Adapter": {"vmhba64"}, "TransportType": {"TCP"}},
+			{"Adapter": {"vmhba2"}, "TransportType": {"PCIe"}},
+			{"Adapter": {"vmhba65"}, "TransportType": {"FC"}},
+		}, nil)
+
+		hostNQN, adapters, err := nvmeHost(mockClient, host)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hostNQN).To(Equal("nqn.2014-08.com.vmware:nvme:esx-1"))
+		Expect(adapters).To(Equal([]string{"vmhba64", "vmhba65"}))
+	})
+
+	It("should fail when the host has no NVMe over fabrics adapters", func() {
+		mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(infoCmd)).Return(
+			[]esx.Values{{"HostNQN": {"nqn.2014-08.com.vmware:nvme:esx-1"}}}, nil)
+		mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(adapterCmd)).Return(
+			[]esx.Values{{"Adapter": {"vmhba2"}, "TransportType": {"PCIe"}}}, nil)
+
+		_, _, err := nvmeHost(mockClient, host)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no NVMe over fabrics adapters"))
+	})
+
+	It("should fail when the host has no NQN", func() {
+		mockClient.EXPECT().RunEsxCommand(gomock.Any(), host, gomock.Eq(infoCmd)).Return([]esx.Values{{}}, nil)
+
+		_, _, err := nvmeHost(mockClient, host)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no NVMe host NQN"))
+	})
 })
 
 // stubSSHClient is a test implementation of SSHClient for testing checkScriptVersion
@@ -7,6 +7,8 @@ import (
 const (
 	// CleanupXcopyInitiatorGroup is the key to signal cleanup of the initiator group.
 	CleanupXcopyInitiatorGroup = "cleanupXcopyInitiatorGroup"
+	// ProtocolNVMe is the LUN protocol of volumes exposed as NVMe namespaces.
+	ProtocolNVMe = "NVMe"
 )
 
 //go:generate go run go.uber.org/mock/mockgen -destination=mocks/storage_mock_client.go -package=storage_mocks . StorageApi
@@ -47,6 +49,41 @@ type StorageMapper interface {
 	CurrentMappedGroups(targetLUN LUN, context MappingContext) ([]string, error)
 }
 
+// NVMeMapper handles namespace to host subsystem mapping for VMDK/Xcopy operations
+// over NVMe-oF (TCP or FC). Storage implementing it is mapped through the host
+// subsystem whenever the resolved LUN protocol is ProtocolNVMe
+type NVMeMapper interface {
+	// EnsureClonnerSubsystem creates or updates a host subsystem with the clonner host NQNs
+	EnsureClonnerSubsystem(subsystem string, hostNQNs []string) (MappingContext, error)
+	// MapNamespace is responsible for attaching a namespace to a host subsystem
+	MapNamespace(subsystem string, targetNamespace LUN, context MappingContext) (LUN, error)
+	// UnMapNamespace is responsible for detaching a namespace from a host subsystem
+	UnMapNamespace(subsystem string, targetNamespace LUN, context MappingContext) error
+	// CurrentMappedSubsystems returns the host subsystems the namespace is attached to
+	CurrentMappedSubsystems(targetNamespace LUN, context MappingContext) ([]string, error)
+}
+
+// nvmeStorageMapper adapts an NVMeMapper to the StorageMapper used by the populator
+type nvmeStorageMapper struct {
+	NVMeMapper
+}
+
+func (m nvmeStorageMapper) EnsureClonnerIgroup(subsystem string, hostNQNs []string) (MappingContext, error) {
+	return m.EnsureClonnerSubsystem(subsystem, hostNQNs)
+}
+
+func (m nvmeStorageMapper) Map(subsystem string, targetLUN LUN, context MappingContext) (LUN, error) {
+	return m.MapNamespace(subsystem, targetLUN, context)
+}
+
+func (m nvmeStorageMapper) UnMap(subsystem string, targetLUN LUN, context MappingContext) error {
+	return m.UnMapNamespace(subsystem, targetLUN, context)
+}
+
+func (m nvmeStorageMapper) CurrentMappedGroups(targetLUN LUN, context MappingContext) ([]string, error) {
+	return m.CurrentMappedSubsystems(targetLUN, context)
+}
+
 // VMDKCapable defines storage that can perform VMDK/Xcopy operations (DEFAULT fallback)
 // This is the required interface - all storage implementations must support this
 type VMDKCapable interface {
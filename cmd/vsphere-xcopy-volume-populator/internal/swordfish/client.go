@@ -0,0 +1,352 @@
+package swordfish
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kubev2v/forklift/cmd/vsphere-xcopy-volume-populator/internal/version"
+	"k8s.io/klog/v2"
+)
+
+// serviceRoot is the root of the Redfish/Swordfish service
+const serviceRoot = "/redfish/v1"
+
+// userAgent is the User-Agent string sent with all HTTP requests
+var userAgent = "swordfish/forklift/" + version.Version
+
+// Link is a reference to another Swordfish resource
+type Link struct {
+	ODataID string `json:"@odata.id"`
+}
+
+// Collection is a Swordfish resource collection. Large collections are
+// returned in pages linked by the next link
+type Collection struct {
+	Members  []Link `json:"Members"`
+	NextLink string `json:"Members@odata.nextLink,omitempty"`
+}
+
+// page is a page of a collection with the members as returned by the
+// service, either links or expanded resources
+type page struct {
+	Members  []json.RawMessage `json:"Members"`
+	NextLink string            `json:"Members@odata.nextLink,omitempty"`
+}
+
+// ExpandQuery describes the support of the $expand query parameter
+type ExpandQuery struct {
+	ExpandAll bool `json:"ExpandAll"`
+	NoLinks   bool `json:"NoLinks"`
+}
+
+// ProtocolFeatures are the optional protocol features of the service
+type ProtocolFeatures struct {
+	ExpandQuery *ExpandQuery `json:"ExpandQuery,omitempty"`
+	FilterQuery bool         `json:"FilterQuery"`
+}
+
+// ServiceRoot is the root resource of the service
+type ServiceRoot struct {
+	ProtocolFeaturesSupported ProtocolFeatures `json:"ProtocolFeaturesSupported"`
+}
+
+// Identifier is a durable name of a resource, e.g. the NAA of a volume or
+// the IQN of an endpoint
+type Identifier struct {
+	DurableName       string `json:"DurableName"`
+	DurableNameFormat string `json:"DurableNameFormat"`
+}
+
+// NVMeNamespaceProperties is set on volumes exposed as NVMe namespaces
+type NVMeNamespaceProperties struct {
+	NamespaceId string `json:"NamespaceId,omitempty"`
+}
+
+// Volume represents a Swordfish volume or NVMe namespace
+type Volume struct {
+	ODataID                 string                   `json:"@odata.id,omitempty"`
+	Id                      string                   `json:"Id,omitempty"`
+	Name                    string                   `json:"Name,omitempty"`
+	Identifiers             []Identifier             `json:"Identifiers,omitempty"`
+	NVMeNamespaceProperties *NVMeNamespaceProperties `json:"NVMeNamespaceProperties,omitempty"`
+}
+
+// Endpoint represents a host initiator port on the fabric
+type Endpoint struct {
+	ODataID     string       `json:"@odata.id,omitempty"`
+	Id          string       `json:"Id,omitempty"`
+	Name        string       `json:"Name,omitempty"`
+	EntityRole  string       `json:"EntityRole,omitempty"`
+	Identifiers []Identifier `json:"Identifiers,omitempty"`
+}
+
+// EndpointGroupLinks holds the endpoints of a group
+type EndpointGroupLinks struct {
+	Endpoints []Link `json:"Endpoints"`
+}
+
+// EndpointGroup is a group of initiator endpoints, the initiator group of
+// SCSI storage or the host of an NVMe subsystem
+type EndpointGroup struct {
+	ODataID   string             `json:"@odata.id,omitempty"`
+	Id        string             `json:"Id,omitempty"`
+	Name      string             `json:"Name,omitempty"`
+	GroupType string             `json:"GroupType,omitempty"`
+	Links     EndpointGroupLinks `json:"Links"`
+}
+
+// VolumeInfo is a volume exposed by a connection
+type VolumeInfo struct {
+	Volume             Link     `json:"Volume"`
+	AccessCapabilities []string `json:"AccessCapabilities,omitempty"`
+}
+
+// ConnectionLinks holds the initiators of a connection
+type ConnectionLinks struct {
+	InitiatorEndpointGroups []Link `json:"InitiatorEndpointGroups,omitempty"`
+}
+
+// Connection exposes volumes to groups of initiator endpoints
+type Connection struct {
+	ODataID        string          `json:"@odata.id,omitempty"`
+	Id             string          `json:"Id,omitempty"`
+	Name           string          `json:"Name,omitempty"`
+	ConnectionType string          `json:"ConnectionType,omitempty"`
+	VolumeInfo     []VolumeInfo    `json:"VolumeInfo"`
+	Links          ConnectionLinks `json:"Links"`
+}
+
+// Client provides REST API access to a Swordfish service
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+	// the $expand value reading the members of a collection at once, empty
+	// when the service does not support it
+	expand string
+	// whether the service supports the $filter query parameter
+	filter bool
+}
+
+// NewClient creates a new REST client for a Swordfish service. The hostname may
+// include the scheme and port, https is used when the scheme is missing
+func NewClient(hostname, username, password string, skipSSLVerify bool) (*Client, error) {
+	baseURL := strings.TrimSuffix(hostname, "/")
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "https://" + baseURL
+	}
+	client := &Client{
+		baseURL:  baseURL,
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: skipSSLVerify,
+				},
+			},
+		},
+	}
+
+	root := ServiceRoot{}
+	if err := client.Get(serviceRoot, &root); err != nil {
+		return nil, fmt.Errorf("failed to connect to the swordfish service %s: %w", baseURL, err)
+	}
+	if expand := root.ProtocolFeaturesSupported.ExpandQuery; expand != nil {
+		switch {
+		case expand.NoLinks:
+			client.expand = "."
+		case expand.ExpandAll:
+			client.expand = "*"
+		}
+	}
+	client.filter = root.ProtocolFeaturesSupported.FilterQuery
+
+	// the service root is public, the storage collection verifies the credentials
+	storage := Collection{}
+	if err := client.Get(serviceRoot+"/Storage", &storage); err != nil {
+		return nil, fmt.Errorf("failed to connect to the swordfish service %s: %w", baseURL, err)
+	}
+	klog.Infof("Swordfish REST Client: connected to %s with %d storage systems, expand %q filter %t",
+		baseURL, len(storage.Members), client.expand, client.filter)
+	return client, nil
+}
+
+// Get reads the resource at path into out
+func (c *Client) Get(path string, out any) error {
+	_, err := c.do(http.MethodGet, path, nil, out)
+	return err
+}
+
+// Create posts the resource to the collection at path and reads the created
+// resource into out
+func (c *Client) Create(path string, in any, out any) error {
+	resp, err := c.do(http.MethodPost, path, in, out)
+	if err != nil {
+		return err
+	}
+	// services may answer with the location only
+	if location := resp.Header.Get("Location"); location != "" && resp.ContentLength == 0 {
+		return c.Get(location, out)
+	}
+	return nil
+}
+
+// Update patches the resource at path
+func (c *Client) Update(path string, in any) error {
+	_, err := c.do(http.MethodPatch, path, in, nil)
+	return err
+}
+
+// Delete deletes the resource at path
+func (c *Client) Delete(path string) error {
+	_, err := c.do(http.MethodDelete, path, nil, nil)
+	return err
+}
+
+// Members returns the members of the collection at path, following the next
+// links of paged collections
+func (c *Client) Members(path string) ([]Link, error) {
+	members := []Link{}
+	for next := path; next != ""; {
+		collection := Collection{}
+		if err := c.Get(next, &collection); err != nil {
+			return nil, err
+		}
+		members = append(members, collection.Members...)
+		next = collection.NextLink
+	}
+	return members, nil
+}
+
+// list reads all the members of the collection at path. The members are
+// expanded in the collection when the service supports it, otherwise each
+// member is read on its own. The filter is an OData $filter expression sent
+// when the service supports it, the caller still has to match the members
+func list[T any](c *Client, path string, filter string) ([]T, error) {
+	query := url.Values{}
+	if c.expand != "" {
+		query.Set("$expand", c.expand+"($levels=1)")
+	}
+	if c.filter && filter != "" {
+		query.Set("$filter", filter)
+	}
+	items := []T{}
+	next := path
+	if len(query) > 0 {
+		next = path + "?" + query.Encode()
+	}
+	for next != "" {
+		p := page{}
+		if err := c.Get(next, &p); err != nil {
+			if len(query) == 0 {
+				return nil, err
+			}
+			// the service advertises the query parameters but rejects them
+			klog.Infof("failed to list %s with the query %s, listing each member: %v", path, query.Encode(), err)
+			query = url.Values{}
+			items = items[:0]
+			next = path
+			continue
+		}
+		for _, raw := range p.Members {
+			var item T
+			if expanded(raw) {
+				if err := json.Unmarshal(raw, &item); err != nil {
+					return nil, fmt.Errorf("failed to parse a member of %s: %w", path, err)
+				}
+			} else {
+				link := Link{}
+				if err := json.Unmarshal(raw, &link); err != nil {
+					return nil, fmt.Errorf("failed to parse a member of %s: %w", path, err)
+				}
+				if err := c.Get(link.ODataID, &item); err != nil {
+					return nil, err
+				}
+			}
+			items = append(items, item)
+		}
+		next = p.NextLink
+	}
+	return items, nil
+}
+
+// expanded returns whether the member holds the resource rather than a link
+func expanded(member json.RawMessage) bool {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(member, &fields); err != nil {
+		return false
+	}
+	for k := range fields {
+		if k != "@odata.id" {
+			return true
+		}
+	}
+	return false
+}
+
+// filterEq returns an OData filter matching the property to any of the values
+func filterEq(property string, values ...string) string {
+	terms := []string{}
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		terms = append(terms, fmt.Sprintf("%s eq '%s'", property, strings.ReplaceAll(v, "'", "''")))
+	}
+	return strings.Join(terms, " or ")
+}
+
+func (c *Client) do(method, path string, in any, out any) (*http.Response, error) {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s %s request: %w", method, path, err)
+		}
+		body = bytes.NewReader(payload)
+	}
+	target := path
+	if strings.HasPrefix(path, "/") {
+		target = c.baseURL + path
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s %s request: %w", method, path, err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s %s response: %w", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, string(respBody))
+	}
+	resp.ContentLength = int64(len(respBody))
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return nil, fmt.Errorf("failed to parse %s %s response: %w", method, path, err)
+		}
+	}
+	return resp, nil
+}
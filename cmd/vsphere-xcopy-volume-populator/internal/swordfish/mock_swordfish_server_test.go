@@ -0,0 +1,212 @@
+package swordfish
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	mockUsername = "admin"
+	mockPassword = "secret"
+
+	mockStorage   = serviceRoot + "/Storage/1"
+	mockVolumes   = mockStorage + "/Volumes"
+	mockFabric    = serviceRoot + "/Fabrics/1"
+	mockEndpoints = mockFabric + "/Endpoints"
+	mockGroups    = mockFabric + "/EndpointGroups"
+	mockConns     = mockFabric + "/Connections"
+)
+
+// mockSwordfishServer is a local in-memory Swordfish service with a single
+// storage system and fabric. Resources are kept as JSON objects by URI and
+// collections hold the URIs of their members.
+type mockSwordfishServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	resources   map[string]map[string]any
+	collections map[string][]string
+	nextID      int
+	// the number of members per page of a collection, all when zero
+	pageSize int
+	// reject the query parameters even when advertised
+	rejectQuery bool
+	// the number of GET requests served
+	gets int
+}
+
+func newMockSwordfishServer(t *testing.T) *mockSwordfishServer {
+	s := &mockSwordfishServer{
+		resources:   map[string]map[string]any{},
+		collections: map[string][]string{},
+	}
+	for _, c := range []string{
+		serviceRoot + "/Storage", mockVolumes,
+		serviceRoot + "/Fabrics", mockEndpoints, mockGroups, mockConns,
+	} {
+		s.collections[c] = []string{}
+	}
+	s.resources[serviceRoot] = map[string]any{"@odata.id": serviceRoot}
+	s.add(serviceRoot+"/Storage", map[string]any{"Id": "1", "Name": "array"})
+	s.add(serviceRoot+"/Fabrics", map[string]any{"Id": "1", "Name": "fabric"})
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// add stores the resource in the collection and returns its URI. The id of
+// the resource is generated when not set.
+func (s *mockSwordfishServer) add(collection string, resource map[string]any) string {
+	id, ok := resource["Id"].(string)
+	if !ok {
+		s.nextID++
+		id = strconv.Itoa(s.nextID)
+		resource["Id"] = id
+	}
+	uri := collection + "/" + id
+	resource["@odata.id"] = uri
+	s.resources[uri] = resource
+	s.collections[collection] = append(s.collections[collection], uri)
+	return uri
+}
+
+// features sets the query parameters advertised by the service root
+func (s *mockSwordfishServer) features(expand, filter bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[serviceRoot]["ProtocolFeaturesSupported"] = map[string]any{
+		"ExpandQuery": map[string]any{"NoLinks": expand},
+		"FilterQuery": filter,
+	}
+}
+
+// members returns the resources of the collection
+func (s *mockSwordfishServer) members(collection string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	members := []map[string]any{}
+	for _, uri := range s.collections[collection] {
+		members = append(members, s.resources[uri])
+	}
+	return members
+}
+
+func (s *mockSwordfishServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, password, ok := r.BasicAuth()
+	if !ok || user != mockUsername || password != mockPassword {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "unauthorized"})
+		return
+	}
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodGet:
+		s.gets++
+		if members, found := s.collections[path]; found {
+			s.serveCollection(w, r, path, members)
+			return
+		}
+		if resource, found := s.resources[path]; found {
+			writeJSON(w, http.StatusOK, resource)
+			return
+		}
+	case http.MethodPost:
+		if _, found := s.collections[path]; found {
+			resource := map[string]any{}
+			if err := json.NewDecoder(r.Body).Decode(&resource); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+				return
+			}
+			uri := s.add(path, resource)
+			w.Header().Set("Location", uri)
+			writeJSON(w, http.StatusCreated, resource)
+			return
+		}
+	case http.MethodPatch:
+		if resource, found := s.resources[path]; found {
+			patch := map[string]any{}
+			if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+				return
+			}
+			for k, v := range patch {
+				resource[k] = v
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	case http.MethodDelete:
+		if _, found := s.resources[path]; found {
+			delete(s.resources, path)
+			collection := path[:strings.LastIndex(path, "/")]
+			s.collections[collection] = slices.DeleteFunc(s.collections[collection], func(uri string) bool {
+				return uri == path
+			})
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]any{"error": "not found"})
+}
+
+// serveCollection serves a page of the collection with the members filtered
+// and expanded as requested
+func (s *mockSwordfishServer) serveCollection(w http.ResponseWriter, r *http.Request, path string, members []string) {
+	query := r.URL.Query()
+	expand, filter := query.Get("$expand"), query.Get("$filter")
+	if s.rejectQuery && (expand != "" || filter != "") {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "query not supported"})
+		return
+	}
+	if filter != "" {
+		members = slices.DeleteFunc(slices.Clone(members), func(uri string) bool {
+			return !matches(s.resources[uri], filter)
+		})
+	}
+	skip, _ := strconv.Atoi(query.Get("$skip"))
+	end := len(members)
+	if s.pageSize > 0 {
+		end = min(skip+s.pageSize, end)
+	}
+	items := []any{}
+	for _, uri := range members[skip:end] {
+		if expand != "" {
+			items = append(items, s.resources[uri])
+		} else {
+			items = append(items, Link{ODataID: uri})
+		}
+	}
+	body := map[string]any{"Members": items, "Members@odata.count": len(members)}
+	if end < len(members) {
+		query.Set("$skip", strconv.Itoa(end))
+		body["Members@odata.nextLink"] = path + "?" + query.Encode()
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// matches evaluates a filter of "Property eq 'value'" terms joined by "or"
+func matches(resource map[string]any, filter string) bool {
+	for _, term := range strings.Split(filter, " or ") {
+		property, value, found := strings.Cut(term, " eq ")
+		if !found {
+			continue
+		}
+		value = strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(value, "'"), "'"), "''", "'")
+		if resource[property] == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,457 @@
+package swordfish
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/kubev2v/forklift/cmd/vsphere-xcopy-volume-populator/internal/fcutil"
+	"github.com/kubev2v/forklift/cmd/vsphere-xcopy-volume-populator/internal/populator"
+	"k8s.io/klog/v2"
+)
+
+const (
+	STORAGE_ID_ENV_KEY = "SWORDFISH_STORAGE_ID"
+	FABRIC_ID_ENV_KEY  = "SWORDFISH_FABRIC_ID"
+
+	endpointGroupContextKey string = "endpointGroup"
+
+	// durable name formats of the Swordfish identifiers
+	formatNAA   = "NAA"
+	formatEUI   = "EUI"
+	formatNGUID = "NGUID"
+	formatIQN   = "iQN"
+	formatWWN   = "FC_WWN"
+	formatNQN   = "NQN"
+
+	entityRoleInitiator = "Initiator"
+	groupTypeInitiator  = "Initiator"
+	connectionTypeStore = "Storage"
+)
+
+var _ populator.VMDKCapable = &SwordfishClonner{}
+var _ populator.NVMeMapper = &SwordfishClonner{}
+
+// SwordfishClonner implements the storage api for any array exposing a SNIA
+// Swordfish service. Initiator groups and NVMe hosts are fabric endpoint groups,
+// and volumes are mapped to them through fabric connections.
+type SwordfishClonner struct {
+	api *Client
+	// the storage system holding the volumes, all systems when empty
+	storageID string
+	// the fabric holding the endpoints and the connections
+	fabricID string
+}
+
+// NewSwordfishClonner creates a clonner for the Swordfish service at hostname
+func NewSwordfishClonner(hostname, username, password string, skipSSLVerify bool) (SwordfishClonner, error) {
+	api, err := NewClient(hostname, username, password, skipSSLVerify)
+	if err != nil {
+		return SwordfishClonner{}, err
+	}
+	return newSwordfishClonner(api, os.Getenv(STORAGE_ID_ENV_KEY), os.Getenv(FABRIC_ID_ENV_KEY))
+}
+
+func newSwordfishClonner(api *Client, storageID, fabricID string) (SwordfishClonner, error) {
+	if fabricID == "" {
+		fabrics, err := api.Members(serviceRoot + "/Fabrics")
+		if err != nil {
+			return SwordfishClonner{}, fmt.Errorf("failed to list the fabrics: %w", err)
+		}
+		if len(fabrics) != 1 {
+			return SwordfishClonner{}, fmt.Errorf("found %d fabrics, please set %s in the pod environment or in the secret", len(fabrics), FABRIC_ID_ENV_KEY)
+		}
+		fabricID = lastSegment(fabrics[0].ODataID)
+	}
+	klog.Infof("using swordfish fabric %s and storage %q", fabricID, storageID)
+	return SwordfishClonner{api: api, storageID: storageID, fabricID: fabricID}, nil
+}
+
+// EnsureClonnerIgroup implements populator.StorageApi.
+func (c *SwordfishClonner) EnsureClonnerIgroup(initiatorGroup string, adapterIds []string) (populator.MappingContext, error) {
+	identifiers := []Identifier{}
+	for _, id := range adapterIds {
+		switch {
+		case strings.HasPrefix(id, "iqn."):
+			identifiers = append(identifiers, Identifier{DurableName: id, DurableNameFormat: formatIQN})
+		case strings.HasPrefix(id, "fc."):
+			wwpn, err := fcutil.ExtractAndFormatWWPN(id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract the WWPN of adapter %s: %w", id, err)
+			}
+			identifiers = append(identifiers, Identifier{DurableName: wwpn, DurableNameFormat: formatWWN})
+		case strings.HasPrefix(id, "nqn."):
+			identifiers = append(identifiers, Identifier{DurableName: id, DurableNameFormat: formatNQN})
+		default:
+			klog.Infof("skipping adapter %s with unknown identifier format", id)
+		}
+	}
+	return c.ensureEndpointGroup(initiatorGroup, identifiers)
+}
+
+// EnsureClonnerSubsystem implements populator.NVMeMapper.
+func (c *SwordfishClonner) EnsureClonnerSubsystem(subsystem string, hostNQNs []string) (populator.MappingContext, error) {
+	identifiers := make([]Identifier, 0, len(hostNQNs))
+	for _, nqn := range hostNQNs {
+		identifiers = append(identifiers, Identifier{DurableName: nqn, DurableNameFormat: formatNQN})
+	}
+	return c.ensureEndpointGroup(subsystem, identifiers)
+}
+
+// Map implements populator.StorageApi.
+func (c *SwordfishClonner) Map(initiatorGroup string, targetLUN populator.LUN, _ populator.MappingContext) (populator.LUN, error) {
+	return targetLUN, c.connect(initiatorGroup, targetLUN)
+}
+
+// MapNamespace implements populator.NVMeMapper.
+func (c *SwordfishClonner) MapNamespace(subsystem string, targetNamespace populator.LUN, _ populator.MappingContext) (populator.LUN, error) {
+	return targetNamespace, c.connect(subsystem, targetNamespace)
+}
+
+// UnMap implements populator.StorageApi.
+func (c *SwordfishClonner) UnMap(initiatorGroup string, targetLUN populator.LUN, mappingContext populator.MappingContext) error {
+	return c.disconnect(initiatorGroup, targetLUN, mappingContext)
+}
+
+// UnMapNamespace implements populator.NVMeMapper.
+func (c *SwordfishClonner) UnMapNamespace(subsystem string, targetNamespace populator.LUN, mappingContext populator.MappingContext) error {
+	return c.disconnect(subsystem, targetNamespace, mappingContext)
+}
+
+// CurrentMappedGroups implements populator.StorageApi.
+func (c *SwordfishClonner) CurrentMappedGroups(targetLUN populator.LUN, _ populator.MappingContext) ([]string, error) {
+	return c.connectedGroups(targetLUN)
+}
+
+// CurrentMappedSubsystems implements populator.NVMeMapper.
+func (c *SwordfishClonner) CurrentMappedSubsystems(targetNamespace populator.LUN, _ populator.MappingContext) ([]string, error) {
+	return c.connectedGroups(targetNamespace)
+}
+
+// ResolvePVToLUN implements populator.StorageApi.
+// The volume is matched by the id, name or URI set as the volume handle, or
+// by the name of the persistent volume.
+func (c *SwordfishClonner) ResolvePVToLUN(pv populator.PersistentVolume) (populator.LUN, error) {
+	// volume handles holding the URI are only matched locally
+	filter := ""
+	if !strings.Contains(pv.VolumeHandle, "/") {
+		filter = filterEq("Name", pv.VolumeHandle, pv.Name)
+		if pv.VolumeHandle != "" {
+			filter += " or " + filterEq("Id", pv.VolumeHandle)
+		}
+	}
+	volumes, err := c.volumes(filter)
+	if err != nil {
+		return populator.LUN{}, err
+	}
+	matched := []Volume{}
+	for _, v := range volumes {
+		if (pv.VolumeHandle != "" && (v.Id == pv.VolumeHandle || v.Name == pv.VolumeHandle || v.ODataID == pv.VolumeHandle)) ||
+			(pv.Name != "" && v.Name == pv.Name) {
+			matched = append(matched, v)
+		}
+	}
+	if len(matched) != 1 {
+		return populator.LUN{}, fmt.Errorf("expected a single volume for volume handle %s but found %d", pv.VolumeHandle, len(matched))
+	}
+	volume := matched[0]
+
+	lun := populator.LUN{
+		Name:         volume.Name,
+		ProviderID:   volume.ODataID,
+		VolumeHandle: pv.VolumeHandle,
+	}
+	if nguid := identifier(volume.Identifiers, formatNGUID, formatEUI); volume.NVMeNamespaceProperties != nil || nguid != "" {
+		if nguid == "" {
+			return populator.LUN{}, fmt.Errorf("namespace %s has no NGUID or EUI identifier", volume.ODataID)
+		}
+		lun.Protocol = populator.ProtocolNVMe
+		lun.SerialNumber = nguid
+		lun.NAA = fmt.Sprintf("eui.%s", nguid)
+	} else {
+		naa := identifier(volume.Identifiers, formatNAA)
+		if naa == "" {
+			return populator.LUN{}, fmt.Errorf("volume %s has no NAA identifier", volume.ODataID)
+		}
+		lun.SerialNumber = naa
+		lun.NAA = fmt.Sprintf("naa.%s", naa)
+	}
+	klog.Infof("resolved volume handle %s to %s %s", pv.VolumeHandle, volume.ODataID, lun.NAA)
+	return lun, nil
+}
+
+// ensureEndpointGroup ensures an initiator endpoint for every identifier and
+// a group named name holding them
+func (c *SwordfishClonner) ensureEndpointGroup(name string, identifiers []Identifier) (populator.MappingContext, error) {
+	klog.Infof("ensuring endpoint group %s for initiators %+v", name, identifiers)
+	if len(identifiers) == 0 {
+		return nil, fmt.Errorf("no initiators with a known identifier for endpoint group %s", name)
+	}
+	endpoints, err := list[Endpoint](c.api, c.fabricPath("Endpoints"), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the endpoints: %w", err)
+	}
+	links := []Link{}
+	for _, id := range identifiers {
+		endpoint, found := findEndpoint(endpoints, id)
+		if !found {
+			klog.Infof("creating initiator endpoint for %s", id.DurableName)
+			err = c.api.Create(c.fabricPath("Endpoints"), Endpoint{
+				Name:        id.DurableName,
+				EntityRole:  entityRoleInitiator,
+				Identifiers: []Identifier{id},
+			}, &endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create the endpoint of initiator %s: %w", id.DurableName, err)
+			}
+		}
+		links = append(links, Link{ODataID: endpoint.ODataID})
+	}
+
+	group, found, err := c.endpointGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		klog.Infof("endpoint group %s not found, creating it", name)
+		err = c.api.Create(c.fabricPath("EndpointGroups"), EndpointGroup{
+			Name:      name,
+			GroupType: groupTypeInitiator,
+			Links:     EndpointGroupLinks{Endpoints: links},
+		}, &group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create endpoint group %s: %w", name, err)
+		}
+	} else {
+		missing := false
+		for _, l := range links {
+			if !slices.Contains(group.Links.Endpoints, l) {
+				group.Links.Endpoints = append(group.Links.Endpoints, l)
+				missing = true
+			}
+		}
+		if missing {
+			klog.Infof("adding the initiators to endpoint group %s", name)
+			err = c.api.Update(group.ODataID, map[string]any{"Links": group.Links})
+			if err != nil {
+				return nil, fmt.Errorf("failed to update endpoint group %s: %w", name, err)
+			}
+		}
+	}
+	return populator.MappingContext{endpointGroupContextKey: group.ODataID}, nil
+}
+
+// connect exposes the volume to the endpoint group
+func (c *SwordfishClonner) connect(groupName string, targetLUN populator.LUN) error {
+	klog.Infof("connecting volume %s to endpoint group %s", targetLUN.Name, groupName)
+	group, found, err := c.endpointGroup(groupName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("endpoint group %s not found", groupName)
+	}
+	connections, err := c.connections(group.ODataID, targetLUN.ProviderID)
+	if err != nil {
+		return err
+	}
+	if len(connections) > 0 {
+		klog.Infof("volume %s is already connected to endpoint group %s", targetLUN.Name, groupName)
+		return nil
+	}
+	err = c.api.Create(c.fabricPath("Connections"), Connection{
+		Name:           fmt.Sprintf("%s-%s", groupName, targetLUN.Name),
+		ConnectionType: connectionTypeStore,
+		VolumeInfo: []VolumeInfo{{
+			Volume:             Link{ODataID: targetLUN.ProviderID},
+			AccessCapabilities: []string{"Read", "Write"},
+		}},
+		Links: ConnectionLinks{InitiatorEndpointGroups: []Link{{ODataID: group.ODataID}}},
+	}, &Connection{})
+	if err != nil {
+		return fmt.Errorf("failed to connect volume %s to endpoint group %s: %w", targetLUN.Name, groupName, err)
+	}
+	return nil
+}
+
+// disconnect removes the volume from the connections of the endpoint group, and
+// deletes the group when the cleanup of the clonner group is requested
+func (c *SwordfishClonner) disconnect(groupName string, targetLUN populator.LUN, mappingContext populator.MappingContext) error {
+	klog.Infof("disconnecting volume %s from endpoint group %s", targetLUN.Name, groupName)
+	group, found, err := c.endpointGroup(groupName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		klog.Infof("endpoint group %s not found, nothing to disconnect", groupName)
+		return nil
+	}
+	connections, err := c.connections(group.ODataID, targetLUN.ProviderID)
+	if err != nil {
+		return err
+	}
+	for _, conn := range connections {
+		if len(conn.VolumeInfo) == 1 && len(conn.Links.InitiatorEndpointGroups) == 1 {
+			err = c.api.Delete(conn.ODataID)
+		} else {
+			remaining := slices.DeleteFunc(conn.VolumeInfo, func(v VolumeInfo) bool {
+				return v.Volume.ODataID == targetLUN.ProviderID
+			})
+			err = c.api.Update(conn.ODataID, map[string]any{"VolumeInfo": remaining})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to disconnect volume %s from endpoint group %s: %w", targetLUN.Name, groupName, err)
+		}
+	}
+
+	cleanup, ok := mappingContext[populator.CleanupXcopyInitiatorGroup]
+	if ok && cleanup.(bool) {
+		remaining, err := c.connections(group.ODataID, "")
+		if err != nil {
+			return err
+		}
+		if len(remaining) > 0 {
+			klog.Infof("endpoint group %s still has %d connections, keeping it", groupName, len(remaining))
+			return nil
+		}
+		klog.Infof("deleting endpoint group %s", groupName)
+		if err := c.api.Delete(group.ODataID); err != nil {
+			return fmt.Errorf("failed to delete endpoint group %s: %w", groupName, err)
+		}
+	}
+	return nil
+}
+
+// connectedGroups returns the names of the endpoint groups the volume is connected to
+func (c *SwordfishClonner) connectedGroups(targetLUN populator.LUN) ([]string, error) {
+	connections, err := c.connections("", targetLUN.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(connections) == 0 {
+		return []string{}, nil
+	}
+	all, err := list[EndpointGroup](c.api, c.fabricPath("EndpointGroups"), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the endpoint groups: %w", err)
+	}
+	groups := []string{}
+	for _, conn := range connections {
+		for _, l := range conn.Links.InitiatorEndpointGroups {
+			i := slices.IndexFunc(all, func(g EndpointGroup) bool { return g.ODataID == l.ODataID })
+			if i == -1 {
+				return nil, fmt.Errorf("endpoint group %s not found", l.ODataID)
+			}
+			if !slices.Contains(groups, all[i].Name) {
+				groups = append(groups, all[i].Name)
+			}
+		}
+	}
+	return groups, nil
+}
+
+// connections returns the connections of the fabric, filtered by the endpoint
+// group and the volume when set
+func (c *SwordfishClonner) connections(group, volume string) ([]Connection, error) {
+	all, err := list[Connection](c.api, c.fabricPath("Connections"), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the connections: %w", err)
+	}
+	connections := []Connection{}
+	for _, conn := range all {
+		if group != "" && !slices.Contains(conn.Links.InitiatorEndpointGroups, Link{ODataID: group}) {
+			continue
+		}
+		if volume != "" && !slices.ContainsFunc(conn.VolumeInfo, func(v VolumeInfo) bool {
+			return v.Volume.ODataID == volume
+		}) {
+			continue
+		}
+		connections = append(connections, conn)
+	}
+	return connections, nil
+}
+
+// endpointGroup finds the endpoint group by name
+func (c *SwordfishClonner) endpointGroup(name string) (EndpointGroup, bool, error) {
+	groups, err := list[EndpointGroup](c.api, c.fabricPath("EndpointGroups"), filterEq("Name", name))
+	if err != nil {
+		return EndpointGroup{}, false, fmt.Errorf("failed to list the endpoint groups: %w", err)
+	}
+	for _, g := range groups {
+		if g.Name == name {
+			return g, true, nil
+		}
+	}
+	return EndpointGroup{}, false, nil
+}
+
+// volumes returns the volumes of the storage system, or of all the systems,
+// filtered by the service when supported
+func (c *SwordfishClonner) volumes(filter string) ([]Volume, error) {
+	storage := []string{c.storageID}
+	if c.storageID == "" {
+		members, err := c.api.Members(serviceRoot + "/Storage")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the storage systems: %w", err)
+		}
+		storage = storage[:0]
+		for _, m := range members {
+			storage = append(storage, lastSegment(m.ODataID))
+		}
+	}
+	volumes := []Volume{}
+	for _, id := range storage {
+		v, err := list[Volume](c.api, fmt.Sprintf("%s/Storage/%s/Volumes", serviceRoot, id), filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the volumes of storage %s: %w", id, err)
+		}
+		volumes = append(volumes, v...)
+	}
+	return volumes, nil
+}
+
+func (c *SwordfishClonner) fabricPath(collection string) string {
+	return fmt.Sprintf("%s/Fabrics/%s/%s", serviceRoot, c.fabricID, collection)
+}
+
+// findEndpoint finds the initiator endpoint with the identifier
+func findEndpoint(endpoints []Endpoint, id Identifier) (Endpoint, bool) {
+	for _, e := range endpoints {
+		for _, eid := range e.Identifiers {
+			if eid.DurableNameFormat != id.DurableNameFormat {
+				continue
+			}
+			match := strings.EqualFold(eid.DurableName, id.DurableName)
+			if id.DurableNameFormat == formatWWN {
+				match = fcutil.CompareWWNs(eid.DurableName, id.DurableName)
+			}
+			if match {
+				return e, true
+			}
+		}
+	}
+	return Endpoint{}, false
+}
+
+// identifier returns the first durable name in one of the formats, as lower
+// case hex without separators or prefix
+func identifier(identifiers []Identifier, formats ...string) string {
+	for _, id := range identifiers {
+		if !slices.Contains(formats, id.DurableNameFormat) {
+			continue
+		}
+		name := strings.ToLower(id.DurableName)
+		for _, prefix := range []string{"naa.", "eui."} {
+			name = strings.TrimPrefix(name, prefix)
+		}
+		return strings.ToLower(fcutil.NormalizeWWN(name))
+	}
+	return ""
+}
+
+func lastSegment(uri string) string {
+	uri = strings.TrimSuffix(uri, "/")
+	return uri[strings.LastIndex(uri, "/")+1:]
+}
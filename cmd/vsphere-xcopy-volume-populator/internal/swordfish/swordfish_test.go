@@ -0,0 +1,343 @@
+package swordfish
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kubev2v/forklift/cmd/vsphere-xcopy-volume-populator/internal/populator"
+)
+
+func newTestClonner(t *testing.T, server *mockSwordfishServer) SwordfishClonner {
+	t.Helper()
+	t.Setenv(STORAGE_ID_ENV_KEY, "")
+	t.Setenv(FABRIC_ID_ENV_KEY, "")
+	clonner, err := NewSwordfishClonner(server.URL, mockUsername, mockPassword, true)
+	if err != nil {
+		t.Fatalf("failed to create the clonner: %v", err)
+	}
+	return clonner
+}
+
+func TestNewSwordfishClonner(t *testing.T) {
+	server := newMockSwordfishServer(t)
+	hostname := strings.TrimPrefix(server.URL, "https://")
+
+	clonner, err := NewSwordfishClonner(hostname, mockUsername, mockPassword, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if clonner.fabricID != "1" {
+		t.Errorf("expected the single fabric to be discovered, got %q", clonner.fabricID)
+	}
+
+	_, err = NewSwordfishClonner(hostname, mockUsername, "wrong", true)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected an unauthorized error, got %v", err)
+	}
+
+	server.add(serviceRoot+"/Fabrics", map[string]any{"Name": "other"})
+	_, err = NewSwordfishClonner(hostname, mockUsername, mockPassword, true)
+	if err == nil || !strings.Contains(err.Error(), FABRIC_ID_ENV_KEY) {
+		t.Errorf("expected the fabric id to be required, got %v", err)
+	}
+	t.Setenv(FABRIC_ID_ENV_KEY, "1")
+	if _, err = NewSwordfishClonner(hostname, mockUsername, mockPassword, true); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestResolvePVToLUN(t *testing.T) {
+	server := newMockSwordfishServer(t)
+	server.add(mockVolumes, map[string]any{
+		"Id":          "7",
+		"Name":        "pvc-scsi",
+		"Identifiers": []any{map[string]any{"DurableName": "600A0980383041334A3F4A2D2F6C5A31", "DurableNameFormat": "NAA"}},
+	})
+	server.add(mockVolumes, map[string]any{
+		"Id":                      "8",
+		"Name":                    "pvc-nvme",
+		"Identifiers":             []any{map[string]any{"DurableName": "9A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9", "DurableNameFormat": "NGUID"}},
+		"NVMeNamespaceProperties": map[string]any{"NamespaceId": "0x1"},
+	})
+	server.add(mockVolumes, map[string]any{
+		"Id":                      "9",
+		"Name":                    "pvc-uuid",
+		"Identifiers":             []any{map[string]any{"DurableName": "6b9b1c6e-1f0a-4bd6-9a3f-3c9e0c1d2e3f", "DurableNameFormat": "UUID"}},
+		"NVMeNamespaceProperties": map[string]any{"NamespaceId": "0x2"},
+	})
+	clonner := newTestClonner(t, server)
+
+	tests := []struct {
+		name         string
+		pv           populator.PersistentVolume
+		wantNAA      string
+		wantProtocol string
+		wantErr      string
+	}{
+		{
+			name:    "volume id as the volume handle",
+			pv:      populator.PersistentVolume{Name: "pv-1", VolumeHandle: "7"},
+			wantNAA: "naa.600a0980383041334a3f4a2d2f6c5a31",
+		},
+		{
+			name:    "volume uri as the volume handle",
+			pv:      populator.PersistentVolume{Name: "pv-1", VolumeHandle: mockVolumes + "/7"},
+			wantNAA: "naa.600a0980383041334a3f4a2d2f6c5a31",
+		},
+		{
+			name:         "namespace named after the persistent volume",
+			pv:           populator.PersistentVolume{Name: "pvc-nvme", VolumeHandle: "csi-handle"},
+			wantNAA:      "eui.9a1b2c3d4e5f60718293a4b5c6d7e8f9",
+			wantProtocol: populator.ProtocolNVMe,
+		},
+		{
+			name:    "namespace without NGUID",
+			pv:      populator.PersistentVolume{Name: "pvc-uuid"},
+			wantErr: "no NGUID or EUI identifier",
+		},
+		{
+			name:    "unknown volume",
+			pv:      populator.PersistentVolume{Name: "pv-2", VolumeHandle: "42"},
+			wantErr: "found 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lun, err := clonner.ResolvePVToLUN(tt.pv)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lun.NAA != tt.wantNAA {
+				t.Errorf("expected NAA %s, got %s", tt.wantNAA, lun.NAA)
+			}
+			if lun.Protocol != tt.wantProtocol {
+				t.Errorf("expected protocol %q, got %q", tt.wantProtocol, lun.Protocol)
+			}
+		})
+	}
+}
+
+func TestEnsureClonnerIgroup(t *testing.T) {
+	server := newMockSwordfishServer(t)
+	// the FC initiator is known to the array with a different formatting
+	server.add(mockEndpoints, map[string]any{
+		"Name":        "esx-fc",
+		"EntityRole":  "Initiator",
+		"Identifiers": []any{map[string]any{"DurableName": "2100000000000001", "DurableNameFormat": "FC_WWN"}},
+	})
+	clonner := newTestClonner(t, server)
+	adapters := []string{"iqn.1998-01.com.vmware:esx-1", "fc.2000000000000001:2100000000000001"}
+
+	mappingContext, err := clonner.EnsureClonnerIgroup("xcopy-host-1", adapters)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mappingContext[endpointGroupContextKey] == "" {
+		t.Errorf("expected the endpoint group in the mapping context, got %v", mappingContext)
+	}
+	if n := len(server.members(mockEndpoints)); n != 2 {
+		t.Errorf("expected only the iSCSI endpoint to be created, found %d endpoints", n)
+	}
+	groups := server.members(mockGroups)
+	if len(groups) != 1 || groups[0]["Name"] != "xcopy-host-1" {
+		t.Fatalf("expected the endpoint group to be created, got %v", groups)
+	}
+	if links := groups[0]["Links"].(map[string]any)["Endpoints"].([]any); len(links) != 2 {
+		t.Errorf("expected the group to hold both endpoints, got %v", links)
+	}
+
+	// idempotent
+	if _, err = clonner.EnsureClonnerIgroup("xcopy-host-1", adapters); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(server.members(mockEndpoints)) != 2 || len(server.members(mockGroups)) != 1 {
+		t.Errorf("expected the endpoints and the group to be reused")
+	}
+
+	if _, err = clonner.EnsureClonnerIgroup("xcopy-host-2", []string{"unknown.1"}); err == nil {
+		t.Errorf("expected an error for adapters without a known identifier")
+	}
+}
+
+func TestMapUnMap(t *testing.T) {
+	server := newMockSwordfishServer(t)
+	volume := server.add(mockVolumes, map[string]any{
+		"Name":        "pvc-1",
+		"Identifiers": []any{map[string]any{"DurableName": "600a0980383041334a3f4a2d2f6c5a31", "DurableNameFormat": "NAA"}},
+	})
+	worker := server.add(mockGroups, map[string]any{"Name": "ocp-worker", "GroupType": "Initiator"})
+	server.add(mockConns, map[string]any{
+		"Name":       "ocp-worker-pvc-1",
+		"VolumeInfo": []any{map[string]any{"Volume": map[string]any{"@odata.id": volume}}},
+		"Links":      map[string]any{"InitiatorEndpointGroups": []any{map[string]any{"@odata.id": worker}}},
+	})
+	clonner := newTestClonner(t, server)
+
+	mappingContext, err := clonner.EnsureClonnerIgroup("xcopy-host-1", []string{"iqn.1998-01.com.vmware:esx-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lun, err := clonner.ResolvePVToLUN(populator.PersistentVolume{Name: "pvc-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	groups, err := clonner.CurrentMappedGroups(lun, mappingContext)
+	if err != nil || !slices.Equal(groups, []string{"ocp-worker"}) {
+		t.Fatalf("expected the volume to be mapped to the worker, got %v %v", groups, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err = clonner.Map("xcopy-host-1", lun, mappingContext); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := len(server.members(mockConns)); n != 2 {
+		t.Errorf("expected a single connection for the clonner, found %d connections", n)
+	}
+	groups, _ = clonner.CurrentMappedGroups(lun, mappingContext)
+	if !slices.Equal(groups, []string{"ocp-worker", "xcopy-host-1"}) {
+		t.Errorf("expected the volume to be mapped to the clonner, got %v", groups)
+	}
+
+	mappingContext[populator.CleanupXcopyInitiatorGroup] = true
+	if err = clonner.UnMap("xcopy-host-1", lun, mappingContext); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	groups, _ = clonner.CurrentMappedGroups(lun, mappingContext)
+	if !slices.Equal(groups, []string{"ocp-worker"}) {
+		t.Errorf("expected the volume to be unmapped from the clonner only, got %v", groups)
+	}
+	remaining := server.members(mockGroups)
+	if len(remaining) != 1 || remaining[0]["Name"] != "ocp-worker" {
+		t.Errorf("expected the clonner group to be deleted, got %v", remaining)
+	}
+
+	if _, err = clonner.Map("missing", lun, mappingContext); err == nil {
+		t.Errorf("expected an error mapping to a missing group")
+	}
+}
+
+func TestNVMeSubsystem(t *testing.T) {
+	server := newMockSwordfishServer(t)
+	server.add(mockVolumes, map[string]any{
+		"Name":                    "pvc-nvme",
+		"Identifiers":             []any{map[string]any{"DurableName": "9a1b2c3d4e5f60718293a4b5c6d7e8f9", "DurableNameFormat": "NGUID"}},
+		"NVMeNamespaceProperties": map[string]any{"NamespaceId": "0x1"},
+	})
+	clonner := newTestClonner(t, server)
+	hostNQN := "nqn.2014-08.com.vmware:nvme:esx-1"
+
+	mappingContext, err := clonner.EnsureClonnerSubsystem("xcopy-host-1", []string{hostNQN})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	endpoints := server.members(mockEndpoints)
+	if len(endpoints) != 1 {
+		t.Fatalf("expected the host NQN endpoint to be created, got %v", endpoints)
+	}
+	id := endpoints[0]["Identifiers"].([]any)[0].(map[string]any)
+	if id["DurableNameFormat"] != "NQN" || id["DurableName"] != hostNQN {
+		t.Errorf("expected the endpoint to be identified by the host NQN, got %v", id)
+	}
+
+	namespace, err := clonner.ResolvePVToLUN(populator.PersistentVolume{Name: "pvc-nvme"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = clonner.MapNamespace("xcopy-host-1", namespace, mappingContext); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subsystems, err := clonner.CurrentMappedSubsystems(namespace, mappingContext)
+	if err != nil || !slices.Equal(subsystems, []string{"xcopy-host-1"}) {
+		t.Errorf("expected the namespace to be attached to the host, got %v %v", subsystems, err)
+	}
+	if err = clonner.UnMapNamespace("xcopy-host-1", namespace, mappingContext); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(server.members(mockConns)); n != 0 {
+		t.Errorf("expected the namespace to be detached, found %d connections", n)
+	}
+}
+
+func TestList(t *testing.T) {
+	server := newMockSwordfishServer(t)
+	server.pageSize = 2
+	for _, name := range []string{"pvc-1", "pvc-2", "pvc-3", "pvc-'4'", "pvc-5"} {
+		server.add(mockVolumes, map[string]any{"Name": name})
+	}
+	names := func(volumes []Volume) []string {
+		n := []string{}
+		for _, v := range volumes {
+			n = append(n, v.Name)
+		}
+		return n
+	}
+
+	tests := []struct {
+		name        string
+		expand      bool
+		filter      bool
+		rejectQuery bool
+		wantGets    int
+		wantNames   []string
+	}{
+		{
+			name:      "members read one by one",
+			wantGets:  3 + 5,
+			wantNames: []string{"pvc-1", "pvc-2", "pvc-3", "pvc-'4'", "pvc-5"},
+		},
+		{
+			name:      "members expanded",
+			expand:    true,
+			wantGets:  3,
+			wantNames: []string{"pvc-1", "pvc-2", "pvc-3", "pvc-'4'", "pvc-5"},
+		},
+		{
+			name:      "members expanded and filtered",
+			expand:    true,
+			filter:    true,
+			wantGets:  1,
+			wantNames: []string{"pvc-'4'"},
+		},
+		{
+			name:        "query rejected",
+			expand:      true,
+			filter:      true,
+			rejectQuery: true,
+			wantGets:    1 + 3 + 5,
+			wantNames:   []string{"pvc-1", "pvc-2", "pvc-3", "pvc-'4'", "pvc-5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.features(tt.expand, tt.filter)
+			server.rejectQuery = tt.rejectQuery
+			client, err := NewClient(server.URL, mockUsername, mockPassword, true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			members, err := client.Members(mockVolumes)
+			if err != nil || len(members) != 5 {
+				t.Fatalf("expected the members of all the pages, got %v %v", members, err)
+			}
+			server.gets = 0
+			volumes, err := list[Volume](client, mockVolumes, filterEq("Name", "pvc-'4'"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(names(volumes), tt.wantNames) {
+				t.Errorf("expected volumes %v, got %v", tt.wantNames, names(volumes))
+			}
+			if server.gets != tt.wantGets {
+				t.Errorf("expected %d requests, got %d", tt.wantGets, server.gets)
+			}
+		})
+	}
+}
@@ -37,6 +37,10 @@ const (
 	ConditionFailed    = "Failed"
 	ConditionBlocked   = "Blocked"
 	ConditionDeleted   = "Deleted"
+	// A hook that may fail has failed.
+	ConditionHookFailed = "HookFailed"
+	// A guest script that may fail has failed.
+	ConditionGuestScriptFailed = "GuestScriptFailed"
 )
 
 // Condition categories
@@ -58,6 +62,16 @@ const (
 	PhaseCompleted = "Completed"
 )
 
+// Hook phases.
+// Hooks run at these phases in addition to PreHook and PostHook.
+const (
+	PhaseCutoverHook       = "CutoverHook"
+	PhaseFailureHook       = "FailureHook"
+	PhasePreConversionHook = "PreConversionHook"
+	PhasePrePowerOffHook   = "PrePowerOffHook"
+	PhasePrecopyHook       = "PrecopyHook"
+)
+
 // Warm and cold phases.
 const (
 	PhaseAddCheckpoint                     = "AddCheckpoint"
@@ -80,9 +94,11 @@ const (
 	PhaseRemoveFinalSnapshot               = "RemoveFinalSnapshot"
 	PhaseRemovePenultimateSnapshot         = "RemovePenultimateSnapshot"
 	PhaseRemovePreviousSnapshot            = "RemovePreviousSnapshot"
+	PhaseRunGuestScripts                   = "RunGuestScripts"
 	PhaseStoreInitialSnapshotDeltas        = "StoreInitialSnapshotDeltas"
 	PhaseStorePowerState                   = "StorePowerState"
 	PhaseStoreSnapshotDeltas               = "StoreSnapshotDeltas"
+	PhaseVerifyVM                          = "VerifyVM"
 	PhaseWaitForFinalSnapshot              = "WaitForFinalSnapshot"
 	PhaseWaitForFinalSnapshotRemoval       = "WaitForFinalSnapshotRemoval"
 	PhaseWaitForInitialSnapshot            = "WaitForInitialSnapshot"
@@ -92,6 +108,13 @@ const (
 	PhaseWaitForSnapshot                   = "WaitForSnapshot"
 )
 
+// Rollback phases.
+const (
+	PhaseRollbackDeleteTarget      = "RollbackDeleteTarget"
+	PhaseRollbackRemoveSnapshots   = "RollbackRemoveSnapshots"
+	PhaseRollbackRestorePowerState = "RollbackRestorePowerState"
+)
+
 // Step/task phases.
 const (
 	StepStarted   = "Started"
@@ -104,4 +127,5 @@ const (
 const (
 	AnnDiskSource = "forklift.konveyor.io/disk-source"
 	AnnSource     = "forklift.konveyor.io/source"
+	AnnPreview    = "forklift.konveyor.io/preview"
 )
@@ -70,6 +70,7 @@ const (
 	StorageVendorProductPowerMax       StorageVendorProduct = "powermax"
 	StorageVendorProductPowerStore     StorageVendorProduct = "powerstore"
 	StorageVendorProductInfinibox      StorageVendorProduct = "infinibox"
+	StorageVendorProductSwordfish      StorageVendorProduct = "swordfish"
 )
 
 func StorageVendorProducts() []StorageVendorProduct {
@@ -83,6 +84,7 @@ func StorageVendorProducts() []StorageVendorProduct {
 		StorageVendorProductPowerMax,
 		StorageVendorProductPowerStore,
 		StorageVendorProductInfinibox,
+		StorageVendorProductSwordfish,
 	}
 }
 
@@ -93,7 +95,7 @@ type VSphereXcopyPluginConfig struct {
 	// The secret should reside in the same namespace where the source provider is.
 	SecretRef string `json:"secretRef"`
 	// StorageVendorProduct the string identifier of the storage vendor product
-	// +kubebuilder:validation:Enum=flashsystem;vantara;ontap;primera3par;pureFlashArray;powerflex;powermax;powerstore;infinibox
+	// +kubebuilder:validation:Enum=flashsystem;vantara;ontap;primera3par;pureFlashArray;powerflex;powermax;powerstore;infinibox;swordfish
 	StorageVendorProduct StorageVendorProduct `json:"storageVendorProduct"`
 }
 
@@ -119,12 +121,53 @@ type DestinationStorage struct {
 	AccessMode core.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
 }
 
+// Network map rule.
+// Generates the pairs of the source networks with
+// a name matching the source pattern.
+type NetworkMapRule struct {
+	// Regular expression matched against the source network name.
+	Source string `json:"source"`
+	// Destination network. The namespace and name may reference
+	// the groups of the source pattern. Example: `vlan-$1`.
+	Destination DestinationNetwork `json:"destination"`
+	// Labels selecting the destination network (NAD) instead of
+	// the name (multus only). The values may reference the groups
+	// of the source pattern. The first matching NAD (by namespace/name)
+	// in the destination namespace (or any namespace when not set) is used.
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+}
+
+// Storage map rule.
+// Generates the pairs of the source storage with
+// a name matching the source pattern.
+type StorageMapRule struct {
+	// Regular expression matched against the source storage name.
+	Source string `json:"source"`
+	// Destination storage. The storage class may reference
+	// the groups of the source pattern. Example: `${tier}-rbd`.
+	Destination DestinationStorage `json:"destination"`
+	// Labels selecting the destination storage class. The values
+	// may reference the groups of the source pattern. The first matching
+	// storage class (by name) is used. The storage class of the destination
+	// (when not empty) is used when no storage class is selected.
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+}
+
 // Network map spec.
 type NetworkMapSpec struct {
 	// Provider
 	Provider provider.Pair `json:"provider"`
 	// Map.
 	Map []NetworkPair `json:"map"`
+	// Rules generating the map pairs for the source networks
+	// found in the inventory. The first matching rule is used.
+	// Networks mapped explicitly are not generated. The generated
+	// pairs are reported in the status (not in the map) and are
+	// regenerated when the source networks change.
+	// +optional
+	Rules []NetworkMapRule `json:"rules,omitempty"`
 }
 
 // Storage map spec.
@@ -133,6 +176,13 @@ type StorageMapSpec struct {
 	Provider provider.Pair `json:"provider"`
 	// Map.
 	Map []StoragePair `json:"map"`
+	// Rules generating the map pairs for the source storage
+	// found in the inventory. The first matching rule is used.
+	// Storage mapped explicitly is not generated. The generated
+	// pairs are reported in the status (not in the map) and are
+	// regenerated when the source storage changes.
+	// +optional
+	Rules []StorageMapRule `json:"rules,omitempty"`
 }
 
 // MapStatus defines the observed state of Maps.
@@ -146,6 +196,22 @@ type MapStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// Network map status.
+type NetworkMapStatus struct {
+	MapStatus `json:",inline"`
+	// Pairs generated by the rules.
+	// +optional
+	Generated []NetworkPair `json:"generated,omitempty"`
+}
+
+// Storage map status.
+type StorageMapStatus struct {
+	MapStatus `json:",inline"`
+	// Pairs generated by the rules.
+	// +optional
+	Generated []StoragePair `json:"generated,omitempty"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:openapi-gen=true
@@ -155,13 +221,29 @@ type MapStatus struct {
 type NetworkMap struct {
 	meta.TypeMeta   `json:",inline"`
 	meta.ObjectMeta `json:"metadata,omitempty"`
-	Spec            NetworkMapSpec `json:"spec,omitempty"`
-	Status          MapStatus      `json:"status,omitempty"`
+	Spec            NetworkMapSpec   `json:"spec,omitempty"`
+	Status          NetworkMapStatus `json:"status,omitempty"`
 	// Referenced resources populated
 	// during validation.
 	Referenced `json:"-"`
 }
 
+// The pairs of the map followed by the
+// pairs generated by the rules.
+func (r *NetworkMap) Pairs() (pairs []NetworkPair) {
+	pairs = append(pairs, r.Spec.Map...)
+	pairs = append(pairs, r.Status.Generated...)
+	return
+}
+
+// Resolve the pairs generated by the rules into the map.
+// Must only be used with a map that is not updated.
+func (r *NetworkMap) Resolve() {
+	if len(r.Status.Generated) > 0 {
+		r.Spec.Map = r.Pairs()
+	}
+}
+
 // Find network map for source ID.
 func (r *NetworkMap) FindNetwork(networkID string) (pair NetworkPair, found bool) {
 	for _, pair = range r.Spec.Map {
@@ -219,13 +301,29 @@ type NetworkMapList struct {
 type StorageMap struct {
 	meta.TypeMeta   `json:",inline"`
 	meta.ObjectMeta `json:"metadata,omitempty"`
-	Spec            StorageMapSpec `json:"spec,omitempty"`
-	Status          MapStatus      `json:"status,omitempty"`
+	Spec            StorageMapSpec   `json:"spec,omitempty"`
+	Status          StorageMapStatus `json:"status,omitempty"`
 	// Referenced resources populated
 	// during validation.
 	Referenced `json:"-"`
 }
 
+// The pairs of the map followed by the
+// pairs generated by the rules.
+func (r *StorageMap) Pairs() (pairs []StoragePair) {
+	pairs = append(pairs, r.Spec.Map...)
+	pairs = append(pairs, r.Status.Generated...)
+	return
+}
+
+// Resolve the pairs generated by the rules into the map.
+// Must only be used with a map that is not updated.
+func (r *StorageMap) Resolve() {
+	if len(r.Status.Generated) > 0 {
+		r.Spec.Map = r.Pairs()
+	}
+}
+
 // Find storage map for source ID.
 func (r *StorageMap) FindStorage(storageID string) (pair StoragePair, found bool) {
 	for _, pair = range r.Spec.Map {
@@ -250,6 +348,22 @@ func (r *StorageMap) FindStorageByName(storageName string) (pair StoragePair, fo
 	return
 }
 
+// Find storage map for the source storage.
+// Matched by ID, then by name.
+func (r *StorageMap) FindStorageByRef(storage ref.Ref) (pair StoragePair, found bool) {
+	if storage.ID != "" {
+		pair, found = r.FindStorage(storage.ID)
+		if found {
+			return
+		}
+	}
+	if storage.Name != "" {
+		pair, found = r.FindStorageByName(storage.Name)
+	}
+
+	return
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type StorageMapList struct {
 	meta.TypeMeta `json:",inline"`
@@ -93,6 +93,12 @@ func (r *Migration) Match(plan *Plan) bool {
 		ref.Name == plan.Name
 }
 
+// Name of the ConfigMap containing the
+// report of the completed migration.
+func (r *Migration) ReportName() string {
+	return r.Name + "-report"
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type MigrationList struct {
 	meta.TypeMeta `json:",inline"`
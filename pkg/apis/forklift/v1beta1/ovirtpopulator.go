@@ -26,6 +26,9 @@ type OvirtVolumePopulatorSpec struct {
 	DiskID           string `json:"diskId"`
 	// The network attachment definition that should be used for disk transfer.
 	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+	// Maximum transfer rate in MB/s. Zero is unlimited.
+	// +optional
+	TransferRateLimit int `json:"transferRateLimit,omitempty"`
 }
 
 type OvirtVolumePopulatorStatus struct {
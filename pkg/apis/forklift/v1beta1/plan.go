@@ -119,7 +119,21 @@ type PlanSpec struct {
 	// Resource mapping.
 	Map plan.Map `json:"map"`
 	// List of VMs.
+	// +optional
 	VMs []plan.VM `json:"vms"`
+	// Selects (additional) VMs in the source inventory.
+	// Resolved when the plan is validated and frozen
+	// when a migration is started.
+	// +optional
+	VMSelector *plan.VMSelector `json:"vmSelector,omitempty"`
+	// Maps the vSphere tags and custom attributes of the
+	// source VMs to labels or annotations of the target VMs.
+	// +optional
+	MetadataMapping []plan.MetadataMapping `json:"metadataMapping,omitempty"`
+	// Places the target VMs in namespaces (or labels them) by the
+	// vSphere folder or resource pool of the source VMs.
+	// +optional
+	Placement *plan.Placement `json:"placement,omitempty"`
 	// Whether this is a warm migration.
 	// Deprecated: this field will be deprecated in 2.10. Use Type instead.
 	Warm bool `json:"warm,omitempty"`
@@ -253,6 +267,27 @@ type PlanSpec struct {
 	//
 	// +optional
 	DeleteVmOnFailMigration bool `json:"deleteVmOnFailMigration,omitempty"`
+	// Rollback determines what happens to a VM when its migration fails.
+	// - "None" (default): the target and source VMs are left as they are.
+	// - "Automatic": a compensating itinerary deletes the target VM, DataVolumes
+	//   and PVCs, removes the snapshots created by the migration and restores
+	//   the recorded power state of the source VM. Rollback progress is
+	//   recorded as steps in the VM pipeline.
+	// +optional
+	// +kubebuilder:validation:Enum=None;Automatic
+	Rollback plan.RollbackPolicy `json:"rollback,omitempty"`
+	// HealthCheck verifies the target VM after it has been created: the VM
+	// instance is running, the guest agent is connected and (optionally) the
+	// probes and the readiness script pass within the timeout. A failed check
+	// fails the VM migration (and triggers the rollback when automatic).
+	// +optional
+	HealthCheck *plan.HealthCheck `json:"healthCheck,omitempty"`
+	// TransferRateLimit is the maximum rate, in MB/s, of the disk transfers of each VM.
+	// The rate is shared by the disks of a VM transferred at once and is capped by the
+	// `transferRateLimit` setting of the source provider. Zero (default) is unlimited.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TransferRateLimit int `json:"transferRateLimit,omitempty"`
 	// InstallLegacyDrivers determines whether to install legacy windows drivers in the VM.
 	//The following Vm's are lack of SHA-2 support and need legacy drivers:
 	// Windows XP (all)
@@ -305,6 +340,31 @@ type PlanSpec struct {
 	// execution order. If not specified, no custom scripts are injected.
 	// +optional
 	CustomizationScripts *core.ObjectReference `json:"customizationScripts,omitempty"`
+	// GuestScripts run inside the target VM through the qemu guest agent after
+	// the VM has booted. The scripts are selected by the OS family reported by
+	// the guest agent and run one at a time. A failed script fails the VM
+	// migration unless `continueOnFailure` is set.
+	// +optional
+	GuestScripts *plan.GuestScripts `json:"guestScripts,omitempty"`
+	// Schedule the migration of the plan.
+	// When set, the controller creates the Migration once the start time is reached
+	// and sets the cutover time on it. Neither happens inside a blackout period; the
+	// action is deferred to the end of the blackout instead.
+	//   - start: Date and time the migration is started.
+	//   - cutover: Date and time a warm migration is finalized. Ignored for cold migrations.
+	//   - timezone: IANA timezone used to evaluate recurring blackouts (default: UTC).
+	//   - blackouts: Absolute (start, end) or recurring (days, from, to) periods.
+	// Example:
+	//   schedule:
+	//     start: "2025-06-06T22:00:00Z"
+	//     cutover: "2025-06-07T02:00:00Z"
+	//     timezone: Europe/Prague
+	//     blackouts:
+	//       - days: [Mon, Tue, Wed, Thu, Fri]
+	//         from: "08:00"
+	//         to: "18:00"
+	// +optional
+	Schedule *plan.Schedule `json:"schedule,omitempty"`
 }
 
 // Find a planned VM.
@@ -339,6 +399,18 @@ type PlanStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Migration
 	Migration plan.MigrationStatus `json:"migration,omitempty"`
+	// Schedule
+	// +optional
+	Schedule *plan.ScheduleStatus `json:"schedule,omitempty"`
+	// Migration readiness of the VMs.
+	// +optional
+	Readiness *plan.ReadinessSummary `json:"readiness,omitempty"`
+	// VMs selected by the VM selector.
+	// +optional
+	Selection *plan.Selection `json:"selection,omitempty"`
+	// VM placement resolved by the placement policy.
+	// +optional
+	Placement []plan.VMPlacement `json:"placement,omitempty"`
 }
 
 // +genclient
@@ -398,6 +470,85 @@ func (p *Plan) ShouldUseV2vForTransfer() (bool, error) {
 	}
 }
 
+// Target namespace of the VM.
+// The namespace assigned by the placement policy when the
+// migration started, otherwise the plan target namespace.
+func (r *Plan) VMTargetNamespace(vmRef ref.Ref) string {
+	if vm, found := r.Status.Migration.FindVM(vmRef); found && vm.TargetNamespace != "" {
+		return vm.TargetNamespace
+	}
+	return r.Spec.TargetNamespace
+}
+
+// Target namespace of the VM resolved by the placement
+// policy, otherwise the plan target namespace.
+func (r *Plan) PlacedNamespace(vmRef ref.Ref) string {
+	if p, found := r.FindPlacement(vmRef); found && p.TargetNamespace != "" {
+		return p.TargetNamespace
+	}
+	return r.Spec.TargetNamespace
+}
+
+// Find the placement of the VM.
+func (r *Plan) FindPlacement(vmRef ref.Ref) (placement *plan.VMPlacement, found bool) {
+	for i := range r.Status.Placement {
+		p := &r.Status.Placement[i]
+		if (vmRef.ID != "" && p.ID == vmRef.ID) || (vmRef.ID == "" && p.Name == vmRef.Name) {
+			placement = p
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// Target namespaces.
+// The plan target namespace, the namespaces resolved by the
+// placement policy and the namespaces of the migrated VMs.
+func (r *Plan) TargetNamespaces() (namespaces []string) {
+	namespaces = []string{r.Spec.TargetNamespace}
+	seen := map[string]bool{r.Spec.TargetNamespace: true}
+	add := func(namespace string) {
+		if namespace != "" && !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	for _, p := range r.Status.Placement {
+		add(p.TargetNamespace)
+	}
+	for _, vm := range r.Status.Migration.VMs {
+		add(vm.TargetNamespace)
+	}
+	return
+}
+
+// Transfer rate limit of each VM in MB/s.
+// The plan limit capped by the aggregate limit of the
+// source provider. Zero is unlimited.
+func (r *Plan) TransferRateLimit() (limit int) {
+	limit = r.Spec.TransferRateLimit
+	source := r.Referenced.Provider.Source
+	if source == nil {
+		return
+	}
+	aggregate := source.TransferRateLimit()
+	if aggregate > 0 && (limit == 0 || limit > aggregate) {
+		limit = aggregate
+	}
+	return
+}
+
+// Transfer rate limit of each disk of a VM in MB/s.
+// The VM limit is shared by the disks transferred at once.
+func (r *Plan) DiskTransferRateLimit(disks int) (limit int) {
+	limit = r.TransferRateLimit()
+	if limit > 0 && disks > 1 {
+		limit = max(limit/disks, 1)
+	}
+	return
+}
+
 func (r *Plan) DestinationHasUdnNetwork(client k8sclient.Client) bool {
 	key := k8sclient.ObjectKey{
 		Name: r.Spec.TargetNamespace,
@@ -440,6 +591,12 @@ func init() {
 	SchemeBuilder.Register(&Plan{}, &PlanList{})
 }
 
+// Name of the ConfigMap containing the rendered
+// preview of the resources created by the plan.
+func (r *Plan) PreviewName() string {
+	return r.Name + "-preview"
+}
+
 func (r *Plan) IsSourceProviderOpenstack() bool {
 	return r.Provider.Source.Type() == OpenStack
 }
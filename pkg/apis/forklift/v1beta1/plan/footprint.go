@@ -0,0 +1,31 @@
+package plan
+
+import "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+
+// Resources held by a VM migration.
+// Recorded when the VM migration is started and used
+// to enforce the provider concurrency limits.
+type Footprint struct {
+	// Disks.
+	Disks []DiskFootprint `json:"disks,omitempty"`
+	// Transfer rate limit in MB/s.
+	Rate int `json:"rate,omitempty"`
+}
+
+// Disk held by a VM migration.
+type DiskFootprint struct {
+	// Source storage (datastore, storage domain, volume type).
+	Storage ref.Ref `json:"storage"`
+	// Destination storage class.
+	StorageClass string `json:"storageClass,omitempty"`
+	// Capacity in bytes.
+	Capacity int64 `json:"capacity,omitempty"`
+}
+
+// Total capacity of the disks.
+func (r *Footprint) Bytes() (bytes int64) {
+	for _, disk := range r.Disks {
+		bytes += disk.Capacity
+	}
+	return
+}
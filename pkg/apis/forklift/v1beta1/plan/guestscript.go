@@ -0,0 +1,30 @@
+package plan
+
+import (
+	core "k8s.io/api/core/v1"
+)
+
+// Guest OS families.
+const (
+	GuestOSLinux   = "linux"
+	GuestOSWindows = "windows"
+)
+
+// Guest scripts.
+// Run inside the target VM through the guest agent after the VM has booted.
+type GuestScripts struct {
+	// ConfigMap containing the scripts. The key selects the OS family:
+	//   - Windows: [0-9]+_win_postboot_[description_text].ps1
+	//   - Linux: [0-9]+_linux_postboot_[description_text].sh
+	// The number at the start of the key determines the execution order.
+	// The namespace defaults to the target namespace of each VM.
+	ConfigMap core.ObjectReference `json:"configMap"`
+	// Timeout (seconds) of each script.
+	// Default: 300.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Timeout int `json:"timeout,omitempty"`
+	// Report a failed script as a warning and continue the migration.
+	// +optional
+	ContinueOnFailure bool `json:"continueOnFailure,omitempty"`
+}
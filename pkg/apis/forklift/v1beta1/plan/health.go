@@ -0,0 +1,98 @@
+package plan
+
+import (
+	"fmt"
+
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	core "k8s.io/api/core/v1"
+)
+
+// Health probe types.
+const (
+	HealthProbeTCP  = "TCP"
+	HealthProbeHTTP = "HTTP"
+)
+
+// Health verification tasks.
+const (
+	HealthTaskRunning    = "VMIRunning"
+	HealthTaskGuestAgent = "GuestAgentConnected"
+	HealthTaskScript     = "ReadinessScript"
+)
+
+// Post-migration health check.
+// Verifies the target VM after it has been created.
+type HealthCheck struct {
+	// Timeout (seconds) for all of the checks to pass.
+	// Default: 600.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Timeout int `json:"timeout,omitempty"`
+	// Do not wait for the guest agent to connect.
+	// +optional
+	SkipGuestAgent bool `json:"skipGuestAgent,omitempty"`
+	// TCP/HTTP probes run against the IP address of the VM.
+	// +optional
+	Probes []HealthProbe `json:"probes,omitempty"`
+	// Hook run as the readiness script after the other checks
+	// have passed. The check passes when the hook succeeds.
+	// +optional
+	Hook *core.ObjectReference `json:"hook,omitempty" ref:"Hook"`
+}
+
+// Build the tasks of the health verification step.
+func (r *HealthCheck) Tasks() (tasks []*Task) {
+	add := func(name, description string) {
+		tasks = append(
+			tasks,
+			&Task{
+				Name:        name,
+				Description: description,
+				Progress:    libitr.Progress{Total: 1},
+			})
+	}
+	add(HealthTaskRunning, "Wait for the VM instance to run.")
+	if !r.SkipGuestAgent {
+		add(HealthTaskGuestAgent, "Wait for the guest agent to connect.")
+	}
+	for i := range r.Probes {
+		probe := &r.Probes[i]
+		add(probe.Name(), "Probe "+probe.String()+".")
+	}
+	if r.Hook != nil {
+		add(HealthTaskScript, "Run the readiness script.")
+	}
+	return
+}
+
+// Health probe.
+type HealthProbe struct {
+	// Probe type: TCP|HTTP.
+	// +kubebuilder:validation:Enum=TCP;HTTP
+	Type string `json:"type"`
+	// Port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// HTTP path.
+	// Default: "/".
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// Task name.
+func (r *HealthProbe) Name() string {
+	return fmt.Sprintf("%s-%d", r.Type, r.Port)
+}
+
+// String representation.
+func (r *HealthProbe) String() string {
+	if r.Type == HealthProbeHTTP {
+		path := r.Path
+		if path == "" {
+			path = "/"
+		}
+		return fmt.Sprintf("%s :%d%s", r.Type, r.Port, path)
+	}
+	return fmt.Sprintf("%s :%d", r.Type, r.Port)
+}
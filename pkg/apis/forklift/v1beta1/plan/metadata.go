@@ -0,0 +1,35 @@
+package plan
+
+// Metadata mapping sources.
+const (
+	// vSphere tag (by category).
+	MetadataTag = "Tag"
+	// vSphere custom attribute.
+	MetadataAttribute = "Attribute"
+)
+
+// Metadata mapping targets.
+const (
+	MetadataLabel      = "Label"
+	MetadataAnnotation = "Annotation"
+)
+
+// Maps the metadata of the source VM to a label
+// or an annotation of the target VM.
+type MetadataMapping struct {
+	// Source metadata: Tag|Attribute.
+	// +kubebuilder:validation:Enum=Tag;Attribute
+	Source string `json:"source"`
+	// Tag category or custom attribute name.
+	// The tags of a category are joined by a comma.
+	Name string `json:"name"`
+	// Target metadata: Label|Annotation.
+	// Default: Label.
+	// +kubebuilder:validation:Enum=Label;Annotation
+	// +optional
+	Target string `json:"target,omitempty"`
+	// Key of the label or annotation. e.g. "example.com/cost-center".
+	// Default: the name.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
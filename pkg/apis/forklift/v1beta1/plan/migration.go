@@ -37,6 +37,8 @@ type MigrationStatus struct {
 	History []Snapshot `json:"history,omitempty"`
 	// VM status
 	VMs []*VMStatus `json:"vms,omitempty"`
+	// Migration waves.
+	Waves []Wave `json:"waves,omitempty"`
 }
 
 // The active snapshot.
@@ -148,10 +150,34 @@ type Task struct {
 	Progress libitr.Progress `json:"progress"`
 	// Annotations.
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// Output.
+	// The result reported by the hook run by the task.
+	Output map[string]string `json:"output,omitempty"`
+	// Hook run by the task.
+	Hook *HookRun `json:"hook,omitempty"`
 	// Error.
 	Error *Error `json:"error,omitempty"`
 }
 
+// Hook run.
+type HookRun struct {
+	// Number of attempts.
+	Attempts int `json:"attempts,omitempty"`
+	// Pods (namespace/name) of the attempts.
+	// The hook logs are the logs of the pods.
+	Pods []string `json:"pods,omitempty"`
+}
+
+// Record the pod of an attempt.
+func (r *HookRun) AddPod(pod string) {
+	for _, p := range r.Pods {
+		if p == pod {
+			return
+		}
+	}
+	r.Pods = append(r.Pods, pod)
+}
+
 // Add an error.
 func (r *Task) AddError(reason ...string) {
 	if r.Error == nil {
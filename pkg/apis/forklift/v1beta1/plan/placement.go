@@ -0,0 +1,67 @@
+package plan
+
+import (
+	"strings"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+)
+
+// Placement sources.
+const (
+	PlacementFolder       = "Folder"
+	PlacementResourcePool = "ResourcePool"
+)
+
+// Placement policy.
+// Places the target VMs by the vSphere folder or resource
+// pool of the source VMs.
+type Placement struct {
+	// Source hierarchy: Folder|ResourcePool.
+	// +kubebuilder:validation:Enum=Folder;ResourcePool
+	Source string `json:"source"`
+	// Rules matched in order against the path of the folder
+	// (resource pool) of the VM. The first matching rule applies.
+	// +optional
+	Rules []PlacementRule `json:"rules,omitempty"`
+	// Template of the target namespace of the VMs not
+	// matched by a rule (or matched by a rule without a namespace).
+	// Variables:
+	//   - .Path: path of the folder (resource pool).
+	//   - .Name: name of the folder (resource pool).
+	// e.g. "{{ lower .Name }}"
+	// Default: the plan target namespace.
+	// +optional
+	NamespaceTemplate string `json:"namespaceTemplate,omitempty"`
+}
+
+// Placement rule.
+type PlacementRule struct {
+	// Path of the folder (resource pool), including the subfolders.
+	// e.g. "/Datacenter/vm/production"
+	Path string `json:"path"`
+	// Target namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Labels applied to the target VMs.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Match the path of the folder (resource pool).
+func (r *PlacementRule) Match(path string) bool {
+	prefix := strings.TrimSuffix(r.Path, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// VM placement resolved by the placement policy.
+type VMPlacement struct {
+	// The VM.
+	ref.Ref `json:",inline"`
+	// Path of the folder (resource pool).
+	Path string `json:"path,omitempty"`
+	// Target namespace.
+	TargetNamespace string `json:"targetNamespace"`
+	// Labels applied to the target VM.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
@@ -0,0 +1,41 @@
+package plan
+
+// Throughput source.
+const (
+	// Measured by previous migrations from the provider.
+	ThroughputHistory = "History"
+	// Default (no previous migrations).
+	ThroughputDefault = "Default"
+)
+
+// Migration readiness of the plan VMs.
+// Summarizes the readiness assessed by the inventory
+// for each VM. Only reported for the providers that
+// support the assessment.
+type ReadinessSummary struct {
+	// Number of VMs assessed.
+	VMs int `json:"vms"`
+	// Number of VMs that could not be assessed.
+	Unassessed int `json:"unassessed,omitempty"`
+	// Number of VMs blocked by critical concerns.
+	Blocked int `json:"blocked,omitempty"`
+	// Number of VMs by complexity.
+	Low    int `json:"low,omitempty"`
+	Medium int `json:"medium,omitempty"`
+	High   int `json:"high,omitempty"`
+	// Average score (0-100) of the VMs.
+	Score int `json:"score"`
+	// Number of warning concerns.
+	Warnings int `json:"warnings,omitempty"`
+	// Estimated bytes to transfer.
+	TransferSize int64 `json:"transferSize,omitempty"`
+	// Throughput (MB/s) used for the estimate.
+	Throughput int64 `json:"throughput,omitempty"`
+	// Throughput source: History|Default.
+	ThroughputSource string `json:"throughputSource,omitempty"`
+	// Estimated transfer time (sequential).
+	EstimatedTransferTime string `json:"estimatedTransferTime,omitempty"`
+	// Plan generation assessed.
+	// The readiness is assessed again when the spec changes.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
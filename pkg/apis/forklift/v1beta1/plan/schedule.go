@@ -0,0 +1,257 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Scheduled actions.
+const (
+	ScheduleActionStart   = "Start"
+	ScheduleActionCutover = "Cutover"
+)
+
+// Layout of the time of day used by recurring blackouts.
+const TimeOfDayLayout = "15:04"
+
+// Schedule of a migration plan.
+// The plan controller creates the Migration when the start time
+// is reached and sets the cutover on it. Actions that fall inside
+// a blackout are deferred to the end of the blackout.
+type Schedule struct {
+	// Date and time the migration is started.
+	// +optional
+	Start *meta.Time `json:"start,omitempty"`
+	// Date and time to finalize a warm migration.
+	// +optional
+	Cutover *meta.Time `json:"cutover,omitempty"`
+	// IANA timezone used to evaluate recurring blackouts. e.g. "Europe/Prague".
+	// Default: UTC.
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+	// Periods during which neither the start nor the cutover may happen.
+	// +optional
+	Blackouts []Blackout `json:"blackouts,omitempty"`
+}
+
+// Blackout period.
+// Either an absolute period (start, end) or a recurring
+// daily window (days, from, to) evaluated in the schedule timezone.
+type Blackout struct {
+	// Absolute start.
+	// +optional
+	Start *meta.Time `json:"start,omitempty"`
+	// Absolute end.
+	// +optional
+	End *meta.Time `json:"end,omitempty"`
+	// Days of the week on which the recurring window begins. e.g. "Mon".
+	// Default: every day.
+	// +optional
+	Days []string `json:"days,omitempty"`
+	// Time of day (HH:MM) the recurring window begins.
+	// +optional
+	From string `json:"from,omitempty"`
+	// Time of day (HH:MM) the recurring window ends.
+	// A window ending before it begins spans midnight.
+	// +optional
+	To string `json:"to,omitempty"`
+}
+
+// ScheduleStatus reports the progress of a scheduled plan.
+type ScheduleStatus struct {
+	// Next scheduled action.
+	// +optional
+	NextAction string `json:"nextAction,omitempty"`
+	// Date and time of the next scheduled action.
+	// +optional
+	NextActionTime *meta.Time `json:"nextActionTime,omitempty"`
+	// The scheduled start that has been acted on.
+	// +optional
+	Started *meta.Time `json:"started,omitempty"`
+	// The migration created (or adopted) for the schedule.
+	// +optional
+	Migration *core.ObjectReference `json:"migration,omitempty"`
+}
+
+// Location of the schedule timezone.
+func (r *Schedule) Location() (location *time.Location, err error) {
+	location, err = time.LoadLocation(r.Timezone)
+	if err != nil {
+		err = liberr.New(fmt.Sprintf("timezone '%s' not valid.", r.Timezone))
+	}
+	return
+}
+
+// Validate the schedule.
+func (r *Schedule) Validate() (err error) {
+	_, err = r.Location()
+	if err != nil {
+		return
+	}
+	if r.Start != nil && r.Cutover != nil && r.Cutover.Before(r.Start) {
+		err = liberr.New("cutover must not be before start.")
+		return
+	}
+	for i := range r.Blackouts {
+		err = r.Blackouts[i].Validate()
+		if err != nil {
+			err = liberr.New(fmt.Sprintf("blackout[%d]: %s", i, err.Error()))
+			return
+		}
+	}
+	// Some time must remain outside of the blackouts.
+	start := time.Now()
+	if r.Start != nil {
+		start = r.Start.Time
+	}
+	_, err = r.NextAllowed(start)
+	return
+}
+
+// NextAllowed returns the earliest time at or after the
+// specified time that is not inside a blackout.
+func (r *Schedule) NextAllowed(t time.Time) (next time.Time, err error) {
+	location, err := r.Location()
+	if err != nil {
+		return
+	}
+	next = t
+	// Adjacent and overlapping blackouts are chained. The
+	// bound protects against a recurring window that never ends.
+	for n := 0; n < 366*len(r.Blackouts)+1; n++ {
+		moved := false
+		for i := range r.Blackouts {
+			end, inside := r.Blackouts[i].Contains(next, location)
+			if inside {
+				next = end
+				moved = true
+			}
+		}
+		if !moved {
+			return
+		}
+	}
+	err = liberr.New("no time outside of blackouts found.")
+	return
+}
+
+// Validate the blackout.
+func (r *Blackout) Validate() (err error) {
+	absolute := r.Start != nil || r.End != nil
+	recurring := r.From != "" || r.To != "" || len(r.Days) > 0
+	switch {
+	case absolute && recurring:
+		err = liberr.New("start/end and days/from/to are mutually exclusive.")
+	case absolute:
+		if r.Start == nil || r.End == nil {
+			err = liberr.New("start and end are required.")
+			return
+		}
+		if !r.End.After(r.Start.Time) {
+			err = liberr.New("end must be after start.")
+		}
+	case recurring:
+		from, pErr := time.Parse(TimeOfDayLayout, r.From)
+		if pErr != nil {
+			err = liberr.New(fmt.Sprintf("from '%s' must be HH:MM.", r.From))
+			return
+		}
+		to, pErr := time.Parse(TimeOfDayLayout, r.To)
+		if pErr != nil {
+			err = liberr.New(fmt.Sprintf("to '%s' must be HH:MM.", r.To))
+			return
+		}
+		if from.Equal(to) {
+			err = liberr.New("from and to must not be equal.")
+			return
+		}
+		for _, day := range r.Days {
+			if _, found := weekday(day); !found {
+				err = liberr.New(fmt.Sprintf("day '%s' not valid.", day))
+				return
+			}
+		}
+	default:
+		err = liberr.New("either start/end or from/to must be specified.")
+	}
+	return
+}
+
+// Contains determines whether the time is inside the blackout.
+// Returns the end of the blackout when inside.
+func (r *Blackout) Contains(t time.Time, location *time.Location) (end time.Time, inside bool) {
+	if r.Start != nil && r.End != nil {
+		if !t.Before(r.Start.Time) && t.Before(r.End.Time) {
+			end = r.End.Time
+			inside = true
+		}
+		return
+	}
+	from, err := time.Parse(TimeOfDayLayout, r.From)
+	if err != nil {
+		return
+	}
+	to, err := time.Parse(TimeOfDayLayout, r.To)
+	if err != nil {
+		return
+	}
+	length := to.Sub(from)
+	if length <= 0 {
+		length += 24 * time.Hour
+	}
+	local := t.In(location)
+	// A window that began on the previous day
+	// may still be open when it spans midnight.
+	for _, offset := range []int{-1, 0} {
+		day := local.AddDate(0, 0, offset)
+		if !r.onDay(day.Weekday()) {
+			continue
+		}
+		begin := time.Date(
+			day.Year(),
+			day.Month(),
+			day.Day(),
+			from.Hour(),
+			from.Minute(),
+			0,
+			0,
+			location)
+		if !local.Before(begin) && local.Before(begin.Add(length)) {
+			end = begin.Add(length)
+			inside = true
+			return
+		}
+	}
+	return
+}
+
+// The recurring window begins on the weekday.
+func (r *Blackout) onDay(d time.Weekday) bool {
+	if len(r.Days) == 0 {
+		return true
+	}
+	for _, day := range r.Days {
+		if wd, found := weekday(day); found && wd == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse a weekday by full or abbreviated name.
+func weekday(name string) (d time.Weekday, found bool) {
+	name = strings.ToLower(name)
+	for d = time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			found = true
+			return
+		}
+	}
+	return
+}
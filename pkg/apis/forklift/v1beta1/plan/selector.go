@@ -0,0 +1,48 @@
+package plan
+
+import (
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VM selector.
+// Selects the VMs in the source inventory. The criteria
+// are combined (all must match). The selected VMs are added
+// to the VMs listed on the plan.
+type VMSelector struct {
+	// Path of the folder containing the VMs (including subfolders).
+	// e.g. "/Datacenter/vm/production". Supported by vSphere.
+	// +optional
+	Folder string `json:"folder,omitempty"`
+	// Name of the cluster running the VMs. Supported by vSphere and oVirt.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+	// Labels (tags) of the VMs in the inventory. Not supported by OpenShift.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Regex matched against the VM name.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Regex matched against the guest OS. Supported by vSphere and oVirt.
+	// +optional
+	GuestOS string `json:"guestOS,omitempty"`
+}
+
+// VMs selected by the plan VM selector.
+type Selection struct {
+	// Selected VMs.
+	// +optional
+	VMs []ref.Ref `json:"vms,omitempty"`
+	// Date and time the selection was frozen by a migration.
+	// The frozen selection is no longer resolved.
+	// +optional
+	Frozen *meta.Time `json:"frozen,omitempty"`
+}
+
+// Freeze the selection.
+func (r *Selection) Freeze() {
+	if r.Frozen == nil {
+		now := meta.Now()
+		r.Frozen = &now
+	}
+}
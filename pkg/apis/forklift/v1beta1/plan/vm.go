@@ -16,6 +16,24 @@ type HookRef struct {
 	Step string `json:"step"`
 	// Hook reference.
 	Hook core.ObjectReference `json:"hook" ref:"Hook"`
+	// Maximum number of times the hook is retried after it has failed.
+	// Default: the HOOK_RETRY setting.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Retries *int `json:"retries,omitempty"`
+	// Delay (seconds) before the hook is retried.
+	// Default: 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Backoff *int `json:"backoff,omitempty"`
+	// Timeout (seconds) of each attempt.
+	// Overrides the deadline of the hook.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Timeout int64 `json:"timeout,omitempty"`
+	// Report the failed hook as a warning and continue the migration.
+	// +optional
+	ContinueOnFailure bool `json:"continueOnFailure,omitempty"`
 }
 
 // TargetPowerState defines the desired power state of the target VM after migration
@@ -28,6 +46,15 @@ const (
 	TargetPowerStateAuto TargetPowerState = "auto"
 )
 
+// RollbackPolicy defines what happens to a VM when its migration fails.
+type RollbackPolicy string
+
+const (
+	// Rollback policy constants
+	RollbackNone      RollbackPolicy = "None"
+	RollbackAutomatic RollbackPolicy = "Automatic"
+)
+
 func (r *HookRef) String() string {
 	return fmt.Sprintf(
 		"%s @%s",
@@ -145,6 +172,18 @@ type VM struct {
 	//
 	// +optional
 	DeleteVmOnFailMigration bool `json:"deleteVmOnFailMigration,omitempty"`
+	// Wave is the migration wave of the VM. The migration of VMs in a wave
+	// is not started until the migration of all VMs in lower waves has completed.
+	// Default: 0 (first wave).
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Wave int `json:"wave,omitempty"`
+	// DependsOn lists the VMs (in the plan) that must be migrated successfully
+	// before the migration of this VM is started. The VMs must be in the same
+	// or in a lower wave. When one of them fails or is canceled, the migration
+	// of this VM is canceled.
+	// +optional
+	DependsOn []ref.Ref `json:"dependsOn,omitempty"`
 }
 
 // Find a Hook for the specified step.
@@ -180,6 +219,10 @@ type VMStatus struct {
 	OperatingSystem string `json:"operatingSystem,omitempty"`
 	// The new name of the VM after matching DNS1123 requirements.
 	NewName string `json:"newName,omitempty"`
+	// The target namespace assigned by the placement policy.
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+	// Resources held by the migration.
+	Footprint *Footprint `json:"footprint,omitempty"`
 
 	// Conditions.
 	libcnd.Conditions `json:",inline"`
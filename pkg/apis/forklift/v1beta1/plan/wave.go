@@ -0,0 +1,131 @@
+package plan
+
+import (
+	"sort"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+)
+
+// VM conditions.
+const (
+	succeeded = "Succeeded"
+	canceled  = "Canceled"
+)
+
+// Migration wave.
+type Wave struct {
+	Timed `json:",inline"`
+	// Wave number.
+	Wave int `json:"wave"`
+	// VMs in the wave.
+	VMs []ref.Ref `json:"vms"`
+}
+
+// Determine whether the VM is referenced by the dependency.
+// Matched by ID when specified, otherwise by name.
+func (r *VM) Matches(dependency ref.Ref) bool {
+	if dependency.ID != "" {
+		return dependency.ID == r.ID
+	}
+	return dependency.Name != "" && dependency.Name == r.Name
+}
+
+// Find the VM referenced by a dependency.
+func (r *MigrationStatus) FindDependency(dependency ref.Ref) (vm *VMStatus, found bool) {
+	for _, v := range r.VMs {
+		if v.Matches(dependency) {
+			vm = v
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// Released determines whether the migration of the VM may be started.
+// The migration of all VMs in lower waves must have completed and
+// the migration of all VMs it depends on must have succeeded.
+func (r *MigrationStatus) Released(vm *VMStatus) bool {
+	for _, v := range r.VMs {
+		if v.Wave < vm.Wave && !v.MarkedCompleted() {
+			return false
+		}
+	}
+	for _, dependency := range vm.DependsOn {
+		v, found := r.FindDependency(dependency)
+		if !found {
+			continue
+		}
+		if !v.MarkedCompleted() || !v.HasCondition(succeeded) {
+			return false
+		}
+	}
+	return true
+}
+
+// BlockedBy returns the dependency that can no longer
+// succeed and so prevents the migration of the VM.
+func (r *MigrationStatus) BlockedBy(vm *VMStatus) (dependency *VMStatus, blocked bool) {
+	for _, d := range vm.DependsOn {
+		v, found := r.FindDependency(d)
+		if !found {
+			continue
+		}
+		if v.HasCondition(canceled) || (v.MarkedCompleted() && !v.HasCondition(succeeded)) {
+			dependency = v
+			blocked = true
+			return
+		}
+	}
+	return
+}
+
+// Reflect the wave layout.
+// Reported only when waves or dependencies are used.
+func (r *MigrationStatus) ReflectWaves() {
+	r.Waves = nil
+	ordered := false
+	for _, vm := range r.VMs {
+		if vm.Wave > 0 || len(vm.DependsOn) > 0 {
+			ordered = true
+			break
+		}
+	}
+	if !ordered {
+		return
+	}
+	waves := make(map[int]*Wave)
+	for _, vm := range r.VMs {
+		wave, found := waves[vm.Wave]
+		if !found {
+			wave = &Wave{Wave: vm.Wave}
+			waves[vm.Wave] = wave
+		}
+		wave.VMs = append(wave.VMs, vm.Ref)
+	}
+	for _, wave := range waves {
+		completed := true
+		for _, vm := range r.VMs {
+			if vm.Wave != wave.Wave {
+				continue
+			}
+			if vm.Started != nil && (wave.Started == nil || vm.Started.Before(wave.Started)) {
+				wave.Started = vm.Started.DeepCopy()
+			}
+			if vm.Completed == nil {
+				completed = false
+				continue
+			}
+			if wave.Completed == nil || wave.Completed.Before(vm.Completed) {
+				wave.Completed = vm.Completed.DeepCopy()
+			}
+		}
+		if !completed {
+			wave.Completed = nil
+		}
+		r.Waves = append(r.Waves, *wave)
+	}
+	sort.Slice(r.Waves, func(i, j int) bool {
+		return r.Waves[i].Wave < r.Waves[j].Wave
+	})
+}
@@ -20,7 +20,38 @@ limitations under the License.
 
 package plan
 
-import ()
+import (
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"k8s.io/api/core/v1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Blackout) DeepCopyInto(out *Blackout) {
+	*out = *in
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Blackout.
+func (in *Blackout) DeepCopy() *Blackout {
+	if in == nil {
+		return nil
+	}
+	out := new(Blackout)
+	in.DeepCopyInto(out)
+	return out
+}
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiskDelta) DeepCopyInto(out *DiskDelta) {
@@ -37,6 +68,22 @@ func (in *DiskDelta) DeepCopy() *DiskDelta {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskFootprint) DeepCopyInto(out *DiskFootprint) {
+	*out = *in
+	out.Storage = in.Storage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskFootprint.
+func (in *DiskFootprint) DeepCopy() *DiskFootprint {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskFootprint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Error) DeepCopyInto(out *Error) {
 	*out = *in
@@ -57,10 +104,96 @@ func (in *Error) DeepCopy() *Error {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Footprint) DeepCopyInto(out *Footprint) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskFootprint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Footprint.
+func (in *Footprint) DeepCopy() *Footprint {
+	if in == nil {
+		return nil
+	}
+	out := new(Footprint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestScripts) DeepCopyInto(out *GuestScripts) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestScripts.
+func (in *GuestScripts) DeepCopy() *GuestScripts {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestScripts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *in
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]HealthProbe, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hook != nil {
+		in, out := &in.Hook, &out.Hook
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
+func (in *HealthCheck) DeepCopy() *HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthProbe) DeepCopyInto(out *HealthProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthProbe.
+func (in *HealthProbe) DeepCopy() *HealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HookRef) DeepCopyInto(out *HookRef) {
 	*out = *in
 	out.Hook = in.Hook
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookRef.
@@ -73,6 +206,26 @@ func (in *HookRef) DeepCopy() *HookRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookRun) DeepCopyInto(out *HookRun) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookRun.
+func (in *HookRun) DeepCopy() *HookRun {
+	if in == nil {
+		return nil
+	}
+	out := new(HookRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Map) DeepCopyInto(out *Map) {
 	*out = *in
@@ -90,6 +243,21 @@ func (in *Map) DeepCopy() *Map {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataMapping) DeepCopyInto(out *MetadataMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataMapping.
+func (in *MetadataMapping) DeepCopy() *MetadataMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
 	*out = *in
@@ -112,6 +280,13 @@ func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
 			}
 		}
 	}
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]Wave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
@@ -124,6 +299,50 @@ func (in *MigrationStatus) DeepCopy() *MigrationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Placement) DeepCopyInto(out *Placement) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]PlacementRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Placement.
+func (in *Placement) DeepCopy() *Placement {
+	if in == nil {
+		return nil
+	}
+	out := new(Placement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementRule) DeepCopyInto(out *PlacementRule) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementRule.
+func (in *PlacementRule) DeepCopy() *PlacementRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Precopy) DeepCopyInto(out *Precopy) {
 	*out = *in
@@ -152,6 +371,103 @@ func (in *Precopy) DeepCopy() *Precopy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessSummary) DeepCopyInto(out *ReadinessSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessSummary.
+func (in *ReadinessSummary) DeepCopy() *ReadinessSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Schedule) DeepCopyInto(out *Schedule) {
+	*out = *in
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.Cutover != nil {
+		in, out := &in.Cutover, &out.Cutover
+		*out = (*in).DeepCopy()
+	}
+	if in.Blackouts != nil {
+		in, out := &in.Blackouts, &out.Blackouts
+		*out = make([]Blackout, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Schedule.
+func (in *Schedule) DeepCopy() *Schedule {
+	if in == nil {
+		return nil
+	}
+	out := new(Schedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleStatus) DeepCopyInto(out *ScheduleStatus) {
+	*out = *in
+	if in.NextActionTime != nil {
+		in, out := &in.NextActionTime, &out.NextActionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Started != nil {
+		in, out := &in.Started, &out.Started
+		*out = (*in).DeepCopy()
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleStatus.
+func (in *ScheduleStatus) DeepCopy() *ScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Selection) DeepCopyInto(out *Selection) {
+	*out = *in
+	if in.VMs != nil {
+		in, out := &in.VMs, &out.VMs
+		*out = make([]ref.Ref, len(*in))
+		copy(*out, *in)
+	}
+	if in.Frozen != nil {
+		in, out := &in.Frozen, &out.Frozen
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Selection.
+func (in *Selection) DeepCopy() *Selection {
+	if in == nil {
+		return nil
+	}
+	out := new(Selection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Snapshot) DeepCopyInto(out *Snapshot) {
 	*out = *in
@@ -260,6 +576,18 @@ func (in *Task) DeepCopyInto(out *Task) {
 			(*out)[key] = val
 		}
 	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hook != nil {
+		in, out := &in.Hook, &out.Hook
+		*out = new(HookRun)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Error != nil {
 		in, out := &in.Error, &out.Error
 		*out = new(Error)
@@ -307,9 +635,16 @@ func (in *VM) DeepCopyInto(out *VM) {
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
 		*out = make([]HookRef, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	out.LUKS = in.LUKS
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]ref.Ref, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VM.
@@ -322,6 +657,51 @@ func (in *VM) DeepCopy() *VM {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMPlacement) DeepCopyInto(out *VMPlacement) {
+	*out = *in
+	out.Ref = in.Ref
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMPlacement.
+func (in *VMPlacement) DeepCopy() *VMPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(VMPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSelector) DeepCopyInto(out *VMSelector) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSelector.
+func (in *VMSelector) DeepCopy() *VMSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMStatus) DeepCopyInto(out *VMStatus) {
 	*out = *in
@@ -348,6 +728,11 @@ func (in *VMStatus) DeepCopyInto(out *VMStatus) {
 		*out = new(Warm)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Footprint != nil {
+		in, out := &in.Footprint, &out.Footprint
+		*out = new(Footprint)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Conditions.DeepCopyInto(&out.Conditions)
 }
 
@@ -386,3 +771,24 @@ func (in *Warm) DeepCopy() *Warm {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Wave) DeepCopyInto(out *Wave) {
+	*out = *in
+	in.Timed.DeepCopyInto(&out.Timed)
+	if in.VMs != nil {
+		in, out := &in.VMs, &out.VMs
+		*out = make([]ref.Ref, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Wave.
+func (in *Wave) DeepCopy() *Wave {
+	if in == nil {
+		return nil
+	}
+	out := new(Wave)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -20,6 +20,7 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -78,6 +79,7 @@ const (
 	ESXiCloneMethod        = "esxiCloneMethod"
 	TargetAZ               = "target-az"
 	TargetRegion           = "target-region"
+	TransferRateLimit      = "transferRateLimit"
 )
 
 // ESXi clone method values.
@@ -101,6 +103,10 @@ type ProviderSpec struct {
 	Secret core.ObjectReference `json:"secret" ref:"Secret"`
 	// Provider settings.
 	Settings map[string]string `json:"settings,omitempty"`
+	// Migration concurrency limits enforced across all plans
+	// using the provider. When not set, MAX_VM_INFLIGHT applies.
+	// +optional
+	Limits *provider.Limits `json:"limits,omitempty"`
 }
 
 // ProviderStatus defines the observed state of Provider
@@ -200,3 +206,17 @@ func (p *Provider) UseVddkAioOptimization() bool {
 	}
 	return parseBool
 }
+
+// Aggregate transfer rate limit, in MB/s, of the VMs
+// migrated from this provider. Zero is unlimited.
+func (p *Provider) TransferRateLimit() (limit int) {
+	setting, found := p.Spec.Settings[TransferRateLimit]
+	if !found {
+		return
+	}
+	limit, err := strconv.Atoi(setting)
+	if err != nil || limit < 0 {
+		limit = 0
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Concurrency limit.
+// Zero (unset) values are not enforced.
+type Limit struct {
+	// Maximum number of VMs migrated at once.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxVMs int `json:"maxVMs,omitempty"`
+	// Maximum number of disks transferred at once.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxDisks int `json:"maxDisks,omitempty"`
+	// Maximum capacity of the disks transferred at once.
+	// +optional
+	MaxBytes *resource.Quantity `json:"maxBytes,omitempty"`
+}
+
+// Exceeded determines whether the usage exceeds the limit.
+func (r *Limit) Exceeded(vms, disks int, bytes int64) bool {
+	if r.MaxVMs > 0 && vms > r.MaxVMs {
+		return true
+	}
+	if r.MaxDisks > 0 && disks > r.MaxDisks {
+		return true
+	}
+	if r.MaxBytes != nil && !r.MaxBytes.IsZero() && bytes > r.MaxBytes.Value() {
+		return true
+	}
+	return false
+}
+
+// Limit on a source storage.
+// (datastore, storage domain, volume type).
+type StorageLimit struct {
+	// Source storage.
+	ref.Ref `json:",inline"`
+	// Limit.
+	Limit `json:",inline"`
+}
+
+// Limit on a destination storage class.
+type StorageClassLimit struct {
+	// Storage class name.
+	Name string `json:"name"`
+	// Limit.
+	Limit `json:",inline"`
+}
+
+// Migration concurrency limits.
+// Enforced across all plans using the provider.
+type Limits struct {
+	// Overrides MAX_VM_INFLIGHT for the provider. This is the
+	// number of disks per ESXi host for vSphere and the number
+	// of VMs for other providers.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+	// Limit on the provider as a whole.
+	Limit `json:",inline"`
+	// Limits per source storage.
+	// Applies when this is the source provider.
+	// +optional
+	Storage []StorageLimit `json:"storage,omitempty"`
+	// Limits per destination storage class.
+	// Applies when this is the destination provider.
+	// +optional
+	StorageClasses []StorageClassLimit `json:"storageClasses,omitempty"`
+}
@@ -22,6 +22,56 @@ package provider
 
 import ()
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Limit) DeepCopyInto(out *Limit) {
+	*out = *in
+	if in.MaxBytes != nil {
+		in, out := &in.MaxBytes, &out.MaxBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Limit.
+func (in *Limit) DeepCopy() *Limit {
+	if in == nil {
+		return nil
+	}
+	out := new(Limit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Limits) DeepCopyInto(out *Limits) {
+	*out = *in
+	in.Limit.DeepCopyInto(&out.Limit)
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = make([]StorageLimit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]StorageClassLimit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Limits.
+func (in *Limits) DeepCopy() *Limits {
+	if in == nil {
+		return nil
+	}
+	out := new(Limits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pair) DeepCopyInto(out *Pair) {
 	*out = *in
@@ -38,3 +88,36 @@ func (in *Pair) DeepCopy() *Pair {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageClassLimit) DeepCopyInto(out *StorageClassLimit) {
+	*out = *in
+	in.Limit.DeepCopyInto(&out.Limit)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClassLimit.
+func (in *StorageClassLimit) DeepCopy() *StorageClassLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClassLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageLimit) DeepCopyInto(out *StorageLimit) {
+	*out = *in
+	out.Ref = in.Ref
+	in.Limit.DeepCopyInto(&out.Limit)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageLimit.
+func (in *StorageLimit) DeepCopy() *StorageLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageLimit)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -22,6 +22,7 @@ package v1beta1
 
 import (
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -526,6 +527,29 @@ func (in *NetworkMapList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkMapRule) DeepCopyInto(out *NetworkMapRule) {
+	*out = *in
+	out.Destination = in.Destination
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkMapRule.
+func (in *NetworkMapRule) DeepCopy() *NetworkMapRule {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkMapRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkMapSpec) DeepCopyInto(out *NetworkMapSpec) {
 	*out = *in
@@ -535,6 +559,13 @@ func (in *NetworkMapSpec) DeepCopyInto(out *NetworkMapSpec) {
 		*out = make([]NetworkPair, len(*in))
 		copy(*out, *in)
 	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]NetworkMapRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkMapSpec.
@@ -547,6 +578,27 @@ func (in *NetworkMapSpec) DeepCopy() *NetworkMapSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkMapStatus) DeepCopyInto(out *NetworkMapStatus) {
+	*out = *in
+	in.MapStatus.DeepCopyInto(&out.MapStatus)
+	if in.Generated != nil {
+		in, out := &in.Generated, &out.Generated
+		*out = make([]NetworkPair, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkMapStatus.
+func (in *NetworkMapStatus) DeepCopy() *NetworkMapStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkMapStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkNameTemplateData) DeepCopyInto(out *NetworkNameTemplateData) {
 	*out = *in
@@ -1008,11 +1060,31 @@ func (in *PlanSpec) DeepCopyInto(out *PlanSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VMSelector != nil {
+		in, out := &in.VMSelector, &out.VMSelector
+		*out = new(plan.VMSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetadataMapping != nil {
+		in, out := &in.MetadataMapping, &out.MetadataMapping
+		*out = make([]plan.MetadataMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(plan.Placement)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TransferNetwork != nil {
 		in, out := &in.TransferNetwork, &out.TransferNetwork
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(plan.HealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstallLegacyDrivers != nil {
 		in, out := &in.InstallLegacyDrivers, &out.InstallLegacyDrivers
 		*out = new(bool)
@@ -1023,6 +1095,16 @@ func (in *PlanSpec) DeepCopyInto(out *PlanSpec) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.GuestScripts != nil {
+		in, out := &in.GuestScripts, &out.GuestScripts
+		*out = new(plan.GuestScripts)
+		**out = **in
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(plan.Schedule)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanSpec.
@@ -1040,6 +1122,28 @@ func (in *PlanStatus) DeepCopyInto(out *PlanStatus) {
 	*out = *in
 	in.Conditions.DeepCopyInto(&out.Conditions)
 	in.Migration.DeepCopyInto(&out.Migration)
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(plan.ScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(plan.ReadinessSummary)
+		**out = **in
+	}
+	if in.Selection != nil {
+		in, out := &in.Selection, &out.Selection
+		*out = new(plan.Selection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = make([]plan.VMPlacement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanStatus.
@@ -1127,6 +1231,11 @@ func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(provider.Limits)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSpec.
@@ -1220,6 +1329,29 @@ func (in *StorageMapList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageMapRule) DeepCopyInto(out *StorageMapRule) {
+	*out = *in
+	out.Destination = in.Destination
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMapRule.
+func (in *StorageMapRule) DeepCopy() *StorageMapRule {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageMapRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageMapSpec) DeepCopyInto(out *StorageMapSpec) {
 	*out = *in
@@ -1231,6 +1363,13 @@ func (in *StorageMapSpec) DeepCopyInto(out *StorageMapSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]StorageMapRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMapSpec.
@@ -1243,6 +1382,29 @@ func (in *StorageMapSpec) DeepCopy() *StorageMapSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageMapStatus) DeepCopyInto(out *StorageMapStatus) {
+	*out = *in
+	in.MapStatus.DeepCopyInto(&out.MapStatus)
+	if in.Generated != nil {
+		in, out := &in.Generated, &out.Generated
+		*out = make([]StoragePair, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMapStatus.
+func (in *StorageMapStatus) DeepCopy() *StorageMapStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageMapStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoragePair) DeepCopyInto(out *StoragePair) {
 	*out = *in
@@ -0,0 +1,185 @@
+package network
+
+import (
+	"errors"
+	"reflect"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/map/rule"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+)
+
+// Types
+const (
+	RuleNotValid                 = "RuleNotValid"
+	DestinationNetworkNotMatched = "DestinationNetworkNotMatched"
+)
+
+// Reasons
+const (
+	NotValid     = "NotValid"
+	NotSupported = "NotSupported"
+)
+
+// Generate the map pairs using the rules.
+// The generated pairs are reported in the status and
+// are never written to the map.
+func (r *Reconciler) generate(mp *api.NetworkMap) (err error) {
+	if len(mp.Spec.Rules) == 0 {
+		mp.Status.Generated = nil
+		return
+	}
+	sources, err := rule.Networks(mp.Referenced.Provider.Source)
+	if err != nil {
+		if errors.As(err, &rule.NotSupportedError{}) {
+			mp.Status.SetCondition(libcnd.Condition{
+				Type:     RuleNotValid,
+				Status:   True,
+				Reason:   NotSupported,
+				Category: Critical,
+				Message:  "Rules not supported by the source provider.",
+			})
+			mp.Status.Generated = nil
+			err = nil
+		}
+		return
+	}
+	nads := []ocp.NetworkAttachmentDefinition{}
+	for _, mr := range mp.Spec.Rules {
+		if len(mr.Selector) > 0 {
+			nads, err = rule.NetworkAttachmentDefinitions(mp.Referenced.Provider.Destination)
+			if err != nil {
+				return
+			}
+			break
+		}
+	}
+	generator := Generator{
+		Rules:   mp.Spec.Rules,
+		Sources: sources,
+		NADs:    nads,
+	}
+	generated, err := generator.Generate(mp.Spec.Map)
+	if err != nil {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     RuleNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: Critical,
+			Message:  "Rule source pattern not valid: " + err.Error(),
+		})
+		err = nil
+		return
+	}
+	if len(generator.NotMatched) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     DestinationNetworkNotMatched,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Warn,
+			Message:  "No destination network (NAD) matched the rule selector.",
+			Items:    generator.NotMatched,
+		})
+	}
+	if !reflect.DeepEqual(generated, mp.Status.Generated) {
+		r.Log.Info(
+			"Network map generated.",
+			"pairs",
+			len(generated))
+	}
+	mp.Status.Generated = generated
+
+	return
+}
+
+// Network map generator.
+type Generator struct {
+	// Rules.
+	Rules []api.NetworkMapRule
+	// Source networks.
+	Sources []rule.Source
+	// Destination networks (NADs) matched by the selectors.
+	NADs []ocp.NetworkAttachmentDefinition
+	// Sources without destination matched by the selector.
+	NotMatched []string
+}
+
+// Generate the pairs.
+// Sources mapped explicitly are skipped.
+func (r *Generator) Generate(explicit []api.NetworkPair) (pairs []api.NetworkPair, err error) {
+	patterns := []*rule.Pattern{}
+	for _, mr := range r.Rules {
+		var p *rule.Pattern
+		p, err = rule.Compile(mr.Source)
+		if err != nil {
+			return
+		}
+		patterns = append(patterns, p)
+	}
+	r.NotMatched = nil
+next:
+	for _, source := range r.Sources {
+		for _, pair := range explicit {
+			if matches(pair.Source, source.Ref) {
+				continue next
+			}
+		}
+		for i, mr := range r.Rules {
+			p := patterns[i]
+			if !p.MatchString(source.Name) {
+				continue
+			}
+			destination, found := r.destination(p, &mr, source.Name)
+			if !found {
+				r.NotMatched = append(r.NotMatched, source.Name)
+				continue next
+			}
+			pairs = append(
+				pairs,
+				api.NetworkPair{
+					Source:      source.Ref,
+					Destination: destination,
+				})
+			continue next
+		}
+	}
+	return
+}
+
+// Build the destination.
+// The NAD is selected by labels when the rule has a selector.
+func (r *Generator) destination(p *rule.Pattern, mr *api.NetworkMapRule, name string) (destination api.DestinationNetwork, found bool) {
+	destination = mr.Destination
+	destination.Namespace = p.Expand(name, destination.Namespace)
+	destination.Name = p.Expand(name, destination.Name)
+	if len(mr.Selector) == 0 || destination.Type != Multus {
+		found = true
+		return
+	}
+	selector := p.ExpandSelector(name, mr.Selector)
+	for _, nad := range r.NADs {
+		if destination.Namespace != "" && nad.Namespace != destination.Namespace {
+			continue
+		}
+		if rule.Selected(nad.Object.Labels, selector) {
+			destination.Namespace = nad.Namespace
+			destination.Name = nad.Name
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// The pair source matches the inventory ref.
+func matches(source, ref refapi.Ref) bool {
+	if source.ID != "" {
+		return source.ID == ref.ID
+	}
+	if source.Namespace != "" && source.Namespace != ref.Namespace {
+		return false
+	}
+	return source.Name != "" && source.Name == ref.Name
+}
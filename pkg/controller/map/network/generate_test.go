@@ -0,0 +1,125 @@
+package network
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/map/rule"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NetworkMap Generator", func() {
+	sources := []rule.Source{
+		{Ref: ref.Ref{ID: "net-1", Name: "VLAN-100"}, Name: "VLAN-100"},
+		{Ref: ref.Ref{ID: "net-2", Name: "VLAN-200"}, Name: "VLAN-200"},
+		{Ref: ref.Ref{ID: "net-3", Name: "mgmt"}, Name: "mgmt"},
+		{Ref: ref.Ref{ID: "net-4", Name: "storage"}, Name: "storage"},
+	}
+	nad := func(ns, name string, labels map[string]string) ocp.NetworkAttachmentDefinition {
+		m := ocp.NetworkAttachmentDefinition{}
+		m.Namespace = ns
+		m.Name = name
+		m.Object.Labels = labels
+		return m
+	}
+
+	It("should generate pairs using the pattern groups", func() {
+		generator := Generator{
+			Rules: []api.NetworkMapRule{
+				{
+					Source: `VLAN-(\d+)`,
+					Destination: api.DestinationNetwork{
+						Type:      Multus,
+						Namespace: "net",
+						Name:      "vlan-$1",
+					},
+				},
+				{
+					Source:      "mgmt",
+					Destination: api.DestinationNetwork{Type: Pod},
+				},
+			},
+			Sources: sources,
+		}
+		explicit := api.NetworkPair{
+			Source:      ref.Ref{Name: "VLAN-200"},
+			Destination: api.DestinationNetwork{Type: Ignored},
+		}
+		pairs, err := generator.Generate([]api.NetworkPair{explicit})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pairs).To(Equal([]api.NetworkPair{
+			{
+				Source:      ref.Ref{ID: "net-1", Name: "VLAN-100"},
+				Destination: api.DestinationNetwork{Type: Multus, Namespace: "net", Name: "vlan-100"},
+			},
+			{
+				Source:      ref.Ref{ID: "net-3", Name: "mgmt"},
+				Destination: api.DestinationNetwork{Type: Pod},
+			},
+		}))
+
+		// Regenerated from the rules.
+		generator.Rules = generator.Rules[1:]
+		pairs, err = generator.Generate([]api.NetworkPair{explicit})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pairs).To(Equal([]api.NetworkPair{
+			{
+				Source:      ref.Ref{ID: "net-3", Name: "mgmt"},
+				Destination: api.DestinationNetwork{Type: Pod},
+			},
+		}))
+	})
+
+	It("should select the NAD by labels", func() {
+		generator := Generator{
+			Rules: []api.NetworkMapRule{
+				{
+					Source:      `VLAN-(?P<vlan>\d+)`,
+					Destination: api.DestinationNetwork{Type: Multus},
+					Selector:    map[string]string{"vlan": "${vlan}"},
+				},
+			},
+			Sources: sources,
+			NADs: []ocp.NetworkAttachmentDefinition{
+				nad("a", "br-100", map[string]string{"vlan": "100"}),
+				nad("b", "br-100", map[string]string{"vlan": "100"}),
+			},
+		}
+		pairs, err := generator.Generate(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pairs).To(HaveLen(1))
+		Expect(pairs[0].Destination).To(Equal(api.DestinationNetwork{Type: Multus, Namespace: "a", Name: "br-100"}))
+		Expect(generator.NotMatched).To(Equal([]string{"VLAN-200"}))
+	})
+
+	It("should report a pattern not valid", func() {
+		generator := Generator{
+			Rules:   []api.NetworkMapRule{{Source: "(", Destination: api.DestinationNetwork{Type: Pod}}},
+			Sources: sources,
+		}
+		_, err := generator.Generate(nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not generate pairs without matches", func() {
+		generator := Generator{Sources: sources}
+		pairs, err := generator.Generate(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pairs).To(BeEmpty())
+	})
+
+	It("should use the generated pairs with the pairs of the map", func() {
+		mp := &api.NetworkMap{}
+		explicit := api.NetworkPair{Source: ref.Ref{ID: "net-2"}, Destination: api.DestinationNetwork{Type: Ignored}}
+		generated := api.NetworkPair{Source: ref.Ref{ID: "net-1"}, Destination: api.DestinationNetwork{Type: Pod}}
+		mp.Spec.Map = []api.NetworkPair{explicit}
+		mp.Status.Generated = []api.NetworkPair{generated}
+		Expect(mp.Pairs()).To(Equal([]api.NetworkPair{explicit, generated}))
+		Expect(mp.Spec.Map).To(HaveLen(1))
+		mp.Resolve()
+		pair, found := mp.FindNetwork("net-1")
+		Expect(found).To(BeTrue())
+		Expect(pair).To(Equal(generated))
+	})
+})
@@ -89,8 +89,8 @@ func (r *Handler) changed(models ...*openstack.Network) {
 		if !r.MatchProvider(ref) {
 			continue
 		}
-		referenced := false
-		for _, pair := range mp.Spec.Map {
+		referenced := len(mp.Spec.Rules) > 0
+		for _, pair := range mp.Pairs() {
 			ref := pair.Source
 			for _, network := range models {
 				if ref.ID == network.ID || strings.HasSuffix(network.Path, ref.Name) {
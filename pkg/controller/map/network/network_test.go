@@ -227,8 +227,10 @@ var _ = Describe("MapPredicate", func() {
 					Namespace:  "default",
 					Generation: 1,
 				},
-				Status: api.MapStatus{
-					ObservedGeneration: 1,
+				Status: api.NetworkMapStatus{
+					MapStatus: api.MapStatus{
+						ObservedGeneration: 1,
+					},
 				},
 			}
 			newNm := &api.NetworkMap{
@@ -237,8 +239,10 @@ var _ = Describe("MapPredicate", func() {
 					Namespace:  "default",
 					Generation: 2,
 				},
-				Status: api.MapStatus{
-					ObservedGeneration: 1,
+				Status: api.NetworkMapStatus{
+					MapStatus: api.MapStatus{
+						ObservedGeneration: 1,
+					},
 				},
 			}
 			result := predicate.Update(event.TypedUpdateEvent[*api.NetworkMap]{
@@ -255,8 +259,10 @@ var _ = Describe("MapPredicate", func() {
 					Namespace:  "default",
 					Generation: 1,
 				},
-				Status: api.MapStatus{
-					ObservedGeneration: 1,
+				Status: api.NetworkMapStatus{
+					MapStatus: api.MapStatus{
+						ObservedGeneration: 1,
+					},
 				},
 			}
 			newNm := &api.NetworkMap{
@@ -265,8 +271,10 @@ var _ = Describe("MapPredicate", func() {
 					Namespace:  "default",
 					Generation: 1,
 				},
-				Status: api.MapStatus{
-					ObservedGeneration: 1,
+				Status: api.NetworkMapStatus{
+					MapStatus: api.MapStatus{
+						ObservedGeneration: 1,
+					},
 				},
 			}
 			result := predicate.Update(event.TypedUpdateEvent[*api.NetworkMap]{
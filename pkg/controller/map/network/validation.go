@@ -64,6 +64,10 @@ func (r *Reconciler) validate(mp *api.NetworkMap) error {
 	mp.Referenced.Provider.Source = pv.Referenced.Source
 	mp.Referenced.Provider.Destination = pv.Referenced.Destination
 
+	err = r.generate(mp)
+	if err != nil {
+		return err
+	}
 	err = r.validateSource(mp)
 	if err != nil {
 		return err
@@ -86,7 +90,7 @@ func (r *Reconciler) validateSource(mp *api.NetworkMap) (err error) {
 	notValid := []string{}
 	ambiguous := []string{}
 	references := refapi.Refs{}
-	list := mp.Spec.Map
+	list := mp.Pairs()
 	for i := range list {
 		ref := &list[i].Source
 		if ref.NotSet() {
@@ -146,7 +150,7 @@ func (r *Reconciler) validateDestination(mp *api.NetworkMap) (err error) {
 	if err != nil {
 		return
 	}
-	list := mp.Spec.Map
+	list := mp.Pairs()
 	notFound := []string{}
 	ambiguous := []string{}
 next:
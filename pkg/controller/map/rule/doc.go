@@ -0,0 +1,70 @@
+// Package rule evaluates the rules of the network and
+// storage maps against the provider inventory.
+package rule
+
+import (
+	"fmt"
+	"regexp"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Compiled source pattern.
+// The pattern must match the whole name.
+type Pattern struct {
+	*regexp.Regexp
+}
+
+// Compile the source pattern.
+func Compile(pattern string) (p *Pattern, err error) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		err = liberr.Wrap(err, "pattern", pattern)
+		return
+	}
+	p = &Pattern{Regexp: re}
+	return
+}
+
+// Expand the template with the groups matched in the name.
+// Returns the template unchanged when the name does not match.
+func (r *Pattern) Expand(name, template string) (expanded string) {
+	match := r.FindStringSubmatchIndex(name)
+	if match == nil {
+		expanded = template
+		return
+	}
+	expanded = string(r.ExpandString(nil, template, name, match))
+	return
+}
+
+// Expand the selector values with the groups matched in the name.
+func (r *Pattern) ExpandSelector(name string, selector map[string]string) (expanded map[string]string) {
+	expanded = make(map[string]string, len(selector))
+	for k, v := range selector {
+		expanded[k] = r.Expand(name, v)
+	}
+	return
+}
+
+// Labels matched by the selector.
+func Selected(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Provider not supported by the rules.
+type NotSupportedError struct {
+	Provider *api.Provider
+}
+
+func (r NotSupportedError) Error() string {
+	return fmt.Sprintf(
+		"Provider type: %s not supported by the map rules.",
+		r.Provider.Type())
+}
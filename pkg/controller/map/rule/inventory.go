@@ -0,0 +1,197 @@
+package rule
+
+import (
+	"sort"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/hyperv"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Source (inventory) object matched by the rules.
+type Source struct {
+	// Reference used in the generated pair.
+	Ref ref.Ref
+	// Name matched by the source pattern.
+	Name string
+}
+
+// List the source networks of the provider.
+// Sorted by name.
+func Networks(provider *api.Provider) (list []Source, err error) {
+	inventory, err := web.NewClient(provider)
+	if err != nil {
+		return
+	}
+	switch provider.Type() {
+	case api.VSphere:
+		networks := []vsphere.Network{}
+		err = inventory.List(&networks)
+		for _, m := range networks {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.OVirt:
+		networks := []ovirt.Network{}
+		err = inventory.List(&networks)
+		for _, m := range networks {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.OpenStack:
+		networks := []openstack.Network{}
+		err = inventory.List(&networks)
+		for _, m := range networks {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.Ova:
+		networks := []ova.Network{}
+		err = inventory.List(&networks)
+		for _, m := range networks {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.HyperV:
+		networks := []hyperv.Network{}
+		err = inventory.List(&networks)
+		for _, m := range networks {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.OpenShift:
+		networks := []ocp.NetworkAttachmentDefinition{}
+		err = inventory.List(&networks)
+		for _, m := range networks {
+			list = append(
+				list,
+				Source{
+					Ref:  ref.Ref{Namespace: m.Namespace, Name: m.Name},
+					Name: m.Namespace + "/" + m.Name,
+				})
+		}
+	default:
+		err = liberr.Wrap(NotSupportedError{Provider: provider})
+	}
+	if err != nil {
+		return
+	}
+	sortSources(list)
+	return
+}
+
+// List the source storage of the provider.
+// Sorted by name.
+func Storage(provider *api.Provider) (list []Source, err error) {
+	inventory, err := web.NewClient(provider)
+	if err != nil {
+		return
+	}
+	switch provider.Type() {
+	case api.VSphere:
+		datastores := []vsphere.Datastore{}
+		err = inventory.List(&datastores)
+		for _, m := range datastores {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.OVirt:
+		domains := []ovirt.StorageDomain{}
+		err = inventory.List(&domains)
+		for _, m := range domains {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.OpenStack:
+		volumeTypes := []openstack.VolumeType{}
+		err = inventory.List(&volumeTypes)
+		for _, m := range volumeTypes {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.Ova:
+		storage := []ova.Storage{}
+		err = inventory.List(&storage)
+		for _, m := range storage {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.HyperV:
+		storage := []hyperv.Storage{}
+		err = inventory.List(&storage)
+		for _, m := range storage {
+			list = append(list, Source{Ref: ref.Ref{ID: m.ID, Name: m.Name}, Name: m.Name})
+		}
+	case api.OpenShift:
+		classes := []ocp.StorageClass{}
+		err = inventory.List(&classes)
+		for _, m := range classes {
+			list = append(list, Source{Ref: ref.Ref{Name: m.Name}, Name: m.Name})
+		}
+	default:
+		err = liberr.Wrap(NotSupportedError{Provider: provider})
+	}
+	if err != nil {
+		return
+	}
+	sortSources(list)
+	return
+}
+
+// List the destination networks (NADs) with the labels.
+// Sorted by namespace/name.
+func NetworkAttachmentDefinitions(provider *api.Provider) (list []ocp.NetworkAttachmentDefinition, err error) {
+	inventory, err := web.NewClient(provider)
+	if err != nil {
+		return
+	}
+	list = []ocp.NetworkAttachmentDefinition{}
+	err = inventory.List(
+		&list,
+		base.Param{
+			Key:   ocp.DetailParam,
+			Value: "all",
+		})
+	if err != nil {
+		return
+	}
+	sort.Slice(
+		list,
+		func(i, j int) bool {
+			return list[i].Namespace+"/"+list[i].Name < list[j].Namespace+"/"+list[j].Name
+		})
+	return
+}
+
+// List the destination storage classes with the labels.
+// Sorted by name.
+func StorageClasses(provider *api.Provider) (list []ocp.StorageClass, err error) {
+	inventory, err := web.NewClient(provider)
+	if err != nil {
+		return
+	}
+	list = []ocp.StorageClass{}
+	err = inventory.List(
+		&list,
+		base.Param{
+			Key:   ocp.DetailParam,
+			Value: "all",
+		})
+	if err != nil {
+		return
+	}
+	sort.Slice(
+		list,
+		func(i, j int) bool {
+			return list[i].Name < list[j].Name
+		})
+	return
+}
+
+// Sort by name.
+func sortSources(list []Source) {
+	sort.Slice(
+		list,
+		func(i, j int) bool {
+			return list[i].Name < list[j].Name
+		})
+}
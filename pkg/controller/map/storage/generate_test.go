@@ -0,0 +1,73 @@
+package storage
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/map/rule"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+)
+
+var _ = Describe("StorageMap Generator", func() {
+	sources := []rule.Source{
+		{Ref: ref.Ref{ID: "ds-1", Name: "gold-ds01"}, Name: "gold-ds01"},
+		{Ref: ref.Ref{ID: "ds-2", Name: "silver-ds01"}, Name: "silver-ds01"},
+		{Ref: ref.Ref{ID: "ds-3", Name: "local"}, Name: "local"},
+	}
+
+	It("should generate pairs using the pattern groups", func() {
+		generator := Generator{
+			Rules: []api.StorageMapRule{
+				{
+					Source: `(?P<tier>gold|silver)-.*`,
+					Destination: api.DestinationStorage{
+						StorageClass: "${tier}-rbd",
+						VolumeMode:   core.PersistentVolumeBlock,
+					},
+				},
+			},
+			Sources: sources,
+		}
+		explicit := api.StoragePair{
+			Source:      ref.Ref{ID: "ds-2"},
+			Destination: api.DestinationStorage{StorageClass: "manual"},
+		}
+		pairs, err := generator.Generate([]api.StoragePair{explicit})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pairs).To(Equal([]api.StoragePair{
+			{
+				Source: ref.Ref{ID: "ds-1", Name: "gold-ds01"},
+				Destination: api.DestinationStorage{
+					StorageClass: "gold-rbd",
+					VolumeMode:   core.PersistentVolumeBlock,
+				},
+			},
+		}))
+	})
+
+	It("should select the storage class by labels", func() {
+		class := func(name, tier string) ocp.StorageClass {
+			m := ocp.StorageClass{}
+			m.Name = name
+			m.Object.Labels = map[string]string{"tier": tier}
+			return m
+		}
+		generator := Generator{
+			Rules: []api.StorageMapRule{
+				{
+					Source:   `(\w+)-.*`,
+					Selector: map[string]string{"tier": "$1"},
+				},
+			},
+			Sources:        sources,
+			StorageClasses: []ocp.StorageClass{class("ceph-fast", "gold"), class("nfs", "bronze")},
+		}
+		pairs, err := generator.Generate(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pairs).To(HaveLen(1))
+		Expect(pairs[0].Destination.StorageClass).To(Equal("ceph-fast"))
+		Expect(generator.NotMatched).To(Equal([]string{"silver-ds01"}))
+	})
+})
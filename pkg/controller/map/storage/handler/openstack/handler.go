@@ -102,7 +102,10 @@ func (r *Handler) changed(models ...*openstack.VolumeType) {
 }
 
 func isReferenced(models []*openstack.VolumeType, storageMap *api.StorageMap) bool {
-	for _, pair := range storageMap.Spec.Map {
+	if len(storageMap.Spec.Rules) > 0 {
+		return true
+	}
+	for _, pair := range storageMap.Pairs() {
 		ref := pair.Source
 		for _, model := range models {
 			if ref.ID == model.ID || strings.HasSuffix(model.Path, ref.Name) {
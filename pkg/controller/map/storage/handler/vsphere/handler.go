@@ -89,8 +89,8 @@ func (r *Handler) changed(models ...*vsphere.Datastore) {
 		if !r.MatchProvider(ref) {
 			continue
 		}
-		referenced := false
-		for _, pair := range mp.Spec.Map {
+		referenced := len(mp.Spec.Rules) > 0
+		for _, pair := range mp.Pairs() {
 			ref := pair.Source
 			for _, ds := range models {
 				if ref.ID == ds.ID || strings.HasSuffix(ds.Path, ref.Name) {
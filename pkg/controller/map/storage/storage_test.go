@@ -203,8 +203,10 @@ var _ = Describe("MapPredicate", func() {
 					Namespace:  "default",
 					Generation: 1,
 				},
-				Status: api.MapStatus{
-					ObservedGeneration: 1,
+				Status: api.StorageMapStatus{
+					MapStatus: api.MapStatus{
+						ObservedGeneration: 1,
+					},
 				},
 			}
 			newSm := &api.StorageMap{
@@ -213,8 +215,10 @@ var _ = Describe("MapPredicate", func() {
 					Namespace:  "default",
 					Generation: 2,
 				},
-				Status: api.MapStatus{
-					ObservedGeneration: 1,
+				Status: api.StorageMapStatus{
+					MapStatus: api.MapStatus{
+						ObservedGeneration: 1,
+					},
 				},
 			}
 			result := predicate.Update(event.TypedUpdateEvent[*api.StorageMap]{
@@ -231,8 +235,10 @@ var _ = Describe("MapPredicate", func() {
 					Namespace:  "default",
 					Generation: 1,
 				},
-				Status: api.MapStatus{
-					ObservedGeneration: 1,
+				Status: api.StorageMapStatus{
+					MapStatus: api.MapStatus{
+						ObservedGeneration: 1,
+					},
 				},
 			}
 			newSm := &api.StorageMap{
@@ -241,8 +247,10 @@ var _ = Describe("MapPredicate", func() {
 					Namespace:  "default",
 					Generation: 1,
 				},
-				Status: api.MapStatus{
-					ObservedGeneration: 1,
+				Status: api.StorageMapStatus{
+					MapStatus: api.MapStatus{
+						ObservedGeneration: 1,
+					},
 				},
 			}
 			result := predicate.Update(event.TypedUpdateEvent[*api.StorageMap]{
@@ -409,7 +417,7 @@ var _ = Describe("ProviderPredicate", func() {
 var _ = Describe("StorageVendorProducts", func() {
 	It("should return all storage vendor products", func() {
 		products := api.StorageVendorProducts()
-		Expect(products).To(HaveLen(9))
+		Expect(products).To(HaveLen(10))
 		Expect(products).To(ContainElement(api.StorageVendorProductFlashSystem))
 		Expect(products).To(ContainElement(api.StorageVendorProductVantara))
 		Expect(products).To(ContainElement(api.StorageVendorProductOntap))
@@ -419,5 +427,6 @@ var _ = Describe("StorageVendorProducts", func() {
 		Expect(products).To(ContainElement(api.StorageVendorProductPowerMax))
 		Expect(products).To(ContainElement(api.StorageVendorProductPowerStore))
 		Expect(products).To(ContainElement(api.StorageVendorProductInfinibox))
+		Expect(products).To(ContainElement(api.StorageVendorProductSwordfish))
 	})
 })
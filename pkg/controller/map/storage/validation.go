@@ -55,6 +55,10 @@ func (r *Reconciler) validate(mp *api.StorageMap) error {
 	}
 	mp.Referenced.Provider.Source = pv.Referenced.Source
 	mp.Referenced.Provider.Destination = pv.Referenced.Destination
+	err = r.generate(mp)
+	if err != nil {
+		return err
+	}
 	err = r.validateSource(mp)
 	if err != nil {
 		return err
@@ -77,7 +81,7 @@ func (r *Reconciler) validateSource(mp *api.StorageMap) (err error) {
 	notValid := []string{}
 	ambiguous := []string{}
 	references := refapi.Refs{}
-	list := mp.Spec.Map
+	list := mp.Pairs()
 	for i := range list {
 		ref := &list[i].Source
 		if ref.NotSet() {
@@ -138,7 +142,7 @@ func (r *Reconciler) validateDestination(mp *api.StorageMap) (err error) {
 		return
 	}
 	notValid := []string{}
-	list := mp.Spec.Map
+	list := mp.Pairs()
 	for _, entry := range list {
 		name := entry.Destination.StorageClass
 		_, pErr := inventory.Storage(&refapi.Ref{Name: name})
@@ -141,6 +141,9 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 
 	// Detected completed.
 	if migration.Status.MarkedCompleted() {
+		if r.report(migration) {
+			result.RequeueAfter = base.SlowReQ
+		}
 		return
 	}
 
@@ -176,6 +179,11 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		return
 	}
 
+	// Store the report when completed.
+	if r.report(migration) {
+		result.RequeueAfter = base.SlowReQ
+	}
+
 	// Done
 	return
 }
@@ -0,0 +1,498 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Report key in the ConfigMap.
+// The CSV and HTML formats are rendered from the JSON document
+// when served so that the report is stored once.
+const (
+	ReportJSON = "report.json"
+)
+
+// Size limit of the data of a ConfigMap.
+const ReportMaxSize = 1 << 20
+
+// Report condition.
+const (
+	ReportNotStored = "ReportNotStored"
+)
+
+// Report reasons.
+const (
+	TooLarge = "TooLarge"
+)
+
+// Disk transfer steps.
+const (
+	DiskTransfer    = "DiskTransfer"
+	DiskTransferV2v = "DiskTransferV2v"
+)
+
+// Migration report.
+type Report struct {
+	// Migration name.
+	Migration string `json:"migration"`
+	// Namespace.
+	Namespace string `json:"namespace"`
+	// Plan name.
+	Plan string `json:"plan"`
+	// Result (Succeeded, Failed, Canceled).
+	Result string `json:"result"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// VMs.
+	VMs []VMReport `json:"vms"`
+}
+
+// VM report.
+type VMReport struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Result    string          `json:"result"`
+	Started   *meta.Time      `json:"started,omitempty"`
+	Completed *meta.Time      `json:"completed,omitempty"`
+	Duration  string          `json:"duration,omitempty"`
+	Bytes     int64           `json:"bytes"`
+	Phases    []PhaseReport   `json:"phases"`
+	Precopies []PrecopyReport `json:"precopies,omitempty"`
+	Errors    []string        `json:"errors,omitempty"`
+	Concerns  []ConcernReport `json:"concerns,omitempty"`
+}
+
+// Phase (pipeline step) report.
+type PhaseReport struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Phase       string     `json:"phase,omitempty"`
+	Started     *meta.Time `json:"started,omitempty"`
+	Completed   *meta.Time `json:"completed,omitempty"`
+	Duration    string     `json:"duration,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// Warm precopy report.
+type PrecopyReport struct {
+	Snapshot  string     `json:"snapshot,omitempty"`
+	Started   *meta.Time `json:"started,omitempty"`
+	Completed *meta.Time `json:"completed,omitempty"`
+	Duration  string     `json:"duration,omitempty"`
+}
+
+// Inventory concern report.
+type ConcernReport struct {
+	Category   string `json:"category"`
+	Label      string `json:"label"`
+	Assessment string `json:"assessment,omitempty"`
+}
+
+// Store the report of a completed migration.
+// The report is stored once in a ConfigMap owned by the migration.
+// A report larger than a ConfigMap can hold is not stored and the
+// ReportNotStored condition is set on the migration.
+// Returns true when the report needs to be stored again.
+func (r *Reconciler) report(migration *api.Migration) (retry bool) {
+	if !migration.Status.HasAnyCondition(Succeeded, Failed, Canceled) {
+		return
+	}
+	if migration.Status.HasCondition(ReportNotStored) {
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err := r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: migration.Namespace,
+			Name:      migration.ReportName(),
+		},
+		configMap)
+	if err == nil {
+		return
+	}
+	if !k8serr.IsNotFound(err) {
+		r.Log.Error(err, "Couldn't get the migration report.")
+		retry = true
+		return
+	}
+	report := NewReport(migration, r.concerns(migration))
+	document, err := report.JSON()
+	if err != nil {
+		r.Log.Error(err, "Couldn't render the migration report.")
+		return
+	}
+	if len(ReportJSON)+len(document) > ReportMaxSize {
+		retry = r.reportNotStored(migration, len(document))
+		return
+	}
+	configMap = &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: migration.Namespace,
+			Name:      migration.ReportName(),
+			OwnerReferences: []meta.OwnerReference{
+				{
+					APIVersion: api.SchemeGroupVersion.String(),
+					Kind:       "Migration",
+					Name:       migration.Name,
+					UID:        migration.UID,
+				},
+			},
+		},
+		Data: map[string]string{ReportJSON: document},
+	}
+	err = r.Create(context.TODO(), configMap)
+	if err != nil && !k8serr.IsAlreadyExists(err) {
+		if k8serr.IsRequestEntityTooLargeError(err) || k8serr.IsInvalid(err) {
+			r.Log.Error(err, "Couldn't store the migration report.")
+			retry = r.reportNotStored(migration, len(document))
+			return
+		}
+		r.Log.Error(err, "Couldn't store the migration report.")
+		retry = true
+		return
+	}
+	r.Log.Info("Stored the migration report.", "configMap", configMap.Name)
+	return
+}
+
+// Set the condition reporting that the report cannot be stored.
+// The condition is durable so the report is not rendered again.
+// Returns true when the condition needs to be set again.
+func (r *Reconciler) reportNotStored(migration *api.Migration, size int) (retry bool) {
+	migration.Status.SetCondition(libcnd.Condition{
+		Type:     ReportNotStored,
+		Status:   True,
+		Reason:   TooLarge,
+		Category: Warn,
+		Message: fmt.Sprintf(
+			"The migration report (%d bytes) exceeds the size of a ConfigMap (%d bytes).",
+			size,
+			ReportMaxSize),
+		Durable: true,
+	})
+	err := r.Status().Update(context.TODO(), migration)
+	if err != nil {
+		r.Log.Error(err, "Couldn't update the migration status.")
+		retry = true
+	}
+	return
+}
+
+// Concerns found in the inventory by VM ID.
+// Best effort, the report is stored without the concerns
+// when the inventory cannot be reached.
+func (r *Reconciler) concerns(migration *api.Migration) (concerns map[string][]ConcernReport) {
+	concerns = make(map[string][]ConcernReport)
+	plan := &api.Plan{}
+	err := r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: migration.Spec.Plan.Namespace,
+			Name:      migration.Spec.Plan.Name,
+		},
+		plan)
+	if err != nil {
+		r.Log.Info("Report without concerns, the plan not found.", "error", err.Error())
+		return
+	}
+	provider := &api.Provider{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: plan.Spec.Provider.Source.Namespace,
+			Name:      plan.Spec.Provider.Source.Name,
+		},
+		provider)
+	if err != nil {
+		r.Log.Info("Report without concerns, the source provider not found.", "error", err.Error())
+		return
+	}
+	inventory, err := web.NewClient(provider)
+	if err != nil {
+		r.Log.Info("Report without concerns, the inventory not available.", "error", err.Error())
+		return
+	}
+	for _, vm := range migration.Status.VMs {
+		object, err := inventory.VM(&vm.Ref)
+		if err != nil {
+			r.Log.V(1).Info("Report without VM concerns.", "vm", vm.String(), "error", err.Error())
+			continue
+		}
+		found, err := vmConcerns(object)
+		if err != nil {
+			r.Log.V(1).Info("Report without VM concerns.", "vm", vm.String(), "error", err.Error())
+			continue
+		}
+		concerns[vm.ID] = found
+	}
+	return
+}
+
+// Concerns of an inventory VM.
+// The VM resources of the providers differ but share
+// the JSON representation of the concerns.
+func vmConcerns(object interface{}) (concerns []ConcernReport, err error) {
+	b, err := json.Marshal(object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	vm := struct {
+		Concerns []ConcernReport `json:"concerns"`
+	}{}
+	err = json.Unmarshal(b, &vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	concerns = vm.Concerns
+	return
+}
+
+// Build the report of a migration.
+func NewReport(migration *api.Migration, concerns map[string][]ConcernReport) (report *Report) {
+	report = &Report{
+		Migration: migration.Name,
+		Namespace: migration.Namespace,
+		Plan:      migration.Spec.Plan.Name,
+		Started:   migration.Status.Started,
+		Completed: migration.Status.Completed,
+	}
+	for _, result := range []string{Succeeded, Failed, Canceled} {
+		if migration.Status.HasCondition(result) {
+			report.Result = result
+			break
+		}
+	}
+	for _, vm := range migration.Status.VMs {
+		vmReport := VMReport{
+			ID:        vm.ID,
+			Name:      vm.Name,
+			Result:    vmResult(vm),
+			Started:   vm.Started,
+			Completed: vm.Completed,
+			Duration:  duration(vm.Started, vm.Completed),
+			Concerns:  concerns[vm.ID],
+		}
+		if vm.Error != nil {
+			vmReport.Errors = append(vmReport.Errors, vm.Error.Reasons...)
+		}
+		for _, step := range vm.Pipeline {
+			phase := PhaseReport{
+				Name:        step.Name,
+				Description: step.Description,
+				Phase:       step.Phase,
+				Started:     step.Started,
+				Completed:   step.Completed,
+				Duration:    duration(step.Started, step.Completed),
+			}
+			if step.Error != nil {
+				phase.Error = strings.Join(step.Error.Reasons, "; ")
+			}
+			vmReport.Phases = append(vmReport.Phases, phase)
+			if step.Name == DiskTransfer || step.Name == DiskTransferV2v {
+				vmReport.Bytes += transferred(step)
+			}
+		}
+		if vm.Warm != nil {
+			for _, precopy := range vm.Warm.Precopies {
+				vmReport.Precopies = append(
+					vmReport.Precopies,
+					PrecopyReport{
+						Snapshot:  precopy.Snapshot,
+						Started:   precopy.Start,
+						Completed: precopy.End,
+						Duration:  duration(precopy.Start, precopy.End),
+					})
+			}
+		}
+		report.VMs = append(report.VMs, vmReport)
+	}
+	return
+}
+
+// Result of a VM migration.
+func vmResult(vm *planapi.VMStatus) string {
+	for _, result := range []string{Succeeded, Failed, Canceled} {
+		if vm.HasCondition(result) {
+			return result
+		}
+	}
+	return vm.Phase
+}
+
+// Bytes transferred by a disk transfer step.
+// The progress of the tasks is reported in MB.
+func transferred(step *planapi.Step) (n int64) {
+	tasks := step.Tasks
+	if len(tasks) == 0 {
+		tasks = []*planapi.Task{&step.Task}
+	}
+	for _, task := range tasks {
+		if task.Annotations["unit"] == "MB" {
+			n += task.Progress.Completed << 20
+		}
+	}
+	return
+}
+
+// Elapsed time between the timestamps.
+func duration(started, completed *meta.Time) string {
+	if started == nil || completed == nil {
+		return ""
+	}
+	return completed.Sub(started.Time).Round(time.Second).String()
+}
+
+// Format a timestamp.
+func timestamp(t *meta.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Render the report as JSON.
+func (r *Report) JSON() (document string, err error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	document = string(b)
+	return
+}
+
+// Render the report as CSV.
+// One record for each VM, phase, precopy, error and concern.
+func (r *Report) CSV() (document string, err error) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	records := [][]string{
+		{"vmID", "vmName", "record", "name", "result", "started", "completed", "duration", "bytes", "detail"},
+	}
+	for _, vm := range r.VMs {
+		records = append(records, []string{
+			vm.ID, vm.Name, "vm", vm.Name, vm.Result,
+			timestamp(vm.Started), timestamp(vm.Completed), vm.Duration,
+			strconv.FormatInt(vm.Bytes, 10), "",
+		})
+		for _, phase := range vm.Phases {
+			records = append(records, []string{
+				vm.ID, vm.Name, "phase", phase.Name, phase.Phase,
+				timestamp(phase.Started), timestamp(phase.Completed), phase.Duration,
+				"", phase.Error,
+			})
+		}
+		for _, precopy := range vm.Precopies {
+			records = append(records, []string{
+				vm.ID, vm.Name, "precopy", precopy.Snapshot, "",
+				timestamp(precopy.Started), timestamp(precopy.Completed), precopy.Duration,
+				"", "",
+			})
+		}
+		for _, reason := range vm.Errors {
+			records = append(records, []string{
+				vm.ID, vm.Name, "error", "", "", "", "", "", "", reason,
+			})
+		}
+		for _, concern := range vm.Concerns {
+			records = append(records, []string{
+				vm.ID, vm.Name, "concern", concern.Label, concern.Category, "", "", "", "", concern.Assessment,
+			})
+		}
+	}
+	err = writer.WriteAll(records)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	document = buffer.String()
+	return
+}
+
+// HTML report template.
+var reportTemplate = template.Must(template.New("report").Funcs(
+	template.FuncMap{"timestamp": timestamp}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Migration {{.Namespace}}/{{.Migration}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Migration {{.Namespace}}/{{.Migration}}</h1>
+<table>
+<tr><th>Plan</th><td>{{.Plan}}</td></tr>
+<tr><th>Result</th><td>{{.Result}}</td></tr>
+<tr><th>Started</th><td>{{timestamp .Started}}</td></tr>
+<tr><th>Completed</th><td>{{timestamp .Completed}}</td></tr>
+</table>
+{{range .VMs}}
+<h2>VM {{.Name}} ({{.ID}})</h2>
+<table>
+<tr><th>Result</th><td>{{.Result}}</td></tr>
+<tr><th>Started</th><td>{{timestamp .Started}}</td></tr>
+<tr><th>Completed</th><td>{{timestamp .Completed}}</td></tr>
+<tr><th>Duration</th><td>{{.Duration}}</td></tr>
+<tr><th>Bytes transferred</th><td>{{.Bytes}}</td></tr>
+</table>
+<h3>Phases</h3>
+<table>
+<tr><th>Name</th><th>Phase</th><th>Started</th><th>Completed</th><th>Duration</th><th>Error</th></tr>
+{{range .Phases}}<tr><td>{{.Name}}</td><td>{{.Phase}}</td><td>{{timestamp .Started}}</td><td>{{timestamp .Completed}}</td><td>{{.Duration}}</td><td>{{.Error}}</td></tr>
+{{end}}</table>
+{{if .Precopies}}<h3>Precopies</h3>
+<table>
+<tr><th>Snapshot</th><th>Started</th><th>Completed</th><th>Duration</th></tr>
+{{range .Precopies}}<tr><td>{{.Snapshot}}</td><td>{{timestamp .Started}}</td><td>{{timestamp .Completed}}</td><td>{{.Duration}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Errors}}<h3>Errors</h3>
+<ul>
+{{range .Errors}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{if .Concerns}}<h3>Concerns</h3>
+<table>
+<tr><th>Category</th><th>Label</th><th>Assessment</th></tr>
+{{range .Concerns}}<tr><td>{{.Category}}</td><td>{{.Label}}</td><td>{{.Assessment}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+// Render the report as HTML.
+func (r *Report) HTML() (document string, err error) {
+	buffer := &bytes.Buffer{}
+	err = reportTemplate.Execute(buffer, r)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	document = buffer.String()
+	return
+}
@@ -0,0 +1,135 @@
+package migration
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/base"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	started := meta.NewTime(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+	completed := meta.NewTime(started.Add(90 * time.Second))
+	transfer := &planapi.Step{
+		Task: planapi.Task{
+			Name:  DiskTransfer,
+			Phase: "Completed",
+			Timed: planapi.Timed{Started: &started, Completed: &completed},
+		},
+		Tasks: []*planapi.Task{
+			{Annotations: map[string]string{"unit": "MB"}},
+			{Annotations: map[string]string{"unit": "MB"}},
+		},
+	}
+	transfer.Tasks[0].Progress.Completed = 1
+	transfer.Tasks[1].Progress.Completed = 2
+	failed := &planapi.Step{Task: planapi.Task{Name: "VirtualMachineCreation"}}
+	failed.AddError("quota exceeded")
+	vm := &planapi.VMStatus{
+		VM:    planapi.VM{Ref: ref.Ref{ID: "vm-1", Name: "web"}},
+		Timed: planapi.Timed{Started: &started, Completed: &completed},
+		Error: &planapi.Error{Reasons: []string{"quota exceeded"}},
+		Warm: &planapi.Warm{
+			Precopies: []planapi.Precopy{{Snapshot: "snapshot-1", Start: &started, End: &completed}},
+		},
+		Pipeline: []*planapi.Step{transfer, failed},
+	}
+	vm.SetCondition(libcnd.Condition{Type: Failed, Status: True})
+	migration := &api.Migration{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "migration"}}
+	migration.Spec.Plan.Name = "plan"
+	migration.Status.SetCondition(libcnd.Condition{Type: Failed, Status: True})
+	migration.Status.VMs = []*planapi.VMStatus{vm}
+
+	report := NewReport(
+		migration,
+		map[string][]ConcernReport{
+			"vm-1": {{Category: "Warning", Label: "Changed Block Tracking (CBT) not enabled"}},
+		})
+	g.Expect(report.Result).To(gomega.Equal(Failed))
+	g.Expect(report.VMs).To(gomega.HaveLen(1))
+	vmReport := report.VMs[0]
+	g.Expect(vmReport.Result).To(gomega.Equal(Failed))
+	g.Expect(vmReport.Duration).To(gomega.Equal("1m30s"))
+	g.Expect(vmReport.Bytes).To(gomega.Equal(int64(3 << 20)))
+	g.Expect(vmReport.Phases).To(gomega.HaveLen(2))
+	g.Expect(vmReport.Phases[0].Duration).To(gomega.Equal("1m30s"))
+	g.Expect(vmReport.Phases[1].Error).To(gomega.Equal("quota exceeded"))
+	g.Expect(vmReport.Precopies).To(gomega.HaveLen(1))
+	g.Expect(vmReport.Errors).To(gomega.ConsistOf("quota exceeded"))
+	g.Expect(vmReport.Concerns).To(gomega.HaveLen(1))
+
+	document, err := report.JSON()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(document).To(gomega.ContainSubstring(`"bytes":3145728`))
+	csv, err := report.CSV()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	lines := strings.Split(strings.TrimSpace(csv), "\n")
+	// Header, VM, 2 phases, precopy, error and concern.
+	g.Expect(lines).To(gomega.HaveLen(7))
+	g.Expect(lines[1]).To(gomega.HavePrefix("vm-1,web,vm,web,Failed,2026-01-01T10:00:00Z"))
+	html, err := report.HTML()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(html).To(gomega.ContainSubstring("<h2>VM web (vm-1)</h2>"))
+	g.Expect(html).To(gomega.ContainSubstring("Changed Block Tracking (CBT) not enabled"))
+}
+
+func TestReportTooLarge(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := &planapi.VMStatus{
+		VM:    planapi.VM{Ref: ref.Ref{ID: "vm-1", Name: "web"}},
+		Error: &planapi.Error{Reasons: []string{strings.Repeat("x", ReportMaxSize)}},
+	}
+	migration := &api.Migration{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "migration"}}
+	migration.Status.SetCondition(libcnd.Condition{Type: Failed, Status: True})
+	migration.Status.VMs = []*planapi.VMStatus{vm}
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(migration).
+		WithStatusSubresource(migration).
+		Build()
+	r := &Reconciler{
+		Reconciler: base.Reconciler{Client: client, Log: logging.WithName("test")},
+	}
+
+	// The report is not stored and not retried.
+	g.Expect(r.report(migration)).To(gomega.BeFalse())
+	cnd := migration.Status.FindCondition(ReportNotStored)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Reason).To(gomega.Equal(TooLarge))
+	configMaps := &core.ConfigMapList{}
+	g.Expect(client.List(context.TODO(), configMaps)).To(gomega.Succeed())
+	g.Expect(configMaps.Items).To(gomega.BeEmpty())
+	g.Expect(r.report(migration)).To(gomega.BeFalse())
+}
+
+func TestVMConcerns(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := map[string]interface{}{
+		"id": "vm-1",
+		"concerns": []map[string]string{
+			{"id": "id", "label": "label", "category": "Critical"},
+		},
+	}
+	concerns, err := vmConcerns(vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(concerns).To(gomega.Equal([]ConcernReport{{Category: "Critical", Label: "label"}}))
+}
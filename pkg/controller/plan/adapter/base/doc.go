@@ -30,6 +30,12 @@ const (
 	// Set the source PVC of the conversion, used on the DV for filtering
 	AnnConversionSourcePVC = "forklift.konveyor.io/conversionSourcePVC"
 
+	// Backup of the latest precopy set on an OpenStack volume populator.
+	AnnPrecopyBackup = "forklift.konveyor.io/precopy-backup"
+
+	// Backup of the previous precopy set on an OpenStack volume populator.
+	AnnPrecopyParentBackup = "forklift.konveyor.io/precopy-parent-backup"
+
 	// CDI
 
 	// Causes the importer pod to be retained after import.
@@ -254,6 +260,20 @@ type Validator interface {
 	PVCNameTemplate(vmRef ref.Ref, pvcNameTemplate string) (bool, error)
 	// Validate guest tools installation and status (e.g., VMware Tools, VirtIO drivers).
 	GuestToolsInstalled(vmRef ref.Ref) (ok bool, err error)
+	// Resources required by the VM on the destination.
+	Requirements(vmRef ref.Ref) (*Requirements, error)
+}
+
+// Resources required by a VM on the destination.
+// Used to validate the destination capacity and quotas.
+type Requirements struct {
+	// Disks by source storage.
+	// Capacity in bytes, excluding the filesystem or block overhead.
+	Disks []planapi.DiskFootprint
+	// Virtual CPUs.
+	CPU int64
+	// Memory in bytes.
+	Memory int64
 }
 
 // DestinationClient API.
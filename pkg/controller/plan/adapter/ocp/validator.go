@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
@@ -173,6 +174,70 @@ func (r *Validator) InvalidDiskSizes(vmRef ref.Ref) ([]string, error) {
 	return []string{}, nil
 }
 
+// Resources required by the VM on the destination.
+// The disks are keyed by the source storage class.
+func (r *Validator) Requirements(vmRef ref.Ref) (requirements *planbase.Requirements, err error) {
+	vm := &cnv.VirtualMachine{}
+	err = r.sourceClient.Get(context.TODO(), k8sclient.ObjectKey{Namespace: vmRef.Namespace, Name: vmRef.Name}, vm)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			VM_NOT_FOUND,
+			"vm",
+			vmRef.String())
+		return
+	}
+	requirements = &planbase.Requirements{}
+	if vm.Spec.Template == nil {
+		return
+	}
+	domain := vm.Spec.Template.Spec.Domain
+	if cpu := domain.CPU; cpu != nil {
+		requirements.CPU = int64(max(cpu.Sockets, 1)) * int64(max(cpu.Cores, 1)) * int64(max(cpu.Threads, 1))
+	}
+	if domain.Memory != nil && domain.Memory.Guest != nil {
+		requirements.Memory = domain.Memory.Guest.Value()
+	} else if memory, found := domain.Resources.Requests[core.ResourceMemory]; found {
+		requirements.Memory = memory.Value()
+	}
+	for _, vol := range vm.Spec.Template.Spec.Volumes {
+		var pvcName string
+		switch {
+		case vol.PersistentVolumeClaim != nil:
+			pvcName = vol.PersistentVolumeClaim.ClaimName
+		case vol.DataVolume != nil:
+			pvcName = vol.DataVolume.Name
+		default:
+			continue
+		}
+		pvc := &core.PersistentVolumeClaim{}
+		err = r.sourceClient.Get(context.TODO(), k8sclient.ObjectKey{
+			Namespace: vmRef.Namespace,
+			Name:      pvcName,
+		}, pvc)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"PVC not found.",
+				"pvc",
+				pvcName)
+			return
+		}
+		storage := ref.Ref{}
+		if pvc.Spec.StorageClassName != nil {
+			storage.Name = *pvc.Spec.StorageClassName
+		}
+		capacity := pvc.Spec.Resources.Requests[core.ResourceStorage]
+		requirements.Disks = append(
+			requirements.Disks,
+			planapi.DiskFootprint{
+				Storage:  storage,
+				Capacity: capacity.Value(),
+			})
+	}
+	return
+}
+
 func (r *Validator) MacConflicts(vmRef ref.Ref) ([]planbase.MacConflict, error) {
 	// Only check MAC conflicts for live migrations
 	// For cold migrations, the source VM is shut down, so no conflicts occur
@@ -860,9 +860,13 @@ func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
 	return ""
 }
 
-// Return a stable identifier for a PersistentDataVolume
+// Resolve the task name of a PVC populated from the image of a volume.
 func (r *Builder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
-	return ""
+	volumeID, found := pvc.Annotations[planbase.AnnDiskSource]
+	if !found {
+		return ""
+	}
+	return getImageFromVolumeName(r.Context, pvc.Labels["vmID"], volumeID)
 }
 
 // Build credential secret.
@@ -913,6 +917,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		images = append(images, image)
 	}
 
+	rate := r.Plan.DiskTransferRateLimit(len(images))
 	for _, image := range images {
 		if imageID, ok := image.Properties[forkliftPropertyOriginalImageID]; ok && imageID == workload.ImageID {
 			if image.DiskFormat != "raw" {
@@ -925,7 +930,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 			r.Log.Info("the image is not ready yet", "image", image.Name, "status", image.Status)
 			continue
 		}
-		if pvc, pvcErr := r.getCorrespondingPvc(image, workload, annotations, secretName); pvcErr == nil {
+		if pvc, pvcErr := r.getCorrespondingPvc(image, workload, annotations, secretName, rate); pvcErr == nil {
 			pvcs = append(pvcs, pvc)
 		} else {
 			err = pvcErr
@@ -935,22 +940,22 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 	return
 }
 
-func (r *Builder) getCorrespondingPvc(image model.Image, workload *model.Workload, annotations map[string]string, secretName string) (pvc *core.PersistentVolumeClaim, err error) {
-	populatorCR, err := r.ensureVolumePopulator(workload, &image, secretName)
+func (r *Builder) getCorrespondingPvc(image model.Image, workload *model.Workload, annotations map[string]string, secretName string, rate int) (pvc *core.PersistentVolumeClaim, err error) {
+	populatorCR, err := r.ensureVolumePopulator(workload, &image, secretName, rate)
 	if err != nil {
 		return
 	}
 	return r.ensureVolumePopulatorPVC(workload, &image, annotations, populatorCR.Name)
 }
 
-func (r *Builder) ensureVolumePopulator(workload *model.Workload, image *model.Image, secretName string) (populatorCR *api.OpenstackVolumePopulator, err error) {
+func (r *Builder) ensureVolumePopulator(workload *model.Workload, image *model.Image, secretName string, rate int) (populatorCR *api.OpenstackVolumePopulator, err error) {
 	volumePopulatorCR, err := r.getVolumePopulatorCR(image.ID)
 	if err != nil {
 		if !k8serr.IsNotFound(err) {
 			err = liberr.Wrap(err)
 			return
 		}
-		return r.createVolumePopulatorCR(*image, secretName, workload.ID)
+		return r.createVolumePopulatorCR(*image, secretName, workload.ID, rate)
 	}
 	populatorCR = &volumePopulatorCR
 	return
@@ -1025,7 +1030,7 @@ func (r *Builder) getImagesFromVolumes(workload *model.Workload) (images []model
 	images = []model.Image{}
 	for _, volume := range workload.Volumes {
 		image := model.Image{}
-		imageName := getImageFromVolumeName(r.Context, workload.ID, volume.ID)
+		imageName := r.imageFromVolumeName(workload.ID, volume.ID)
 		err = r.Source.Inventory.Find(&image, ref.Ref{Name: imageName})
 		if err != nil {
 			if errors.As(err, &model.NotFoundError{}) {
@@ -1046,7 +1051,21 @@ func (r *Builder) getImagesFromVolumes(workload *model.Workload) (images []model
 	return
 }
 
-func (r *Builder) createVolumePopulatorCR(image model.Image, secretName, vmId string) (populatorCR *api.OpenstackVolumePopulator, err error) {
+// Name of the image transferred for a volume. Warm migrations
+// transfer the image of the first precopy, the following ones
+// are applied from incremental backups.
+func (r *Builder) imageFromVolumeName(vmID, volumeID string) string {
+	if r.Plan.IsWarm() {
+		vm, found := r.Plan.Status.Migration.FindVM(ref.Ref{ID: vmID})
+		if found && vm.Warm != nil && len(vm.Warm.Precopies) > 0 {
+			precopy := vm.Warm.Precopies[0].Snapshot
+			return getPrecopyImageName(r.Context, vmID, volumeID, precopy)
+		}
+	}
+	return getImageFromVolumeName(r.Context, vmID, volumeID)
+}
+
+func (r *Builder) createVolumePopulatorCR(image model.Image, secretName, vmId string, rate int) (populatorCR *api.OpenstackVolumePopulator, err error) {
 	populatorCR = &api.OpenstackVolumePopulator{
 		ObjectMeta: meta.ObjectMeta{
 			GenerateName: fmt.Sprintf("%s-", image.Name),
@@ -1058,10 +1077,11 @@ func (r *Builder) createVolumePopulatorCR(image model.Image, secretName, vmId st
 			},
 		},
 		Spec: api.OpenstackVolumePopulatorSpec{
-			IdentityURL:     r.Source.Provider.Spec.URL,
-			SecretName:      secretName,
-			ImageID:         image.ID,
-			TransferNetwork: r.Plan.Spec.TransferNetwork,
+			IdentityURL:       r.Source.Provider.Spec.URL,
+			SecretName:        secretName,
+			ImageID:           image.ID,
+			TransferNetwork:   r.Plan.Spec.TransferNetwork,
+			TransferRateLimit: rate,
 		},
 	}
 	err = r.Context.Client.Create(context.TODO(), populatorCR, &client.CreateOptions{})
@@ -1297,7 +1317,7 @@ func (r *Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []*core.Persi
 	}
 	var images []*model.Image
 	for _, volume := range workload.Volumes {
-		lookupName := getImageFromVolumeName(r.Context, vmRef.ID, volume.ID)
+		lookupName := r.imageFromVolumeName(vmRef.ID, volume.ID)
 		image, err := r.getImageByName(lookupName)
 		if err != nil {
 			r.Log.Error(err, "Couldn't find the image from the volume.", "volume", volume.ID, "vmRef", vmRef)
@@ -1355,6 +1375,11 @@ func (r *Builder) setPopulatorLabels(populatorCr api.OpenstackVolumePopulator, v
 }
 
 func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error) {
+	if r.Plan.IsWarm() {
+		// The image is named after the precopy.
+		taskName = r.ResolvePersistentVolumeClaimIdentifier(pvc)
+		return
+	}
 	image, err := r.getImageFromPVC(pvc)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -29,6 +29,10 @@ const (
 	SnapshotStatusDeleting  = libclient.SnapshotStatusDeleting
 	SnapshotStatusDeleted   = libclient.SnapshotStatusDeleted
 
+	BackupStatusAvailable = libclient.BackupStatusAvailable
+	BackupStatusCreating  = libclient.BackupStatusCreating
+	BackupStatusDeleting  = libclient.BackupStatusDeleting
+
 	VolumeStatusAvailable = libclient.VolumeStatusAvailable
 	VolumeStatusInUse     = libclient.VolumeStatusInUse
 	VolumeStatusCreating  = libclient.VolumeStatusCreating
@@ -109,13 +113,34 @@ func (r *Client) PoweredOff(vmRef ref.Ref) (off bool, err error) {
 	return
 }
 
-// Create a snapshot of the source VM.
+// Create the volume snapshots of a warm migration precopy.
+// The returned snapshot ID is the name of the precopy, shared
+// by the snapshots, volumes and images created for it.
 func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapshotId string, creationTaskId string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	precopy := getPrecopyName()
+	for _, attachedVolume := range vm.AttachedVolumes {
+		_, err = r.createPrecopySnapshot(vm, attachedVolume.ID, precopy)
+		if err != nil {
+			return
+		}
+	}
+	snapshotId = precopy
 	return
 }
 
-// Check if a snapshot is ready to transfer.
+// Check if the backups and images of a precopy are ready to transfer.
 func (r *Client) CheckSnapshotReady(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (ready bool, snapshotId string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	ready, err = r.ensurePrecopy(vm, precopy.Snapshot)
 	return
 }
 
@@ -124,13 +149,52 @@ func (r *Client) CheckSnapshotRemove(vmRef ref.Ref, precopy planapi.Precopy, hos
 	return false, nil
 }
 
-// Set DataVolume checkpoints.
-func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool, hostsFunc util.HostsFunc) error {
-	return nil
+// Point the volume populators at the backups of the latest and the
+// previous precopies and remove the image of the first precopy,
+// which has already been transferred.
+func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool, hostsFunc util.HostsFunc) (err error) {
+	n := len(precopies)
+	if n < 2 {
+		return
+	}
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	err = r.setPopulatorBackups(vm, precopies[n-1].Snapshot, precopies[n-2].Snapshot)
+	if err != nil {
+		return
+	}
+	for _, precopy := range precopies[:n-1] {
+		err = r.removePrecopyImages(vm, precopy.Snapshot)
+		if err != nil {
+			r.Log.Error(err, "removing the images of a previous precopy",
+				"vm", vm.Name, "precopy", precopy.Snapshot)
+			err = nil
+		}
+	}
+	return
 }
 
-// Remove a VM snapshot. No-op for this provider.
+// Remove the snapshots, volumes and images of a precopy.
+// The backups are removed when the migration ends.
 func (r *Client) RemoveSnapshot(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (removeTaskId string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	err = r.removePrecopyImages(vm, snapshot)
+	if err != nil {
+		return
+	}
+	for _, attachedVolume := range vm.AttachedVolumes {
+		err = r.cleanupPrecopy(vm, attachedVolume.ID, snapshot)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -151,6 +215,20 @@ func (r *Client) Finalize(vmStatuses []*planapi.VMStatus, migrationName string)
 			r.Log.Error(err, "failed to find vm", "vm", vm.Name)
 			return
 		}
+		if vmStatus.Warm != nil {
+			for _, precopy := range vmStatus.Warm.Precopies {
+				err = r.removePrecopyImages(vm, precopy.Snapshot)
+				if err != nil {
+					r.Log.Error(err, "removing the precopy images", "vm", vm.Name, "precopy", precopy.Snapshot)
+					return
+				}
+			}
+			err = r.removePrecopyBackups(vm)
+			if err != nil {
+				r.Log.Error(err, "removing the precopy backups", "vm", vm.Name)
+				return
+			}
+		}
 		err = r.removeImagesFromVolumes(vm)
 		if err != nil {
 			r.Log.Error(err, "removing the images from volumes", "vm", vm.Name)
@@ -194,6 +272,11 @@ func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
 }
 
 func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
+	if r.Context.Plan.IsWarm() {
+		// The images are created by the precopies.
+		ready = true
+		return
+	}
 	vm, err := r.getVM(vmRef)
 	if err != nil {
 		err = liberr.Wrap(err, "vm", vmRef.String())
@@ -473,17 +556,10 @@ func (r *Client) createImageFromVolume(vm *libclient.VM, volumeID string) (image
 		err = liberr.Wrap(err)
 		return
 	}
-	// Workaround for https://bugs.launchpad.net/cinder/+bug/1945500
-	for key := range volume.VolumeImageMetadata {
-		if strings.HasPrefix(key, "os_glance") {
-			err = r.UnsetImageMetadata(volumeID, key)
-			if err != nil {
-				err = liberr.Wrap(err, "vm", vm.Name, "volumeID", volumeID, "key", key)
-				return
-			}
-		}
+	err = r.unsetGlanceMetadata(vm, volume)
+	if err != nil {
+		return
 	}
-	// end Workaround
 	imageName := getImageFromVolumeName(r.Context, vm.ID, volume.Metadata[forkliftPropertyOriginalVolumeID])
 	image, err = r.UploadImage(imageName, volume.ID)
 	if err != nil {
@@ -493,6 +569,20 @@ func (r *Client) createImageFromVolume(vm *libclient.VM, volumeID string) (image
 	return
 }
 
+// Workaround for https://bugs.launchpad.net/cinder/+bug/1945500
+func (r *Client) unsetGlanceMetadata(vm *libclient.VM, volume *libclient.Volume) (err error) {
+	for key := range volume.VolumeImageMetadata {
+		if strings.HasPrefix(key, "os_glance") {
+			err = r.UnsetImageMetadata(volume.ID, key)
+			if err != nil {
+				err = liberr.Wrap(err, "vm", vm.Name, "volumeID", volume.ID, "key", key)
+				return
+			}
+		}
+	}
+	return
+}
+
 // Create a image of the source VM.
 func (r *Client) createVmSnapshotImage(vm *libclient.VM) (vmImage *libclient.Image, err error) {
 	vmSnapshotImageName := getVmSnapshotName(r.Context, vm.ID)
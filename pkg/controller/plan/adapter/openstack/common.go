@@ -2,6 +2,7 @@ package openstack
 
 import (
 	"fmt"
+	"time"
 
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 )
@@ -34,3 +35,31 @@ func getImageFromVolumeName(ctx *plancontext.Context, vmID, volumeID string) str
 	const nameFormat = "%s-volume-%s"
 	return fmt.Sprintf(nameFormat, getVmSnapshotName(ctx, vmID), volumeID)
 }
+
+// Name identifying the snapshots, volumes, images and backups of a warm migration precopy.
+func getPrecopyName() string {
+	const nameFormat = "precopy-%d"
+	return fmt.Sprintf(nameFormat, time.Now().Unix())
+}
+
+func getPrecopySnapshotName(ctx *plancontext.Context, vmID, precopy string) string {
+	const nameFormat = "%s %s"
+	return fmt.Sprintf(nameFormat, getSnapshotFromVolumeName(ctx, vmID), precopy)
+}
+
+func getPrecopyVolumeName(ctx *plancontext.Context, vmID, precopy string) string {
+	const nameFormat = "%s %s"
+	return fmt.Sprintf(nameFormat, getVolumeFromSnapshotName(ctx, vmID, ""), precopy)
+}
+
+func getPrecopyImageName(ctx *plancontext.Context, vmID, volumeID, precopy string) string {
+	const nameFormat = "%s-%s"
+	return fmt.Sprintf(nameFormat, getImageFromVolumeName(ctx, vmID, volumeID), precopy)
+}
+
+// The backups are named after the migration so the incremental
+// backups are based only on the backups of the same migration.
+func getPrecopyBackupName(ctx *plancontext.Context, vmID, precopy string) string {
+	const nameFormat = "backup for %s %s %s"
+	return fmt.Sprintf(nameFormat, getVmSnapshotName(ctx, vmID), getMigrationID(ctx), precopy)
+}
@@ -104,7 +104,7 @@ func (r *DestinationClient) findPVCByCR(cr *v1beta1.OpenstackVolumePopulator) (p
 			Namespace: r.Plan.Spec.TargetNamespace,
 			LabelSelector: labels.SelectorFromSet(map[string]string{
 				"migration": string(r.Plan.Status.Migration.ActiveSnapshot().Migration.UID),
-				"imageID":   cr.Spec.ImageID,
+				"imageID":   cr.Labels["imageID"],
 			}),
 		})
 
@@ -114,11 +114,11 @@ func (r *DestinationClient) findPVCByCR(cr *v1beta1.OpenstackVolumePopulator) (p
 	}
 
 	if len(pvcList.Items) == 0 {
-		err = liberr.New("PVC not found", "imageID", cr.Spec.ImageID)
+		err = liberr.New("PVC not found", "imageID", cr.Labels["imageID"])
 		return
 	}
 	if len(pvcList.Items) > 1 {
-		err = liberr.New("Multiple PVCs found", "imageID", cr.Spec.ImageID)
+		err = liberr.New("Multiple PVCs found", "imageID", cr.Labels["imageID"])
 		return
 	}
 
@@ -0,0 +1,532 @@
+package openstack
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/openstack"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/settings"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Warm migration of volume based VMs.
+//
+// Each precopy takes a Cinder snapshot of the attached volumes
+// and backs it up. The first precopy takes a full backup and
+// uploads a volume created from the snapshot to an image, which
+// is transferred by the volume populator. The following precopies
+// take incremental backups holding only the extents changed since
+// the previous snapshot, which are written to the PVCs by the
+// populator in backup mode. The backups depend on each other so
+// they are kept until the migration ends.
+
+// Ensure the backups of a precopy and, for the first precopy,
+// the images are created and ready. Once they are ready, the
+// snapshots and the volumes created from them are removed.
+func (r *Client) ensurePrecopy(vm *libclient.VM, precopy string) (ready bool, err error) {
+	ready = true
+	for _, attachedVolume := range vm.AttachedVolumes {
+		var backup *libclient.Backup
+		backup, err = r.ensurePrecopyBackup(vm, attachedVolume.ID, precopy)
+		if err != nil {
+			return
+		}
+		if backup == nil {
+			ready = false
+			continue
+		}
+		if backup.IsIncremental {
+			continue
+		}
+		var imageReady bool
+		imageReady, err = r.ensurePrecopyImage(vm, attachedVolume.ID, precopy)
+		if err != nil {
+			return
+		}
+		if !imageReady {
+			ready = false
+		}
+	}
+	if !ready {
+		return
+	}
+	r.Log.Info("the precopy backups are ready", "vm", vm.Name, "precopy", precopy)
+	go func() {
+		// executing this in a non-blocking mode
+		for _, attachedVolume := range vm.AttachedVolumes {
+			err := r.cleanupPrecopy(vm, attachedVolume.ID, precopy)
+			if err != nil {
+				r.Log.Error(err, "failed to cleanup the precopy snapshot and volume",
+					"vm", vm.Name, "volumeId", attachedVolume.ID, "precopy", precopy)
+			}
+		}
+	}()
+	return
+}
+
+// Advance the backup of the precopy snapshot of a volume.
+// The backup is returned once available. The backup is full
+// when the migration has no previous backup of the volume,
+// incremental otherwise.
+func (r *Client) ensurePrecopyBackup(vm *libclient.VM, volumeID, precopy string) (backup *libclient.Backup, err error) {
+	found, err := r.getPrecopyBackup(vm, volumeID, precopy)
+	if err == nil {
+		switch found.Status {
+		case BackupStatusCreating:
+			r.Log.Info("the precopy backup is still being created",
+				"vm", vm.Name, "backup", found.Name)
+		case BackupStatusAvailable:
+			backup = found
+		default:
+			err = liberr.New("unexpected backup status")
+			r.Log.Error(err, "checking the precopy backup",
+				"vm", vm.Name, "backup", found.Name, "status", found.Status, "reason", found.FailReason)
+		}
+		return
+	}
+	if !errors.Is(err, ResourceNotFoundError) {
+		err = liberr.Wrap(err, "vm", vm.Name, "volumeID", volumeID)
+		return
+	}
+	snapshot, err := r.getPrecopySnapshot(vm, volumeID, precopy)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name, "volumeID", volumeID, "precopy", precopy)
+		return
+	}
+	switch snapshot.Status {
+	case SnapshotStatusCreating:
+		r.Log.Info("the precopy snapshot is still being created",
+			"vm", vm.Name, "snapshot", snapshot.Name)
+	case SnapshotStatusAvailable:
+		var previous []libclient.Backup
+		previous, err = r.getPrecopyBackups(vm, volumeID)
+		if err != nil {
+			return
+		}
+		r.Log.Info("creating the precopy backup from the snapshot",
+			"vm", vm.Name, "snapshot", snapshot.Name, "incremental", len(previous) > 0)
+		_, err = r.createPrecopyBackup(vm, snapshot, precopy, len(previous) > 0)
+	default:
+		err = liberr.New("unexpected snapshot status")
+		r.Log.Error(err, "checking the precopy snapshot",
+			"vm", vm.Name, "snapshot", snapshot.Name, "status", snapshot.Status)
+	}
+	return
+}
+
+func (r *Client) createPrecopyBackup(vm *libclient.VM, snapshot *libclient.Snapshot, precopy string, incremental bool) (backup *libclient.Backup, err error) {
+	opts := &libclient.BackupCreateOpts{
+		Name:        getPrecopyBackupName(r.Context, vm.ID, precopy),
+		VolumeID:    snapshot.VolumeID,
+		SnapshotID:  snapshot.ID,
+		Incremental: incremental,
+		Force:       true,
+	}
+	backup = &libclient.Backup{}
+	err = r.Create(backup, opts)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name, "snapshot", snapshot.Name)
+	}
+	return
+}
+
+func (r *Client) getPrecopyBackup(vm *libclient.VM, volumeID, precopy string) (backup *libclient.Backup, err error) {
+	backups := []libclient.Backup{}
+	opts := &libclient.BackupListOpts{
+		Name:     getPrecopyBackupName(r.Context, vm.ID, precopy),
+		VolumeID: volumeID,
+	}
+	err = r.List(&backups, opts)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(backups) == 0 {
+		err = ResourceNotFoundError
+		return
+	}
+	backup = &backups[0]
+	return
+}
+
+// List the precopy backups of a volume taken by the migration,
+// the latest first.
+func (r *Client) getPrecopyBackups(vm *libclient.VM, volumeID string) (backups []libclient.Backup, err error) {
+	all := []libclient.Backup{}
+	opts := &libclient.BackupListOpts{VolumeID: volumeID}
+	err = r.List(&all, opts)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	prefix := getPrecopyBackupName(r.Context, vm.ID, "")
+	for _, backup := range all {
+		if strings.HasPrefix(backup.Name, prefix) {
+			backups = append(backups, backup)
+		}
+	}
+	// The precopy names hold the creation time.
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name > backups[j].Name
+	})
+	return
+}
+
+// Remove the precopy backups of the VM, the latest first as a
+// backup cannot be removed while incremental backups depend on it.
+func (r *Client) removePrecopyBackups(vm *libclient.VM) (err error) {
+	for _, attachedVolume := range vm.AttachedVolumes {
+		var backups []libclient.Backup
+		backups, err = r.getPrecopyBackups(vm, attachedVolume.ID)
+		if err != nil {
+			return
+		}
+		for i := range backups {
+			err = r.removePrecopyBackup(vm, &backups[i])
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Remove a precopy backup and wait for it to be gone.
+func (r *Client) removePrecopyBackup(vm *libclient.VM, backup *libclient.Backup) (err error) {
+	if backup.Status != BackupStatusDeleting {
+		err = r.Delete(backup)
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vm.Name, "backup", backup.Name)
+			return
+		}
+	}
+	condition := func() (done bool, err error) {
+		err = r.Get(&libclient.Backup{}, backup.ID)
+		if r.IsNotFound(err) {
+			done = true
+			err = nil
+		}
+		return
+	}
+	backoff := wait.Backoff{
+		Duration: 3 * time.Second,
+		Factor:   1.5,
+		Steps:    settings.Settings.CleanupRetries,
+	}
+	err = wait.ExponentialBackoff(backoff, condition)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name, "backup", backup.Name)
+	}
+	return
+}
+
+// Advance the snapshot, volume and image of a precopy.
+func (r *Client) ensurePrecopyImage(vm *libclient.VM, volumeID, precopy string) (ready bool, err error) {
+	imageName := getPrecopyImageName(r.Context, vm.ID, volumeID, precopy)
+	image, err := r.getImage(ref.Ref{Name: imageName})
+	if err == nil {
+		switch image.Status {
+		case ImageStatusQueued, ImageStatusImporting, ImageStatusUploading, ImageStatusSaving:
+			r.Log.Info("the precopy image is still being processed",
+				"vm", vm.Name, "image", image.Name, "status", image.Status)
+		case ImageStatusActive:
+			if _, found := image.Properties[forkliftPropertyOriginalVolumeID]; !found {
+				imageUpdateOpts := &libclient.ImageUpdateOpts{}
+				imageUpdateOpts.AddImageProperty(forkliftPropertyOriginalVolumeID, volumeID)
+				err = r.Update(image, imageUpdateOpts)
+				if err != nil {
+					err = liberr.Wrap(err, "vm", vm.Name, "image", image.Name)
+				}
+				return
+			}
+			ready, err = r.ensureImageUpToDate(vm, image, vmTypeVolumeBased)
+		default:
+			err = liberr.New("unexpected image status")
+			r.Log.Error(err, "checking the precopy image",
+				"vm", vm.Name, "image", image.Name, "status", image.Status)
+		}
+		return
+	}
+	if !errors.Is(err, ResourceNotFoundError) {
+		err = liberr.Wrap(err, "vm", vm.Name, "image", imageName)
+		return
+	}
+	volume, err := r.getPrecopyVolume(vm, volumeID, precopy)
+	if err == nil {
+		switch volume.Status {
+		case VolumeStatusCreating, VolumeStatusUploading:
+			r.Log.Info("the precopy volume is not ready yet",
+				"vm", vm.Name, "volume", volume.Name, "status", volume.Status)
+		case VolumeStatusAvailable:
+			r.Log.Info("creating the precopy image from the volume",
+				"vm", vm.Name, "volume", volume.Name)
+			_, err = r.createPrecopyImage(vm, volume, volumeID, precopy)
+		default:
+			err = UnexpectedVolumeStatusError
+			r.Log.Error(err, "checking the precopy volume",
+				"vm", vm.Name, "volume", volume.Name, "status", volume.Status)
+		}
+		return
+	}
+	if !errors.Is(err, ResourceNotFoundError) {
+		err = liberr.Wrap(err, "vm", vm.Name, "volumeID", volumeID)
+		return
+	}
+	snapshot, err := r.getPrecopySnapshot(vm, volumeID, precopy)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name, "volumeID", volumeID, "precopy", precopy)
+		return
+	}
+	switch snapshot.Status {
+	case SnapshotStatusCreating:
+		r.Log.Info("the precopy snapshot is still being created",
+			"vm", vm.Name, "snapshot", snapshot.Name)
+	case SnapshotStatusAvailable:
+		r.Log.Info("creating the precopy volume from the snapshot",
+			"vm", vm.Name, "snapshot", snapshot.Name)
+		_, err = r.createPrecopyVolume(vm, snapshot, precopy)
+	default:
+		err = liberr.New("unexpected snapshot status")
+		r.Log.Error(err, "checking the precopy snapshot",
+			"vm", vm.Name, "snapshot", snapshot.Name, "status", snapshot.Status)
+	}
+	return
+}
+
+func (r *Client) createPrecopySnapshot(vm *libclient.VM, volumeID, precopy string) (snapshot *libclient.Snapshot, err error) {
+	opts := &libclient.SnapshotCreateOpts{}
+	opts.Name = getPrecopySnapshotName(r.Context, vm.ID, precopy)
+	opts.VolumeID = volumeID
+	opts.Force = true
+	opts.Metadata = map[string]string{
+		forkliftPropertyOriginalVolumeID: volumeID,
+	}
+	snapshot = &libclient.Snapshot{}
+	err = r.Create(snapshot, opts)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name, "volumeID", volumeID)
+	}
+	return
+}
+
+func (r *Client) getPrecopySnapshot(vm *libclient.VM, volumeID, precopy string) (snapshot *libclient.Snapshot, err error) {
+	snapshots := []libclient.Snapshot{}
+	opts := libclient.SnapshotListOpts{}
+	opts.Name = getPrecopySnapshotName(r.Context, vm.ID, precopy)
+	opts.VolumeID = volumeID
+	opts.Limit = 1
+	err = r.List(&snapshots, &opts)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(snapshots) == 0 {
+		err = ResourceNotFoundError
+		return
+	}
+	snapshot = &snapshots[0]
+	return
+}
+
+func (r *Client) createPrecopyVolume(vm *libclient.VM, snapshot *libclient.Snapshot, precopy string) (volume *libclient.Volume, err error) {
+	opts := &libclient.VolumeCreateOpts{}
+	opts.Name = getPrecopyVolumeName(r.Context, vm.ID, precopy)
+	opts.SnapshotID = snapshot.ID
+	opts.Metadata = map[string]string{
+		forkliftPropertyOriginalVolumeID: snapshot.VolumeID,
+	}
+	volume = &libclient.Volume{}
+	err = r.Create(volume, opts)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name, "snapshot", snapshot.Name)
+	}
+	return
+}
+
+func (r *Client) getPrecopyVolume(vm *libclient.VM, volumeID, precopy string) (volume *libclient.Volume, err error) {
+	volumes := []libclient.Volume{}
+	opts := libclient.VolumeListOpts{}
+	opts.Name = getPrecopyVolumeName(r.Context, vm.ID, precopy)
+	opts.Metadata = map[string]string{
+		forkliftPropertyOriginalVolumeID: volumeID,
+	}
+	opts.Limit = 1
+	err = r.List(&volumes, &opts)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(volumes) == 0 {
+		err = ResourceNotFoundError
+		return
+	}
+	volume = &volumes[0]
+	return
+}
+
+func (r *Client) createPrecopyImage(vm *libclient.VM, volume *libclient.Volume, volumeID, precopy string) (image *libclient.Image, err error) {
+	err = r.unsetGlanceMetadata(vm, volume)
+	if err != nil {
+		return
+	}
+	imageName := getPrecopyImageName(r.Context, vm.ID, volumeID, precopy)
+	image, err = r.UploadImage(imageName, volume.ID)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name, "volume", volume.Name)
+	}
+	return
+}
+
+// Remove the volume created from the precopy snapshot, then the snapshot.
+func (r *Client) cleanupPrecopy(vm *libclient.VM, volumeID, precopy string) (err error) {
+	volume, err := r.getPrecopyVolume(vm, volumeID, precopy)
+	switch {
+	case err == nil:
+		if volume.Status == VolumeStatusAvailable {
+			err = r.Delete(volume)
+			if err != nil {
+				err = liberr.Wrap(err, "vm", vm.Name, "volume", volume.Name)
+				return
+			}
+		}
+		condition := func() (done bool, err error) {
+			_, err = r.getPrecopyVolume(vm, volumeID, precopy)
+			if errors.Is(err, ResourceNotFoundError) {
+				done = true
+				err = nil
+			}
+			return
+		}
+		backoff := wait.Backoff{
+			Duration: 3 * time.Second,
+			Factor:   1.5,
+			Steps:    settings.Settings.CleanupRetries,
+		}
+		err = wait.ExponentialBackoff(backoff, condition)
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vm.Name, "volume", volume.Name)
+			return
+		}
+	case !errors.Is(err, ResourceNotFoundError):
+		return
+	}
+	snapshot, err := r.getPrecopySnapshot(vm, volumeID, precopy)
+	if err != nil {
+		if errors.Is(err, ResourceNotFoundError) {
+			err = nil
+		}
+		return
+	}
+	if snapshot.Status == SnapshotStatusAvailable {
+		err = r.Delete(snapshot)
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vm.Name, "snapshot", snapshot.Name)
+		}
+	}
+	return
+}
+
+// Remove the images of a precopy.
+func (r *Client) removePrecopyImages(vm *libclient.VM, precopy string) (err error) {
+	for _, attachedVolume := range vm.AttachedVolumes {
+		imageName := getPrecopyImageName(r.Context, vm.ID, attachedVolume.ID, precopy)
+		var image *libclient.Image
+		image, err = r.getImage(ref.Ref{Name: imageName})
+		if err != nil {
+			if errors.Is(err, ResourceNotFoundError) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err, "vm", vm.Name, "image", imageName)
+			return
+		}
+		if image.Status != ImageStatusActive {
+			r.Log.Info("unexpected image status when removing the precopy image, the image will remain",
+				"vm", vm.Name, "image", image.Name, "status", image.Status)
+			continue
+		}
+		err = r.Delete(image)
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vm.Name, "image", image.Name)
+			return
+		}
+	}
+	return
+}
+
+// Set the backups of the latest and the previous precopies on the
+// volume populators of the VM. The populators are not run again once
+// the PVCs are bound, the backups are read by the pods applying the
+// extents changed since the previous precopy.
+func (r *Client) setPopulatorBackups(vm *libclient.VM, precopy, previous string) (err error) {
+	pvcs := &core.PersistentVolumeClaimList{}
+	err = r.Context.Destination.Client.List(
+		context.TODO(),
+		pvcs,
+		&client.ListOptions{
+			Namespace: r.Context.Plan.Spec.TargetNamespace,
+			LabelSelector: labels.SelectorFromSet(map[string]string{
+				"migration": getMigrationID(r.Context),
+				"vmID":      vm.ID,
+			}),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, pvc := range pvcs.Items {
+		volumeID, found := pvc.Annotations[planbase.AnnDiskSource]
+		if !found || pvc.Spec.DataSourceRef == nil {
+			continue
+		}
+		var backup, parent *libclient.Backup
+		backup, err = r.getPrecopyBackup(vm, volumeID, precopy)
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vm.Name, "volumeID", volumeID, "precopy", precopy)
+			return
+		}
+		parent, err = r.getPrecopyBackup(vm, volumeID, previous)
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vm.Name, "volumeID", volumeID, "precopy", previous)
+			return
+		}
+		populatorCr := &api.OpenstackVolumePopulator{}
+		err = r.Context.Destination.Client.Get(
+			context.TODO(),
+			types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Spec.DataSourceRef.Name},
+			populatorCr)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if populatorCr.Annotations[planbase.AnnPrecopyBackup] == backup.ID &&
+			populatorCr.Annotations[planbase.AnnPrecopyParentBackup] == parent.ID {
+			continue
+		}
+		populatorCrCopy := populatorCr.DeepCopy()
+		if populatorCr.Annotations == nil {
+			populatorCr.Annotations = make(map[string]string)
+		}
+		populatorCr.Annotations[planbase.AnnPrecopyBackup] = backup.ID
+		populatorCr.Annotations[planbase.AnnPrecopyParentBackup] = parent.ID
+		err = r.Context.Destination.Client.Patch(context.TODO(), populatorCr, client.MergeFrom(populatorCrCopy))
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info("set the precopy backups on the volume populator",
+			"vm", vm.Name, "populator", populatorCr.Name, "backup", backup.Name)
+	}
+	return
+}
@@ -2,6 +2,7 @@ package openstack
 
 import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
@@ -89,6 +90,38 @@ func (r *Validator) InvalidDiskSizes(vmRef ref.Ref) ([]string, error) {
 	return invalidDisks, nil
 }
 
+// Resources required by the VM on the destination.
+func (r *Validator) Requirements(vmRef ref.Ref) (requirements *planbase.Requirements, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	requirements = &planbase.Requirements{
+		CPU: int64(vm.Flavor.VCPUs),
+		// RAM in MiB.
+		Memory: int64(vm.Flavor.RAM) << 20,
+	}
+	for _, volume := range vm.Volumes {
+		storage := ref.Ref{Name: volume.VolumeType}
+		for _, volumeType := range vm.VolumeTypes {
+			if volumeType.Name == volume.VolumeType {
+				storage.ID = volumeType.ID
+				break
+			}
+		}
+		requirements.Disks = append(
+			requirements.Disks,
+			planapi.DiskFootprint{
+				Storage: storage,
+				// Size in GiB.
+				Capacity: int64(volume.Size) << 30,
+			})
+	}
+	return
+}
+
 func (r *Validator) MacConflicts(vmRef ref.Ref) ([]planbase.MacConflict, error) {
 	// Get source VM using common helper
 	vm, err := planbase.FindSourceVM[model.Workload](r.Source.Inventory, vmRef)
@@ -141,7 +174,7 @@ func (r *Validator) HasSnapshot(vmRef ref.Ref) (ok bool, msg string, category st
 
 // Validate whether warm migration is supported from this provider type.
 func (r *Validator) WarmMigration() (ok bool) {
-	ok = false
+	ok = true
 	return
 }
 
@@ -149,7 +182,7 @@ func (r *Validator) WarmMigration() (ok bool) {
 // is supported by this provider.
 func (r *Validator) MigrationType() bool {
 	switch r.Plan.Spec.Type {
-	case api.MigrationCold, "":
+	case api.MigrationCold, api.MigrationWarm, "":
 		return true
 	default:
 		return false
@@ -192,8 +225,21 @@ func (r *Validator) PowerState(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// Validate that the VM can be migrated with the plan's migration type.
+// Warm migration snapshots the Cinder volumes so the VM must not be
+// booted from an image.
 func (r *Validator) VMMigrationType(vmRef ref.Ref) (ok bool, err error) {
-	ok = true
+	if !r.Plan.IsWarm() {
+		ok = true
+		return
+	}
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	ok = vm.ImageID == ""
 	return
 }
 
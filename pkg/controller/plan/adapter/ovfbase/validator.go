@@ -2,6 +2,7 @@ package ovfbase
 
 import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
@@ -55,6 +56,29 @@ func (r *Validator) InvalidDiskSizes(vmRef ref.Ref) ([]string, error) {
 	return invalidDisks, nil
 }
 
+// Resources required by the VM on the destination.
+func (r *Validator) Requirements(vmRef ref.Ref) (requirements *planbase.Requirements, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	requirements = &planbase.Requirements{
+		CPU:    int64(vm.CpuCount),
+		Memory: int64(vm.MemoryMB) << 20,
+	}
+	for _, disk := range vm.Disks {
+		requirements.Disks = append(
+			requirements.Disks,
+			planapi.DiskFootprint{
+				Storage:  ref.Ref{ID: disk.ID, Name: disk.Name},
+				Capacity: disk.Capacity,
+			})
+	}
+	return
+}
+
 func (r *Validator) MacConflicts(vmRef ref.Ref) ([]planbase.MacConflict, error) {
 	// Get source VM using common helper
 	vm, err := planbase.FindSourceVM[model.VM](r.Source.Inventory, vmRef)
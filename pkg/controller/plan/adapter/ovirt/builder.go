@@ -752,6 +752,13 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		return
 	}
 
+	disks := 0
+	for _, diskAttachment := range workload.DiskAttachments {
+		if diskAttachment.Disk.StorageType != "lun" {
+			disks++
+		}
+	}
+	rate := r.Plan.DiskTransferRateLimit(disks)
 	var sdToStorageClass map[string]string
 	for _, diskAttachment := range workload.DiskAttachments {
 		if diskAttachment.Disk.StorageType == "lun" {
@@ -764,7 +771,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 				return
 			}
 			var populatorName string
-			populatorName, err = r.createVolumePopulatorCR(diskAttachment, secretName, vmRef.ID)
+			populatorName, err = r.createVolumePopulatorCR(diskAttachment, secretName, vmRef.ID, rate)
 			if err != nil {
 				err = liberr.Wrap(err)
 				return
@@ -832,7 +839,7 @@ func (r *Builder) getVolumePopulator(diskID string) (populatorCr api.OvirtVolume
 	return
 }
 
-func (r *Builder) createVolumePopulatorCR(diskAttachment model.XDiskAttachment, secretName, vmId string) (name string, err error) {
+func (r *Builder) createVolumePopulatorCR(diskAttachment model.XDiskAttachment, secretName, vmId string, rate int) (name string, err error) {
 	migrationId := string(r.Migration.UID)
 	providerURL, err := url.Parse(r.Source.Provider.Spec.URL)
 	if err != nil {
@@ -854,10 +861,11 @@ func (r *Builder) createVolumePopulatorCR(diskAttachment model.XDiskAttachment,
 			},
 		},
 		Spec: api.OvirtVolumePopulatorSpec{
-			EngineURL:        engineURL.String(),
-			EngineSecretName: secretName,
-			DiskID:           diskAttachment.Disk.ID,
-			TransferNetwork:  r.Plan.Spec.TransferNetwork,
+			EngineURL:         engineURL.String(),
+			EngineSecretName:  secretName,
+			DiskID:            diskAttachment.Disk.ID,
+			TransferNetwork:   r.Plan.Spec.TransferNetwork,
+			TransferRateLimit: rate,
 		},
 	}
 	err = r.Context.Client.Create(context.TODO(), populatorCR, &client.CreateOptions{})
@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
@@ -52,6 +53,33 @@ func (r *Validator) InvalidDiskSizes(vmRef ref.Ref) ([]string, error) {
 	return invalidDisks, nil
 }
 
+// Resources required by the VM on the destination.
+// Direct LUNs are attached and not copied.
+func (r *Validator) Requirements(vmRef ref.Ref) (requirements *planbase.Requirements, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	requirements = &planbase.Requirements{
+		CPU:    int64(vm.CpuSockets) * int64(vm.CpuCores) * int64(vm.CpuThreads),
+		Memory: vm.Memory,
+	}
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.IsLun() {
+			continue
+		}
+		requirements.Disks = append(
+			requirements.Disks,
+			planapi.DiskFootprint{
+				Storage:  ref.Ref{ID: da.Disk.StorageDomain},
+				Capacity: da.Disk.ProvisionedSize,
+			})
+	}
+	return
+}
+
 // NO-OP
 func (r *Validator) UdnStaticIPs(vmRef ref.Ref, client client.Client) (ok bool, err error) {
 	return true, nil
@@ -545,7 +545,7 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 			context.TODO(),
 			pvcs,
 			&client.ListOptions{
-				Namespace:     r.Plan.Spec.TargetNamespace,
+				Namespace:     r.Plan.VMTargetNamespace(vmRef),
 				LabelSelector: labels.SelectorFromSet(pvcLabels),
 			},
 		)
@@ -687,7 +687,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 		return
 	}
 	if !r.Context.Plan.Spec.MigrateSharedDisks {
-		sharedPVCs, missingDiskPVCs, err := findSharedPVCs(r.Destination.Client, vm, r.Plan.Spec.TargetNamespace)
+		sharedPVCs, missingDiskPVCs, err := findSharedPVCs(r.Destination.Client, vm, r.Plan.VMTargetNamespace(vmRef))
 		if err != nil {
 			return liberr.Wrap(err)
 		}
@@ -1339,7 +1339,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		pvcList,
 		&client.ListOptions{
 			LabelSelector: labels.SelectorFromSet(pvcLabels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vmRef),
 		})
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -1394,7 +1394,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 						"the offload plugin configuration has missing details, cannot continue with PVC and populator resource creation")
 				}
 
-				namespace := r.Plan.Spec.TargetNamespace
+				namespace := r.Plan.VMTargetNamespace(vmRef)
 				labels := map[string]string{
 					"migration": string(r.Migration.UID),
 					// we need uniqness and a value which is less than 64 chars, hence using vmRef.id + disk.key
@@ -1547,7 +1547,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		if len(pvcs) > 0 {
 			secret := &core.Secret{}
 			err = r.Destination.Client.Get(context.TODO(), client.ObjectKey{
-				Namespace: r.Plan.Spec.TargetNamespace,
+				Namespace: r.Plan.VMTargetNamespace(vmRef),
 				Name:      secretName,
 			}, secret)
 			if err != nil {
@@ -1576,7 +1576,7 @@ func (r *Builder) PrePopulateActions(c planbase.Client, vmRef ref.Ref) (ready bo
 func (r *Builder) PopulatorTransferredBytes(pvc *core.PersistentVolumeClaim) (transferredBytes int64, err error) {
 	vmdkKey := pvc.Labels["vmdkKey"]
 	vmId := pvc.Labels["vmID"]
-	populatorCr, err := r.getVolumePopulator(vmId, vmdkKey)
+	populatorCr, err := r.getVolumePopulator(pvc.Namespace, vmId, vmdkKey)
 	if err != nil {
 		return
 	}
@@ -1595,10 +1595,10 @@ func (r *Builder) PopulatorTransferredBytes(pvc *core.PersistentVolumeClaim) (tr
 	return
 }
 
-func (r *Builder) getVolumePopulator(vmId, vmdkKey string) (api.VSphereXcopyVolumePopulator, error) {
+func (r *Builder) getVolumePopulator(namespace, vmId, vmdkKey string) (api.VSphereXcopyVolumePopulator, error) {
 	list := api.VSphereXcopyVolumePopulatorList{}
 	err := r.Destination.Client.List(context.TODO(), &list, &client.ListOptions{
-		Namespace: r.Plan.Spec.TargetNamespace,
+		Namespace: namespace,
 		LabelSelector: labels.SelectorFromSet(map[string]string{
 			"migration": string(r.Migration.UID),
 			"vmdkKey":   vmdkKey,
@@ -1612,7 +1612,7 @@ func (r *Builder) getVolumePopulator(vmId, vmdkKey string) (api.VSphereXcopyVolu
 		return api.VSphereXcopyVolumePopulator{},
 			liberr.New(
 				"No VSphereXcopyVolumePopulator CR found - populator may not have been created or was deleted",
-				"namespace", r.Plan.Spec.TargetNamespace,
+				"namespace", namespace,
 				"migration", string(r.Migration.UID),
 				"vmID", vmId,
 				"vmdkKey", vmdkKey)
@@ -1625,7 +1625,7 @@ func (r *Builder) getVolumePopulator(vmId, vmdkKey string) (api.VSphereXcopyVolu
 		return api.VSphereXcopyVolumePopulator{},
 			liberr.New(
 				"Multiple VSphereXcopyVolumePopulator CRs found for the same VMDK disk",
-				"namespace", r.Plan.Spec.TargetNamespace,
+				"namespace", namespace,
 				"migration", string(r.Migration.UID),
 				"vmID", vmId,
 				"vmdkKey", vmdkKey,
@@ -67,25 +67,28 @@ func (r *DestinationClient) getPopulatorCrList() (populatorCrList v1beta1.VSpher
 		return v1beta1.VSphereXcopyVolumePopulatorList{}, err
 	}
 	migUID := string(snap.Migration.UID)
-	r.Log.Info("Getting populator CR list",
-		"namespace", r.Plan.Spec.TargetNamespace,
-		"migrationUID", migUID)
-
 	populatorCrList = v1beta1.VSphereXcopyVolumePopulatorList{}
-	err = r.Destination.Client.List(
-		context.TODO(),
-		&populatorCrList,
-		&client.ListOptions{
-			Namespace:     r.Plan.Spec.TargetNamespace,
-			LabelSelector: labels.SelectorFromSet(map[string]string{"migration": migUID}),
-		})
-
-	if err != nil {
-		r.Log.Error(err, "Failed to list populator CRs")
-	} else {
-		r.Log.Info("Successfully listed populator CRs", "count", len(populatorCrList.Items))
+	for _, namespace := range r.Plan.TargetNamespaces() {
+		r.Log.Info("Getting populator CR list",
+			"namespace", namespace,
+			"migrationUID", migUID)
+		list := v1beta1.VSphereXcopyVolumePopulatorList{}
+		err = r.Destination.Client.List(
+			context.TODO(),
+			&list,
+			&client.ListOptions{
+				Namespace:     namespace,
+				LabelSelector: labels.SelectorFromSet(map[string]string{"migration": migUID}),
+			})
+		if err != nil {
+			r.Log.Error(err, "Failed to list populator CRs")
+			return populatorCrList, err
+		}
+		populatorCrList.Items = append(populatorCrList.Items, list.Items...)
 	}
 
+	r.Log.Info("Successfully listed populator CRs", "count", len(populatorCrList.Items))
+
 	return populatorCrList, err
 }
 
@@ -130,7 +133,7 @@ func (r *DestinationClient) findPVCByCR(cr *v1beta1.VSphereXcopyVolumePopulator)
 	r.Log.Info("Finding PVC for populator CR",
 		"populatorName", cr.Name,
 		"vmdkPath", cr.Spec.VmdkPath,
-		"namespace", r.Plan.Spec.TargetNamespace,
+		"namespace", cr.Namespace,
 		"migrationUID", migUID)
 
 	pvcList := core.PersistentVolumeClaimList{}
@@ -138,7 +141,7 @@ func (r *DestinationClient) findPVCByCR(cr *v1beta1.VSphereXcopyVolumePopulator)
 		context.TODO(),
 		&pvcList,
 		&client.ListOptions{
-			Namespace: r.Plan.Spec.TargetNamespace,
+			Namespace: cr.Namespace,
 			LabelSelector: labels.SelectorFromSet(map[string]string{
 				"migration": migUID,
 			}),
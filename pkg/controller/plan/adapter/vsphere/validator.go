@@ -264,6 +264,29 @@ func (r *Validator) InvalidDiskSizes(vmRef ref.Ref) ([]string, error) {
 	return invalidDisks, nil
 }
 
+// Resources required by the VM on the destination.
+func (r *Validator) Requirements(vmRef ref.Ref) (requirements *planbase.Requirements, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	requirements = &planbase.Requirements{
+		CPU:    int64(vm.CpuCount),
+		Memory: int64(vm.MemoryMB) << 20,
+	}
+	for _, disk := range vm.Disks {
+		requirements.Disks = append(
+			requirements.Disks,
+			plan.DiskFootprint{
+				Storage:  ref.Ref{ID: disk.Datastore.ID},
+				Capacity: disk.Capacity,
+			})
+	}
+	return
+}
+
 func (r *Validator) MacConflicts(vmRef ref.Ref) ([]planbase.MacConflict, error) {
 	// Get source VM using common helper
 	vm, err := planbase.FindSourceVM[model.VM](r.Source.Inventory, vmRef)
@@ -323,7 +346,7 @@ func (r *Validator) SharedDisks(vmRef ref.Ref, client client.Client) (ok bool, m
 
 	// Check existing PVCs
 	if !r.Plan.Spec.MigrateSharedDisks {
-		_, missingDiskPVCs, err := findSharedPVCs(client, vm, r.Plan.Spec.TargetNamespace)
+		_, missingDiskPVCs, err := findSharedPVCs(client, vm, r.Plan.PlacedNamespace(vmRef))
 		if err != nil {
 			return false, "", "", liberr.Wrap(err, "vm", vm)
 		}
@@ -333,12 +356,12 @@ func (r *Validator) SharedDisks(vmRef ref.Ref, client client.Client) (ok bool, m
 				missingDiskNames = append(missingDiskNames, disk.File)
 			}
 			msg = fmt.Sprintf("Missing shared disks PVC %s in namespace '%s', the VMs can be migrated but the disk will not be attached",
-				stringifyWithQuotes(missingDiskNames), r.Plan.Spec.TargetNamespace)
+				stringifyWithQuotes(missingDiskNames), r.Plan.PlacedNamespace(vmRef))
 			return false, msg, validation.Warn, nil
 		}
 	} else {
 		// Find duplicate already shared disk
-		sharedPVCs, _, err := findSharedPVCs(client, vm, r.Plan.Spec.TargetNamespace)
+		sharedPVCs, _, err := findSharedPVCs(client, vm, r.Plan.PlacedNamespace(vmRef))
 		if err != nil {
 			return false, "", "", liberr.Wrap(err, "vm", vm)
 		}
@@ -348,7 +371,7 @@ func (r *Validator) SharedDisks(vmRef ref.Ref, client client.Client) (ok bool, m
 				alreadyExistingPvc = append(alreadyExistingPvc, pvc.Annotations[planbase.AnnDiskSource])
 			}
 			msg = fmt.Sprintf("Already existing shared disks PVCs %s in namespace '%s', the VMs can be migrated but the disk will be duplicated",
-				stringifyWithQuotes(alreadyExistingPvc), r.Plan.Spec.TargetNamespace)
+				stringifyWithQuotes(alreadyExistingPvc), r.Plan.PlacedNamespace(vmRef))
 			return false, msg, validation.Warn, nil
 		}
 
@@ -394,9 +417,9 @@ func (r *Validator) SharedDisks(vmRef ref.Ref, client client.Client) (ok bool, m
 	return true, "", "", nil
 }
 
-func (r *Validator) getUdnSubnet(client client.Client) (string, error) {
+func (r *Validator) getUdnSubnet(client client.Client, targetNamespace string) (string, error) {
 	key := k8sclient.ObjectKey{
-		Name: r.Plan.Spec.TargetNamespace,
+		Name: targetNamespace,
 	}
 	namespace := &core.Namespace{}
 	err := client.Get(context.TODO(), key, namespace)
@@ -410,7 +433,7 @@ func (r *Validator) getUdnSubnet(client client.Client) (string, error) {
 
 	nadList := &k8snet.NetworkAttachmentDefinitionList{}
 	listOpts := []k8sclient.ListOption{
-		k8sclient.InNamespace(r.Plan.Spec.TargetNamespace),
+		k8sclient.InNamespace(targetNamespace),
 		k8sclient.MatchingLabels{nadLabelUDN: ""},
 	}
 
@@ -482,7 +505,7 @@ func (r *Validator) UdnStaticIPs(vmRef ref.Ref, client client.Client) (ok bool,
 		return
 	}
 
-	udnSubnet, err := r.getUdnSubnet(client)
+	udnSubnet, err := r.getUdnSubnet(client, r.Plan.PlacedNamespace(vmRef))
 	if udnSubnet == "" {
 		// No UDN subnet configured, validation passes
 		return true, nil
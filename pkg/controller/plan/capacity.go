@@ -0,0 +1,336 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Suffixes of the storage class scoped quota resources.
+const (
+	StorageClassQuotaStorage = ".storageclass.storage.k8s.io/requests.storage"
+	StorageClassQuotaClaims  = ".storageclass.storage.k8s.io/persistentvolumeclaims"
+)
+
+// Capacity required on the destination by the VMs of a plan.
+type capacityDemand struct {
+	// Storage in bytes by storage class, including overhead.
+	storage map[string]int64
+	// Claims by storage class.
+	claims map[string]int64
+	// Largest disk in bytes by storage class, including overhead.
+	disk map[string]int64
+	// Total virtual CPUs.
+	cpu int64
+	// Total memory in bytes.
+	memory int64
+	// Largest VM memory in bytes.
+	vmMemory int64
+}
+
+// Build an empty demand.
+func newCapacityDemand() *capacityDemand {
+	return &capacityDemand{
+		storage: make(map[string]int64),
+		claims:  make(map[string]int64),
+		disk:    make(map[string]int64),
+	}
+}
+
+// Add the requirements of a VM.
+// Disks backed by unmapped storage are ignored, they are reported
+// by the storage map validation. The filesystem or block overhead
+// is added according to the mapped volume mode.
+func (r *capacityDemand) add(storageMap *api.StorageMap, requirements *planbase.Requirements) {
+	for _, disk := range requirements.Disks {
+		destination, found := destinationStorage(storageMap, disk.Storage)
+		if !found {
+			continue
+		}
+		volumeMode := destination.VolumeMode
+		if volumeMode == "" {
+			volumeMode = core.PersistentVolumeFilesystem
+		}
+		size := util.CalculateSpaceWithOverhead(disk.Capacity, &volumeMode)
+		class := destination.StorageClass
+		r.storage[class] += size
+		r.claims[class]++
+		if size > r.disk[class] {
+			r.disk[class] = size
+		}
+	}
+	r.cpu += requirements.CPU
+	r.memory += requirements.Memory
+	if requirements.Memory > r.vmMemory {
+		r.vmMemory = requirements.Memory
+	}
+}
+
+// Quota resources requested by the demand.
+// The guest memory is a lower bound of the memory requested
+// by the virt-launcher pods. CPU requests depend on the KubeVirt
+// CPU allocation ratio so only the CPU limits are checked.
+func (r *capacityDemand) quotaRequests() (requests core.ResourceList) {
+	requests = core.ResourceList{}
+	var storage, claims int64
+	for class, bytes := range r.storage {
+		storage += bytes
+		claims += r.claims[class]
+		if class == "" {
+			continue
+		}
+		requests[core.ResourceName(class+StorageClassQuotaStorage)] = *resource.NewQuantity(bytes, resource.BinarySI)
+		requests[core.ResourceName(class+StorageClassQuotaClaims)] = *resource.NewQuantity(r.claims[class], resource.DecimalSI)
+	}
+	if claims > 0 {
+		requests[core.ResourceRequestsStorage] = *resource.NewQuantity(storage, resource.BinarySI)
+		requests[core.ResourcePersistentVolumeClaims] = *resource.NewQuantity(claims, resource.DecimalSI)
+	}
+	if r.memory > 0 {
+		memory := *resource.NewQuantity(r.memory, resource.BinarySI)
+		requests[core.ResourceMemory] = memory
+		requests[core.ResourceRequestsMemory] = memory
+		requests[core.ResourceLimitsMemory] = memory
+	}
+	if r.cpu > 0 {
+		requests[core.ResourceLimitsCPU] = *resource.NewQuantity(r.cpu, resource.DecimalSI)
+	}
+	return
+}
+
+// Find the resource quotas that cannot fit the demand.
+func (r *capacityDemand) quotaShortfalls(quotas []core.ResourceQuota) (shortfalls []string) {
+	requests := r.quotaRequests()
+	names := []string{}
+	for name := range requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, quota := range quotas {
+		hard := quota.Status.Hard
+		if len(hard) == 0 {
+			hard = quota.Spec.Hard
+		}
+		for _, name := range names {
+			limit, found := hard[core.ResourceName(name)]
+			if !found {
+				continue
+			}
+			requested := requests[core.ResourceName(name)]
+			available := limit.DeepCopy()
+			if used, found := quota.Status.Used[core.ResourceName(name)]; found {
+				available.Sub(used)
+			}
+			if requested.Cmp(available) > 0 {
+				shortfalls = append(
+					shortfalls,
+					fmt.Sprintf(
+						"ResourceQuota %s: %s requested %s, available %s.",
+						quota.Name,
+						name,
+						requested.String(),
+						available.String()))
+			}
+		}
+	}
+	return
+}
+
+// Find the limit ranges that reject a disk or a VM.
+func (r *capacityDemand) limitShortfalls(limitRanges []core.LimitRange) (shortfalls []string) {
+	classes := []string{}
+	for class := range r.disk {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			switch item.Type {
+			case core.LimitTypePersistentVolumeClaim:
+				limit, found := item.Max[core.ResourceStorage]
+				if !found {
+					continue
+				}
+				for _, class := range classes {
+					requested := resource.NewQuantity(r.disk[class], resource.BinarySI)
+					if requested.Cmp(limit) > 0 {
+						shortfalls = append(
+							shortfalls,
+							fmt.Sprintf(
+								"LimitRange %s: disk of %s in storage class %q exceeds the maximum %s.",
+								limitRange.Name,
+								requested.String(),
+								class,
+								limit.String()))
+					}
+				}
+			case core.LimitTypeContainer, core.LimitTypePod:
+				limit, found := item.Max[core.ResourceMemory]
+				if !found || r.vmMemory == 0 {
+					continue
+				}
+				requested := resource.NewQuantity(r.vmMemory, resource.BinarySI)
+				if requested.Cmp(limit) > 0 {
+					shortfalls = append(
+						shortfalls,
+						fmt.Sprintf(
+							"LimitRange %s: VM memory of %s exceeds the %s maximum %s.",
+							limitRange.Name,
+							requested.String(),
+							item.Type,
+							limit.String()))
+				}
+			}
+		}
+	}
+	return
+}
+
+// Find the storage classes without enough capacity.
+// The capacity reported by the CSI drivers for all topology
+// segments is summed, the demand cannot be satisfied when it
+// exceeds the total. Classes without capacity information
+// are not checked.
+func (r *capacityDemand) storageShortfalls(capacities []storagev1.CSIStorageCapacity) (shortfalls []string) {
+	available := make(map[string]*resource.Quantity)
+	for i := range capacities {
+		capacity := &capacities[i]
+		if capacity.Capacity == nil {
+			continue
+		}
+		total, found := available[capacity.StorageClassName]
+		if !found {
+			total = resource.NewQuantity(0, resource.BinarySI)
+			available[capacity.StorageClassName] = total
+		}
+		total.Add(*capacity.Capacity)
+	}
+	classes := []string{}
+	for class := range r.storage {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		total, found := available[class]
+		if !found {
+			continue
+		}
+		requested := resource.NewQuantity(r.storage[class], resource.BinarySI)
+		if requested.Cmp(*total) > 0 {
+			shortfalls = append(
+				shortfalls,
+				fmt.Sprintf(
+					"StorageClass %s: requested %s, available %s.",
+					class,
+					requested.String(),
+					total.String()))
+		}
+	}
+	return
+}
+
+// Destination storage mapped to the source storage.
+func destinationStorage(storageMap *api.StorageMap, storage ref.Ref) (destination api.DestinationStorage, found bool) {
+	if storageMap == nil {
+		return
+	}
+	pair, found := storageMap.FindStorageByRef(storage)
+	if found {
+		destination = pair.Destination
+	}
+	return
+}
+
+// Validate that the destination storage classes, resource quotas
+// and limit ranges of the namespaces the VMs are placed in can fit
+// the VMs that have not been started.
+func (r *Reconciler) validateCapacity(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	if plan.Status.HasCondition(Executing) || plan.Status.HasBlockerCondition() {
+		return
+	}
+	pAdapter, err := adapter.New(ctx.Source.Provider)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(ctx)
+	if err != nil {
+		return
+	}
+	demand := newCapacityDemand()
+	byNamespace := make(map[string]*capacityDemand)
+	for _, vm := range plan.Spec.VMs {
+		if status, found := plan.Status.Migration.FindVM(vm.Ref); found {
+			if status.MarkedStarted() || status.HasCondition(api.ConditionSucceeded) {
+				continue
+			}
+		}
+		var requirements *planbase.Requirements
+		requirements, err = validator.Requirements(vm.Ref)
+		if err != nil {
+			return
+		}
+		demand.add(plan.Referenced.Map.Storage, requirements)
+		namespace := plan.PlacedNamespace(vm.Ref)
+		if _, found := byNamespace[namespace]; !found {
+			byNamespace[namespace] = newCapacityDemand()
+		}
+		byNamespace[namespace].add(plan.Referenced.Map.Storage, requirements)
+	}
+	shortfalls := []string{}
+	for _, namespace := range placedNamespaces(plan) {
+		nsDemand, found := byNamespace[namespace]
+		if !found {
+			continue
+		}
+		quotas := &core.ResourceQuotaList{}
+		err = ctx.Destination.Client.List(context.TODO(), quotas, client.InNamespace(namespace))
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		shortfalls = append(shortfalls, nsDemand.quotaShortfalls(quotas.Items)...)
+		limitRanges := &core.LimitRangeList{}
+		err = ctx.Destination.Client.List(context.TODO(), limitRanges, client.InNamespace(namespace))
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		shortfalls = append(shortfalls, nsDemand.limitShortfalls(limitRanges.Items)...)
+	}
+	capacities := &storagev1.CSIStorageCapacityList{}
+	err = ctx.Destination.Client.List(context.TODO(), capacities, client.InNamespace(core.NamespaceAll))
+	if err != nil {
+		r.Log.Info(
+			"Could not list CSIStorageCapacity (capacity check skipped).",
+			"error",
+			err.Error())
+		err = nil
+	} else {
+		shortfalls = append(shortfalls, demand.storageShortfalls(capacities.Items)...)
+	}
+	if len(shortfalls) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     InsufficientCapacity,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryCritical,
+			Message:  "The destination capacity or quotas are insufficient for the VMs.",
+			Items:    shortfalls,
+		})
+	}
+	return
+}
@@ -0,0 +1,194 @@
+package plan
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/settings"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = ginkgo.Describe("Plan Capacity", func() {
+	const GiB = int64(1) << 30
+	var overhead int
+	var blockOverhead int64
+
+	ginkgo.BeforeEach(func() {
+		overhead = settings.Settings.FileSystemOverhead
+		blockOverhead = settings.Settings.BlockOverhead
+		settings.Settings.FileSystemOverhead = 0
+		settings.Settings.BlockOverhead = 0
+	})
+
+	ginkgo.AfterEach(func() {
+		settings.Settings.FileSystemOverhead = overhead
+		settings.Settings.BlockOverhead = blockOverhead
+	})
+
+	storageMap := &api.StorageMap{
+		Spec: api.StorageMapSpec{
+			Map: []api.StoragePair{
+				{
+					Source:      ref.Ref{ID: "ds-1"},
+					Destination: api.DestinationStorage{StorageClass: "fast"},
+				},
+				{
+					Source: ref.Ref{Name: "gp3"},
+					Destination: api.DestinationStorage{
+						StorageClass: "block",
+						VolumeMode:   core.PersistentVolumeBlock,
+					},
+				},
+			},
+		},
+	}
+	newDemand := func() *capacityDemand {
+		demand := newCapacityDemand()
+		demand.add(storageMap, &planbase.Requirements{
+			CPU:    2,
+			Memory: 4 * GiB,
+			Disks: []plan.DiskFootprint{
+				{Storage: ref.Ref{ID: "ds-1"}, Capacity: 10 * GiB},
+				{Storage: ref.Ref{Name: "gp3"}, Capacity: 20 * GiB},
+				{Storage: ref.Ref{ID: "unmapped"}, Capacity: 100 * GiB},
+			},
+		})
+		demand.add(storageMap, &planbase.Requirements{
+			CPU:    4,
+			Memory: 8 * GiB,
+			Disks: []plan.DiskFootprint{
+				{Storage: ref.Ref{ID: "ds-1"}, Capacity: 30 * GiB},
+			},
+		})
+		return demand
+	}
+
+	ginkgo.Describe("destinationStorage", func() {
+		ginkgo.It("should match the storage by ID, then by name", func() {
+			destination, found := destinationStorage(storageMap, ref.Ref{ID: "ds-1", Name: "gp3"})
+			gomega.Expect(found).To(gomega.BeTrue())
+			gomega.Expect(destination.StorageClass).To(gomega.Equal("fast"))
+			destination, found = destinationStorage(storageMap, ref.Ref{ID: "vol-1", Name: "gp3"})
+			gomega.Expect(found).To(gomega.BeTrue())
+			gomega.Expect(destination.StorageClass).To(gomega.Equal("block"))
+			_, found = destinationStorage(storageMap, ref.Ref{})
+			gomega.Expect(found).To(gomega.BeFalse())
+		})
+	})
+
+	ginkgo.Describe("add", func() {
+		ginkgo.It("should sum the mapped disks by storage class", func() {
+			demand := newDemand()
+			gomega.Expect(demand.storage).To(gomega.Equal(map[string]int64{"fast": 40 * GiB, "block": 20 * GiB}))
+			gomega.Expect(demand.claims).To(gomega.Equal(map[string]int64{"fast": 2, "block": 1}))
+			gomega.Expect(demand.disk).To(gomega.Equal(map[string]int64{"fast": 30 * GiB, "block": 20 * GiB}))
+			gomega.Expect(demand.cpu).To(gomega.Equal(int64(6)))
+			gomega.Expect(demand.memory).To(gomega.Equal(12 * GiB))
+			gomega.Expect(demand.vmMemory).To(gomega.Equal(8 * GiB))
+		})
+
+		ginkgo.It("should add the overhead of the volume mode", func() {
+			settings.Settings.FileSystemOverhead = 20
+			settings.Settings.BlockOverhead = GiB
+			demand := newDemand()
+			gomega.Expect(demand.storage["fast"]).To(gomega.Equal(50 * GiB))
+			gomega.Expect(demand.storage["block"]).To(gomega.Equal(21 * GiB))
+		})
+	})
+
+	ginkgo.Describe("quotaShortfalls", func() {
+		ginkgo.It("should report the exceeded quota resources", func() {
+			quota := core.ResourceQuota{
+				ObjectMeta: meta.ObjectMeta{Name: "quota"},
+				Status: core.ResourceQuotaStatus{
+					Hard: core.ResourceList{
+						core.ResourceRequestsStorage:                           resource.MustParse("100Gi"),
+						"fast" + StorageClassQuotaStorage:                      resource.MustParse("50Gi"),
+						"block" + StorageClassQuotaClaims:                      resource.MustParse("1"),
+						core.ResourceRequestsMemory:                            resource.MustParse("16Gi"),
+						core.ResourceLimitsCPU:                                 resource.MustParse("8"),
+						core.ResourceName("count/virtualmachines.kubevirt.io"): resource.MustParse("1"),
+					},
+					Used: core.ResourceList{
+						core.ResourceRequestsStorage:      resource.MustParse("50Gi"),
+						"fast" + StorageClassQuotaStorage: resource.MustParse("20Gi"),
+						core.ResourceRequestsMemory:       resource.MustParse("2Gi"),
+					},
+				},
+			}
+			shortfalls := newDemand().quotaShortfalls([]core.ResourceQuota{quota})
+			gomega.Expect(shortfalls).To(gomega.ConsistOf(
+				"ResourceQuota quota: fast.storageclass.storage.k8s.io/requests.storage requested 40Gi, available 30Gi.",
+				"ResourceQuota quota: requests.storage requested 60Gi, available 50Gi.",
+			))
+		})
+
+		ginkgo.It("should use the spec when the status is not reported", func() {
+			quota := core.ResourceQuota{
+				ObjectMeta: meta.ObjectMeta{Name: "quota"},
+				Spec: core.ResourceQuotaSpec{
+					Hard: core.ResourceList{core.ResourceLimitsCPU: resource.MustParse("4")},
+				},
+			}
+			shortfalls := newDemand().quotaShortfalls([]core.ResourceQuota{quota})
+			gomega.Expect(shortfalls).To(gomega.ConsistOf(
+				"ResourceQuota quota: limits.cpu requested 6, available 4.",
+			))
+		})
+	})
+
+	ginkgo.Describe("limitShortfalls", func() {
+		ginkgo.It("should report the disks and VMs exceeding the maximum", func() {
+			limitRange := core.LimitRange{
+				ObjectMeta: meta.ObjectMeta{Name: "limits"},
+				Spec: core.LimitRangeSpec{
+					Limits: []core.LimitRangeItem{
+						{
+							Type: core.LimitTypePersistentVolumeClaim,
+							Max:  core.ResourceList{core.ResourceStorage: resource.MustParse("25Gi")},
+						},
+						{
+							Type: core.LimitTypeContainer,
+							Max:  core.ResourceList{core.ResourceMemory: resource.MustParse("8Gi")},
+						},
+						{
+							Type: core.LimitTypePod,
+							Max:  core.ResourceList{core.ResourceMemory: resource.MustParse("6Gi")},
+						},
+					},
+				},
+			}
+			shortfalls := newDemand().limitShortfalls([]core.LimitRange{limitRange})
+			gomega.Expect(shortfalls).To(gomega.ConsistOf(
+				"LimitRange limits: disk of 30Gi in storage class \"fast\" exceeds the maximum 25Gi.",
+				"LimitRange limits: VM memory of 8Gi exceeds the Pod maximum 6Gi.",
+			))
+		})
+	})
+
+	ginkgo.Describe("storageShortfalls", func() {
+		ginkgo.It("should compare the total capacity of the storage class", func() {
+			capacities := []storagev1.CSIStorageCapacity{
+				{StorageClassName: "fast", Capacity: ptr.To(resource.MustParse("20Gi"))},
+				{StorageClassName: "fast", Capacity: ptr.To(resource.MustParse("10Gi"))},
+				{StorageClassName: "block", Capacity: ptr.To(resource.MustParse("1Ti"))},
+				{StorageClassName: "other", Capacity: ptr.To(resource.MustParse("1Gi"))},
+			}
+			shortfalls := newDemand().storageShortfalls(capacities)
+			gomega.Expect(shortfalls).To(gomega.ConsistOf(
+				"StorageClass fast: requested 40Gi, available 30Gi.",
+			))
+		})
+
+		ginkgo.It("should not check classes without capacity information", func() {
+			gomega.Expect(newDemand().storageShortfalls(nil)).To(gomega.BeEmpty())
+		})
+	})
+})
@@ -171,6 +171,8 @@ type Destination struct {
 	Provider *api.Provider
 	// Provider API client.
 	Inventory web.Client
+	// Provider Secret.
+	Secret *core.Secret
 }
 
 // Build.
@@ -205,6 +207,7 @@ func (r *Destination) build(ctx *Context) (err error) {
 			err = liberr.Wrap(err)
 			return
 		}
+		r.Secret = secret
 	} else {
 		r.Client, err = ocp.Client(r.Provider, nil)
 		if err != nil {
@@ -245,6 +245,9 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		r.archive(plan)
 	}
 
+	// Render the preview when requested.
+	r.preview(plan)
+
 	// Ready condition.
 	if !plan.Status.HasBlockerCondition() && !plan.Status.HasCondition(Archived) && !plan.Status.HasCondition(ValidatingVDDK) {
 		plan.Status.SetCondition(libcnd.Condition{
@@ -346,12 +349,13 @@ func (r *Reconciler) archive(plan *api.Plan) {
 }
 
 // Execute the plan.
-//  1. Find active (current) migration.
-//  2. If found, update the context and match the snapshot.
-//  3. Cancel as needed.
-//  4. If not, find the next pending migration.
-//  5. If a new migration is being started, update the context and snapshot.
-//  6. Run the migration.
+//  1. Reconcile the schedule.
+//  2. Find active (current) migration.
+//  3. If found, update the context and match the snapshot.
+//  4. Cancel as needed.
+//  5. If not, find the next pending migration.
+//  6. If a new migration is being started, update the context and snapshot.
+//  7. Run the migration.
 func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	defer func() {
 		if err == nil {
@@ -375,6 +379,18 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 		}
 		return
 	}
+	//
+	// Schedule.
+	// Requeue no later than the next scheduled action.
+	scheduleReQ, err := r.schedule(plan)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if scheduleReQ > 0 && (reQ == 0 || scheduleReQ < reQ) {
+			reQ = scheduleReQ
+		}
+	}()
 
 	ctx, err := plancontext.New(r, plan, r.Log)
 	if err != nil {
@@ -423,11 +439,17 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	}
 	//
 	// Cancel.
+	// Requeue while the failure hooks of canceled VMs are running.
 	runner := Migration{Context: ctx}
-	err = runner.Cancel()
+	cancelReQ, err := runner.Cancel()
 	if err != nil {
 		return
 	}
+	defer func() {
+		if cancelReQ > 0 && (reQ == 0 || cancelReQ < reQ) {
+			reQ = cancelReQ
+		}
+	}()
 	//
 	// Find pending migrations.
 	pending, err := r.pendingMigrations(plan)
@@ -441,6 +463,7 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 		migration = pending[0]
 		ctx.SetMigration(migration)
 		snapshot = r.newSnapshot(ctx)
+		r.freezeSelection(plan)
 		plan.Status.DeleteCondition(Failed, Canceled)
 		r.Log.Info(
 			"Found (new) migration.",
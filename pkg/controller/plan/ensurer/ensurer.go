@@ -31,7 +31,7 @@ func (r *Ensurer) VirtualMachine(vm *planapi.VMStatus, target *cnv.VirtualMachin
 		vms,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.Labeler.VMLabels(vm.Ref)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		},
 	)
 	if err != nil {
@@ -67,7 +67,7 @@ func (r *Ensurer) DataVolumes(vm *planapi.VMStatus, dvs []cdi.DataVolume) (err e
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.Labeler.VMLabels(vm.Ref)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		})
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -108,7 +108,7 @@ func (r *Ensurer) PersistentVolumeClaims(vm *planapi.VMStatus, pvcs []core.Persi
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.Labeler.VMLabels(vm.Ref)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		})
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -0,0 +1,685 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	ocp "github.com/kubev2v/forklift/pkg/lib/client/openshift"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Types
+const (
+	GuestScriptsNotValid = "GuestScriptsNotValid"
+)
+
+// Default timeout (seconds) of a guest script.
+const DefaultGuestScriptTimeout = 300
+
+// Keys of the guest scripts by OS family.
+const (
+	WindowsGuestScriptRegex = `^([0-9]+)_win_postboot([\w\-]*)\.ps1$`
+	LinuxGuestScriptRegex   = `^([0-9]+)_linux_postboot([\w\-]*)\.sh$`
+)
+
+// Guest script task annotations.
+const (
+	// PID of the script in the guest.
+	AnnGuestScriptPID = "pid"
+)
+
+// Output (bytes) of a guest script kept in the task.
+// The end of the output is kept.
+const guestScriptOutputLimit = 1024
+
+// Timeout of a guest agent command.
+const guestAgentTimeout = 30 * time.Second
+
+// Cluster role bound in the namespace of the VM running
+// the guest scripts. Grants the exec into the virt-launcher pod.
+const GuestExecRole = "forklift-controller-guest-exec-role"
+
+// Guest agent.
+// Runs programs inside the VM.
+type GuestAgent interface {
+	// Start a program. The input is passed on stdin.
+	// Returns the PID.
+	Exec(vmi *cnv.VirtualMachineInstance, path string, args []string, input []byte) (pid int, err error)
+	// Status of a started program.
+	ExecStatus(vmi *cnv.VirtualMachineInstance, pid int) (status *GuestExecStatus, err error)
+}
+
+// Status of a program started by the guest agent.
+type GuestExecStatus struct {
+	// The program has exited.
+	Exited bool `json:"exited"`
+	// Exit code.
+	ExitCode int `json:"exitcode,omitempty"`
+	// Signal that terminated the program.
+	Signal int `json:"signal,omitempty"`
+	// Stdout.
+	OutData []byte `json:"out-data,omitempty"`
+	// Stderr.
+	ErrData []byte `json:"err-data,omitempty"`
+}
+
+// Validate the guest scripts.
+// The ConfigMap must exist and contain scripts.
+func (r *Reconciler) validateGuestScripts(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	scripts := plan.Spec.GuestScripts
+	if scripts == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     GuestScriptsNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "Guest scripts not valid.",
+		Items:    []string{},
+	}
+	if scripts.Timeout < 0 {
+		notValid.Items = append(notValid.Items, "timeout: must not be negative.")
+	}
+	if scripts.ConfigMap.Name == "" {
+		notValid.Items = append(notValid.Items, "configMap: `name` must be specified.")
+	} else {
+		// The ConfigMap is read from the namespace of each VM
+		// unless the namespace is specified.
+		namespaces := placedNamespaces(plan)
+		if scripts.ConfigMap.Namespace != "" {
+			namespaces = []string{scripts.ConfigMap.Namespace}
+		}
+		for _, namespace := range namespaces {
+			mp := &core.ConfigMap{}
+			err = ctx.Destination.Client.Get(
+				context.TODO(),
+				client.ObjectKey{
+					Namespace: namespace,
+					Name:      scripts.ConfigMap.Name,
+				},
+				mp)
+			if err != nil {
+				if !k8serr.IsNotFound(err) {
+					err = liberr.Wrap(err)
+					return
+				}
+				err = nil
+				notValid.Items = append(
+					notValid.Items,
+					fmt.Sprintf(
+						"configMap: %s/%s not found.",
+						namespace,
+						scripts.ConfigMap.Name))
+			} else if len(selectGuestScripts(mp, planapi.GuestOSLinux)) == 0 &&
+				len(selectGuestScripts(mp, planapi.GuestOSWindows)) == 0 {
+				notValid.Items = append(
+					notValid.Items,
+					fmt.Sprintf(
+						"configMap: %s/%s does not contain guest scripts.",
+						namespace,
+						scripts.ConfigMap.Name))
+			}
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+	return
+}
+
+// Run the guest scripts inside the target VM.
+// The scripts for the OS family are selected once the guest
+// agent has connected. Each script is a task of the step and
+// the scripts run one at a time. Returns true when done.
+func (r *Migration) runGuestScripts(vm *planapi.VMStatus, step *planapi.Step) (done bool, err error) {
+	scripts := r.Plan.Spec.GuestScripts
+	if r.kubevirt.determineRunStrategy(vm) == cnv.RunStrategyHalted {
+		step.Reason = "Skipped: the target VM is not started."
+		step.MarkCompleted()
+		done = true
+		return
+	}
+	timeout := time.Duration(scripts.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = DefaultGuestScriptTimeout * time.Second
+	}
+	vmi, found, err := r.kubevirt.getVMI(vm)
+	if err != nil {
+		return
+	}
+	ready := found && vmi.Status.Phase == cnv.Running && agentConnected(vmi)
+	if len(step.Tasks) == 0 {
+		if !ready {
+			step.Reason = "Waiting for the guest agent to connect."
+			if step.Started != nil && time.Since(step.Started.Time) > timeout {
+				r.guestScriptFailed(vm, &step.Task, "The guest agent is not connected.")
+				done = !step.HasError()
+			}
+			return
+		}
+		step.Reason = ""
+		family := guestFamily(vmi)
+		var mp *core.ConfigMap
+		mp, err = r.guestScriptsConfigMap(vm)
+		if err != nil {
+			return
+		}
+		for _, key := range selectGuestScripts(mp, family) {
+			step.Tasks = append(
+				step.Tasks,
+				&planapi.Task{
+					Name:        key,
+					Description: fmt.Sprintf("Run %s.", key),
+					Progress:    libitr.Progress{Total: 1},
+				})
+		}
+		step.Progress.Total = int64(len(step.Tasks))
+		if len(step.Tasks) == 0 {
+			step.Reason = fmt.Sprintf("No guest scripts for the %s OS family.", family)
+			step.MarkCompleted()
+			done = true
+			return
+		}
+	}
+	for _, task := range step.Tasks {
+		if task.MarkedCompleted() {
+			continue
+		}
+		if !ready {
+			task.MarkStarted()
+			task.Reason = "The guest agent is not connected."
+			r.guestScriptTimeout(vm, task, timeout)
+		} else {
+			err = r.runGuestScript(vm, vmi, task, timeout)
+			if err != nil {
+				return
+			}
+		}
+		break
+	}
+	step.ReflectTasks()
+	if step.MarkedCompleted() {
+		err = r.kubevirt.DeleteGuestExecRoleBinding(vm)
+		if err != nil {
+			return
+		}
+	}
+	done = step.MarkedCompleted() && !step.HasError()
+	return
+}
+
+// Run a guest script.
+// The script is started on the first pass and the
+// status is polled on the passes that follow.
+func (r *Migration) runGuestScript(vm *planapi.VMStatus, vmi *cnv.VirtualMachineInstance, task *planapi.Task, timeout time.Duration) (err error) {
+	task.MarkStarted()
+	if task.Annotations == nil {
+		task.Annotations = map[string]string{}
+	}
+	pid, started := task.Annotations[AnnGuestScriptPID]
+	if !started {
+		var mp *core.ConfigMap
+		mp, err = r.guestScriptsConfigMap(vm)
+		if err != nil {
+			return
+		}
+		script, found := mp.Data[task.Name]
+		if !found {
+			r.guestScriptFailed(vm, task, "Script not found.")
+			return
+		}
+		err = r.kubevirt.EnsureGuestExecRoleBinding(vm)
+		if err != nil {
+			return
+		}
+		path, args := guestShell(task.Name)
+		n, aErr := r.guestAgent.Exec(vmi, path, args, []byte(script))
+		if aErr != nil {
+			task.Reason = aErr.Error()
+			r.guestScriptTimeout(vm, task, timeout)
+			return
+		}
+		task.Reason = ""
+		task.Annotations[AnnGuestScriptPID] = strconv.Itoa(n)
+		r.Log.Info(
+			"Guest script started.",
+			"vm",
+			vm.String(),
+			"script",
+			task.Name,
+			"pid",
+			n)
+		return
+	}
+	n, err := strconv.Atoi(pid)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	status, aErr := r.guestAgent.ExecStatus(vmi, n)
+	if aErr != nil {
+		task.Reason = aErr.Error()
+		r.guestScriptTimeout(vm, task, timeout)
+		return
+	}
+	if !status.Exited {
+		task.Reason = ""
+		r.guestScriptTimeout(vm, task, timeout)
+		return
+	}
+	task.Output = map[string]string{
+		"exitCode": strconv.Itoa(status.ExitCode),
+	}
+	if len(status.OutData) > 0 {
+		task.Output["stdout"] = guestScriptOutput(status.OutData)
+	}
+	if len(status.ErrData) > 0 {
+		task.Output["stderr"] = guestScriptOutput(status.ErrData)
+	}
+	switch {
+	case status.Signal > 0:
+		r.guestScriptFailed(vm, task, fmt.Sprintf("Terminated by signal %d.", status.Signal))
+	case status.ExitCode != 0:
+		r.guestScriptFailed(vm, task, fmt.Sprintf("Exited with code %d.", status.ExitCode))
+	default:
+		task.Progress.Completed = 1
+		task.MarkCompleted()
+	}
+	return
+}
+
+// Fail the script that has not completed within the timeout.
+func (r *Migration) guestScriptTimeout(vm *planapi.VMStatus, task *planapi.Task, timeout time.Duration) {
+	if task.Started == nil || time.Since(task.Started.Time) <= timeout {
+		return
+	}
+	message := fmt.Sprintf("Timed out after %s.", timeout)
+	if task.Reason != "" {
+		message = fmt.Sprintf("Timed out after %s: %s", timeout, task.Reason)
+	}
+	r.guestScriptFailed(vm, task, message)
+}
+
+// Report a failed script.
+// The failure is reported as a warning when the
+// migration continues on failure.
+func (r *Migration) guestScriptFailed(vm *planapi.VMStatus, task *planapi.Task, message string) {
+	task.MarkCompleted()
+	if !r.Plan.Spec.GuestScripts.ContinueOnFailure {
+		task.AddError(message)
+		return
+	}
+	task.Reason = message
+	task.Progress.Completed = task.Progress.Total
+	items := []string{fmt.Sprintf("[%s] %s", task.Name, message)}
+	if cnd := vm.FindCondition(api.ConditionGuestScriptFailed); cnd != nil {
+		items = append(cnd.Items, items...)
+	}
+	vm.SetCondition(libcnd.Condition{
+		Type:     api.ConditionGuestScriptFailed,
+		Status:   True,
+		Category: api.CategoryWarn,
+		Message:  "A guest script has failed and the migration continued.",
+		Items:    items,
+		Durable:  true,
+	})
+	r.Log.Info(
+		"Guest script failed.",
+		"vm",
+		vm.String(),
+		"script",
+		task.Name,
+		"reason",
+		message)
+}
+
+// Get the guest scripts ConfigMap of the VM.
+func (r *Migration) guestScriptsConfigMap(vm *planapi.VMStatus) (mp *core.ConfigMap, err error) {
+	mp = &core.ConfigMap{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: guestScriptsNamespace(r.Plan, vm.Ref),
+			Name:      r.Plan.Spec.GuestScripts.ConfigMap.Name,
+		},
+		mp)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Namespace of the guest scripts ConfigMap.
+// Defaults to the target namespace of the VM.
+func guestScriptsNamespace(plan *api.Plan, vmRef ref.Ref) (namespace string) {
+	namespace = plan.Spec.GuestScripts.ConfigMap.Namespace
+	if namespace == "" {
+		namespace = plan.VMTargetNamespace(vmRef)
+	}
+	return
+}
+
+// Ensure the role binding that grants the controller the exec
+// into the virt-launcher pod of the VM while the guest scripts run.
+// The binding is only needed on the host cluster. On a remote
+// cluster the credentials of the provider are used.
+func (r *KubeVirt) EnsureGuestExecRoleBinding(vm *planapi.VMStatus) (err error) {
+	if !r.Plan.Provider.Destination.IsHost() {
+		return
+	}
+	list := &rbac.RoleBindingList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(r.guestExecLabels(vm.Ref)),
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(list.Items) > 0 {
+		return
+	}
+	binding := &rbac.RoleBinding{
+		ObjectMeta: meta.ObjectMeta{
+			GenerateName: "forklift-guest-exec-",
+			Namespace:    r.Plan.VMTargetNamespace(vm.Ref),
+			Labels:       r.guestExecLabels(vm.Ref),
+		},
+		Subjects: []rbac.Subject{
+			{
+				Kind:      rbac.ServiceAccountKind,
+				Name:      Settings.ServiceAccount,
+				Namespace: Settings.Inventory.Namespace,
+			},
+		},
+		RoleRef: rbac.RoleRef{
+			APIGroup: rbac.GroupName,
+			Kind:     "ClusterRole",
+			Name:     GuestExecRole,
+		},
+	}
+	err = r.Destination.Client.Create(context.TODO(), binding)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Created guest exec role binding.",
+		"binding",
+		path.Join(
+			binding.Namespace,
+			binding.Name),
+		"vm",
+		vm.String())
+	return
+}
+
+// Delete the guest exec role binding of the VM.
+func (r *KubeVirt) DeleteGuestExecRoleBinding(vm *planapi.VMStatus) (err error) {
+	if !r.Plan.Provider.Destination.IsHost() {
+		return
+	}
+	labels := r.guestExecLabels(vm.Ref)
+	delete(labels, kMigration)
+	list := &rbac.RoleBindingList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(labels),
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		err = r.DeleteObject(&list.Items[i], vm, "Deleted guest exec role binding.", "binding")
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Labels for the guest exec role binding.
+func (r *KubeVirt) guestExecLabels(vmRef ref.Ref) (labels map[string]string) {
+	labels = r.vmLabels(vmRef)
+	labels[kApp] = "guest-exec"
+	return
+}
+
+// Select the guest scripts for the OS family.
+// Sorted by the number at the start of the key.
+func selectGuestScripts(mp *core.ConfigMap, family string) (keys []string) {
+	pattern := regexp.MustCompile(LinuxGuestScriptRegex)
+	if family == planapi.GuestOSWindows {
+		pattern = regexp.MustCompile(WindowsGuestScriptRegex)
+	}
+	order := map[string]int{}
+	for key := range mp.Data {
+		match := pattern.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+		order[key], _ = strconv.Atoi(match[1])
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if order[keys[i]] != order[keys[j]] {
+			return order[keys[i]] < order[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return
+}
+
+// OS family reported by the guest agent.
+func guestFamily(vmi *cnv.VirtualMachineInstance) (family string) {
+	family = planapi.GuestOSLinux
+	info := vmi.Status.GuestOSInfo
+	if info.ID == "mswindows" || strings.Contains(strings.ToLower(info.Name), "windows") {
+		family = planapi.GuestOSWindows
+	}
+	return
+}
+
+// Shell that runs the script read from stdin.
+func guestShell(key string) (path string, args []string) {
+	if regexp.MustCompile(WindowsGuestScriptRegex).MatchString(key) {
+		path = "powershell.exe"
+		args = []string{"-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-Command", "-"}
+		return
+	}
+	path = "/bin/sh"
+	args = []string{"-s"}
+	return
+}
+
+// The end of the output of a script.
+func guestScriptOutput(data []byte) string {
+	if len(data) > guestScriptOutputLimit {
+		data = data[len(data)-guestScriptOutputLimit:]
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Guest agent reached through the virt-launcher pod.
+// The agent commands are run by virsh in the compute container.
+type LauncherAgent struct {
+	*plancontext.Context
+}
+
+// Start a program.
+func (r *LauncherAgent) Exec(vmi *cnv.VirtualMachineInstance, path string, args []string, input []byte) (pid int, err error) {
+	arguments := map[string]interface{}{
+		"path":           path,
+		"arg":            args,
+		"capture-output": true,
+	}
+	if len(input) > 0 {
+		arguments["input-data"] = input
+	}
+	result := struct {
+		PID int `json:"pid"`
+	}{}
+	err = r.command(vmi, "guest-exec", arguments, &result)
+	if err != nil {
+		return
+	}
+	pid = result.PID
+	return
+}
+
+// Status of a started program.
+func (r *LauncherAgent) ExecStatus(vmi *cnv.VirtualMachineInstance, pid int) (status *GuestExecStatus, err error) {
+	status = &GuestExecStatus{}
+	err = r.command(vmi, "guest-exec-status", map[string]interface{}{"pid": pid}, status)
+	return
+}
+
+// Run a guest agent command and decode the result.
+func (r *LauncherAgent) command(vmi *cnv.VirtualMachineInstance, execute string, arguments interface{}, result interface{}) (err error) {
+	pod, err := r.launcher(vmi)
+	if err != nil {
+		return
+	}
+	request, err := json.Marshal(
+		map[string]interface{}{
+			"execute":   execute,
+			"arguments": arguments,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	// The command is read by virsh from stdin so that the
+	// script is not passed in the URL of the exec request.
+	domain := vmi.Namespace + "_" + vmi.Name
+	input := fmt.Sprintf("qemu-agent-command %s %s\n", virshQuote(domain), virshQuote(string(request)))
+	stdout, err := r.exec(pod, []string{"virsh", "--quiet"}, []byte(input))
+	if err != nil {
+		return
+	}
+	reply := struct {
+		Return json.RawMessage `json:"return"`
+	}{}
+	err = json.Unmarshal(stdout, &reply)
+	if err != nil {
+		err = liberr.Wrap(err, "reply", string(stdout))
+		return
+	}
+	err = json.Unmarshal(reply.Return, result)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Find the running virt-launcher pod of the VM instance.
+func (r *LauncherAgent) launcher(vmi *cnv.VirtualMachineInstance) (pod *core.Pod, err error) {
+	list := &core.PodList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(map[string]string{cnv.CreatedByLabel: string(vmi.UID)}),
+			Namespace:     vmi.Namespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		if list.Items[i].Status.Phase == core.PodRunning {
+			pod = &list.Items[i]
+			return
+		}
+	}
+	err = liberr.New("The virt-launcher pod is not running.", "vmi", vmi.Namespace+"/"+vmi.Name)
+	return
+}
+
+// Quote an argument of a virsh command.
+func virshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Execute a command in the compute container of the pod.
+// The input is passed on stdin.
+func (r *LauncherAgent) exec(pod *core.Pod, command []string, input []byte) (stdout []byte, err error) {
+	cfg := ocp.RestCfg(r.Destination.Provider, r.Destination.Secret)
+	if cfg == nil {
+		err = liberr.New("REST configuration not found.")
+		return
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec")
+	request.VersionedParams(
+		&core.PodExecOptions{
+			Container: "compute",
+			Command:   command,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    true,
+		},
+		scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(cfg, "POST", request.URL())
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), guestAgentTimeout)
+	defer cancel()
+	out, errOut := bytes.Buffer{}, bytes.Buffer{}
+	err = executor.StreamWithContext(
+		ctx,
+		remotecommand.StreamOptions{
+			Stdin:  bytes.NewReader(input),
+			Stdout: &out,
+			Stderr: &errOut,
+		})
+	if err != nil {
+		err = liberr.Wrap(err, "stderr", strings.TrimSpace(errOut.String()))
+		return
+	}
+	stdout = out.Bytes()
+	return
+}
@@ -0,0 +1,214 @@
+package plan
+
+import (
+	"context"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Guest agent running the programs to completion.
+type fakeGuestAgent struct {
+	// Started programs.
+	started []string
+	// Input of the started programs.
+	input []string
+	// Status reported for the programs.
+	status GuestExecStatus
+}
+
+func (r *fakeGuestAgent) Exec(vmi *cnv.VirtualMachineInstance, path string, args []string, input []byte) (pid int, err error) {
+	r.started = append(r.started, path)
+	r.input = append(r.input, string(input))
+	pid = len(r.started)
+	return
+}
+
+func (r *fakeGuestAgent) ExecStatus(vmi *cnv.VirtualMachineInstance, pid int) (status *GuestExecStatus, err error) {
+	status = &GuestExecStatus{}
+	*status = r.status
+	return
+}
+
+var _ = ginkgo.Describe("Plan guest scripts", func() {
+	scripts := &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "scripts"},
+		Data: map[string]string{
+			"10_linux_postboot_monitoring.sh": "echo monitoring",
+			"2_linux_postboot_hostname.sh":    "hostnamectl set-hostname vm",
+			"1_linux_firstboot_network.sh":    "echo firstboot",
+			"1_win_postboot_license.ps1":      "slmgr /ato",
+		},
+	}
+
+	ginkgo.Describe("selectGuestScripts", func() {
+		ginkgo.It("should select the scripts of the OS family in order", func() {
+			gomega.Expect(selectGuestScripts(scripts, plan.GuestOSLinux)).To(gomega.Equal([]string{
+				"2_linux_postboot_hostname.sh",
+				"10_linux_postboot_monitoring.sh",
+			}))
+			gomega.Expect(selectGuestScripts(scripts, plan.GuestOSWindows)).To(gomega.Equal([]string{
+				"1_win_postboot_license.ps1",
+			}))
+		})
+
+		ginkgo.It("should read the scripts from the namespace of the VM", func() {
+			p := &api.Plan{}
+			p.Spec.TargetNamespace = "target"
+			p.Spec.GuestScripts = &plan.GuestScripts{}
+			vmRef := ref.Ref{ID: "vm-1"}
+			p.Status.Migration.VMs = []*plan.VMStatus{{VM: plan.VM{Ref: vmRef}, TargetNamespace: "placed"}}
+			gomega.Expect(guestScriptsNamespace(p, vmRef)).To(gomega.Equal("placed"))
+			p.Spec.GuestScripts.ConfigMap.Namespace = "scripts"
+			gomega.Expect(guestScriptsNamespace(p, vmRef)).To(gomega.Equal("scripts"))
+		})
+
+		ginkgo.It("should quote the virsh arguments", func() {
+			gomega.Expect(virshQuote(`{"input-data":"echo 'hi'"}`)).To(gomega.Equal(`'{"input-data":"echo '\''hi'\''"}'`))
+		})
+
+		ginkgo.It("should select the OS family reported by the guest agent", func() {
+			vmi := &cnv.VirtualMachineInstance{}
+			vmi.Status.GuestOSInfo.ID = "rhel"
+			gomega.Expect(guestFamily(vmi)).To(gomega.Equal(plan.GuestOSLinux))
+			vmi.Status.GuestOSInfo.ID = "mswindows"
+			gomega.Expect(guestFamily(vmi)).To(gomega.Equal(plan.GuestOSWindows))
+		})
+	})
+
+	ginkgo.Describe("runGuestScripts", func() {
+		newMigration := func(agent bool) (*Migration, *fakeGuestAgent, *plan.VMStatus, *plan.Step) {
+			vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1", Name: "vm"}}}
+			vm.RestorePowerState = plan.VMPowerStateOn
+			p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "test", UID: "plan"}}
+			p.Spec.TargetNamespace = "test"
+			p.Spec.GuestScripts = &plan.GuestScripts{ConfigMap: core.ObjectReference{Name: "scripts"}}
+			openshift := api.OpenShift
+			p.Referenced.Provider.Destination = &api.Provider{Spec: api.ProviderSpec{Type: &openshift}}
+			target := &cnv.VirtualMachine{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "vm",
+					Labels: map[string]string{
+						kMigration: "migration",
+						kPlan:      "plan",
+						kVM:        "vm-1",
+						kResource:  ResourceVMConfig,
+					},
+				},
+			}
+			vmi := &cnv.VirtualMachineInstance{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "vm"}}
+			vmi.Status.Phase = cnv.Running
+			if agent {
+				vmi.Status.Conditions = []cnv.VirtualMachineInstanceCondition{
+					{Type: cnv.VirtualMachineInstanceAgentConnected, Status: core.ConditionTrue},
+				}
+			}
+			scheme := runtime.NewScheme()
+			_ = core.AddToScheme(scheme)
+			_ = cnv.AddToScheme(scheme)
+			_ = rbac.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(target, vmi, scripts.DeepCopy()).Build()
+			ctx := &plancontext.Context{
+				Destination: plancontext.Destination{Client: client},
+				Log:         logging.WithName("test"),
+				Migration:   &api.Migration{ObjectMeta: meta.ObjectMeta{UID: "migration"}},
+				Plan:        p,
+			}
+			guestAgent := &fakeGuestAgent{status: GuestExecStatus{Exited: true}}
+			step := &plan.Step{Task: plan.Task{Name: "GuestScripts"}}
+			step.MarkStarted()
+			migration := &Migration{Context: ctx, kubevirt: KubeVirt{Context: ctx}, guestAgent: guestAgent}
+			return migration, guestAgent, vm, step
+		}
+
+		ginkgo.It("should run the scripts one at a time", func() {
+			migration, agent, vm, step := newMigration(true)
+			agent.status.OutData = []byte("done\n")
+			done, err := migration.runGuestScripts(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(done).To(gomega.BeFalse())
+			gomega.Expect(step.Tasks).To(gomega.HaveLen(2))
+			gomega.Expect(agent.started).To(gomega.Equal([]string{"/bin/sh"}))
+			gomega.Expect(agent.input).To(gomega.Equal([]string{"hostnamectl set-hostname vm"}))
+			bindings := &rbac.RoleBindingList{}
+			gomega.Expect(migration.Destination.Client.List(context.TODO(), bindings)).To(gomega.Succeed())
+			gomega.Expect(bindings.Items).To(gomega.HaveLen(1))
+			gomega.Expect(bindings.Items[0].Namespace).To(gomega.Equal("test"))
+			gomega.Expect(bindings.Items[0].RoleRef.Name).To(gomega.Equal(GuestExecRole))
+			// First script exited, second started.
+			for i := 0; i < 3 && !done; i++ {
+				done, err = migration.runGuestScripts(vm, step)
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			}
+			gomega.Expect(done).To(gomega.BeTrue())
+			gomega.Expect(agent.started).To(gomega.HaveLen(2))
+			gomega.Expect(step.Progress.Completed).To(gomega.Equal(int64(2)))
+			gomega.Expect(step.Tasks[0].Output).To(gomega.Equal(map[string]string{"exitCode": "0", "stdout": "done"}))
+			// The exec is no longer granted.
+			gomega.Expect(migration.Destination.Client.List(context.TODO(), bindings)).To(gomega.Succeed())
+			gomega.Expect(bindings.Items).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("should fail the step when a script fails", func() {
+			migration, agent, vm, step := newMigration(true)
+			agent.status.ExitCode = 1
+			_, _ = migration.runGuestScripts(vm, step)
+			done, err := migration.runGuestScripts(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(done).To(gomega.BeFalse())
+			gomega.Expect(step.HasError()).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should report a failed script as a warning when the migration continues on failure", func() {
+			migration, agent, vm, step := newMigration(true)
+			migration.Plan.Spec.GuestScripts.ContinueOnFailure = true
+			agent.status.ExitCode = 1
+			done := false
+			for i := 0; i < 4 && !done; i++ {
+				var err error
+				done, err = migration.runGuestScripts(vm, step)
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			}
+			gomega.Expect(done).To(gomega.BeTrue())
+			gomega.Expect(step.HasError()).To(gomega.BeFalse())
+			cnd := vm.FindCondition(api.ConditionGuestScriptFailed)
+			gomega.Expect(cnd).ToNot(gomega.BeNil())
+			gomega.Expect(cnd.Items).To(gomega.HaveLen(2))
+		})
+
+		ginkgo.It("should fail when the guest agent does not connect within the timeout", func() {
+			migration, agent, vm, step := newMigration(false)
+			done, err := migration.runGuestScripts(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(done).To(gomega.BeFalse())
+			gomega.Expect(step.HasError()).To(gomega.BeFalse())
+			step.Started = &meta.Time{Time: time.Now().Add(-time.Hour)}
+			_, err = migration.runGuestScripts(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(step.HasError()).To(gomega.BeTrue())
+			gomega.Expect(agent.started).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("should skip the scripts when the VM is not started", func() {
+			migration, agent, vm, step := newMigration(true)
+			vm.RestorePowerState = plan.VMPowerStateOff
+			done, err := migration.runGuestScripts(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(done).To(gomega.BeTrue())
+			gomega.Expect(agent.started).To(gomega.BeEmpty())
+		})
+	})
+})
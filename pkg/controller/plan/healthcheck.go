@@ -0,0 +1,497 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Types
+const (
+	HealthCheckNotValid = "HealthCheckNotValid"
+)
+
+// Default health verification timeout (seconds).
+const DefaultHealthCheckTimeout = 600
+
+// Timeout of a single health probe.
+const healthProbeTimeout = 3 * time.Second
+
+// Deadline of a health probe pod, including its scheduling.
+const healthProbeDeadline = 120
+
+// Health probe labels.
+const (
+	// Health probe run by the pod.
+	kHealthProbe = "healthProbe"
+)
+
+// Health probe pod resources.
+var (
+	healthProbeCpu    = resource.MustParse("100m")
+	healthProbeMemory = resource.MustParse("64Mi")
+)
+
+// Validate the health check.
+// The probes must be valid and the readiness hook
+// must exist and be ready.
+func (r *Reconciler) validateHealthCheck(plan *api.Plan) (err error) {
+	check := plan.Spec.HealthCheck
+	source := plan.Referenced.Provider.Source
+	if check == nil || source == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     HealthCheckNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "Health check not valid.",
+		Items:    []string{},
+	}
+	if source.Type() == api.EC2 {
+		notValid.Reason = NotSupported
+		notValid.Message = "Health check is not supported by EC2 source providers."
+		plan.Status.SetCondition(notValid)
+		return
+	}
+	if check.Timeout < 0 {
+		notValid.Items = append(notValid.Items, "timeout: must not be negative.")
+	}
+	names := map[string]bool{}
+	for _, probe := range check.Probes {
+		switch probe.Type {
+		case planapi.HealthProbeTCP:
+			if probe.Path != "" {
+				notValid.Items = append(notValid.Items, probe.String()+": path is supported by HTTP probes only.")
+			}
+		case planapi.HealthProbeHTTP:
+		default:
+			notValid.Items = append(notValid.Items, probe.String()+": type must be TCP or HTTP.")
+		}
+		if probe.Port < 1 || probe.Port > 65535 {
+			notValid.Items = append(notValid.Items, probe.String()+": port not valid.")
+		}
+		if names[probe.Name()] {
+			notValid.Items = append(notValid.Items, probe.String()+": duplicate probe.")
+		}
+		names[probe.Name()] = true
+	}
+	if check.Hook != nil {
+		if !libref.RefSet(check.Hook) {
+			notValid.Items = append(notValid.Items, "hook: specified by `namespace` and `name`.")
+		} else {
+			hook := &api.Hook{}
+			err = r.Get(
+				context.TODO(),
+				client.ObjectKey{
+					Namespace: check.Hook.Namespace,
+					Name:      check.Hook.Name,
+				},
+				hook)
+			if err != nil {
+				if !k8serr.IsNotFound(err) {
+					err = liberr.Wrap(err)
+					return
+				}
+				err = nil
+				notValid.Items = append(notValid.Items, "hook: "+check.Hook.String()+" not found.")
+			} else {
+				if !hook.Status.HasCondition(libcnd.Ready) {
+					notValid.Items = append(notValid.Items, "hook: "+check.Hook.String()+" not ready.")
+				}
+				referenced := false
+				for _, h := range plan.Referenced.Hooks {
+					if h.Namespace == hook.Namespace && h.Name == hook.Name {
+						referenced = true
+						break
+					}
+				}
+				if !referenced {
+					plan.Referenced.Hooks = append(plan.Referenced.Hooks, hook)
+				}
+			}
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+	return
+}
+
+// Verify the health of the target VM.
+// Each check is a task of the step. The VM instance must
+// be running before the guest agent and the probes are
+// checked, and the readiness script runs after all of the
+// other checks have passed. A check that has not passed
+// within the timeout is reported as a step error.
+func (r *Migration) verifyHealth(vm *planapi.VMStatus, step *planapi.Step) (ready bool, err error) {
+	check := r.Plan.Spec.HealthCheck
+	if r.kubevirt.determineRunStrategy(vm) == cnv.RunStrategyHalted {
+		for _, task := range step.Tasks {
+			task.Reason = "Skipped: the target VM is not started."
+			task.MarkCompleted()
+		}
+		step.ReflectTasks()
+		ready = true
+		return
+	}
+	vmi, found, err := r.kubevirt.getVMI(vm)
+	if err != nil {
+		return
+	}
+	if found && (vmi.Status.Phase == cnv.Failed || vmi.Status.Phase == cnv.Succeeded) {
+		step.AddError(fmt.Sprintf("The VM instance is not running: %s.", vmi.Status.Phase))
+		return
+	}
+	running := found && vmi.Status.Phase == cnv.Running
+	reason := "The VM instance does not exist."
+	if found {
+		reason = fmt.Sprintf("The VM instance phase: %s.", vmi.Status.Phase)
+	}
+	markHealthTask(step, planapi.HealthTaskRunning, running, reason)
+	if running {
+		markHealthTask(step, planapi.HealthTaskGuestAgent, agentConnected(vmi), "The guest agent is not connected.")
+		address := vmiAddress(vmi)
+		for _, probe := range check.Probes {
+			if task, found := step.FindTask(probe.Name()); !found || task.MarkedCompleted() {
+				continue
+			}
+			if address == "" {
+				markHealthTask(step, probe.Name(), false, "The IP address of the VM is not reported.")
+				continue
+			}
+			var pod *core.Pod
+			pod, err = r.kubevirt.EnsureHealthProbePod(vm, probe, address)
+			if err != nil {
+				return
+			}
+			switch pod.Status.Phase {
+			case core.PodSucceeded:
+				reason = ""
+			case core.PodFailed:
+				reason = probeFailure(pod)
+			default:
+				continue
+			}
+			err = r.kubevirt.DeleteObject(pod, vm, "Deleted health probe pod.", "pod")
+			if err != nil {
+				return
+			}
+			markHealthTask(step, probe.Name(), pod.Status.Phase == core.PodSucceeded, reason)
+		}
+		if task, found := step.FindTask(planapi.HealthTaskScript); found && !task.MarkedCompleted() && healthPassed(step) {
+			err = r.runReadinessScript(vm, vmi, task)
+			if err != nil {
+				return
+			}
+		}
+	}
+	step.ReflectTasks()
+	if step.HasError() {
+		return
+	}
+	if step.MarkedCompleted() {
+		ready = true
+		return
+	}
+	timeout := time.Duration(check.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = DefaultHealthCheckTimeout * time.Second
+	}
+	if step.Started != nil && time.Since(step.Started.Time) > timeout {
+		pending := []string{}
+		for _, task := range step.Tasks {
+			if !task.MarkedCompleted() {
+				pending = append(pending, fmt.Sprintf("%s (%s)", task.Name, task.Reason))
+			}
+		}
+		step.AddError(
+			fmt.Sprintf(
+				"Health verification timed out after %s: %s",
+				timeout,
+				strings.Join(pending, ", ")))
+	}
+	return
+}
+
+// Run the readiness script (hook).
+// The target VM is passed to the hook in the environment.
+func (r *Migration) runReadinessScript(vm *planapi.VMStatus, vmi *cnv.VirtualMachineInstance, task *planapi.Task) (err error) {
+	hook, found := r.FindHook(*r.Plan.Spec.HealthCheck.Hook)
+	if !found {
+		task.AddError("Hook not found.")
+		task.MarkCompleted()
+		return
+	}
+	runner := HookRunner{
+		Context: r.Context,
+		vm:      vm,
+		hook:    hook,
+		target:  vmiTarget(vmi),
+	}
+	err = runner.run(task)
+	return
+}
+
+// Find the instance of the target VM.
+func (r *KubeVirt) getVMI(vm *planapi.VMStatus) (vmi *cnv.VirtualMachineInstance, found bool, err error) {
+	vms := &cnv.VirtualMachineList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		vms,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(r.vmLabels(vm.Ref)),
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(vms.Items) == 0 {
+		return
+	}
+	target := &vms.Items[0]
+	vmi = &cnv.VirtualMachineInstance{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: target.Namespace,
+			Name:      target.Name,
+		},
+		vmi)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+		return
+	}
+	found = true
+	return
+}
+
+// Mark the health task passed, otherwise record
+// the reason on the task. A passed task is not checked again.
+func markHealthTask(step *planapi.Step, name string, passed bool, reason string) {
+	task, found := step.FindTask(name)
+	if !found || task.MarkedCompleted() {
+		return
+	}
+	task.MarkStarted()
+	if passed {
+		task.Reason = ""
+		task.Progress.Completed = 1
+		task.MarkCompleted()
+	} else {
+		task.Reason = reason
+	}
+}
+
+// All of the checks other than the readiness script have passed.
+func healthPassed(step *planapi.Step) bool {
+	for _, task := range step.Tasks {
+		if task.Name != planapi.HealthTaskScript && !task.MarkedCompleted() {
+			return false
+		}
+	}
+	return true
+}
+
+// The guest agent is connected.
+func agentConnected(vmi *cnv.VirtualMachineInstance) bool {
+	for _, cnd := range vmi.Status.Conditions {
+		if cnd.Type == cnv.VirtualMachineInstanceAgentConnected {
+			return cnd.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
+
+// IP address of the VM instance.
+// The first address reported by an interface.
+func vmiAddress(vmi *cnv.VirtualMachineInstance) string {
+	for _, iface := range vmi.Status.Interfaces {
+		if iface.IP != "" {
+			return iface.IP
+		}
+	}
+	return ""
+}
+
+// Ensure the pod running an attempt of the health probe exists.
+// The probe runs in the namespace of the target VM on the destination
+// cluster, so the VM is reached through the network of its namespace
+// rather than from the controller. A failed probe is retried by a new
+// pod once the failed one has been deleted.
+func (r *KubeVirt) EnsureHealthProbePod(vm *planapi.VMStatus, probe planapi.HealthProbe, address string) (pod *core.Pod, err error) {
+	labels := r.healthProbeLabels(vm.Ref, probe)
+	list, err := r.GetPodsWithLabels(labels)
+	if err != nil {
+		return
+	}
+	if len(list.Items) > 0 {
+		pod = &list.Items[0]
+		return
+	}
+	pod = r.healthProbePod(vm, probe, address, labels)
+	err = r.Destination.Client.Create(context.TODO(), pod)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Created health probe pod.",
+		"pod",
+		path.Join(pod.Namespace, pod.Name),
+		"probe",
+		probe.String(),
+		"address",
+		address,
+		"vm",
+		vm.String())
+	return
+}
+
+// Delete the health probe pods of a VM.
+func (r *KubeVirt) DeleteHealthProbePods(vm *planapi.VMStatus) (err error) {
+	labels := r.vmAllButMigrationLabels(vm.Ref)
+	labels[kApp] = kHealthProbe
+	list, err := r.GetPodsWithLabels(labels)
+	if err != nil {
+		return
+	}
+	for _, object := range list.Items {
+		err = r.DeleteObject(&object, vm, "Deleted health probe pod.", "pod")
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Labels for a health probe pod.
+func (r *KubeVirt) healthProbeLabels(vmRef ref.Ref, probe planapi.HealthProbe) (labels map[string]string) {
+	labels = r.vmLabels(vmRef)
+	labels[kApp] = kHealthProbe
+	labels[kHealthProbe] = probe.Name()
+	return
+}
+
+// Build the pod probing the TCP/HTTP endpoint of the VM.
+// The pod runs the virt-v2v image, already used on the
+// destination cluster. An HTTP probe passes on a status
+// below 400. The error of a failed probe is reported in
+// the termination message.
+func (r *KubeVirt) healthProbePod(vm *planapi.VMStatus, probe planapi.HealthProbe, address string, labels map[string]string) (pod *core.Pod) {
+	host := net.JoinHostPort(address, strconv.Itoa(int(probe.Port)))
+	timeout := strconv.Itoa(int(healthProbeTimeout.Seconds()))
+	var command []string
+	switch probe.Type {
+	case planapi.HealthProbeHTTP:
+		path := probe.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		command = []string{
+			"curl",
+			"--silent",
+			"--show-error",
+			"--fail",
+			"--output",
+			"/dev/null",
+			"--max-time",
+			timeout,
+			"http://" + host + path,
+		}
+	default:
+		command = []string{
+			"timeout",
+			timeout,
+			"bash",
+			"-c",
+			`exec 3<>"/dev/tcp/$0/$1"`,
+			address,
+			strconv.Itoa(int(probe.Port)),
+		}
+	}
+	resources := core.ResourceList{
+		core.ResourceCPU:    healthProbeCpu,
+		core.ResourceMemory: healthProbeMemory,
+	}
+	pod = &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:    r.Plan.VMTargetNamespace(vm.Ref),
+			GenerateName: r.getGeneratedName(vm) + "probe-",
+			Labels:       labels,
+		},
+		Spec: core.PodSpec{
+			RestartPolicy:         core.RestartPolicyNever,
+			ActiveDeadlineSeconds: ptr.To[int64](healthProbeDeadline),
+			Containers: []core.Container{
+				{
+					Name:                     "probe",
+					Image:                    Settings.Migration.VirtV2vImage,
+					Command:                  command,
+					TerminationMessagePolicy: core.TerminationMessageFallbackToLogsOnError,
+					Resources: core.ResourceRequirements{
+						Requests: resources,
+						Limits:   resources,
+					},
+					SecurityContext: &core.SecurityContext{
+						AllowPrivilegeEscalation: ptr.To(false),
+						RunAsNonRoot:             ptr.To(true),
+						RunAsUser:                ptr.To[int64](qemuUser),
+						Capabilities: &core.Capabilities{
+							Drop: []core.Capability{"ALL"},
+						},
+					},
+				},
+			},
+			SecurityContext: &core.PodSecurityContext{
+				SeccompProfile: &core.SeccompProfile{
+					Type: core.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+		},
+	}
+	return
+}
+
+// Reason of a failed health probe.
+func probeFailure(pod *core.Pod) (reason string) {
+	reason = "The probe failed."
+	if pod.Status.Reason != "" {
+		reason = fmt.Sprintf("The probe failed: %s.", pod.Status.Reason)
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil {
+			if msg := strings.TrimSpace(terminated.Message); msg != "" {
+				reason = msg
+			} else if terminated.ExitCode == 124 {
+				reason = "The probe timed out."
+			}
+		}
+	}
+	return
+}
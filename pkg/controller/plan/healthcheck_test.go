@@ -0,0 +1,222 @@
+package plan
+
+import (
+	"context"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("Plan health check", func() {
+	ginkgo.Describe("Tasks", func() {
+		ginkgo.It("should build a task for each check", func() {
+			check := &plan.HealthCheck{
+				Probes: []plan.HealthProbe{
+					{Type: plan.HealthProbeTCP, Port: 22},
+					{Type: plan.HealthProbeHTTP, Port: 8080, Path: "/healthz"},
+				},
+				Hook: &core.ObjectReference{Namespace: "ns", Name: "ready"},
+			}
+			names := []string{}
+			for _, task := range check.Tasks() {
+				names = append(names, task.Name)
+			}
+			gomega.Expect(names).To(gomega.Equal([]string{
+				plan.HealthTaskRunning,
+				plan.HealthTaskGuestAgent,
+				"TCP-22",
+				"HTTP-8080",
+				plan.HealthTaskScript,
+			}))
+			check = &plan.HealthCheck{SkipGuestAgent: true}
+			gomega.Expect(check.Tasks()).To(gomega.HaveLen(1))
+		})
+	})
+
+	ginkgo.Describe("validateHealthCheck", func() {
+		reconciler := &Reconciler{
+			Reconciler: base.Reconciler{Log: logging.WithName("planHealthCheck")},
+		}
+		newPlan := func(providerType api.ProviderType, probes ...plan.HealthProbe) *api.Plan {
+			p := &api.Plan{}
+			p.Referenced.Provider.Source = &api.Provider{Spec: api.ProviderSpec{Type: &providerType}}
+			p.Spec.HealthCheck = &plan.HealthCheck{Probes: probes}
+			return p
+		}
+
+		ginkgo.It("should accept valid probes", func() {
+			p := newPlan(
+				api.VSphere,
+				plan.HealthProbe{Type: plan.HealthProbeTCP, Port: 22},
+				plan.HealthProbe{Type: plan.HealthProbeHTTP, Port: 80, Path: "/"})
+			gomega.Expect(reconciler.validateHealthCheck(p)).To(gomega.Succeed())
+			gomega.Expect(p.Status.HasCondition(HealthCheckNotValid)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should reject probes not valid", func() {
+			p := newPlan(
+				api.VSphere,
+				plan.HealthProbe{Type: plan.HealthProbeTCP, Port: 22, Path: "/"},
+				plan.HealthProbe{Type: plan.HealthProbeTCP, Port: 22},
+				plan.HealthProbe{Type: plan.HealthProbeHTTP, Port: 0})
+			gomega.Expect(reconciler.validateHealthCheck(p)).To(gomega.Succeed())
+			cnd := p.Status.FindCondition(HealthCheckNotValid)
+			gomega.Expect(cnd).ToNot(gomega.BeNil())
+			gomega.Expect(cnd.Items).To(gomega.HaveLen(3))
+		})
+
+		ginkgo.It("should reject an EC2 source provider", func() {
+			p := newPlan(api.EC2)
+			gomega.Expect(reconciler.validateHealthCheck(p)).To(gomega.Succeed())
+			gomega.Expect(p.Status.FindCondition(HealthCheckNotValid).Reason).To(gomega.Equal(NotSupported))
+		})
+	})
+
+	ginkgo.Describe("verifyHealth", func() {
+		newMigration := func(vmi *cnv.VirtualMachineInstance) (*Migration, *plan.VMStatus, *plan.Step) {
+			vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+			vm.RestorePowerState = plan.VMPowerStateOn
+			p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "plan", UID: "plan"}}
+			p.Spec.TargetNamespace = "test"
+			p.Spec.HealthCheck = &plan.HealthCheck{
+				Probes: []plan.HealthProbe{
+					{Type: plan.HealthProbeTCP, Port: 22},
+					{Type: plan.HealthProbeHTTP, Port: 8080, Path: "/healthz"},
+				},
+			}
+			scheme := runtime.NewScheme()
+			_ = core.AddToScheme(scheme)
+			_ = cnv.AddToScheme(scheme)
+			objects := []runtime.Object{}
+			if vmi != nil {
+				target := &cnv.VirtualMachine{
+					ObjectMeta: meta.ObjectMeta{
+						Namespace: "test",
+						Name:      "vm",
+						Labels: map[string]string{
+							kMigration: "migration",
+							kPlan:      "plan",
+							kVM:        "vm-1",
+							kResource:  ResourceVMConfig,
+						},
+					},
+				}
+				objects = append(objects, target, vmi)
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+			ctx := &plancontext.Context{
+				Destination: plancontext.Destination{Client: client},
+				Log:         logging.WithName("test"),
+				Migration:   &api.Migration{ObjectMeta: meta.ObjectMeta{UID: "migration"}},
+				Plan:        p,
+			}
+			step := &plan.Step{Tasks: p.Spec.HealthCheck.Tasks()}
+			step.MarkStarted()
+			return &Migration{Context: ctx, kubevirt: KubeVirt{Context: ctx}}, vm, step
+		}
+		// Complete the health probe pods as the kubelet would.
+		complete := func(migration *Migration, vm *plan.VMStatus, phase core.PodPhase, message string) {
+			labels := migration.kubevirt.vmAllButMigrationLabels(vm.Ref)
+			labels[kApp] = kHealthProbe
+			pods, err := migration.kubevirt.GetPodsWithLabels(labels)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(pods.Items).To(gomega.HaveLen(2))
+			for i := range pods.Items {
+				pod := &pods.Items[i]
+				gomega.Expect(pod.Namespace).To(gomega.Equal("test"))
+				pod.Status.Phase = phase
+				pod.Status.ContainerStatuses = []core.ContainerStatus{
+					{
+						State: core.ContainerState{
+							Terminated: &core.ContainerStateTerminated{ExitCode: 22, Message: message},
+						},
+					},
+				}
+				gomega.Expect(migration.Destination.Client.Status().Update(context.TODO(), pod)).To(gomega.Succeed())
+			}
+		}
+		probePods := func(migration *Migration, vm *plan.VMStatus) int {
+			labels := migration.kubevirt.vmAllButMigrationLabels(vm.Ref)
+			labels[kApp] = kHealthProbe
+			pods, err := migration.kubevirt.GetPodsWithLabels(labels)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			return len(pods.Items)
+		}
+		runningVMI := func(agent bool) *cnv.VirtualMachineInstance {
+			vmi := &cnv.VirtualMachineInstance{
+				ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "vm"},
+			}
+			vmi.Status.Phase = cnv.Running
+			vmi.Status.Interfaces = []cnv.VirtualMachineInstanceNetworkInterface{{IP: "127.0.0.1"}}
+			if agent {
+				vmi.Status.Conditions = []cnv.VirtualMachineInstanceCondition{
+					{Type: cnv.VirtualMachineInstanceAgentConnected, Status: core.ConditionTrue},
+				}
+			}
+			return vmi
+		}
+
+		ginkgo.It("should pass when all of the checks pass", func() {
+			migration, vm, step := newMigration(runningVMI(true))
+			// The probes run in pods on the destination cluster.
+			ready, err := migration.verifyHealth(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(ready).To(gomega.BeFalse())
+			complete(migration, vm, core.PodSucceeded, "")
+			ready, err = migration.verifyHealth(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(ready).To(gomega.BeTrue())
+			gomega.Expect(step.Progress.Completed).To(gomega.Equal(int64(4)))
+			gomega.Expect(probePods(migration, vm)).To(gomega.BeZero())
+		})
+
+		ginkgo.It("should wait for the checks to pass", func() {
+			migration, vm, step := newMigration(runningVMI(false))
+			ready, err := migration.verifyHealth(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(ready).To(gomega.BeFalse())
+			complete(migration, vm, core.PodFailed, "curl: (22) The requested URL returned error: 404")
+			ready, err = migration.verifyHealth(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(ready).To(gomega.BeFalse())
+			task, _ := step.FindTask("HTTP-8080")
+			gomega.Expect(task.Reason).To(gomega.Equal("curl: (22) The requested URL returned error: 404"))
+			gomega.Expect(step.HasError()).To(gomega.BeFalse())
+			// The failed probes are run again by new pods.
+			gomega.Expect(probePods(migration, vm)).To(gomega.BeZero())
+			_, err = migration.verifyHealth(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(probePods(migration, vm)).To(gomega.Equal(2))
+		})
+
+		ginkgo.It("should fail when the timeout is exceeded", func() {
+			migration, vm, step := newMigration(nil)
+			step.Started = &meta.Time{Time: time.Now().Add(-time.Hour)}
+			ready, err := migration.verifyHealth(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(ready).To(gomega.BeFalse())
+			gomega.Expect(step.HasError()).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should skip the checks when the VM is not started", func() {
+			migration, vm, step := newMigration(nil)
+			vm.RestorePowerState = plan.VMPowerStateOff
+			ready, err := migration.verifyHealth(vm, step)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(ready).To(gomega.BeTrue())
+			gomega.Expect(step.HasError()).To(gomega.BeFalse())
+		})
+	})
+})
@@ -3,14 +3,19 @@ package plan
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
 	"gopkg.in/yaml.v2"
 	batch "k8s.io/api/batch/v1"
 	core "k8s.io/api/core/v1"
@@ -18,6 +23,7 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/scheme"
+	cnv "kubevirt.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	k8sutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -28,6 +34,8 @@ const (
 	kStep = "step"
 	// Hook ID label
 	kHook = "hook"
+	// Hook attempt label
+	kAttempt = "attempt"
 )
 
 // Resource label
@@ -35,6 +43,33 @@ const (
 	ResourceHookConfig = "hook-config"
 )
 
+// Hook runtime.
+const (
+	// File the hook writes its result to. The result is
+	// reported as the termination message of the container.
+	HookResultFile = core.TerminationMessagePathDefault
+	// Key of the output when the result is not a map.
+	HookOutputMessage = "message"
+	// Key of the output set when the result exceeds the limit.
+	HookOutputTruncated = "truncated"
+	// Output (bytes) of a hook kept in the task. The output
+	// is reported in the plan and migration status of each
+	// VM, the keys (sorted) and values beyond are dropped.
+	HookOutputLimit = 512
+	// Default delay (seconds) before a failed hook is retried.
+	HookBackoff = 10
+)
+
+// Target VM passed to the hook.
+type hookTarget struct {
+	// Name.
+	Name string `yaml:"name"`
+	// Namespace.
+	Namespace string `yaml:"namespace"`
+	// IP addresses reported once the VM is running.
+	IPs []string `yaml:"ips,omitempty"`
+}
+
 // Hook runner.
 type HookRunner struct {
 	*plancontext.Context
@@ -42,6 +77,13 @@ type HookRunner struct {
 	vm *planapi.VMStatus
 	// Hook.
 	hook *api.Hook
+	// Hook reference.
+	// The retry policy and timeout of the hook run.
+	ref *planapi.HookRef
+	// Attempt.
+	attempt int
+	// Target VM.
+	target *hookTarget
 }
 
 // Run.
@@ -53,6 +95,7 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 		return
 	}
 	if ref, found := vm.FindHook(vm.Phase); found {
+		r.ref = &ref
 		if r.hook, found = r.FindHook(ref.Hook); !found {
 			step.Error = &planapi.Error{
 				Reasons: []string{"Hook not found."},
@@ -64,11 +107,23 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 		step.MarkedCompleted()
 		return
 	}
+	err = r.run(&step.Task)
+	return
+}
+
+// Run the hook job and reflect the job status on the task.
+// Each attempt runs a job. A failed attempt is retried after
+// the backoff until the retries are exhausted.
+func (r *HookRunner) run(task *planapi.Task) (err error) {
+	if task.Hook == nil {
+		task.Hook = &planapi.HookRun{Attempts: 1}
+	}
+	r.attempt = task.Hook.Attempts
 	job, err := r.ensureJob()
 	if err != nil {
 		return
 	}
-	step.MarkStarted()
+	task.MarkStarted()
 	conditions := libcnd.Conditions{}
 	for _, cnd := range job.Status.Conditions {
 		conditions.SetCondition(libcnd.Condition{
@@ -78,20 +133,86 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 			Message: cnd.Message,
 		})
 	}
-	if conditions.HasCondition("Failed") {
-		step.AddError(conditions.FindCondition("Failed").Message)
-		step.MarkCompleted()
-	} else if int(job.Status.Failed) > Settings.Migration.HookRetry {
-		step.AddError("Retry limit exceeded.")
-		step.MarkCompleted()
-	} else if job.Status.Succeeded > 0 {
-		step.Progress.Completed = 1
-		step.MarkCompleted()
+	failed := conditions.FindCondition("Failed")
+	if failed == nil && job.Status.Failed == 0 && job.Status.Succeeded == 0 {
+		return
+	}
+	pods, err := r.pods(job)
+	if err != nil {
+		return
+	}
+	for _, pod := range pods {
+		task.Hook.AddPod(path.Join(pod.Namespace, pod.Name))
 	}
+	if job.Status.Succeeded > 0 {
+		task.Output = r.output(pods)
+		task.Progress.Completed = 1
+		task.MarkCompleted()
+		return
+	}
+	message := "Hook failed."
+	if failed != nil && failed.Message != "" {
+		message = failed.Message
+	}
+	failedAt := time.Now()
+	for _, cnd := range job.Status.Conditions {
+		if cnd.Type == batch.JobFailed {
+			failedAt = cnd.LastTransitionTime.Time
+		}
+	}
+	if task.Hook.Attempts <= r.retries() {
+		if time.Since(failedAt) >= r.backoff() {
+			task.Hook.Attempts++
+			r.Log.Info(
+				"Retrying (hook) job.",
+				"job",
+				path.Join(
+					job.Namespace,
+					job.Name),
+				"attempt",
+				task.Hook.Attempts)
+		}
+		return
+	}
+	if r.ref != nil && r.ref.ContinueOnFailure {
+		task.Reason = message
+		task.Progress.Completed = 1
+		task.MarkCompleted()
+		r.vm.SetCondition(libcnd.Condition{
+			Type:     api.ConditionHookFailed,
+			Status:   True,
+			Category: api.CategoryWarn,
+			Message:  "The hook has failed and the migration continued.",
+			Items:    []string{fmt.Sprintf("[%s] %s", task.Name, message)},
+			Durable:  true,
+		})
+		return
+	}
+	task.AddError(message)
+	task.MarkCompleted()
 
 	return
 }
 
+// Number of times a failed hook is retried.
+func (r *HookRunner) retries() (n int) {
+	n = Settings.Migration.HookRetry
+	if r.ref != nil && r.ref.Retries != nil {
+		n = *r.ref.Retries
+	}
+	return
+}
+
+// Delay before a failed hook is retried.
+func (r *HookRunner) backoff() (d time.Duration) {
+	n := HookBackoff
+	if r.ref != nil && r.ref.Backoff != nil {
+		n = *r.ref.Backoff
+	}
+	d = time.Duration(n) * time.Second
+	return
+}
+
 // Ensure the job.
 func (r *HookRunner) ensureJob() (job *batch.Job, err error) {
 	mp, err := r.ensureConfigMap()
@@ -110,7 +231,13 @@ func (r *HookRunner) ensureJob() (job *batch.Job, err error) {
 		err = liberr.Wrap(err)
 		return
 	}
-	if len(list.Items) == 0 {
+	for i := range list.Items {
+		if jobAttempt(&list.Items[i]) == r.attempt {
+			job = &list.Items[i]
+			break
+		}
+	}
+	if job == nil {
 		job, err = r.job(mp)
 		if err != nil {
 			return
@@ -127,7 +254,6 @@ func (r *HookRunner) ensureJob() (job *batch.Job, err error) {
 				job.Namespace,
 				job.Name))
 	} else {
-		job = &list.Items[0]
 		r.Log.V(1).Info(
 			"Found (hook) job.",
 			"job",
@@ -149,10 +275,92 @@ func (r *HookRunner) ensureJob() (job *batch.Job, err error) {
 	return
 }
 
+// Pods of the job.
+func (r *HookRunner) pods(job *batch.Job) (pods []core.Pod, err error) {
+	if job.Spec.Selector == nil {
+		return
+	}
+	selector, err := meta.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	list := core.PodList{}
+	err = r.Client.List(
+		context.TODO(),
+		&list,
+		&client.ListOptions{
+			LabelSelector: selector,
+			Namespace:     job.Namespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	pods = list.Items
+	return
+}
+
+// Read the result the hook has written to the termination
+// message of the succeeded pod. A (yaml or json) map is the
+// output, otherwise the result is reported as the `message`.
+func (r *HookRunner) output(pods []core.Pod) (output map[string]string) {
+	for _, pod := range pods {
+		if pod.Status.Phase != core.PodSucceeded {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != "hook" || status.State.Terminated == nil {
+				continue
+			}
+			message := strings.TrimSpace(status.State.Terminated.Message)
+			if message == "" {
+				return
+			}
+			output = map[string]string{}
+			if yaml.Unmarshal([]byte(message), &output) != nil || len(output) == 0 {
+				output = map[string]string{HookOutputMessage: message}
+			}
+			output = truncateOutput(output)
+			return
+		}
+	}
+	return
+}
+
+// Truncate the output to the limit.
+func truncateOutput(output map[string]string) (truncated map[string]string) {
+	keys := make([]string, 0, len(output))
+	for k := range output {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	truncated = map[string]string{}
+	size := 0
+	for _, k := range keys {
+		v := output[k]
+		if size+len(k)+len(v) > HookOutputLimit {
+			if remaining := HookOutputLimit - size - len(k); remaining > 0 {
+				truncated[k] = strings.ToValidUTF8(v[:remaining], "")
+			}
+			truncated[HookOutputTruncated] = "true"
+			return
+		}
+		truncated[k] = v
+		size += len(k) + len(v)
+	}
+	return
+}
+
 // Build the Job.
 func (r *HookRunner) job(mp *core.ConfigMap) (job *batch.Job, err error) {
+	err = r.ensureTarget()
+	if err != nil {
+		return
+	}
 	template := r.template(mp)
-	backOff := int32(1)
+	// Failed attempts are retried by the runner.
+	backOff := int32(0)
 	job = &batch.Job{
 		Spec: batch.JobSpec{
 			Template:     *template,
@@ -164,9 +372,9 @@ func (r *HookRunner) job(mp *core.ConfigMap) (job *batch.Job, err error) {
 				strings.Join([]string{
 					r.Plan.Name,
 					r.vm.ID,
-					r.vm.Phase},
+					r.step()},
 					"-") + "-"),
-			Labels: r.labels(),
+			Labels: r.jobLabels(),
 		},
 	}
 	err = k8sutil.SetOwnerReference(r.Plan, job, scheme.Scheme)
@@ -187,6 +395,7 @@ func (r *HookRunner) template(mp *core.ConfigMap) (template *core.PodTemplateSpe
 				{
 					Name:  "hook",
 					Image: r.hook.Spec.Image,
+					Env:   r.environment(),
 					Resources: core.ResourceRequirements{
 						Requests: core.ResourceList{
 							core.ResourceCPU:    resource.MustParse(Settings.Migration.HooksContainerRequestsCpu),
@@ -220,6 +429,9 @@ func (r *HookRunner) template(mp *core.ConfigMap) (template *core.PodTemplateSpe
 		},
 	}
 	deadline := r.hook.Spec.Deadline
+	if r.ref != nil && r.ref.Timeout > 0 {
+		deadline = r.ref.Timeout
+	}
 	if deadline > 0 {
 		template.Spec.ActiveDeadlineSeconds = &deadline
 	}
@@ -299,6 +511,14 @@ func (r *HookRunner) configMap() (mp *core.ConfigMap, err error) {
 	if err != nil {
 		return
 	}
+	target, err := r.vmTarget()
+	if err != nil {
+		return
+	}
+	results, err := r.results()
+	if err != nil {
+		return
+	}
 	mp = &core.ConfigMap{
 		ObjectMeta: meta.ObjectMeta{
 			Labels:    r.labels(),
@@ -307,13 +527,15 @@ func (r *HookRunner) configMap() (mp *core.ConfigMap, err error) {
 				strings.Join([]string{
 					r.Plan.Name,
 					r.vm.ID,
-					r.vm.Phase},
+					r.step()},
 					"-")) + "-",
 		},
 		Data: map[string]string{
 			"workload.yml": workload,
 			"playbook.yml": playbook,
 			"plan.yml":     plan,
+			"target.yml":   target,
+			"results.yml":  results,
 		},
 	}
 
@@ -362,14 +584,212 @@ func (r *HookRunner) plan() (plan string, err error) {
 	return
 }
 
+// Target VM (yaml).
+func (r *HookRunner) vmTarget() (target string, err error) {
+	err = r.ensureTarget()
+	if err != nil {
+		return
+	}
+	b, err := yaml.Marshal(r.target)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	target = string(b)
+	return
+}
+
+// Output of the hooks that have already run (yaml),
+// keyed by pipeline step.
+func (r *HookRunner) results() (results string, err error) {
+	outputs := map[string]map[string]string{}
+	for _, step := range r.vm.Pipeline {
+		if len(step.Output) > 0 {
+			outputs[step.Name] = step.Output
+		}
+	}
+	b, err := yaml.Marshal(outputs)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	results = string(b)
+	return
+}
+
+// Resolve the target VM.
+// The name and namespace of the VM instance, when it
+// exists, otherwise the name and namespace the VM is
+// created with.
+func (r *HookRunner) ensureTarget() (err error) {
+	if r.target != nil {
+		return
+	}
+	target := &hookTarget{
+		Name:      r.vm.Name,
+		Namespace: r.Plan.VMTargetNamespace(r.vm.Ref),
+	}
+	if r.vm.NewName != "" {
+		target.Name = r.vm.NewName
+	}
+	kubevirt := KubeVirt{Context: r.Context}
+	vmi, found, err := kubevirt.getVMI(r.vm)
+	if err != nil {
+		return
+	}
+	if found {
+		target = vmiTarget(vmi)
+	}
+	r.target = target
+	return
+}
+
+// Environment of the hook container.
+func (r *HookRunner) environment() (env []core.EnvVar) {
+	env = []core.EnvVar{
+		{Name: "HOOK_STEP", Value: r.vm.Phase},
+		{Name: "HOOK_RESULT_FILE", Value: HookResultFile},
+	}
+	if r.target != nil {
+		ip := ""
+		if len(r.target.IPs) > 0 {
+			ip = r.target.IPs[0]
+		}
+		env = append(
+			env,
+			core.EnvVar{Name: "TARGET_VM_NAME", Value: r.target.Name},
+			core.EnvVar{Name: "TARGET_VM_NAMESPACE", Value: r.target.Namespace},
+			core.EnvVar{Name: "TARGET_VM_IP", Value: ip})
+	}
+	return
+}
+
+// Step of the hook run used to name and label the created resources.
+// The precopy hook runs once for each precopy.
+func (r *HookRunner) step() (step string) {
+	step = r.vm.Phase
+	if step == api.PhasePrecopyHook && r.vm.Warm != nil {
+		step = fmt.Sprintf("%s-%d", step, len(r.vm.Warm.Precopies))
+	}
+	return
+}
+
 // Labels for created resources.
 func (r *HookRunner) labels() map[string]string {
 	return map[string]string{
 		kPlan:      string(r.Plan.UID),
 		kMigration: string(r.Migration.UID),
 		kVM:        r.vm.ID,
-		kStep:      r.vm.Phase,
+		kStep:      r.step(),
 		kHook:      string(r.hook.UID),
 		kResource:  ResourceHookConfig,
 	}
 }
+
+// Labels for the job of the attempt.
+func (r *HookRunner) jobLabels() map[string]string {
+	jobLabels := r.labels()
+	jobLabels[kAttempt] = strconv.Itoa(r.attempt)
+	return jobLabels
+}
+
+// Attempt of the hook job.
+// Jobs created before the attempt label was added
+// are the first attempt.
+func jobAttempt(job *batch.Job) (attempt int) {
+	attempt = 1
+	if value, found := job.Labels[kAttempt]; found {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			attempt = 0
+			return
+		}
+		attempt = n
+	}
+	return
+}
+
+// Move a failed or canceled VM to the failure hook phase
+// when the VM has a failure hook that has not run. The hook
+// step is appended to the pipeline. Returns true while the
+// hook is running.
+func (r *Migration) failureHook(vm *planapi.VMStatus) (running bool) {
+	if vm.Phase == api.PhaseFailureHook {
+		running = true
+		return
+	}
+	if _, found := vm.FindHook(api.PhaseFailureHook); !found || !vm.MarkedStarted() {
+		return
+	}
+	if _, found := vm.FindStep(api.PhaseFailureHook); found {
+		return
+	}
+	vm.Pipeline = append(
+		vm.Pipeline,
+		&planapi.Step{
+			Task: planapi.Task{
+				Name:        api.PhaseFailureHook,
+				Description: "Run failure hook.",
+				Progress:    libitr.Progress{Total: 1},
+				Phase:       api.StepPending,
+			},
+		})
+	vm.Phase = api.PhaseFailureHook
+	r.Log.Info(
+		"Migration [FAILURE HOOK]",
+		"vm",
+		vm.String())
+	running = true
+	return
+}
+
+// Run the failure hook.
+// The VM is completed once the hook has completed,
+// whether or not it has succeeded.
+func (r *Migration) executeFailureHook(vm *planapi.VMStatus) (err error) {
+	runner := HookRunner{Context: r.Context}
+	err = runner.Run(vm)
+	if err != nil {
+		return
+	}
+	step, found := vm.FindStep(vm.Phase)
+	if !found {
+		vm.Phase = api.PhaseCompleted
+		return
+	}
+	step.Phase = api.StepRunning
+	if step.MarkedCompleted() || step.HasError() {
+		step.MarkCompleted()
+		step.Phase = api.StepCompleted
+		vm.Phase = api.PhaseCompleted
+	}
+	return
+}
+
+// Run the failure hook of a canceled VM.
+// Returns true while the hook is running.
+func (r *Migration) cancelHook(vm *planapi.VMStatus) (running bool, err error) {
+	if !r.failureHook(vm) {
+		return
+	}
+	err = r.executeFailureHook(vm)
+	if err != nil {
+		return
+	}
+	running = vm.Phase == api.PhaseFailureHook
+	return
+}
+
+// Target of the VM instance.
+func vmiTarget(vmi *cnv.VirtualMachineInstance) (target *hookTarget) {
+	target = &hookTarget{
+		Name:      vmi.Name,
+		Namespace: vmi.Namespace,
+	}
+	for _, iface := range vmi.Status.Interfaces {
+		if iface.IP != "" {
+			target.IPs = append(target.IPs, iface.IP)
+		}
+	}
+	return
+}
@@ -0,0 +1,385 @@
+package plan
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("Plan hooks", func() {
+	ginkgo.Describe("hookSteps", func() {
+		newPlan := func(providerType api.ProviderType, migrationType api.MigrationType) *api.Plan {
+			p := &api.Plan{}
+			p.Spec.Type = migrationType
+			p.Referenced.Provider.Source = &api.Provider{Spec: api.ProviderSpec{Type: &providerType}}
+			host := api.OpenShift
+			p.Referenced.Provider.Destination = &api.Provider{Spec: api.ProviderSpec{Type: &host}}
+			return p
+		}
+		names := func(steps map[string]bool) (list []string) {
+			for name := range steps {
+				list = append(list, name)
+			}
+			return
+		}
+
+		ginkgo.It("should support the precopy and cutover hooks for warm migrations", func() {
+			steps := hookSteps(newPlan(api.VSphere, api.MigrationWarm))
+			gomega.Expect(names(steps)).To(gomega.ConsistOf(
+				api.PhasePreHook,
+				api.PhasePostHook,
+				api.PhaseFailureHook,
+				api.PhasePrePowerOffHook,
+				api.PhasePrecopyHook,
+				api.PhaseCutoverHook,
+				api.PhasePreConversionHook))
+		})
+
+		ginkgo.It("should support the pre-conversion hook only when the disks are transferred first", func() {
+			steps := hookSteps(newPlan(api.VSphere, api.MigrationCold))
+			gomega.Expect(steps[api.PhasePreConversionHook]).To(gomega.BeTrue())
+			gomega.Expect(steps[api.PhasePrecopyHook]).To(gomega.BeFalse())
+			steps = hookSteps(newPlan(api.Ova, api.MigrationCold))
+			gomega.Expect(steps[api.PhasePreConversionHook]).To(gomega.BeFalse())
+			gomega.Expect(steps[api.PhasePrePowerOffHook]).To(gomega.BeTrue())
+			p := newPlan(api.VSphere, api.MigrationCold)
+			p.Spec.SkipGuestConversion = true
+			gomega.Expect(hookSteps(p)[api.PhasePreConversionHook]).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should support only the pre, post and failure hooks for live migrations", func() {
+			steps := hookSteps(newPlan(api.OpenShift, api.MigrationLive))
+			gomega.Expect(names(steps)).To(gomega.ConsistOf(
+				api.PhasePreHook,
+				api.PhasePostHook,
+				api.PhaseFailureHook))
+		})
+	})
+
+	ginkgo.Describe("HookRunner", func() {
+		ginkgo.It("should label each run of the precopy hook", func() {
+			vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+			vm.Warm = &plan.Warm{Precopies: []plan.Precopy{{}, {}}}
+			runner := HookRunner{
+				Context: &plancontext.Context{
+					Plan:      &api.Plan{ObjectMeta: meta.ObjectMeta{UID: "plan"}},
+					Migration: &api.Migration{ObjectMeta: meta.ObjectMeta{UID: "migration"}},
+				},
+				vm:   vm,
+				hook: &api.Hook{ObjectMeta: meta.ObjectMeta{UID: "hook"}},
+			}
+			vm.Phase = api.PhasePrecopyHook
+			gomega.Expect(runner.labels()[kStep]).To(gomega.Equal("PrecopyHook-2"))
+			vm.Phase = api.PhaseCutoverHook
+			gomega.Expect(runner.labels()[kStep]).To(gomega.Equal(api.PhaseCutoverHook))
+		})
+	})
+
+	ginkgo.Describe("hook runtime", func() {
+		newRunner := func(objects ...runtime.Object) *HookRunner {
+			scheme := runtime.NewScheme()
+			_ = core.AddToScheme(scheme)
+			_ = batch.AddToScheme(scheme)
+			_ = cnv.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+			vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1", Name: "vm"}}}
+			vm.NewName = "vm-new"
+			vm.Phase = api.PhasePostHook
+			vm.Pipeline = []*plan.Step{
+				{Task: plan.Task{Name: api.PhasePreHook, Output: map[string]string{"ticket": "1234"}}},
+				{Task: plan.Task{Name: api.PhasePostHook}},
+			}
+			p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "test", UID: "plan"}}
+			p.Spec.TargetNamespace = "target"
+			ctx := &plancontext.Context{
+				Client:      client,
+				Destination: plancontext.Destination{Client: client},
+				Plan:        p,
+				Migration:   &api.Migration{ObjectMeta: meta.ObjectMeta{UID: "migration"}},
+				Log:         logging.WithName("test"),
+			}
+			return &HookRunner{
+				Context: ctx,
+				vm:      vm,
+				hook:    &api.Hook{ObjectMeta: meta.ObjectMeta{UID: "hook"}},
+			}
+		}
+		newJob := func() *batch.Job {
+			job := &batch.Job{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "hook"}}
+			job.Spec.Selector = &meta.LabelSelector{MatchLabels: map[string]string{"job": "hook"}}
+			return job
+		}
+		newPod := func(phase core.PodPhase, message string) *core.Pod {
+			pod := &core.Pod{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "hook-" + string(phase),
+					Labels:    map[string]string{"job": "hook"},
+				},
+			}
+			pod.Status.Phase = phase
+			pod.Status.ContainerStatuses = []core.ContainerStatus{
+				{
+					Name: "hook",
+					State: core.ContainerState{
+						Terminated: &core.ContainerStateTerminated{Message: message},
+					},
+				},
+			}
+			return pod
+		}
+
+		ginkgo.It("should read the output of the succeeded pod", func() {
+			runner := newRunner(
+				newPod(core.PodFailed, "failed"),
+				newPod(core.PodSucceeded, "{\"dns\": \"updated\", \"records\": 2}"))
+			pods, err := runner.pods(newJob())
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(pods).To(gomega.HaveLen(2))
+			gomega.Expect(runner.output(pods)).To(gomega.Equal(map[string]string{"dns": "updated", "records": "2"}))
+		})
+
+		ginkgo.It("should report a result that is not a map as the message", func() {
+			runner := newRunner(newPod(core.PodSucceeded, "done\n"))
+			pods, err := runner.pods(newJob())
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(runner.output(pods)).To(gomega.Equal(map[string]string{HookOutputMessage: "done"}))
+		})
+
+		ginkgo.It("should truncate the output to the limit", func() {
+			output := truncateOutput(map[string]string{
+				"a": strings.Repeat("x", HookOutputLimit-10),
+				"b": strings.Repeat("y", 20),
+				"c": "dropped",
+			})
+			gomega.Expect(output).To(gomega.HaveLen(3))
+			gomega.Expect(output["b"]).To(gomega.Equal(strings.Repeat("y", 8)))
+			gomega.Expect(output).To(gomega.HaveKeyWithValue(HookOutputTruncated, "true"))
+			gomega.Expect(output).ToNot(gomega.HaveKey("c"))
+		})
+
+		ginkgo.It("should pass the results of the previous hooks", func() {
+			results, err := newRunner().results()
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(results).To(gomega.Equal("PreHook:\n  ticket: \"1234\"\n"))
+		})
+
+		ginkgo.It("should pass the target VM before it is created", func() {
+			runner := newRunner()
+			gomega.Expect(runner.ensureTarget()).To(gomega.Succeed())
+			gomega.Expect(*runner.target).To(gomega.Equal(hookTarget{Name: "vm-new", Namespace: "target"}))
+			gomega.Expect(runner.environment()).To(gomega.ContainElement(
+				core.EnvVar{Name: "TARGET_VM_NAME", Value: "vm-new"}))
+		})
+
+		ginkgo.It("should pass the addresses of the target VM instance", func() {
+			target := &cnv.VirtualMachine{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "target",
+					Name:      "vm-new",
+					Labels: map[string]string{
+						kMigration: "migration",
+						kPlan:      "plan",
+						kVM:        "vm-1",
+						kResource:  ResourceVMConfig,
+					},
+				},
+			}
+			vmi := &cnv.VirtualMachineInstance{ObjectMeta: meta.ObjectMeta{Namespace: "target", Name: "vm-new"}}
+			vmi.Status.Interfaces = []cnv.VirtualMachineInstanceNetworkInterface{{IP: "10.0.0.2"}, {}, {IP: "10.0.1.2"}}
+			runner := newRunner(target, vmi)
+			gomega.Expect(runner.ensureTarget()).To(gomega.Succeed())
+			gomega.Expect(runner.target.IPs).To(gomega.Equal([]string{"10.0.0.2", "10.0.1.2"}))
+			gomega.Expect(runner.environment()).To(gomega.ContainElement(
+				core.EnvVar{Name: "TARGET_VM_IP", Value: "10.0.0.2"}))
+		})
+	})
+
+	ginkgo.Describe("hook retry", func() {
+		labels := func(attempt string) map[string]string {
+			return map[string]string{
+				kPlan:      "plan",
+				kMigration: "migration",
+				kVM:        "vm-1",
+				kStep:      api.PhasePostHook,
+				kHook:      "hook",
+				kResource:  ResourceHookConfig,
+				kAttempt:   attempt,
+			}
+		}
+		newFailedJob := func(attempt string, failedAt time.Time) *batch.Job {
+			job := &batch.Job{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "hook-" + attempt,
+					Labels:    labels(attempt),
+				},
+			}
+			job.Spec.Selector = &meta.LabelSelector{MatchLabels: map[string]string{"job": "hook-" + attempt}}
+			job.Status.Failed = 1
+			job.Status.Conditions = []batch.JobCondition{
+				{
+					Type:               batch.JobFailed,
+					Status:             core.ConditionTrue,
+					Message:            "Job has reached the specified backoff limit",
+					LastTransitionTime: meta.NewTime(failedAt),
+				},
+			}
+			return job
+		}
+		newRunner := func(hookRef *plan.HookRef, objects ...runtime.Object) *HookRunner {
+			scheme := runtime.NewScheme()
+			_ = core.AddToScheme(scheme)
+			_ = batch.AddToScheme(scheme)
+			mp := &core.ConfigMap{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "hook",
+					Labels:    labels("1"),
+				},
+			}
+			delete(mp.Labels, kAttempt)
+			pod := &core.Pod{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "hook-1-pod",
+					Labels:    map[string]string{"job": "hook-1"},
+				},
+			}
+			objects = append(objects, mp, pod)
+			client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+			vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1", Name: "vm"}}}
+			vm.Phase = api.PhasePostHook
+			return &HookRunner{
+				Context: &plancontext.Context{
+					Client:    client,
+					Plan:      &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "test", UID: "plan"}},
+					Migration: &api.Migration{ObjectMeta: meta.ObjectMeta{UID: "migration"}},
+					Log:       logging.WithName("test"),
+				},
+				vm:   vm,
+				hook: &api.Hook{ObjectMeta: meta.ObjectMeta{UID: "hook"}},
+				ref:  hookRef,
+			}
+		}
+		retries := func(n int) *int { return &n }
+
+		ginkgo.It("should retry a failed hook after the backoff", func() {
+			hookRef := &plan.HookRef{Retries: retries(1), Backoff: retries(60)}
+			task := &plan.Task{Name: api.PhasePostHook}
+			// Within the backoff.
+			runner := newRunner(hookRef, newFailedJob("1", time.Now()))
+			gomega.Expect(runner.run(task)).To(gomega.Succeed())
+			gomega.Expect(task.Hook.Attempts).To(gomega.Equal(1))
+			gomega.Expect(task.Hook.Pods).To(gomega.Equal([]string{"test/hook-1-pod"}))
+			gomega.Expect(task.MarkedCompleted()).To(gomega.BeFalse())
+			// Backoff elapsed.
+			runner = newRunner(hookRef, newFailedJob("1", time.Now().Add(-time.Minute)))
+			gomega.Expect(runner.run(task)).To(gomega.Succeed())
+			gomega.Expect(task.Hook.Attempts).To(gomega.Equal(2))
+			gomega.Expect(task.HasError()).To(gomega.BeFalse())
+			gomega.Expect(task.MarkedCompleted()).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should fail once the retries are exhausted", func() {
+			task := &plan.Task{Name: api.PhasePostHook}
+			runner := newRunner(&plan.HookRef{Retries: retries(0)}, newFailedJob("1", time.Now()))
+			gomega.Expect(runner.run(task)).To(gomega.Succeed())
+			gomega.Expect(task.HasError()).To(gomega.BeTrue())
+			gomega.Expect(task.MarkedCompleted()).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should not start a job again when it has no attempt label", func() {
+			task := &plan.Task{Name: api.PhasePostHook}
+			job := newFailedJob("1", time.Now())
+			delete(job.Labels, kAttempt)
+			job.Status.Failed = 0
+			job.Status.Conditions = nil
+			runner := newRunner(&plan.HookRef{}, job)
+			gomega.Expect(runner.run(task)).To(gomega.Succeed())
+			list := batch.JobList{}
+			gomega.Expect(runner.Client.List(context.TODO(), &list)).To(gomega.Succeed())
+			gomega.Expect(list.Items).To(gomega.HaveLen(1))
+			gomega.Expect(task.MarkedStarted()).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should report a failure as a warning when the migration continues on failure", func() {
+			task := &plan.Task{Name: api.PhasePostHook}
+			hookRef := &plan.HookRef{Retries: retries(0), ContinueOnFailure: true}
+			runner := newRunner(hookRef, newFailedJob("1", time.Now()))
+			gomega.Expect(runner.run(task)).To(gomega.Succeed())
+			gomega.Expect(task.HasError()).To(gomega.BeFalse())
+			gomega.Expect(task.MarkedCompleted()).To(gomega.BeTrue())
+			gomega.Expect(task.Reason).To(gomega.Equal("Job has reached the specified backoff limit"))
+			gomega.Expect(runner.vm.HasCondition(api.ConditionHookFailed)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("failureHook", func() {
+		newMigration := func() *Migration {
+			return &Migration{
+				Context: &plancontext.Context{
+					Plan: &api.Plan{},
+					Log:  logging.WithName("test"),
+				},
+			}
+		}
+		newVM := func(hooks ...string) *plan.VMStatus {
+			vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1", Name: "vm"}}}
+			for _, step := range hooks {
+				vm.Hooks = append(
+					vm.Hooks,
+					plan.HookRef{
+						Step: step,
+						Hook: core.ObjectReference{Namespace: "test", Name: "hook"},
+					})
+			}
+			vm.Pipeline = []*plan.Step{{Task: plan.Task{Name: "Initialize"}}}
+			vm.Phase = api.PhaseCopyDisks
+			vm.MarkStarted()
+			return vm
+		}
+
+		ginkgo.It("should not run without a failure hook", func() {
+			vm := newVM(api.PhasePreHook)
+			gomega.Expect(newMigration().failureHook(vm)).To(gomega.BeFalse())
+			gomega.Expect(vm.Pipeline).To(gomega.HaveLen(1))
+			gomega.Expect(vm.Phase).To(gomega.Equal(api.PhaseCopyDisks))
+		})
+
+		ginkgo.It("should not run when the VM has not started", func() {
+			vm := newVM(api.PhaseFailureHook)
+			vm.MarkReset()
+			gomega.Expect(newMigration().failureHook(vm)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should append the hook step and run only once", func() {
+			runner := newMigration()
+			vm := newVM(api.PhaseFailureHook)
+			gomega.Expect(runner.failureHook(vm)).To(gomega.BeTrue())
+			gomega.Expect(vm.Phase).To(gomega.Equal(api.PhaseFailureHook))
+			gomega.Expect(vm.Pipeline).To(gomega.HaveLen(2))
+			// Running.
+			gomega.Expect(runner.failureHook(vm)).To(gomega.BeTrue())
+			gomega.Expect(vm.Pipeline).To(gomega.HaveLen(2))
+			// Completed.
+			vm.Phase = api.PhaseCompleted
+			gomega.Expect(runner.failureHook(vm)).To(gomega.BeFalse())
+			gomega.Expect(vm.Pipeline).To(gomega.HaveLen(2))
+		})
+	})
+})
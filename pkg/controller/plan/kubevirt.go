@@ -71,6 +71,9 @@ const (
 	// Annotation to specify the default route for the transfer network.
 	// To be set on the transfer network NAD by the end user.
 	AnnForkliftNetworkRoute = "forklift.konveyor.io/route"
+	// Ingress bandwidth of the transfer pods (value=bits/s quantity).
+	// Honored by the CNI bandwidth plugin.
+	AnnIngressBandwidth = "kubernetes.io/ingress-bandwidth"
 	// Special value for AnnForkliftNetworkRoute to explicitly request no gateway.
 	// Use this to enable modern k8s.v1.cni.cncf.io/networks annotation without default-route.
 	AnnForkliftRouteValueNone = "none"
@@ -208,7 +211,22 @@ func (r *KubeVirt) VirtualMachineMap() (mp VirtualMachineMap, err error) {
 
 // List VirtualMachine CRs.
 // Each VirtualMachine represents an imported kubevirt VM with associated DataVolumes.
+// The VMs are listed in each of the plan target namespaces.
 func (r *KubeVirt) ListVMs() ([]VirtualMachine, error) {
+	list := []VirtualMachine{}
+	for _, namespace := range r.Plan.TargetNamespaces() {
+		namespaced, err := r.listVMs(namespace)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, namespaced...)
+	}
+
+	return list, nil
+}
+
+// List VirtualMachine CRs in the namespace.
+func (r *KubeVirt) listVMs(namespace string) ([]VirtualMachine, error) {
 	planLabels := r.planLabels()
 	delete(planLabels, kMigration)
 	vList := &cnv.VirtualMachineList{}
@@ -217,7 +235,7 @@ func (r *KubeVirt) ListVMs() ([]VirtualMachine, error) {
 		vList,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(planLabels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     namespace,
 		},
 	)
 	if err != nil {
@@ -236,7 +254,7 @@ func (r *KubeVirt) ListVMs() ([]VirtualMachine, error) {
 	err = r.Destination.Client.List(
 		context.TODO(),
 		dvList,
-		r.getListOptionsNamespaced(),
+		r.getListOptionsNamespaced(namespace),
 	)
 	if err != nil {
 		return nil, liberr.Wrap(err)
@@ -249,7 +267,7 @@ func (r *KubeVirt) ListVMs() ([]VirtualMachine, error) {
 				pvc := &core.PersistentVolumeClaim{}
 				err = r.Destination.Client.Get(
 					context.TODO(),
-					types.NamespacedName{Namespace: r.Plan.Spec.TargetNamespace, Name: dv.Name},
+					types.NamespacedName{Namespace: namespace, Name: dv.Name},
 					pvc,
 				)
 				if err != nil && !k8serr.IsNotFound(err) {
@@ -268,16 +286,21 @@ func (r *KubeVirt) ListVMs() ([]VirtualMachine, error) {
 	return list, nil
 }
 
-// Ensure the namespace exists on the destination.
+// Ensure the namespaces exist on the destination.
+// The plan target namespace and the namespaces
+// resolved by the placement policy.
 func (r *KubeVirt) EnsureNamespace() error {
-	err := ensureNamespace(r.Plan, r.Destination.Client)
-	if err == nil {
+	for _, namespace := range derivedNamespaces(r.Plan) {
+		err := ensureNamespace(namespace, r.Destination.Client)
+		if err != nil {
+			return err
+		}
 		r.Log.Info(
 			"Created namespace.",
 			"import",
-			r.Plan.Spec.TargetNamespace)
+			namespace)
 	}
-	return err
+	return nil
 }
 
 // Ensure the config map that contains extra configuration for virt-v2v exists on the destination.
@@ -297,14 +320,17 @@ func (r *KubeVirt) EnsureExtraV2vConfConfigMap() error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = ensureConfigMap(configMap, genExtraV2vConfConfigMapName, r.Plan, r.Destination.Client)
-	if err == nil {
+	for _, namespace := range derivedNamespaces(r.Plan) {
+		err = ensureConfigMap(configMap.DeepCopy(), genExtraV2vConfConfigMapName, r.Plan, namespace, r.Destination.Client)
+		if err != nil {
+			return err
+		}
 		r.Log.Info(
 			"Created config map for extra configuration for virt-v2v.",
 			"target namespace",
-			r.Plan.Spec.TargetNamespace)
+			namespace)
 	}
-	return err
+	return nil
 }
 
 func genExtraV2vConfConfigMapName(plan *api.Plan) string {
@@ -326,7 +352,7 @@ func (r *KubeVirt) GetImporterPod(pvc core.PersistentVolumeClaim) (pod *core.Pod
 		context.TODO(),
 		types.NamespacedName{
 			Name:      pvc.Annotations[AnnImporterPodName],
-			Namespace: r.Plan.Spec.TargetNamespace,
+			Namespace: pvc.Namespace,
 		},
 		pod,
 	)
@@ -354,7 +380,7 @@ func (r *KubeVirt) getImporterPods(pvc *core.PersistentVolumeClaim) (pods []core
 		context.TODO(),
 		podList,
 		&client.ListOptions{
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     pvc.Namespace,
 			LabelSelector: k8slabels.SelectorFromSet(map[string]string{"app": "containerized-data-importer"}),
 		},
 	)
@@ -387,6 +413,26 @@ func (r *KubeVirt) DeleteDataVolumes(vm *plan.VMStatus) (err error) {
 	return
 }
 
+// Delete the PersistentVolumeClaims created for the VM.
+// PersistentVolumeClaims provided for conversion only
+// migrations are not owned by the migration and are kept.
+func (r *KubeVirt) DeletePVCs(vm *plan.VMStatus) (err error) {
+	if r.Plan.Spec.Type == api.MigrationOnlyConversion {
+		return
+	}
+	pvcs, err := r.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	for _, pvc := range pvcs {
+		err = r.DeleteObject(pvc, vm, "Deleted PVC.", "pvc")
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
 // Delete the importer pods for a PersistentVolumeClaim.
 func (r *KubeVirt) DeleteImporterPods(pvc *core.PersistentVolumeClaim) (err error) {
 	pods, err := r.getImporterPods(pvc)
@@ -428,7 +474,7 @@ func (r *KubeVirt) DeleteJobs(vm *plan.VMStatus) (err error) {
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(vmLabels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		},
 	)
 	if err != nil {
@@ -462,7 +508,7 @@ func (r *KubeVirt) DeleteJobs(vm *plan.VMStatus) (err error) {
 			podList,
 			&client.ListOptions{
 				LabelSelector: k8slabels.SelectorFromSet(map[string]string{"job-name": job}),
-				Namespace:     r.Plan.Spec.TargetNamespace,
+				Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 			},
 		)
 		if err != nil {
@@ -497,7 +543,7 @@ func (r *KubeVirt) EnsureVM(vm *plan.VMStatus) error {
 		vms,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.vmLabels(vm.Ref)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		},
 	)
 	if err != nil {
@@ -532,7 +578,7 @@ func (r *KubeVirt) EnsureVM(vm *plan.VMStatus) error {
 		dvs,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.vmLabels(vm.Ref)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		})
 	if err != nil {
 		return liberr.Wrap(err)
@@ -565,7 +611,7 @@ func (r *KubeVirt) DeleteSecret(vm *plan.VMStatus) (err error) {
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(vmLabels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		},
 	)
 	if err != nil {
@@ -590,7 +636,7 @@ func (r *KubeVirt) DeleteConfigMap(vm *plan.VMStatus) (err error) {
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(vmLabels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		},
 	)
 	if err != nil {
@@ -615,7 +661,7 @@ func (r *KubeVirt) DeleteVM(vm *plan.VMStatus) (err error) {
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(vmLabels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		},
 	)
 	if err != nil {
@@ -659,7 +705,7 @@ func (r *KubeVirt) DataVolumes(vm *plan.VMStatus) (dataVolumes []cdi.DataVolume,
 	}
 	var vddkConfigMap *core.ConfigMap
 	if r.Source.Provider.UseVddkAioOptimization() {
-		vddkConfigMap, err = r.ensureVddkConfigMap()
+		vddkConfigMap, err = r.ensureVddkConfigMap(r.Plan.VMTargetNamespace(vm.Ref))
 		if err != nil {
 			return nil, err
 		}
@@ -697,7 +743,7 @@ func (r *KubeVirt) EnsureDataVolumes(vm *plan.VMStatus, dataVolumes []cdi.DataVo
 		dataVolumeList,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.vmLabels(vm.Ref)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		})
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -723,7 +769,7 @@ func (r *KubeVirt) EnsureDataVolumes(vm *plan.VMStatus, dataVolumes []cdi.DataVo
 	return
 }
 
-func (r *KubeVirt) vddkConfigMap(labels map[string]string) (*core.ConfigMap, error) {
+func (r *KubeVirt) vddkConfigMap(labels map[string]string, namespace string) (*core.ConfigMap, error) {
 	data := make(map[string]string)
 	if r.Source.Provider.UseVddkAioOptimization() {
 		vddkConfig := r.Source.Provider.Spec.Settings[api.VddkConfig]
@@ -739,16 +785,16 @@ func (r *KubeVirt) vddkConfigMap(labels map[string]string) (*core.ConfigMap, err
 		Data: data,
 		ObjectMeta: meta.ObjectMeta{
 			GenerateName: genVddkConfConfigMapName(r.Plan),
-			Namespace:    r.Plan.Spec.TargetNamespace,
+			Namespace:    namespace,
 			Labels:       labels,
 		},
 	}
 	return &configMap, nil
 }
 
-func (r *KubeVirt) ensureVddkConfigMap() (configMap *core.ConfigMap, err error) {
+func (r *KubeVirt) ensureVddkConfigMap(namespace string) (configMap *core.ConfigMap, err error) {
 	labels := r.vddkLabels()
-	newConfigMap, err := r.vddkConfigMap(labels)
+	newConfigMap, err := r.vddkConfigMap(labels, namespace)
 	if err != nil {
 		return
 	}
@@ -759,7 +805,7 @@ func (r *KubeVirt) ensureVddkConfigMap() (configMap *core.ConfigMap, err error)
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(labels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     namespace,
 		},
 	)
 	if err != nil {
@@ -828,7 +874,7 @@ func (r *KubeVirt) getDVs(vm *plan.VMStatus) (edvs []ExtendedDataVolume, err err
 		dvsList,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.vmAllButMigrationLabels(vm.Ref)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		})
 
 	if err != nil {
@@ -883,7 +929,7 @@ func (r *KubeVirt) getPVCs(vmRef ref.Ref) (pvcs []*core.PersistentVolumeClaim, e
 		pvcsList,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(labelSelector),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vmRef),
 		},
 	)
 
@@ -951,7 +997,7 @@ func (r *KubeVirt) createPodToBindPVCs(vm *plan.VMStatus, pvcNames []string) (er
 	allowPrivilageEscalation := false
 	pod := &core.Pod{
 		ObjectMeta: meta.ObjectMeta{
-			Namespace:    r.Plan.Spec.TargetNamespace,
+			Namespace:    r.Plan.VMTargetNamespace(vm.Ref),
 			Labels:       r.consumerLabels(vm.Ref, false),
 			GenerateName: r.getGeneratedName(vm) + "pvcinit-",
 		},
@@ -1027,9 +1073,9 @@ func (r *KubeVirt) setKvmOnPodSpec(podSpec *core.PodSpec) {
 	}
 }
 
-func (r *KubeVirt) getListOptionsNamespaced() (listOptions *client.ListOptions) {
+func (r *KubeVirt) getListOptionsNamespaced(namespace string) (listOptions *client.ListOptions) {
 	return &client.ListOptions{
-		Namespace: r.Plan.Spec.TargetNamespace,
+		Namespace: namespace,
 	}
 }
 
@@ -1044,7 +1090,7 @@ func (r *KubeVirt) EnsureVirtV2vPod(vm *plan.VMStatus, vmCr *VirtualMachine, pvc
 
 	var vddkConfigMap *core.ConfigMap
 	if r.Source.Provider.UseVddkAioOptimization() {
-		vddkConfigMap, err = r.ensureVddkConfigMap()
+		vddkConfigMap, err = r.ensureVddkConfigMap(r.Plan.VMTargetNamespace(vm.Ref))
 		if err != nil {
 			return err
 		}
@@ -1174,7 +1220,7 @@ func (r *KubeVirt) GetGuestConversionPod(vm *plan.VMStatus) (pod *core.Pod, err
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.conversionLabels(vm.Ref, false)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		})
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -1351,17 +1397,21 @@ func (r *KubeVirt) GetPods(vm *plan.VMStatus) (pods *core.PodList, err error) {
 // Gets pods associated with the VM.
 func (r *KubeVirt) GetPodsWithLabels(podLabels map[string]string) (pods *core.PodList, err error) {
 	pods = &core.PodList{}
-	err = r.Destination.Client.List(
-		context.TODO(),
-		pods,
-		&client.ListOptions{
-			LabelSelector: k8slabels.SelectorFromSet(podLabels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
-		},
-	)
-	if err != nil {
-		err = liberr.Wrap(err)
-		return nil, err
+	for _, namespace := range r.Plan.TargetNamespaces() {
+		list := &core.PodList{}
+		err = r.Destination.Client.List(
+			context.TODO(),
+			list,
+			&client.ListOptions{
+				LabelSelector: k8slabels.SelectorFromSet(podLabels),
+				Namespace:     namespace,
+			},
+		)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return nil, err
+		}
+		pods.Items = append(pods.Items, list.Items...)
 	}
 	return
 }
@@ -1397,7 +1447,7 @@ func (r *KubeVirt) DeleteHookJobs(vm *plan.VMStatus) (err error) {
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(vmLabels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vm.Ref),
 		},
 	)
 	if err != nil {
@@ -1463,7 +1513,7 @@ func (r *KubeVirt) DeletePopulatedPVCs(vm *plan.VMStatus) error {
 
 func (r *KubeVirt) deleteCorrespondingPrimePVC(pvc *core.PersistentVolumeClaim, vm *plan.VMStatus) error {
 	primePVC := core.PersistentVolumeClaim{}
-	err := r.Destination.Client.Get(context.TODO(), client.ObjectKey{Namespace: r.Plan.Spec.TargetNamespace, Name: fmt.Sprintf("prime-%s", string(pvc.UID))}, &primePVC)
+	err := r.Destination.Client.Get(context.TODO(), client.ObjectKey{Namespace: pvc.Namespace, Name: fmt.Sprintf("prime-%s", string(pvc.UID))}, &primePVC)
 	switch {
 	case err != nil && !k8serr.IsNotFound(err):
 		return err
@@ -1557,7 +1607,7 @@ func (r *KubeVirt) dataVolumes(vm *plan.VMStatus, secret *core.Secret, configMap
 	annotations[AnnDeleteAfterCompletion] = "false"
 	dvTemplate := cdi.DataVolume{
 		ObjectMeta: meta.ObjectMeta{
-			Namespace:   r.Plan.Spec.TargetNamespace,
+			Namespace:   r.Plan.VMTargetNamespace(vm.Ref),
 			Annotations: annotations,
 		},
 	}
@@ -1573,12 +1623,25 @@ func (r *KubeVirt) dataVolumes(vm *plan.VMStatus, secret *core.Secret, configMap
 	if err != nil {
 		return
 	}
+	if rate := r.Plan.DiskTransferRateLimit(len(dataVolumes)); rate > 0 {
+		for i := range dataVolumes {
+			if dataVolumes[i].Annotations == nil {
+				dataVolumes[i].Annotations = make(map[string]string)
+			}
+			dataVolumes[i].Annotations[AnnIngressBandwidth] = IngressBandwidth(rate)
+		}
+	}
 
 	err = r.createLunDisks(vm.Ref)
 
 	return
 }
 
+// Ingress bandwidth annotation value of a transfer rate in MB/s.
+func IngressBandwidth(rate int) string {
+	return fmt.Sprintf("%dM", rate*8)
+}
+
 // Return the generated name for a specific VM and plan.
 func (r *KubeVirt) getGeneratedName(vm *plan.VMStatus) string {
 	return strings.Join(
@@ -1670,6 +1733,12 @@ func (r *KubeVirt) virtualMachine(vm *plan.VMStatus, sortVolumesByLibvirt bool)
 		object.ObjectMeta.Annotations = annotations
 	}
 
+	err = r.setVmMetadata(vm, object)
+	if err != nil {
+		return
+	}
+	r.setPlacementLabels(vm, object)
+
 	// Assign the determined run strategy to the object
 	runStrategy := r.determineRunStrategy(vm)
 	object.Spec.RunStrategy = &runStrategy
@@ -1770,7 +1839,7 @@ func (r *KubeVirt) setVmLabels(object *cnv.VirtualMachine) (err error) {
 
 // Attempt to find a suitable instance type
 func (r *KubeVirt) getInstanceType(vm *plan.VMStatus, instanceTypeName string) (kind string, err error) {
-	kind, err = r.getVirtualMachineInstanceType(instanceTypeName)
+	kind, err = r.getVirtualMachineInstanceType(instanceTypeName, r.Plan.VMTargetNamespace(vm.Ref))
 	if err != nil {
 		if k8serr.IsNotFound(err) {
 			r.Log.Info("could not find a namespaced instance type for destination VM. trying cluster wide",
@@ -1787,11 +1856,11 @@ func (r *KubeVirt) getInstanceType(vm *plan.VMStatus, instanceTypeName string) (
 	return
 }
 
-func (r *KubeVirt) getVirtualMachineInstanceType(instanceTypeName string) (kind string, err error) {
+func (r *KubeVirt) getVirtualMachineInstanceType(instanceTypeName, namespace string) (kind string, err error) {
 	virtualMachineInstancetype := &instancetype.VirtualMachineInstancetype{}
 	err = r.Destination.Client.Get(
 		context.TODO(),
-		client.ObjectKey{Name: instanceTypeName, Namespace: r.Plan.Spec.TargetNamespace},
+		client.ObjectKey{Name: instanceTypeName, Namespace: namespace},
 		virtualMachineInstancetype)
 	if err != nil {
 		return
@@ -1843,7 +1912,7 @@ func (r *KubeVirt) getOsMapConfig(providerType api.ProviderType) (configMap *cor
 }
 
 func (r *KubeVirt) getPreference(vm *plan.VMStatus, preferenceName string) (name, kind string, err error) {
-	name, kind, err = r.getVirtualMachinePreference(preferenceName)
+	name, kind, err = r.getVirtualMachinePreference(preferenceName, r.Plan.VMTargetNamespace(vm.Ref))
 	if err != nil {
 		if k8serr.IsNotFound(err) {
 			r.Log.Info("could not find a local instance type preference for destination VM. trying cluster wide",
@@ -1859,11 +1928,11 @@ func (r *KubeVirt) getPreference(vm *plan.VMStatus, preferenceName string) (name
 	return
 }
 
-func (r *KubeVirt) getVirtualMachinePreference(preferenceName string) (name, kind string, err error) {
+func (r *KubeVirt) getVirtualMachinePreference(preferenceName, namespace string) (name, kind string, err error) {
 	virtualMachinePreference := &instancetype.VirtualMachinePreference{}
 	err = r.Destination.Client.Get(
 		context.TODO(),
-		client.ObjectKey{Name: preferenceName, Namespace: r.Plan.Spec.TargetNamespace},
+		client.ObjectKey{Name: preferenceName, Namespace: namespace},
 		virtualMachinePreference)
 	if err != nil {
 		return
@@ -1939,7 +2008,7 @@ func (r *KubeVirt) vmTemplate(vm *plan.VMStatus) (virtualMachine *cnv.VirtualMac
 	}
 
 	virtualMachine.Name = r.getNewVMName(vm)
-	virtualMachine.Namespace = r.Plan.Spec.TargetNamespace
+	virtualMachine.Namespace = r.Plan.VMTargetNamespace(vm.Ref)
 	virtualMachine.Spec.Template.Spec.Volumes = []cnv.Volume{}
 	virtualMachine.Spec.Template.Spec.Networks = []cnv.Network{}
 	virtualMachine.Spec.DataVolumeTemplates = []cnv.DataVolumeTemplateSpec{}
@@ -1957,7 +2026,7 @@ func (r *KubeVirt) emptyVm(vm *plan.VMStatus) (virtualMachine *cnv.VirtualMachin
 			Kind:       "VirtualMachine",
 		},
 		ObjectMeta: meta.ObjectMeta{
-			Namespace: r.Plan.Spec.TargetNamespace,
+			Namespace: r.Plan.VMTargetNamespace(vm.Ref),
 			Labels:    r.vmLabels(vm.Ref),
 			Name:      r.getNewVMName(vm),
 		},
@@ -2230,6 +2299,14 @@ func (r *KubeVirt) getVirtV2vPod(vm *plan.VMStatus, vmVolumes []cnv.Volume, vddk
 			})
 	}
 
+	if rate := r.Plan.TransferRateLimit(); rate > 0 {
+		environment = append(environment,
+			core.EnvVar{
+				Name:  "V2V_transferRateLimit",
+				Value: strconv.Itoa(rate),
+			})
+	}
+
 	environment = append(environment,
 		core.EnvVar{
 			Name:  "LOCAL_MIGRATION",
@@ -2332,7 +2409,7 @@ func (r *KubeVirt) getVirtV2vPod(vm *plan.VMStatus, vmVolumes []cnv.Volume, vddk
 	// pod
 	pod = &core.Pod{
 		ObjectMeta: meta.ObjectMeta{
-			Namespace:    r.Plan.Spec.TargetNamespace,
+			Namespace:    r.Plan.VMTargetNamespace(vm.Ref),
 			Annotations:  annotations,
 			Labels:       podLabels,
 			GenerateName: podName,
@@ -2606,7 +2683,7 @@ func (r *KubeVirt) podVolumeMounts(vmVolumes []cnv.Volume, vddkConfigmap *core.C
 		configMapName := r.Plan.Spec.CustomizationScripts.Name
 		configMapNamespace := r.Plan.Spec.CustomizationScripts.Namespace
 		if configMapNamespace == "" {
-			configMapNamespace = r.Plan.Spec.TargetNamespace
+			configMapNamespace = r.Plan.VMTargetNamespace(vm.Ref)
 		}
 
 		var exists bool
@@ -2699,7 +2776,7 @@ func (r *KubeVirt) ensureConfigMap(vmRef ref.Ref) (configMap *core.ConfigMap, er
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.vmLabels(vmRef)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vmRef),
 		},
 	)
 	if err != nil {
@@ -2736,7 +2813,7 @@ func (r *KubeVirt) configMap(vmRef ref.Ref) (object *core.ConfigMap, err error)
 	object = &core.ConfigMap{
 		ObjectMeta: meta.ObjectMeta{
 			Labels:    r.vmLabels(vmRef),
-			Namespace: r.Plan.Spec.TargetNamespace,
+			Namespace: r.Plan.VMTargetNamespace(vmRef),
 			GenerateName: strings.Join(
 				[]string{
 					r.Plan.Name,
@@ -2791,7 +2868,7 @@ func (r *KubeVirt) ensureSecret(vmRef ref.Ref, setSecretData func(*core.Secret)
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(labels),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vmRef),
 		},
 	)
 	if err != nil {
@@ -2841,7 +2918,7 @@ func (r *KubeVirt) secret(vmRef ref.Ref, setSecretData func(*core.Secret) error,
 	secret = &core.Secret{
 		ObjectMeta: meta.ObjectMeta{
 			Labels:    labels,
-			Namespace: r.Plan.Spec.TargetNamespace,
+			Namespace: r.Plan.VMTargetNamespace(vmRef),
 			GenerateName: strings.Join(
 				[]string{
 					r.Plan.Name,
@@ -3120,7 +3197,7 @@ func (r *KubeVirt) EnsurePersistentVolume(vmRef ref.Ref, persistentVolumes []cor
 		list,
 		&client.ListOptions{
 			LabelSelector: k8slabels.SelectorFromSet(r.vmLabels(vmRef)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.VMTargetNamespace(vmRef),
 		})
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -3336,7 +3413,7 @@ func (r *KubeVirt) BuildPVCForNFS(pv *core.PersistentVolume, vm *plan.VMStatus)
 	pvc = &core.PersistentVolumeClaim{
 		ObjectMeta: meta.ObjectMeta{
 			GenerateName: pvcNamePrefix,
-			Namespace:    r.Plan.Spec.TargetNamespace,
+			Namespace:    r.Plan.VMTargetNamespace(vm.Ref),
 			Labels:       r.nfsPVCLabels(vm.ID),
 		},
 		Spec: core.PersistentVolumeClaimSpec{
@@ -3457,7 +3534,7 @@ func (r *KubeVirt) BuildPVCForSMB(pv *core.PersistentVolume, vm *plan.VMStatus)
 	pvc = &core.PersistentVolumeClaim{
 		ObjectMeta: meta.ObjectMeta{
 			GenerateName: pvcNamePrefix,
-			Namespace:    r.Plan.Spec.TargetNamespace,
+			Namespace:    r.Plan.VMTargetNamespace(vm.Ref),
 			Labels:       r.smbPVCLabels(vm.ID),
 		},
 		Spec: core.PersistentVolumeClaimSpec{
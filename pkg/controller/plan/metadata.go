@@ -0,0 +1,159 @@
+package plan
+
+import (
+	"regexp"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"k8s.io/apimachinery/pkg/util/validation"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// Types
+const (
+	MetadataMappingNotValid = "MetadataMappingNotValid"
+)
+
+// Characters not valid in a label value.
+var labelNotValid = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Validate the metadata mapping.
+// Supported by vSphere only and the keys must be
+// qualified names.
+func (r *Reconciler) validateMetadataMapping(plan *api.Plan) error {
+	if len(plan.Spec.MetadataMapping) == 0 {
+		return nil
+	}
+	source := plan.Referenced.Provider.Source
+	if source == nil {
+		return nil
+	}
+	if source.Type() != api.VSphere {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     MetadataMappingNotValid,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: api.CategoryCritical,
+			Message:  "Metadata mapping is supported by vSphere source providers only.",
+			Items:    []string{},
+		})
+		return nil
+	}
+	notValid := libcnd.Condition{
+		Type:     MetadataMappingNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "Metadata mapping key is not a valid label or annotation key.",
+		Items:    []string{},
+	}
+	for _, rule := range plan.Spec.MetadataMapping {
+		key := metadataKey(rule)
+		if len(validation.IsQualifiedName(key)) > 0 {
+			notValid.Items = append(notValid.Items, rule.Name+": "+key)
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+	return nil
+}
+
+// Set the labels and annotations mapped from the
+// tags and custom attributes of the source VM.
+func (r *KubeVirt) setVmMetadata(vm *planapi.VMStatus, object *cnv.VirtualMachine) (err error) {
+	if len(r.Plan.Spec.MetadataMapping) == 0 || r.Source.Provider.Type() != api.VSphere {
+		return
+	}
+	source := &model.VM{}
+	err = r.Source.Inventory.Find(source, vm.Ref)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Ref.String())
+		return
+	}
+	labels, annotations := mapMetadata(r.Plan.Spec.MetadataMapping, source)
+	if len(labels) > 0 {
+		if object.ObjectMeta.Labels == nil {
+			object.ObjectMeta.Labels = map[string]string{}
+		}
+		for k, v := range labels {
+			object.ObjectMeta.Labels[k] = v
+		}
+	}
+	if len(annotations) > 0 {
+		if object.ObjectMeta.Annotations == nil {
+			object.ObjectMeta.Annotations = map[string]string{}
+		}
+		for k, v := range annotations {
+			object.ObjectMeta.Annotations[k] = v
+		}
+	}
+	return
+}
+
+// Map the tags and custom attributes of the VM.
+// The tags of a category are joined by a comma. Label values
+// are sanitized and the entries with a key not valid or
+// without a value are skipped.
+func mapMetadata(rules []planapi.MetadataMapping, vm *model.VM) (labels, annotations map[string]string) {
+	labels = map[string]string{}
+	annotations = map[string]string{}
+	for _, rule := range rules {
+		value := ""
+		switch rule.Source {
+		case planapi.MetadataTag:
+			names := []string{}
+			for _, tag := range vm.Tags {
+				if tag.Category == rule.Name {
+					names = append(names, tag.Name)
+				}
+			}
+			value = strings.Join(names, ",")
+		case planapi.MetadataAttribute:
+			for _, attribute := range vm.CustomAttributes {
+				if attribute.Name == rule.Name {
+					value = attribute.Value
+					break
+				}
+			}
+		}
+		key := metadataKey(rule)
+		if len(validation.IsQualifiedName(key)) > 0 {
+			continue
+		}
+		switch rule.Target {
+		case planapi.MetadataAnnotation:
+			if value != "" {
+				annotations[key] = value
+			}
+		default:
+			value = labelValue(value)
+			if value != "" {
+				labels[key] = value
+			}
+		}
+	}
+	return
+}
+
+// Key of the label or annotation.
+// Default: the (sanitized) name.
+func metadataKey(rule planapi.MetadataMapping) string {
+	if rule.Key != "" {
+		return rule.Key
+	}
+	return labelValue(rule.Name)
+}
+
+// Sanitize a label value.
+func labelValue(s string) string {
+	s = labelNotValid.ReplaceAllString(s, "-")
+	if len(s) > validation.LabelValueMaxLength {
+		s = s[:validation.LabelValueMaxLength]
+	}
+	return strings.Trim(s, "._-")
+}
@@ -0,0 +1,95 @@
+package plan
+
+import (
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/base"
+	vsmodel "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Plan metadata mapping", func() {
+	vm := &vsphere.VM{}
+	vm.Tags = []vsmodel.Tag{
+		{Name: "db", Category: "app"},
+		{Name: "web", Category: "app"},
+		{Name: "prod", Category: "Environment Type"},
+	}
+	vm.CustomAttributes = []vsmodel.CustomAttribute{
+		{Key: 1, Name: "owner", Value: "Alice Smith"},
+		{Key: 2, Name: "notes", Value: strings.Repeat("x", 70) + "."},
+	}
+
+	ginkgo.Describe("mapMetadata", func() {
+		ginkgo.It("should map the tags and attributes to labels", func() {
+			labels, annotations := mapMetadata(
+				[]plan.MetadataMapping{
+					{Source: plan.MetadataTag, Name: "app"},
+					{Source: plan.MetadataTag, Name: "Environment Type"},
+					{Source: plan.MetadataAttribute, Name: "owner", Key: "example.com/owner"},
+					{Source: plan.MetadataAttribute, Name: "notes"},
+					{Source: plan.MetadataAttribute, Name: "missing"},
+				},
+				vm)
+			gomega.Expect(labels).To(gomega.Equal(map[string]string{
+				"app":               "db-web",
+				"Environment-Type":  "prod",
+				"example.com/owner": "Alice-Smith",
+				"notes":             strings.Repeat("x", 63),
+			}))
+			gomega.Expect(annotations).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("should map to annotations without sanitizing", func() {
+			labels, annotations := mapMetadata(
+				[]plan.MetadataMapping{
+					{Source: plan.MetadataTag, Name: "app", Target: plan.MetadataAnnotation, Key: "example.com/app"},
+					{Source: plan.MetadataAttribute, Name: "owner", Target: plan.MetadataAnnotation},
+					{Source: plan.MetadataAttribute, Name: "owner", Key: "not valid/"},
+				},
+				vm)
+			gomega.Expect(labels).To(gomega.BeEmpty())
+			gomega.Expect(annotations).To(gomega.Equal(map[string]string{
+				"example.com/app": "db,web",
+				"owner":           "Alice Smith",
+			}))
+		})
+	})
+
+	ginkgo.Describe("validateMetadataMapping", func() {
+		reconciler := &Reconciler{
+			Reconciler: base.Reconciler{Log: logging.WithName("planMetadata")},
+		}
+		newPlan := func(providerType api.ProviderType, key string) *api.Plan {
+			p := &api.Plan{}
+			p.Referenced.Provider.Source = &api.Provider{Spec: api.ProviderSpec{Type: &providerType}}
+			p.Spec.MetadataMapping = []plan.MetadataMapping{
+				{Source: plan.MetadataTag, Name: "app", Key: key},
+			}
+			return p
+		}
+
+		ginkgo.It("should accept a valid mapping", func() {
+			p := newPlan(api.VSphere, "example.com/app")
+			gomega.Expect(reconciler.validateMetadataMapping(p)).To(gomega.Succeed())
+			gomega.Expect(p.Status.HasCondition(MetadataMappingNotValid)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should reject a key not valid", func() {
+			p := newPlan(api.VSphere, "-app")
+			gomega.Expect(reconciler.validateMetadataMapping(p)).To(gomega.Succeed())
+			gomega.Expect(p.Status.HasCondition(MetadataMappingNotValid)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should reject a source provider other than vSphere", func() {
+			p := newPlan(api.OVirt, "")
+			gomega.Expect(reconciler.validateMetadataMapping(p)).To(gomega.Succeed())
+			gomega.Expect(p.Status.HasCondition(MetadataMappingNotValid)).To(gomega.BeTrue())
+		})
+	})
+})
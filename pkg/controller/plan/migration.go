@@ -73,6 +73,8 @@ type Migration struct {
 	converter *adapter.Converter
 	// vm migrator
 	migrator migrator.Migrator
+	// guest agent
+	guestAgent GuestAgent
 }
 
 // Type of migration.
@@ -100,6 +102,7 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 	}
 
 	r.resolveCanceledRefs()
+	r.cancelBlockedVMs()
 
 	for _, vm := range r.runningVMs() {
 		err = r.execute(vm)
@@ -137,6 +140,8 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 		}
 	}
 
+	r.Plan.Status.Migration.ReflectWaves()
+
 	completed, err := r.end()
 	if completed {
 		reQ = NoReQ
@@ -181,6 +186,7 @@ func (r *Migration) init() (err error) {
 	if err != nil {
 		return
 	}
+	r.guestAgent = &LauncherAgent{Context: r.Context}
 
 	return
 }
@@ -240,6 +246,10 @@ func (r *Migration) begin() (err error) {
 				return
 			}
 			r.migrator.Reset(status, pipeline)
+			status.TargetNamespace = ""
+			if placement, found := r.Plan.FindPlacement(vm.Ref); found {
+				status.TargetNamespace = placement.TargetNamespace
+			}
 			log.Info(
 				"Pipeline reset.",
 				"vm",
@@ -250,6 +260,8 @@ func (r *Migration) begin() (err error) {
 				"vm",
 				vm.String())
 		}
+		status.Wave = vm.Wave
+		status.DependsOn = vm.DependsOn
 		list = append(list, status)
 	}
 
@@ -340,18 +352,29 @@ func (r *Migration) SetPopulatorDataSourceLabels() {
 
 // Cancel the migration.
 // Delete resources associated with VMs that have been marked canceled.
-func (r *Migration) Cancel() error {
+// The failure hook of a canceled VM is run before the cleanup.
+func (r *Migration) Cancel() (reQ time.Duration, err error) {
 	defer func() {
 		if r.provider != nil {
 			r.provider.Close()
 		}
 	}()
-	if err := r.init(); err != nil {
-		return liberr.Wrap(err)
+	if err = r.init(); err != nil {
+		err = liberr.Wrap(err)
+		return
 	}
 
 	for _, vm := range r.Plan.Status.Migration.VMs {
 		if vm.HasCondition(api.ConditionCanceled) && !vm.MarkedCompleted() {
+			var running bool
+			running, err = r.cancelHook(vm)
+			if err != nil {
+				return
+			}
+			if running {
+				reQ = PollReQ
+				continue
+			}
 			dontFailOnError := func(err error) bool {
 				if err != nil {
 					r.Log.Error(liberr.Wrap(err),
@@ -376,7 +399,7 @@ func (r *Migration) Cancel() error {
 		}
 	}
 
-	return nil
+	return
 }
 
 // NextPhase transitions the VM to the next migration phase.
@@ -438,6 +461,9 @@ func (r *Migration) cleanup(vm *plan.VMStatus, failOnErr func(error) bool) error
 	if err := r.kubevirt.DeleteHookJobs(vm); failOnErr(err) {
 		return err
 	}
+	if err := r.kubevirt.DeleteGuestExecRoleBinding(vm); failOnErr(err) {
+		return err
+	}
 	if r.Plan.Provider.Destination.IsHost() {
 		if err := r.destinationClient.DeletePopulatorDataSource(vm); failOnErr(err) {
 			return err
@@ -446,6 +472,12 @@ func (r *Migration) cleanup(vm *plan.VMStatus, failOnErr func(error) bool) error
 	if err := r.kubevirt.DeletePopulatorPods(vm); failOnErr(err) {
 		return err
 	}
+	if err := r.kubevirt.DeletePrecopyPods(vm); failOnErr(err) {
+		return err
+	}
+	if err := r.kubevirt.DeleteHealthProbePods(vm); failOnErr(err) {
+		return err
+	}
 	if err := r.kubevirt.DeleteJobs(vm); failOnErr(err) {
 		return err
 	}
@@ -572,16 +604,20 @@ func (r *Migration) deleteConfigMap() (err error) {
 		kUse:  VddkConf,
 	})
 	list := &core.ConfigMapList{}
-	err = r.Destination.Client.List(
-		context.TODO(),
-		list,
-		&client.ListOptions{
-			LabelSelector: selector,
-			Namespace:     r.Plan.Spec.TargetNamespace,
-		},
-	)
-	if err != nil {
-		return
+	for _, namespace := range r.Plan.TargetNamespaces() {
+		configMaps := &core.ConfigMapList{}
+		err = r.Destination.Client.List(
+			context.TODO(),
+			configMaps,
+			&client.ListOptions{
+				LabelSelector: selector,
+				Namespace:     namespace,
+			},
+		)
+		if err != nil {
+			return
+		}
+		list.Items = append(list.Items, configMaps.Items...)
 	}
 	for _, configmap := range list.Items {
 		background := meta.DeletePropagationBackground
@@ -630,6 +666,34 @@ func (r *Migration) resolveCanceledRefs() {
 	}
 }
 
+// Cancel pending VMs that depend on a VM
+// for which the migration did not succeed.
+func (r *Migration) cancelBlockedVMs() {
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if vm.MarkedStarted() || vm.HasCondition(api.ConditionCanceled) {
+			continue
+		}
+		dependency, blocked := r.Plan.Status.Migration.BlockedBy(vm)
+		if !blocked {
+			continue
+		}
+		vm.SetCondition(libcnd.Condition{
+			Type:     api.ConditionCanceled,
+			Status:   True,
+			Category: api.CategoryAdvisory,
+			Reason:   DependencyFailed,
+			Message:  fmt.Sprintf("The migration of VM '%s' it depends on did not succeed.", dependency.Name),
+			Durable:  true,
+		})
+		r.Log.Info(
+			"Migration [CANCELED] dependency failed.",
+			"vm",
+			vm.String(),
+			"dependency",
+			dependency.String())
+	}
+}
+
 func (r *Migration) runningVMs() (vms []*plan.VMStatus) {
 	vms = make([]*plan.VMStatus, 0)
 	for i := range r.Plan.Status.Migration.VMs {
@@ -655,7 +719,10 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				Message:  "The migration has been canceled.",
 				Durable:  true,
 			})
-		vm.Phase = api.PhaseCompleted
+		// The failure hook of a canceled VM is run by Cancel().
+		if vm.Phase != api.PhaseFailureHook {
+			vm.Phase = api.PhaseCompleted
+		}
 		r.Log.Info(
 			"Migration [CANCELED]",
 			"vm",
@@ -674,16 +741,33 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		"vm",
 		vm)
 
-	// delegate to a provider-specific implementation of a phase
-	// if one exists, otherwise run through the default implementation.
-	ok, err := r.migrator.ExecutePhase(vm)
+	// run the failure hook or step through the rollback itinerary
+	// of a failed VM, otherwise delegate to a provider-specific
+	// implementation of a phase if one exists, otherwise run
+	// through the default implementation.
+	failing := vm.Phase == api.PhaseFailureHook
+	rollingBack := isRollbackPhase(vm.Phase)
+	ok := false
+	if failing {
+		err = r.executeFailureHook(vm)
+		if err != nil {
+			return
+		}
+	} else if rollingBack {
+		err = r.executeRollback(vm)
+		if err != nil {
+			return
+		}
+	} else {
+		ok, err = r.migrator.ExecutePhase(vm)
+	}
 	if ok {
 		r.Log.Info("Delegated phase implementation to migrator.", "vm", vm.String(), "phase", vm.Phase)
 		if err != nil {
 			r.Log.Error(err, "Delegated execution error.", "vm", vm.String(), "phase", vm.Phase)
 			return
 		}
-	} else {
+	} else if !failing && !rollingBack {
 		switch vm.Phase {
 		case api.PhaseStarted:
 			step, found := vm.FindStep(r.migrator.Step(vm))
@@ -714,20 +798,20 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 
 				// Verify target VM name uniqueness in the destination namespace.
 				// Return error if name exists since we do not want to mutate explicit name assignments.
-				nameExist, errName := r.kubevirt.checkIfVmNameExistsInNamespace(vm.NewName, r.Plan.Spec.TargetNamespace)
+				nameExist, errName := r.kubevirt.checkIfVmNameExistsInNamespace(vm.NewName, r.Plan.VMTargetNamespace(vm.Ref))
 				if errName != nil {
 					err = liberr.Wrap(errName)
 					return
 				}
 				if nameExist {
-					err = fmt.Errorf("VM name '%s' already exists in the target namespace '%s'", vm.NewName, r.Plan.Spec.TargetNamespace)
+					err = fmt.Errorf("VM name '%s' already exists in the target namespace '%s'", vm.NewName, r.Plan.VMTargetNamespace(vm.Ref))
 					r.Log.Error(err, "Failed to update the VM name to targetName.")
 					return
 				}
 			} else {
 				// Check if the VM name meets DNS1123 protocol requirements
 				if errs := k8svalidation.IsDNS1123Subdomain(vm.Name); len(errs) > 0 {
-					vm.NewName, err = r.kubevirt.changeVmNameDNS1123(vm.Name, r.Plan.Spec.TargetNamespace)
+					vm.NewName, err = r.kubevirt.changeVmNameDNS1123(vm.Name, r.Plan.VMTargetNamespace(vm.Ref))
 					if err != nil {
 						r.Log.Error(err, "Failed to update the VM name to meet DNS1123 protocol requirements.")
 						return
@@ -740,7 +824,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				configMapName := r.Plan.Spec.CustomizationScripts.Name
 				configMapNamespace := r.Plan.Spec.CustomizationScripts.Namespace
 				if configMapNamespace == "" {
-					configMapNamespace = r.Plan.Spec.TargetNamespace
+					configMapNamespace = r.Plan.VMTargetNamespace(vm.Ref)
 				}
 
 				configMap := &core.ConfigMap{}
@@ -768,7 +852,8 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			}
 
 			r.NextPhase(vm)
-		case api.PhasePreHook, api.PhasePostHook:
+		case api.PhasePreHook, api.PhasePostHook, api.PhasePrePowerOffHook, api.PhasePrecopyHook,
+			api.PhaseCutoverHook, api.PhasePreConversionHook:
 			runner := HookRunner{Context: r.Context}
 			err = runner.Run(vm)
 			if err != nil {
@@ -864,7 +949,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			}
 
 			// Wait for the DataVolume to adopt the PVC before proceeding
-			if r.builder.SupportsVolumePopulators() && r.Plan.IsWarm() {
+			if r.builder.SupportsVolumePopulators() && r.Plan.IsWarm() && r.Source.Provider.Type() == api.VSphere {
 				var pvcs []*core.PersistentVolumeClaim
 				pvcs, err = r.kubevirt.getPVCs(vm.Ref)
 				if err != nil {
@@ -960,6 +1045,38 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				return
 			}
 			r.NextPhase(vm)
+		case api.PhaseVerifyVM:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			step.MarkStarted()
+			step.Phase = api.StepRunning
+			ready, vErr := r.verifyHealth(vm, step)
+			if vErr != nil {
+				err = liberr.Wrap(vErr)
+				return
+			}
+			if ready {
+				r.NextPhase(vm)
+			}
+		case api.PhaseRunGuestScripts:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			step.MarkStarted()
+			step.Phase = api.StepRunning
+			done, gErr := r.runGuestScripts(vm, step)
+			if gErr != nil {
+				err = liberr.Wrap(gErr)
+				return
+			}
+			if done {
+				r.NextPhase(vm)
+			}
 		case api.PhaseAllocateDisks, api.PhaseCopyDisks:
 			step, found := vm.FindStep(r.migrator.Step(vm))
 			if !found {
@@ -1046,7 +1163,11 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			}
 		case api.PhaseCopyingPaused:
 			if r.Migration.Spec.Cutover != nil && !r.Migration.Spec.Cutover.After(time.Now()) {
-				vm.Phase = api.PhaseStorePowerState
+				if _, found := vm.FindHook(api.PhaseCutoverHook); found {
+					vm.Phase = api.PhaseCutoverHook
+				} else {
+					vm.Phase = api.PhaseStorePowerState
+				}
 			} else if vm.Warm.NextPrecopyAt != nil && !vm.Warm.NextPrecopyAt.After(time.Now()) {
 				r.NextPhase(vm)
 			}
@@ -1386,11 +1507,18 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			})
 
 	} else if vm.Error != nil {
+		if r.failureHook(vm) {
+			return
+		}
+		if r.rollback(vm) {
+			return
+		}
 		vm.Phase = api.PhaseCompleted
 
 		// Failed warm migration can't follow its planned itinerary to snapshot removal phase
 		// so we remove the snapshot here to prevent an orphaned snapshot.
-		if r.Plan.IsWarm() && !vm.HasCondition(api.ConditionFailed) {
+		// The rollback has already removed the snapshots.
+		if r.Plan.IsWarm() && !vm.HasCondition(api.ConditionFailed) && r.Plan.Spec.Rollback != plan.RollbackAutomatic {
 			r.removeLastWarmSnapshot(vm)
 		}
 
@@ -1414,6 +1542,12 @@ func (r *Migration) resetPrecopyTasks(vm *plan.VMStatus, step *plan.Step) {
 		task.MarkReset()
 		task.MarkStarted()
 	}
+	// The precopy hook runs again after each precopy.
+	if hook, found := vm.FindStep(api.PhasePrecopyHook); found {
+		hook.MarkReset()
+		hook.Progress.Completed = 0
+		hook.Phase = api.StepPending
+	}
 }
 
 // End the migration.
@@ -1555,6 +1689,9 @@ func (r *Migration) setTaskCompleted(task *plan.Task) {
 
 // Update the progress of the appropriate disk copy step. (DiskTransfer, Cutover)
 func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err error) {
+	if vm.Warm != nil && r.Source.Provider.Type() == api.OpenStack {
+		return r.updatePrecopyProgress(vm, step)
+	}
 	var pendingReason string
 	var pending int
 	var completed int
@@ -1608,7 +1745,7 @@ func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err
 			case cdi.Paused:
 				pvc := &core.PersistentVolumeClaim{}
 				err = r.Destination.Client.Get(context.TODO(), types.NamespacedName{
-					Namespace: r.Plan.Spec.TargetNamespace,
+					Namespace: r.Plan.VMTargetNamespace(vm.Ref),
 					Name:      dv.Status.ClaimName,
 				}, pvc)
 				if err != nil {
@@ -1668,7 +1805,7 @@ func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err
 				} else {
 					pvc := &core.PersistentVolumeClaim{}
 					err = r.Destination.Client.Get(context.TODO(), types.NamespacedName{
-						Namespace: r.Plan.Spec.TargetNamespace,
+						Namespace: r.Plan.VMTargetNamespace(vm.Ref),
 						Name:      dv.Status.ClaimName,
 					}, pvc)
 					if err != nil {
@@ -1682,7 +1819,7 @@ func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err
 						continue
 					}
 					err = r.Destination.Client.Get(context.TODO(), types.NamespacedName{
-						Namespace: r.Plan.Spec.TargetNamespace,
+						Namespace: r.Plan.VMTargetNamespace(vm.Ref),
 						Name:      fmt.Sprintf("prime-%s", pvc.UID),
 					}, pvc)
 					if err != nil {
@@ -19,6 +19,12 @@ var (
 	OpenstackImageMigration libitr.Flag = 0x20
 	VSphere                 libitr.Flag = 0x40
 	RunInspection           libitr.Flag = 0x80
+	HasHealthCheck          libitr.Flag = 0x100
+	HasPrePowerOffHook      libitr.Flag = 0x200
+	HasPrecopyHook          libitr.Flag = 0x400
+	HasCutoverHook          libitr.Flag = 0x800
+	HasPreConversionHook    libitr.Flag = 0x1000
+	HasGuestScripts         libitr.Flag = 0x2000
 )
 
 // Steps.
@@ -31,6 +37,8 @@ const (
 	DiskTransferV2v     = "DiskTransferV2v"
 	VMCreation          = "VirtualMachineCreation"
 	PreflightInspection = "PreflightInspection"
+	HealthVerification  = "HealthVerification"
+	GuestScripts        = "GuestScripts"
 	Unknown             = "Unknown"
 )
 
@@ -12,6 +12,15 @@ import (
 	"github.com/kubev2v/forklift/pkg/lib/logging"
 )
 
+// Descriptions of the hook steps
+// run in addition to the pre and post hooks.
+var hookDescriptions = map[string]string{
+	api.PhasePrePowerOffHook:   "Run hook before the source VM is powered off.",
+	api.PhasePrecopyHook:       "Run hook after each precopy.",
+	api.PhaseCutoverHook:       "Run hook before the cutover.",
+	api.PhasePreConversionHook: "Run hook before the guest conversion.",
+}
+
 type BaseMigrator struct {
 	*plancontext.Context
 	builder adapter.Builder
@@ -92,6 +101,17 @@ func (r *BaseMigrator) Pipeline(vm plan.VM) (pipeline []*plan.Step, err error) {
 						Phase:       api.StepPending,
 					},
 				})
+		case api.PhasePrePowerOffHook, api.PhasePrecopyHook, api.PhaseCutoverHook, api.PhasePreConversionHook:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        step.Name,
+						Description: hookDescriptions[step.Name],
+						Progress:    libitr.Progress{Total: 1},
+						Phase:       api.StepPending,
+					},
+				})
 		case api.PhaseAllocateDisks, api.PhaseCopyDisks, api.PhaseCopyDisksVirtV2V, api.PhaseConvertOpenstackSnapshot:
 			tasks, pErr := r.builder.Tasks(vm.Ref)
 			if pErr != nil {
@@ -194,6 +214,31 @@ func (r *BaseMigrator) Pipeline(vm plan.VM) (pipeline []*plan.Step, err error) {
 						Progress:    libitr.Progress{Total: 1},
 					},
 				})
+		case api.PhaseVerifyVM:
+			tasks := r.Context.Plan.Spec.HealthCheck.Tasks()
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        HealthVerification,
+						Description: "Verify VM health.",
+						Phase:       api.StepPending,
+						Progress:    libitr.Progress{Total: int64(len(tasks))},
+					},
+					Tasks: tasks,
+				})
+		case api.PhaseRunGuestScripts:
+			// The tasks are added once the scripts
+			// for the OS family have been selected.
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        GuestScripts,
+						Description: "Run guest scripts.",
+						Phase:       api.StepPending,
+					},
+				})
 		case api.PhasePreflightInspection:
 			pipeline = append(
 				pipeline,
@@ -274,7 +319,12 @@ func (r *BaseMigrator) Step(status *plan.VMStatus) (step string) {
 		step = DiskTransferV2v
 	case api.PhaseCreateVM:
 		step = VMCreation
-	case api.PhasePreHook, api.PhasePostHook:
+	case api.PhaseVerifyVM:
+		step = HealthVerification
+	case api.PhaseRunGuestScripts:
+		step = GuestScripts
+	case api.PhasePreHook, api.PhasePostHook, api.PhasePrePowerOffHook, api.PhasePrecopyHook,
+		api.PhaseCutoverHook, api.PhasePreConversionHook, api.PhaseFailureHook:
 		step = status.Phase
 	case api.PhaseStorePowerState, api.PhasePowerOffSource, api.PhaseWaitForPowerOff:
 		if r.Context.Plan.IsWarm() {
@@ -303,6 +353,7 @@ func (r *BaseMigrator) warmItinerary() *libitr.Itinerary {
 			{Name: api.PhaseCreateDataVolumes},
 			// Precopy loop start
 			{Name: api.PhaseCopyDisks},
+			{Name: api.PhasePrecopyHook, All: HasPrecopyHook},
 			{Name: api.PhaseCopyingPaused},
 			{Name: api.PhaseRemovePreviousSnapshot, All: VSphere},
 			{Name: api.PhaseWaitForPreviousSnapshotRemoval, All: VSphere},
@@ -311,7 +362,9 @@ func (r *BaseMigrator) warmItinerary() *libitr.Itinerary {
 			{Name: api.PhaseStoreSnapshotDeltas, All: VSphere},
 			{Name: api.PhaseAddCheckpoint},
 			// Precopy loop end
+			{Name: api.PhaseCutoverHook, All: HasCutoverHook},
 			{Name: api.PhaseStorePowerState},
+			{Name: api.PhasePrePowerOffHook, All: HasPrePowerOffHook},
 			{Name: api.PhasePowerOffSource},
 			{Name: api.PhaseWaitForPowerOff},
 			{Name: api.PhaseRemovePenultimateSnapshot, All: VSphere},
@@ -322,9 +375,12 @@ func (r *BaseMigrator) warmItinerary() *libitr.Itinerary {
 			{Name: api.PhaseFinalize},
 			{Name: api.PhaseRemoveFinalSnapshot, All: VSphere},
 			{Name: api.PhaseWaitForFinalSnapshotRemoval, All: VSphere},
+			{Name: api.PhasePreConversionHook, All: HasPreConversionHook | RequiresConversion},
 			{Name: api.PhaseCreateGuestConversionPod, All: RequiresConversion},
 			{Name: api.PhaseConvertGuest, All: RequiresConversion},
 			{Name: api.PhaseCreateVM},
+			{Name: api.PhaseVerifyVM, All: HasHealthCheck},
+			{Name: api.PhaseRunGuestScripts, All: HasGuestScripts},
 			{Name: api.PhasePostHook, All: HasPostHook},
 			{Name: api.PhaseCompleted},
 		},
@@ -338,16 +394,20 @@ func (r *BaseMigrator) coldItinerary() *libitr.Itinerary {
 			{Name: api.PhaseStarted},
 			{Name: api.PhasePreHook, All: HasPreHook},
 			{Name: api.PhaseStorePowerState},
+			{Name: api.PhasePrePowerOffHook, All: HasPrePowerOffHook},
 			{Name: api.PhasePowerOffSource},
 			{Name: api.PhaseWaitForPowerOff},
 			{Name: api.PhaseCreateDataVolumes},
 			{Name: api.PhaseCopyDisks, All: CDIDiskCopy},
 			{Name: api.PhaseAllocateDisks, All: VirtV2vDiskCopy},
+			{Name: api.PhasePreConversionHook, All: HasPreConversionHook | RequiresConversion | CDIDiskCopy},
 			{Name: api.PhaseCreateGuestConversionPod, All: RequiresConversion},
 			{Name: api.PhaseConvertGuest, All: RequiresConversion},
 			{Name: api.PhaseCopyDisksVirtV2V, All: RequiresConversion},
 			{Name: api.PhaseConvertOpenstackSnapshot, All: OpenstackImageMigration},
 			{Name: api.PhaseCreateVM},
+			{Name: api.PhaseVerifyVM, All: HasHealthCheck},
+			{Name: api.PhaseRunGuestScripts, All: HasGuestScripts},
 			{Name: api.PhasePostHook, All: HasPostHook},
 			{Name: api.PhaseCompleted},
 		},
@@ -361,11 +421,15 @@ func (r *BaseMigrator) onlyConversionItinerary() *libitr.Itinerary {
 			{Name: api.PhaseStarted},
 			{Name: api.PhasePreHook, All: HasPreHook},
 			{Name: api.PhaseStorePowerState},
+			{Name: api.PhasePrePowerOffHook, All: HasPrePowerOffHook},
 			{Name: api.PhasePowerOffSource},
 			{Name: api.PhaseWaitForPowerOff},
+			{Name: api.PhasePreConversionHook, All: HasPreConversionHook | RequiresConversion},
 			{Name: api.PhaseCreateGuestConversionPod, All: RequiresConversion},
 			{Name: api.PhaseConvertGuest, All: RequiresConversion},
 			{Name: api.PhaseCreateVM},
+			{Name: api.PhaseVerifyVM, All: HasHealthCheck},
+			{Name: api.PhaseRunGuestScripts, All: HasGuestScripts},
 			{Name: api.PhasePostHook, All: HasPostHook},
 			{Name: api.PhaseCompleted},
 		},
@@ -393,6 +457,14 @@ func (r *BasePredicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
 		_, allowed = r.vm.FindHook(api.PhasePreHook)
 	case HasPostHook:
 		_, allowed = r.vm.FindHook(api.PhasePostHook)
+	case HasPrePowerOffHook:
+		_, allowed = r.vm.FindHook(api.PhasePrePowerOffHook)
+	case HasPrecopyHook:
+		_, allowed = r.vm.FindHook(api.PhasePrecopyHook)
+	case HasCutoverHook:
+		_, allowed = r.vm.FindHook(api.PhaseCutoverHook)
+	case HasPreConversionHook:
+		_, allowed = r.vm.FindHook(api.PhasePreConversionHook)
 	case RequiresConversion:
 		allowed = r.context.Source.Provider.RequiresConversion() && !r.context.Plan.Spec.SkipGuestConversion
 	case CDIDiskCopy:
@@ -405,11 +477,16 @@ func (r *BasePredicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
 		allowed = r.context.Plan.IsSourceProviderVSphere()
 	case RunInspection:
 		allowed = r.context.Plan.ShouldRunPreflightInspection()
+	case HasHealthCheck:
+		allowed = r.context.Plan.Spec.HealthCheck != nil
+	case HasGuestScripts:
+		allowed = r.context.Plan.Spec.GuestScripts != nil
 	}
 
 	return
 }
 
+// Count of the predicate flags.
 func (r *BasePredicate) Count() int {
-	return 0x80
+	return 14
 }
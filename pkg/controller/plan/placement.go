@@ -0,0 +1,246 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/templateutil"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// Types
+const (
+	PlacementNotValid = "PlacementNotValid"
+)
+
+// Placement not valid.
+type PlacementNotValidError struct {
+	Reason string
+}
+
+func (e PlacementNotValidError) Error() string {
+	return e.Reason
+}
+
+// Resolve the placement policy.
+// The target namespace and labels of each VM are recorded
+// in the status. The target namespace of a VM is assigned
+// when the migration starts.
+func (r *Reconciler) resolvePlacement(plan *api.Plan) (err error) {
+	policy := plan.Spec.Placement
+	source := plan.Referenced.Provider.Source
+	if policy == nil || source == nil {
+		plan.Status.Placement = nil
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     PlacementNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "Placement policy not valid.",
+		Items:    []string{},
+	}
+	if source.Type() != api.VSphere {
+		notValid.Reason = NotSupported
+		notValid.Message = "Placement policy is supported by vSphere source providers only."
+		plan.Status.SetCondition(notValid)
+		plan.Status.Placement = nil
+		return
+	}
+	inventory, err := web.NewClient(source)
+	if err != nil {
+		return
+	}
+	resolver := PlacementResolver{
+		Placement: policy,
+		Inventory: inventory,
+		Default:   plan.Spec.TargetNamespace,
+	}
+	vms := []ref.Ref{}
+	for _, vm := range plan.Spec.VMs {
+		vms = append(vms, vm.Ref)
+	}
+	placement, err := resolver.Resolve(vms)
+	if err != nil {
+		if pErr := (PlacementNotValidError{}); errors.As(err, &pErr) {
+			notValid.Message = "Placement policy not valid: " + pErr.Reason
+			plan.Status.SetCondition(notValid)
+			err = nil
+		}
+		return
+	}
+	for _, p := range placement {
+		if len(k8svalidation.IsDNS1123Label(p.TargetNamespace)) > 0 {
+			notValid.Items = append(notValid.Items, fmt.Sprintf("%s: %s", p.Name, p.TargetNamespace))
+			continue
+		}
+		for k, v := range p.Labels {
+			if len(k8svalidation.IsQualifiedName(k)) > 0 || len(k8svalidation.IsValidLabelValue(v)) > 0 {
+				notValid.Items = append(notValid.Items, fmt.Sprintf("%s: %s=%s", p.Name, k, v))
+			}
+		}
+	}
+	if len(notValid.Items) > 0 {
+		notValid.Message = "Placement policy derived a target namespace or label not valid."
+		plan.Status.SetCondition(notValid)
+	}
+	plan.Status.Placement = placement
+	return
+}
+
+// Namespaces derived for the plan VMs.
+// The plan target namespace and the namespaces
+// resolved by the placement policy.
+func derivedNamespaces(plan *api.Plan) (namespaces []string) {
+	namespaces = []string{plan.Spec.TargetNamespace}
+	for _, p := range plan.Status.Placement {
+		if p.TargetNamespace != "" && !slices.Contains(namespaces, p.TargetNamespace) {
+			namespaces = append(namespaces, p.TargetNamespace)
+		}
+	}
+	return
+}
+
+// Namespaces the plan VMs are placed in.
+// The namespaces resolved by the placement policy and the
+// plan target namespace for the VMs without a placement.
+func placedNamespaces(plan *api.Plan) (namespaces []string) {
+	for _, vm := range plan.Spec.VMs {
+		namespace := plan.PlacedNamespace(vm.Ref)
+		if namespace != "" && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{plan.Spec.TargetNamespace}
+	}
+	return
+}
+
+// Set the labels assigned to the VM by the placement policy.
+func (r *KubeVirt) setPlacementLabels(vm *planapi.VMStatus, object *cnv.VirtualMachine) {
+	placement, found := r.Plan.FindPlacement(vm.Ref)
+	if !found || len(placement.Labels) == 0 {
+		return
+	}
+	if object.ObjectMeta.Labels == nil {
+		object.ObjectMeta.Labels = map[string]string{}
+	}
+	for k, v := range placement.Labels {
+		object.ObjectMeta.Labels[k] = v
+	}
+}
+
+// Resolves the placement policy using the source inventory.
+type PlacementResolver struct {
+	// Placement policy.
+	Placement *planapi.Placement
+	// Inventory client.
+	Inventory web.Client
+	// Default (plan) target namespace.
+	Default string
+	// Resource pool paths by ID.
+	pools map[string]string
+}
+
+// Resolve the placement of the VMs.
+// The VMs not found (or ambiguous) are reported by the
+// VM validation and skipped.
+func (r *PlacementResolver) Resolve(vms []ref.Ref) (placement []planapi.VMPlacement, err error) {
+	for _, vmRef := range vms {
+		vm := &vsphere.VM{}
+		err = r.Inventory.Find(vm, vmRef)
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) || errors.As(err, &web.RefNotUniqueError{}) {
+				err = nil
+				continue
+			}
+			return
+		}
+		var hierarchy string
+		hierarchy, err = r.path(vm)
+		if err != nil {
+			return
+		}
+		p := planapi.VMPlacement{
+			Ref:  ref.Ref{ID: vm.ID, Name: vm.Name},
+			Path: hierarchy,
+		}
+		p.TargetNamespace, p.Labels, err = r.Place(hierarchy)
+		if err != nil {
+			return
+		}
+		placement = append(placement, p)
+	}
+	return
+}
+
+// Place the VM by the path of the folder (resource pool).
+// The first matching rule applies. The namespace template
+// is rendered when the rule does not set the namespace.
+func (r *PlacementResolver) Place(hierarchy string) (namespace string, labels map[string]string, err error) {
+	namespace = r.Default
+	for i := range r.Placement.Rules {
+		rule := &r.Placement.Rules[i]
+		if !rule.Match(hierarchy) {
+			continue
+		}
+		labels = rule.Labels
+		if rule.Namespace != "" {
+			namespace = rule.Namespace
+			return
+		}
+		break
+	}
+	if r.Placement.NamespaceTemplate == "" {
+		return
+	}
+	data := struct {
+		Path string
+		Name string
+	}{
+		Path: hierarchy,
+		Name: path.Base(hierarchy),
+	}
+	namespace, err = templateutil.ExecuteTemplate(r.Placement.NamespaceTemplate, data)
+	if err != nil {
+		err = liberr.Wrap(PlacementNotValidError{Reason: "namespaceTemplate: " + err.Error()})
+		return
+	}
+	namespace = strings.TrimSpace(namespace)
+	return
+}
+
+// Path of the folder (resource pool) of the VM.
+func (r *PlacementResolver) path(vm *vsphere.VM) (hierarchy string, err error) {
+	switch r.Placement.Source {
+	case planapi.PlacementResourcePool:
+		if r.pools == nil {
+			list := []vsphere.ResourcePool{}
+			err = r.Inventory.List(&list)
+			if err != nil {
+				return
+			}
+			r.pools = map[string]string{}
+			for _, m := range list {
+				r.pools[m.ID] = m.Path
+			}
+		}
+		hierarchy = r.pools[vm.ResourcePool]
+	default:
+		hierarchy = path.Dir(vm.Path)
+	}
+	return
+}
@@ -0,0 +1,109 @@
+package plan
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Plan placement", func() {
+	ginkgo.Describe("PlacementRule", func() {
+		ginkgo.It("should match the folder and the subfolders", func() {
+			rule := plan.PlacementRule{Path: "/dc/vm/prod/"}
+			gomega.Expect(rule.Match("/dc/vm/prod")).To(gomega.BeTrue())
+			gomega.Expect(rule.Match("/dc/vm/prod/web")).To(gomega.BeTrue())
+			gomega.Expect(rule.Match("/dc/vm/production")).To(gomega.BeFalse())
+			gomega.Expect(rule.Match("/dc/vm")).To(gomega.BeFalse())
+		})
+	})
+
+	ginkgo.Describe("PlacementResolver", func() {
+		resolver := PlacementResolver{
+			Placement: &plan.Placement{
+				Source: plan.PlacementFolder,
+				Rules: []plan.PlacementRule{
+					{Path: "/dc/vm/prod", Namespace: "production", Labels: map[string]string{"env": "prod"}},
+					{Path: "/dc/vm/dev", Labels: map[string]string{"env": "dev"}},
+				},
+				NamespaceTemplate: "{{ lower .Name }}",
+			},
+			Default: "default-ns",
+		}
+
+		ginkgo.It("should place by the first matching rule", func() {
+			namespace, labels, err := resolver.Place("/dc/vm/prod/web")
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(namespace).To(gomega.Equal("production"))
+			gomega.Expect(labels).To(gomega.Equal(map[string]string{"env": "prod"}))
+		})
+
+		ginkgo.It("should render the template when the rule has no namespace", func() {
+			namespace, labels, err := resolver.Place("/dc/vm/dev/Team-A")
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(namespace).To(gomega.Equal("team-a"))
+			gomega.Expect(labels).To(gomega.Equal(map[string]string{"env": "dev"}))
+		})
+
+		ginkgo.It("should default to the plan target namespace", func() {
+			r := PlacementResolver{
+				Placement: &plan.Placement{Source: plan.PlacementFolder},
+				Default:   "default-ns",
+			}
+			namespace, labels, err := r.Place("/dc/vm/other")
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(namespace).To(gomega.Equal("default-ns"))
+			gomega.Expect(labels).To(gomega.BeNil())
+		})
+
+		ginkgo.It("should report a template not valid", func() {
+			r := PlacementResolver{
+				Placement: &plan.Placement{NamespaceTemplate: "{{ .Missing }"},
+			}
+			_, _, err := r.Place("/dc/vm")
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Describe("namespaces", func() {
+		newPlan := func() *api.Plan {
+			p := &api.Plan{}
+			p.Spec.TargetNamespace = "default-ns"
+			p.Status.Placement = []plan.VMPlacement{
+				{Ref: ref.Ref{ID: "vm-1"}, TargetNamespace: "production"},
+				{Ref: ref.Ref{ID: "vm-2"}, TargetNamespace: "default-ns"},
+				{Ref: ref.Ref{ID: "vm-3"}, TargetNamespace: "production"},
+			}
+			return p
+		}
+
+		ginkgo.It("should derive the namespaces from the placement", func() {
+			gomega.Expect(derivedNamespaces(newPlan())).To(gomega.Equal([]string{"default-ns", "production"}))
+		})
+
+		ginkgo.It("should only include the namespaces the VMs are placed in", func() {
+			p := newPlan()
+			p.Spec.VMs = []plan.VM{{Ref: ref.Ref{ID: "vm-1"}}, {Ref: ref.Ref{ID: "vm-3"}}}
+			gomega.Expect(placedNamespaces(p)).To(gomega.Equal([]string{"production"}))
+			p.Spec.VMs = append(p.Spec.VMs, plan.VM{Ref: ref.Ref{ID: "vm-4"}})
+			gomega.Expect(placedNamespaces(p)).To(gomega.Equal([]string{"production", "default-ns"}))
+			p.Spec.VMs = nil
+			gomega.Expect(placedNamespaces(p)).To(gomega.Equal([]string{"default-ns"}))
+		})
+
+		ginkgo.It("should resolve the target namespace of the VM", func() {
+			p := newPlan()
+			gomega.Expect(p.PlacedNamespace(ref.Ref{ID: "vm-1"})).To(gomega.Equal("production"))
+			gomega.Expect(p.PlacedNamespace(ref.Ref{ID: "vm-4"})).To(gomega.Equal("default-ns"))
+			// assigned when the migration started.
+			gomega.Expect(p.VMTargetNamespace(ref.Ref{ID: "vm-1"})).To(gomega.Equal("default-ns"))
+			p.Status.Migration.VMs = []*plan.VMStatus{
+				{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}, TargetNamespace: "production"},
+				{VM: plan.VM{Ref: ref.Ref{ID: "vm-5"}}, TargetNamespace: "archived"},
+			}
+			gomega.Expect(p.VMTargetNamespace(ref.Ref{ID: "vm-1"})).To(gomega.Equal("production"))
+			gomega.Expect(p.TargetNamespaces()).To(gomega.Equal([]string{"default-ns", "production", "archived"}))
+		})
+	})
+})
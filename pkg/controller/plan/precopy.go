@@ -0,0 +1,295 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Precopy labels.
+const (
+	// Precopy applied by the pod.
+	kPrecopy = "precopy"
+	// UID of the PVC the precopy is applied to.
+	kPVC = "pvc"
+)
+
+// Precopy pod volume.
+const (
+	precopyVolumeName = "target"
+	precopyMountPath  = "/mnt/"
+	precopyFilePath   = "/mnt/disk.img"
+	precopyDevicePath = "/dev/block"
+)
+
+// Update the progress of the OpenStack warm migration precopies
+// following the initial transfer. The extents of the latest precopy
+// backup, changed since the previous precopy, are written to each
+// PVC by a pod running the OpenStack populator in backup mode. The
+// PVC is annotated once the backup has been applied so the pod is
+// not created again.
+func (r *Migration) updatePrecopyProgress(vm *plan.VMStatus, step *plan.Step) (err error) {
+	n := len(vm.Warm.Precopies)
+	if n < 1 {
+		return
+	}
+	precopy := vm.Warm.Precopies[n-1].Snapshot
+	annotation := fmt.Sprintf("%s.%s", base.AnnCheckpointsCopied, precopy)
+	pvcs, err := r.kubevirt.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	for _, pvc := range pvcs {
+		if _, ok := pvc.Annotations["lun"]; ok {
+			// skip LUNs
+			continue
+		}
+		task, found := step.FindTask(r.builder.ResolvePersistentVolumeClaimIdentifier(pvc))
+		if !found {
+			continue
+		}
+		if _, copied := pvc.Annotations[annotation]; copied {
+			r.setTaskCompleted(task)
+			continue
+		}
+		var pod *core.Pod
+		pod, err = r.kubevirt.EnsurePrecopyPod(vm, pvc, precopy)
+		if err != nil {
+			return
+		}
+		switch pod.Status.Phase {
+		case core.PodSucceeded:
+			pvcCopy := pvc.DeepCopy()
+			if pvc.Annotations == nil {
+				pvc.Annotations = make(map[string]string)
+			}
+			pvc.Annotations[annotation] = "true"
+			err = r.Destination.Client.Patch(context.TODO(), pvc, client.MergeFrom(pvcCopy))
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			err = r.kubevirt.DeleteObject(pod, vm, "Deleted precopy pod.", "pod")
+			if err != nil {
+				return
+			}
+			r.setTaskCompleted(task)
+		case core.PodFailed:
+			msg, _ := terminationMessage(pod)
+			err = liberr.New(
+				fmt.Sprintf(
+					"precopy pod %s failed for PVC %s. Please check the pod logs. %s",
+					path.Join(pod.Namespace, pod.Name),
+					pvc.Name,
+					msg))
+			return
+		default:
+			task.Phase = api.StepRunning
+			task.MarkStarted()
+			task.Reason = "Applying the precopy backup"
+		}
+	}
+	step.ReflectTasks()
+	return
+}
+
+// Ensure the pod applying the delta of a precopy to a PVC exists.
+func (r *KubeVirt) EnsurePrecopyPod(vm *plan.VMStatus, pvc *core.PersistentVolumeClaim, precopy string) (pod *core.Pod, err error) {
+	labels := r.precopyLabels(vm.Ref, pvc, precopy)
+	list, err := r.GetPodsWithLabels(labels)
+	if err != nil {
+		return
+	}
+	if len(list.Items) > 0 {
+		pod = &list.Items[0]
+		return
+	}
+	pod, err = r.precopyPod(vm, pvc, labels)
+	if err != nil {
+		return
+	}
+	err = r.Destination.Client.Create(context.TODO(), pod)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Created precopy pod.",
+		"pod",
+		path.Join(pod.Namespace, pod.Name),
+		"pvc",
+		pvc.Name,
+		"precopy",
+		precopy,
+		"vm",
+		vm.String())
+	return
+}
+
+// Delete the precopy pods of a VM.
+func (r *KubeVirt) DeletePrecopyPods(vm *plan.VMStatus) (err error) {
+	labels := r.vmAllButMigrationLabels(vm.Ref)
+	labels[kApp] = kPrecopy
+	list, err := r.GetPodsWithLabels(labels)
+	if err != nil {
+		return
+	}
+	for _, object := range list.Items {
+		err = r.DeleteObject(&object, vm, "Deleted precopy pod.", "pod")
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Build the pod applying the precopy backup referenced by
+// the volume populator of the PVC.
+func (r *KubeVirt) precopyPod(vm *plan.VMStatus, pvc *core.PersistentVolumeClaim, labels map[string]string) (pod *core.Pod, err error) {
+	if Settings.Migration.OpenstackPopulatorImage == "" {
+		err = liberr.New("The OpenStack populator image is not configured.")
+		return
+	}
+	if pvc.Spec.DataSourceRef == nil {
+		err = liberr.New(
+			"PVC has no volume populator.",
+			"pvc",
+			path.Join(pvc.Namespace, pvc.Name))
+		return
+	}
+	populator := &api.OpenstackVolumePopulator{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Spec.DataSourceRef.Name},
+		populator)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	backup, found := populator.Annotations[base.AnnPrecopyBackup]
+	if !found {
+		err = liberr.New(
+			"The volume populator has no precopy backup.",
+			"populator",
+			path.Join(populator.Namespace, populator.Name))
+		return
+	}
+	annotations := make(map[string]string)
+	if r.Plan.Spec.TransferNetwork != nil {
+		err = r.setTransferNetwork(annotations)
+		if err != nil {
+			return
+		}
+	}
+	if rate := populator.Spec.TransferRateLimit; rate > 0 {
+		annotations[AnnIngressBandwidth] = IngressBandwidth(rate)
+	}
+	container := core.Container{
+		Name:  "populate",
+		Image: Settings.Migration.OpenstackPopulatorImage,
+		EnvFrom: []core.EnvFromSource{
+			{
+				SecretRef: &core.SecretEnvSource{
+					LocalObjectReference: core.LocalObjectReference{
+						Name: populator.Spec.SecretName,
+					},
+				},
+			},
+		},
+		Resources: core.ResourceRequirements{
+			Requests: core.ResourceList{
+				core.ResourceCPU:    Settings.Migration.PopulatorContainerRequestsCpu,
+				core.ResourceMemory: Settings.Migration.PopulatorContainerRequestsMemory,
+			},
+			Limits: core.ResourceList{
+				core.ResourceCPU:    Settings.Migration.PopulatorContainerLimitsCpu,
+				core.ResourceMemory: Settings.Migration.PopulatorContainerLimitsMemory,
+			},
+		},
+		SecurityContext: &core.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			RunAsNonRoot:             ptr.To(true),
+			RunAsUser:                ptr.To[int64](qemuUser),
+			Capabilities: &core.Capabilities{
+				Drop: []core.Capability{"ALL"},
+			},
+		},
+	}
+	volumePath := precopyFilePath
+	if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == core.PersistentVolumeBlock {
+		volumePath = precopyDevicePath
+		container.VolumeDevices = []core.VolumeDevice{
+			{
+				Name:       precopyVolumeName,
+				DevicePath: precopyDevicePath,
+			},
+		}
+	} else {
+		container.VolumeMounts = []core.VolumeMount{
+			{
+				Name:      precopyVolumeName,
+				MountPath: precopyMountPath,
+			},
+		}
+	}
+	container.Args = []string{
+		"--volume-path=" + volumePath,
+		"--endpoint=" + populator.Spec.IdentityURL,
+		"--secret-name=" + populator.Spec.SecretName,
+		"--backup-id=" + backup,
+		"--parent-backup-id=" + populator.Annotations[base.AnnPrecopyParentBackup],
+		"--cr-name=" + populator.Name,
+		"--cr-namespace=" + populator.Namespace,
+		fmt.Sprintf("--owner-uid=%s", pvc.UID),
+		fmt.Sprintf("--pvc-size=%d", pvc.Spec.Resources.Requests.Storage().Value()),
+	}
+	pod = &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:    pvc.Namespace,
+			GenerateName: r.getGeneratedName(vm) + "precopy-",
+			Labels:       labels,
+			Annotations:  annotations,
+		},
+		Spec: core.PodSpec{
+			RestartPolicy: core.RestartPolicyNever,
+			Containers:    []core.Container{container},
+			Volumes: []core.Volume{
+				{
+					Name: precopyVolumeName,
+					VolumeSource: core.VolumeSource{
+						PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvc.Name,
+						},
+					},
+				},
+			},
+			SecurityContext: &core.PodSecurityContext{
+				FSGroup: ptr.To[int64](qemuGroup),
+				SeccompProfile: &core.SeccompProfile{
+					Type: core.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+		},
+	}
+	return
+}
+
+// Labels for a pod applying a precopy to a PVC.
+func (r *KubeVirt) precopyLabels(vmRef ref.Ref, pvc *core.PersistentVolumeClaim, precopy string) (labels map[string]string) {
+	labels = r.vmLabels(vmRef)
+	labels[kApp] = kPrecopy
+	labels[kPrecopy] = precopy
+	labels[kPVC] = string(pvc.UID)
+	return
+}
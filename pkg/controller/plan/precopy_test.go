@@ -0,0 +1,113 @@
+package plan
+
+import (
+	"context"
+
+	v1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = ginkgo.Describe("Precopy", func() {
+	var image string
+
+	ginkgo.BeforeEach(func() {
+		image = Settings.Migration.OpenstackPopulatorImage
+		Settings.Migration.OpenstackPopulatorImage = "populator:latest"
+	})
+
+	ginkgo.AfterEach(func() {
+		Settings.Migration.OpenstackPopulatorImage = image
+	})
+
+	populator := &v1beta1.OpenstackVolumePopulator{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "populator",
+			Annotations: map[string]string{
+				base.AnnPrecopyBackup:       "backup-2",
+				base.AnnPrecopyParentBackup: "backup-1",
+			},
+		},
+		Spec: v1beta1.OpenstackVolumePopulatorSpec{
+			IdentityURL:       "https://keystone:5000/v3",
+			SecretName:        "secret",
+			ImageID:           "image-1",
+			TransferRateLimit: 10,
+		},
+	}
+	vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+	newPVC := func(volumeMode v1.PersistentVolumeMode) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "disk", UID: "pvc-uid"},
+			Spec: v1.PersistentVolumeClaimSpec{
+				VolumeMode:    ptr.To(volumeMode),
+				DataSourceRef: &v1.TypedObjectReference{Kind: v1beta1.OpenstackVolumePopulatorKind, Name: populator.Name},
+				Resources: v1.VolumeResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		}
+	}
+
+	ginkgo.It("should apply the backup of the populator", func() {
+		kubevirt := createKubeVirt(populator)
+		pvc := newPVC(v1.PersistentVolumeFilesystem)
+		pod, err := kubevirt.EnsurePrecopyPod(vm, pvc, "precopy-1")
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(pod.Labels).To(gomega.HaveKeyWithValue(kApp, kPrecopy))
+		gomega.Expect(pod.Labels).To(gomega.HaveKeyWithValue(kPrecopy, "precopy-1"))
+		gomega.Expect(pod.Annotations).To(gomega.HaveKeyWithValue(AnnIngressBandwidth, "80M"))
+		container := pod.Spec.Containers[0]
+		gomega.Expect(container.Image).To(gomega.Equal("populator:latest"))
+		gomega.Expect(container.Args).To(gomega.ContainElements(
+			"--volume-path=/mnt/disk.img",
+			"--backup-id=backup-2",
+			"--parent-backup-id=backup-1",
+			"--owner-uid=pvc-uid",
+			"--pvc-size=1073741824"))
+		gomega.Expect(container.Args).ToNot(gomega.ContainElement(gomega.HavePrefix("--image-id")))
+		gomega.Expect(container.VolumeMounts).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should write to the block device", func() {
+		kubevirt := createKubeVirt(populator)
+		pod, err := kubevirt.EnsurePrecopyPod(vm, newPVC(v1.PersistentVolumeBlock), "precopy-1")
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		container := pod.Spec.Containers[0]
+		gomega.Expect(container.Args).To(gomega.ContainElement("--volume-path=/dev/block"))
+		gomega.Expect(container.VolumeDevices).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should not create the pod twice", func() {
+		kubevirt := createKubeVirt(populator)
+		pvc := newPVC(v1.PersistentVolumeFilesystem)
+		_, err := kubevirt.EnsurePrecopyPod(vm, pvc, "precopy-1")
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		_, err = kubevirt.EnsurePrecopyPod(vm, pvc, "precopy-1")
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		_, err = kubevirt.EnsurePrecopyPod(vm, pvc, "precopy-2")
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		pods := &v1.PodList{}
+		gomega.Expect(kubevirt.Destination.Client.List(context.TODO(), pods)).To(gomega.Succeed())
+		gomega.Expect(pods.Items).To(gomega.HaveLen(2))
+		gomega.Expect(kubevirt.DeletePrecopyPods(vm)).To(gomega.Succeed())
+		gomega.Expect(kubevirt.Destination.Client.List(context.TODO(), pods)).To(gomega.Succeed())
+		gomega.Expect(pods.Items).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should fail without the precopy backup", func() {
+		withoutBackup := populator.DeepCopy()
+		withoutBackup.Annotations = nil
+		kubevirt := createKubeVirt(withoutBackup)
+		_, err := kubevirt.EnsurePrecopyPod(vm, newPVC(v1.PersistentVolumeFilesystem), "precopy-1")
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+})
@@ -25,7 +25,8 @@ func (r PlanPredicate) Create(e event.TypedCreateEvent[*api.Plan]) bool {
 
 func (r PlanPredicate) Update(e event.TypedUpdateEvent[*api.Plan]) bool {
 	object := e.ObjectNew
-	changed := object.Status.ObservedGeneration < object.Generation
+	changed := object.Status.ObservedGeneration < object.Generation ||
+		e.ObjectOld.Annotations[api.AnnPreview] != object.Annotations[api.AnnPreview]
 	if changed {
 		libref.Mapper.Update(event.UpdateEvent{
 			ObjectOld: e.ObjectOld,
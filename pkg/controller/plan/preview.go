@@ -0,0 +1,473 @@
+package plan
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8smeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/storage/names"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// Preview condition.
+const (
+	PreviewFailed = "PreviewFailed"
+)
+
+// Characters not permitted in a ConfigMap key.
+var previewKeyReplacer = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// Render the preview of the plan when requested by the
+// preview annotation. The preview is stored in a ConfigMap
+// in the plan namespace and rendered again when the plan
+// has been updated.
+func (r *Reconciler) preview(plan *api.Plan) {
+	if plan.Annotations[api.AnnPreview] != "true" || plan.Spec.Archived || plan.Status.HasBlockerCondition() {
+		return
+	}
+	generation := strconv.FormatInt(plan.Generation, 10)
+	configMap := &core.ConfigMap{}
+	err := r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: plan.Namespace,
+			Name:      plan.PreviewName(),
+		},
+		configMap)
+	found := err == nil
+	if err != nil && !k8serr.IsNotFound(err) {
+		r.Log.Error(err, "Couldn't get the plan preview.")
+		return
+	}
+	if found && configMap.Annotations[api.AnnPreview] == generation {
+		return
+	}
+	data, err := r.renderPreview(plan)
+	if err != nil {
+		r.Log.Error(err, "Couldn't render the plan preview.")
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     PreviewFailed,
+			Status:   True,
+			Category: api.CategoryWarn,
+			Message:  "The preview of the resources created by the plan could not be rendered.",
+			Items:    []string{err.Error()},
+		})
+		return
+	}
+	configMap.Namespace = plan.Namespace
+	configMap.Name = plan.PreviewName()
+	configMap.Annotations = map[string]string{api.AnnPreview: generation}
+	configMap.Data = data
+	if found {
+		err = r.Update(context.TODO(), configMap)
+	} else {
+		configMap.OwnerReferences = []meta.OwnerReference{
+			{
+				APIVersion: api.SchemeGroupVersion.String(),
+				Kind:       "Plan",
+				Name:       plan.Name,
+				UID:        plan.UID,
+			},
+		}
+		err = r.Create(context.TODO(), configMap)
+	}
+	if err != nil {
+		r.Log.Error(err, "Couldn't store the plan preview.")
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     PreviewFailed,
+			Status:   True,
+			Category: api.CategoryWarn,
+			Message:  "The preview of the resources created by the plan could not be stored.",
+			Items:    []string{err.Error()},
+		})
+		return
+	}
+	r.Log.Info("Rendered the plan preview.", "configMap", configMap.Name)
+}
+
+// Render the preview of each VM in the plan.
+// Returns the YAML documents keyed by VM.
+func (r *Reconciler) renderPreview(plan *api.Plan) (data map[string]string, err error) {
+	ctx, err := plancontext.New(r, plan, r.Log)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	preview, err := NewPreview(ctx)
+	if err != nil {
+		return
+	}
+	data = map[string]string{}
+	for _, vm := range plan.Spec.VMs {
+		vmStatus := &planapi.VMStatus{VM: vm}
+		if current, found := plan.Status.Migration.FindVM(vm.Ref); found {
+			vmStatus.NewName = current.NewName
+		}
+		var objects []client.Object
+		objects, err = preview.Render(vmStatus)
+		if err != nil {
+			return
+		}
+		var document string
+		document, err = preview.YAML(objects)
+		if err != nil {
+			return
+		}
+		key := vm.Name
+		if key == "" {
+			key = vm.ID
+		}
+		data[PreviewKey(key)] = document
+	}
+	return
+}
+
+// Key of the VM preview in the ConfigMap.
+func PreviewKey(vm string) string {
+	return previewKeyReplacer.ReplaceAllString(vm, "_") + ".yaml"
+}
+
+// Preview renders the resources created on the destination
+// cluster for a VM by running the provider builders against
+// the inventory. Objects the builders and ensurers would write
+// to the cluster are kept in memory instead.
+type Preview struct {
+	*plancontext.Context
+	// Destination client.
+	client *previewClient
+	// KubeVirt.
+	kubevirt KubeVirt
+}
+
+// Build a preview for the plan context.
+func NewPreview(ctx *plancontext.Context) (preview *Preview, err error) {
+	previewCtx := *ctx
+	destination := &previewClient{Client: ctx.Destination.Client}
+	previewCtx.Destination.Client = destination
+	previewCtx.Client = &previewClient{Client: ctx.Client}
+	previewCtx.Labeler = plancontext.Labeler{Context: &previewCtx}
+	adapter, err := adapter.New(ctx.Source.Provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	builder, err := adapter.Builder(&previewCtx)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	ensurer, err := adapter.Ensurer(&previewCtx)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	preview = &Preview{
+		Context: &previewCtx,
+		client:  destination,
+		kubevirt: KubeVirt{
+			Context: &previewCtx,
+			Builder: builder,
+			Ensurer: ensurer,
+		},
+	}
+	return
+}
+
+// Render the VirtualMachine, DataVolumes, PVCs and
+// guest conversion pod created for the VM.
+func (r *Preview) Render(vm *planapi.VMStatus) (objects []client.Object, err error) {
+	var pvcs []*core.PersistentVolumeClaim
+	if r.kubevirt.Builder.SupportsVolumePopulators() {
+		pvcs, err = r.kubevirt.PopulatorVolumes(vm.Ref)
+		if err != nil {
+			return
+		}
+		for _, pvc := range pvcs {
+			err = r.client.Create(context.TODO(), pvc)
+			if err != nil {
+				return
+			}
+		}
+	} else if r.Plan.Spec.Type != api.MigrationOnlyConversion {
+		var dataVolumes []cdi.DataVolume
+		dataVolumes, err = r.kubevirt.DataVolumes(vm)
+		if err != nil {
+			return
+		}
+		for i := range dataVolumes {
+			dv := &dataVolumes[i]
+			err = r.client.Create(context.TODO(), dv)
+			if err != nil {
+				return
+			}
+			objects = append(objects, dv)
+			pvc := r.claim(dv)
+			err = r.client.Create(context.TODO(), pvc)
+			if err != nil {
+				return
+			}
+			pvcs = append(pvcs, pvc)
+		}
+	}
+	virtualMachine, err := r.kubevirt.virtualMachine(vm, false)
+	if err != nil {
+		return
+	}
+	objects = append([]client.Object{virtualMachine}, objects...)
+	for _, pvc := range pvcs {
+		objects = append(objects, pvc)
+	}
+	if r.Source.Provider.RequiresConversion() && !r.Plan.Spec.SkipGuestConversion {
+		var pod *core.Pod
+		pod, err = r.conversionPod(vm)
+		if err != nil {
+			return
+		}
+		if pod != nil {
+			objects = append(objects, pod)
+		}
+	}
+	return
+}
+
+// Render the objects as a multi-document YAML.
+func (r *Preview) YAML(objects []client.Object) (document string, err error) {
+	documents := []string{}
+	for _, object := range objects {
+		object = object.DeepCopyObject().(client.Object)
+		gvk, gErr := apiutil.GVKForObject(object, r.client.Scheme())
+		if gErr != nil {
+			err = liberr.Wrap(gErr)
+			return
+		}
+		object.GetObjectKind().SetGroupVersionKind(gvk)
+		object.SetManagedFields(nil)
+		b, mErr := yaml.Marshal(object)
+		if mErr != nil {
+			err = liberr.Wrap(mErr)
+			return
+		}
+		documents = append(documents, string(b))
+	}
+	document = strings.Join(documents, "---\n")
+	return
+}
+
+// Build the PVC created by CDI for a DataVolume.
+func (r *Preview) claim(dv *cdi.DataVolume) (pvc *core.PersistentVolumeClaim) {
+	pvc = &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Name:        dv.Name,
+			Namespace:   dv.Namespace,
+			Labels:      dv.Labels,
+			Annotations: dv.Annotations,
+		},
+	}
+	if dv.Spec.PVC != nil {
+		pvc.Spec = *dv.Spec.PVC
+	} else if dv.Spec.Storage != nil {
+		pvc.Spec.AccessModes = dv.Spec.Storage.AccessModes
+		pvc.Spec.Resources = dv.Spec.Storage.Resources
+		pvc.Spec.StorageClassName = dv.Spec.Storage.StorageClassName
+		pvc.Spec.VolumeMode = dv.Spec.Storage.VolumeMode
+	}
+	return
+}
+
+// Render the guest conversion pod.
+func (r *Preview) conversionPod(vm *planapi.VMStatus) (pod *core.Pod, err error) {
+	virtualMachine, err := r.kubevirt.virtualMachine(vm, true)
+	if err != nil {
+		return
+	}
+	pvcs, err := r.kubevirt.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	labels := r.kubevirt.vmLabels(vm.Ref)
+	labels[kV2V] = "true"
+	secret, err := r.kubevirt.ensureSecret(vm.Ref, r.kubevirt.secretDataSetterForCDI(vm.Ref), labels)
+	if err != nil {
+		return
+	}
+	var vddkConfigMap *core.ConfigMap
+	if r.Source.Provider.UseVddkAioOptimization() {
+		vddkConfigMap, err = r.kubevirt.ensureVddkConfigMap(r.Plan.VMTargetNamespace(vm.Ref))
+		if err != nil {
+			return
+		}
+	}
+	pod, err = r.kubevirt.getVirtV2vPod(
+		vm,
+		virtualMachine.Spec.Template.Spec.Volumes,
+		vddkConfigMap,
+		pvcs,
+		secret,
+		VirtV2vConversionPod,
+		nil)
+	return
+}
+
+// Destination client used to render the preview.
+// Reads are passed through to the cluster and include
+// the objects created by the preview. Writes are kept
+// in memory and never reach the cluster.
+type previewClient struct {
+	client.Client
+	// Objects created by the preview.
+	created []client.Object
+}
+
+// Get the object, created by the preview or from the cluster.
+func (r *previewClient) Get(ctx context.Context, key client.ObjectKey, object client.Object, opts ...client.GetOption) (err error) {
+	created, found, err := r.find(object, key)
+	if err != nil {
+		return
+	}
+	if found {
+		reflect.ValueOf(object).Elem().Set(reflect.ValueOf(created.DeepCopyObject()).Elem())
+		return
+	}
+	err = r.Client.Get(ctx, key, object, opts...)
+	return
+}
+
+// List the objects from the cluster and the objects
+// created by the preview.
+func (r *previewClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (err error) {
+	err = r.Client.List(ctx, list, opts...)
+	if err != nil {
+		return
+	}
+	gvk, err := apiutil.GVKForObject(list, r.Scheme())
+	if err != nil {
+		return
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	options := &client.ListOptions{}
+	options.ApplyOptions(opts)
+	items, err := k8smeta.ExtractList(list)
+	if err != nil {
+		return
+	}
+	for _, object := range r.created {
+		objectGVK, gErr := apiutil.GVKForObject(object, r.Scheme())
+		if gErr != nil || objectGVK != gvk {
+			continue
+		}
+		if options.Namespace != "" && object.GetNamespace() != options.Namespace {
+			continue
+		}
+		if options.LabelSelector != nil && !options.LabelSelector.Matches(k8slabels.Set(object.GetLabels())) {
+			continue
+		}
+		items = append(items, object.DeepCopyObject())
+	}
+	err = k8smeta.SetList(list, items)
+	return
+}
+
+// Keep the created object in memory.
+// A name is generated when requested.
+func (r *previewClient) Create(ctx context.Context, object client.Object, opts ...client.CreateOption) (err error) {
+	if object.GetName() == "" && object.GetGenerateName() != "" {
+		object.SetName(names.SimpleNameGenerator.GenerateName(object.GetGenerateName()))
+	}
+	_, found, err := r.find(object, client.ObjectKeyFromObject(object))
+	if err != nil {
+		return
+	}
+	if found {
+		gvk, _ := apiutil.GVKForObject(object, r.Scheme())
+		err = k8serr.NewAlreadyExists(gvk.GroupVersion().WithResource(strings.ToLower(gvk.Kind)).GroupResource(), object.GetName())
+		return
+	}
+	r.created = append(r.created, object.DeepCopyObject().(client.Object))
+	return
+}
+
+// Update the object kept in memory.
+func (r *previewClient) Update(ctx context.Context, object client.Object, opts ...client.UpdateOption) (err error) {
+	for i, created := range r.created {
+		if created.GetNamespace() == object.GetNamespace() &&
+			created.GetName() == object.GetName() &&
+			r.sameKind(created, object) {
+			r.created[i] = object.DeepCopyObject().(client.Object)
+		}
+	}
+	return
+}
+
+// The preview does not patch objects.
+func (r *previewClient) Patch(ctx context.Context, object client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return nil
+}
+
+// The preview does not delete objects.
+func (r *previewClient) Delete(ctx context.Context, object client.Object, opts ...client.DeleteOption) error {
+	return nil
+}
+
+// The preview does not delete objects.
+func (r *previewClient) DeleteAllOf(ctx context.Context, object client.Object, opts ...client.DeleteAllOfOption) error {
+	return nil
+}
+
+// The preview does not update the status of objects.
+func (r *previewClient) Status() client.SubResourceWriter {
+	return &previewWriter{}
+}
+
+// Find an object created by the preview.
+func (r *previewClient) find(object client.Object, key client.ObjectKey) (created client.Object, found bool, err error) {
+	for _, c := range r.created {
+		if c.GetNamespace() != key.Namespace || c.GetName() != key.Name {
+			continue
+		}
+		if r.sameKind(c, object) {
+			created = c
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// Determine whether the objects are of the same kind.
+func (r *previewClient) sameKind(a, b runtime.Object) bool {
+	aGVK, aErr := apiutil.GVKForObject(a, r.Scheme())
+	bGVK, bErr := apiutil.GVKForObject(b, r.Scheme())
+	return aErr == nil && bErr == nil && aGVK == bGVK
+}
+
+// Status writer that drops all writes.
+type previewWriter struct{}
+
+func (r *previewWriter) Create(ctx context.Context, object client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return nil
+}
+
+func (r *previewWriter) Update(ctx context.Context, object client.Object, opts ...client.SubResourceUpdateOption) error {
+	return nil
+}
+
+func (r *previewWriter) Patch(ctx context.Context, object client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return nil
+}
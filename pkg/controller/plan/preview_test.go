@@ -0,0 +1,125 @@
+package plan
+
+import (
+	"context"
+
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("Plan Preview", func() {
+	newClient := func(objects ...runtime.Object) (*previewClient, client.Client) {
+		scheme := runtime.NewScheme()
+		_ = core.AddToScheme(scheme)
+		cluster := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(objects...).
+			Build()
+		return &previewClient{Client: cluster}, cluster
+	}
+
+	ginkgo.Describe("previewClient", func() {
+		ginkgo.It("should keep created objects in memory", func() {
+			preview, cluster := newClient()
+			secret := &core.Secret{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace:    "test",
+					GenerateName: "plan-vm-",
+					Labels:       map[string]string{"vmID": "vm-1"},
+				},
+			}
+			gomega.Expect(preview.Create(context.TODO(), secret)).To(gomega.Succeed())
+			gomega.Expect(secret.Name).To(gomega.HavePrefix("plan-vm-"))
+			// Not created on the cluster.
+			err := cluster.Get(context.TODO(), client.ObjectKeyFromObject(secret), &core.Secret{})
+			gomega.Expect(k8serr.IsNotFound(err)).To(gomega.BeTrue())
+			// Read back from the preview.
+			found := &core.Secret{}
+			gomega.Expect(preview.Get(context.TODO(), client.ObjectKeyFromObject(secret), found)).To(gomega.Succeed())
+			gomega.Expect(found.Name).To(gomega.Equal(secret.Name))
+			// Created again.
+			err = preview.Create(context.TODO(), secret)
+			gomega.Expect(k8serr.IsAlreadyExists(err)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should list the cluster and created objects", func() {
+			existing := &core.PersistentVolumeClaim{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "existing",
+					Labels:    map[string]string{"vmID": "vm-1"},
+				},
+			}
+			preview, _ := newClient(existing)
+			for _, pvc := range []*core.PersistentVolumeClaim{
+				{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "disk-0", Labels: map[string]string{"vmID": "vm-1"}}},
+				{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "disk-1", Labels: map[string]string{"vmID": "vm-2"}}},
+				{ObjectMeta: meta.ObjectMeta{Namespace: "other", Name: "disk-2", Labels: map[string]string{"vmID": "vm-1"}}},
+			} {
+				gomega.Expect(preview.Create(context.TODO(), pvc)).To(gomega.Succeed())
+			}
+			gomega.Expect(preview.Create(context.TODO(), &core.Secret{
+				ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "secret", Labels: map[string]string{"vmID": "vm-1"}},
+			})).To(gomega.Succeed())
+			list := &core.PersistentVolumeClaimList{}
+			err := preview.List(
+				context.TODO(),
+				list,
+				&client.ListOptions{
+					Namespace:     "test",
+					LabelSelector: k8slabels.SelectorFromSet(map[string]string{"vmID": "vm-1"}),
+				})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			listed := []string{}
+			for _, pvc := range list.Items {
+				listed = append(listed, pvc.Name)
+			}
+			gomega.Expect(listed).To(gomega.ConsistOf("existing", "disk-0"))
+		})
+
+		ginkgo.It("should not write to the cluster", func() {
+			existing := &core.ConfigMap{
+				ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "config"},
+				Data:       map[string]string{"key": "value"},
+			}
+			preview, cluster := newClient(existing)
+			updated := existing.DeepCopy()
+			updated.Data["key"] = "updated"
+			gomega.Expect(preview.Update(context.TODO(), updated)).To(gomega.Succeed())
+			gomega.Expect(preview.Delete(context.TODO(), existing)).To(gomega.Succeed())
+			found := &core.ConfigMap{}
+			gomega.Expect(cluster.Get(context.TODO(), client.ObjectKeyFromObject(existing), found)).To(gomega.Succeed())
+			gomega.Expect(found.Data["key"]).To(gomega.Equal("value"))
+		})
+	})
+
+	ginkgo.Describe("YAML", func() {
+		ginkgo.It("should render the objects as YAML documents", func() {
+			overlay, _ := newClient()
+			preview := &Preview{client: overlay}
+			document, err := preview.YAML([]client.Object{
+				&core.PersistentVolumeClaim{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "disk-0"}},
+				&core.Pod{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "pod"}},
+			})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(document).To(gomega.ContainSubstring("kind: PersistentVolumeClaim"))
+			gomega.Expect(document).To(gomega.ContainSubstring("---\n"))
+			gomega.Expect(document).To(gomega.ContainSubstring("kind: Pod"))
+		})
+	})
+
+	ginkgo.DescribeTable("PreviewKey",
+		func(vm, key string) {
+			gomega.Expect(PreviewKey(vm)).To(gomega.Equal(key))
+		},
+		ginkgo.Entry("when valid", "vm-1", "vm-1.yaml"),
+		ginkgo.Entry("when invalid characters", "my vm/1", "my_vm_1.yaml"),
+	)
+})
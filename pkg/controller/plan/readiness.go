@@ -0,0 +1,112 @@
+package plan
+
+import (
+	"context"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Build the readiness summary.
+func newReadinessSummary(throughput int64, source string) *planapi.ReadinessSummary {
+	return &planapi.ReadinessSummary{
+		Throughput:       throughput,
+		ThroughputSource: source,
+	}
+}
+
+// Add the readiness of a VM to the summary.
+// The score is averaged by estimate().
+func addReadiness(summary *planapi.ReadinessSummary, readiness *base.Readiness) {
+	summary.VMs++
+	summary.Score += readiness.Score
+	summary.Warnings += len(readiness.Warnings)
+	summary.TransferSize += readiness.TransferSize
+	switch readiness.Complexity {
+	case base.ComplexityBlocked:
+		summary.Blocked++
+	case base.ComplexityLow:
+		summary.Low++
+	case base.ComplexityMedium:
+		summary.Medium++
+	case base.ComplexityHigh:
+		summary.High++
+	}
+}
+
+// Average the score and estimate the transfer time
+// using the throughput.
+func estimate(summary *planapi.ReadinessSummary) {
+	if summary.VMs > 0 {
+		summary.Score /= summary.VMs
+	}
+	if summary.Throughput > 0 {
+		seconds := (summary.TransferSize >> 20) / summary.Throughput
+		summary.EstimatedTransferTime = (time.Duration(seconds) * time.Second).String()
+	}
+}
+
+// Assess the migration readiness of the VMs.
+// The readiness is scored by the inventory and is only
+// reported for the providers that support it. The transfer time is
+// estimated using the throughput of previous migrations from the
+// same provider. The readiness is only assessed again when the
+// spec changes. The VMs that cannot be assessed are counted as
+// unassessed. Failures are logged and do not block the plan.
+func (r *Reconciler) assessReadiness(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	if plan.Status.HasCondition(Executing) {
+		return
+	}
+	if plan.Status.HasBlockerCondition() {
+		plan.Status.Readiness = nil
+		return
+	}
+	if readiness := plan.Status.Readiness; readiness != nil && readiness.ObservedGeneration == plan.Generation {
+		return
+	}
+	plan.Status.Readiness = nil
+	switch ctx.Source.Provider.Type() {
+	case api.VSphere, api.OVirt:
+	default:
+		return
+	}
+	plans := &api.PlanList{}
+	err = r.List(context.TODO(), plans)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	summary := newReadinessSummary(base.DefaultThroughput, planapi.ThroughputDefault)
+	if throughput := base.MeasureThroughput(plans.Items, plan.Spec.Provider.Source); throughput > 0 {
+		summary = newReadinessSummary(throughput, planapi.ThroughputHistory)
+	}
+	for _, vm := range plan.Spec.VMs {
+		vmRef := vm.Ref
+		_, err = ctx.Source.Inventory.VM(&vmRef)
+		if err == nil {
+			readiness := &base.Readiness{}
+			err = ctx.Source.Inventory.Get(readiness, vmRef.ID)
+			if err == nil {
+				addReadiness(summary, readiness)
+				continue
+			}
+		}
+		r.Log.Info(
+			"Could not assess the readiness (VM skipped).",
+			"vm",
+			vmRef.String(),
+			"error",
+			err.Error())
+		err = nil
+		summary.Unassessed++
+	}
+	estimate(summary)
+	summary.ObservedGeneration = plan.Generation
+	plan.Status.Readiness = summary
+	return
+}
@@ -0,0 +1,152 @@
+package plan
+
+import (
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	basecontroller "github.com/kubev2v/forklift/pkg/controller/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("Plan Readiness", func() {
+	const GiB = int64(1) << 30
+	provider := core.ObjectReference{Namespace: "ns", Name: "vcenter"}
+
+	newPlan := func(source core.ObjectReference, steps ...*plan.Step) api.Plan {
+		vm := &plan.VMStatus{}
+		for _, step := range steps {
+			vm.Pipeline = append(vm.Pipeline, step)
+		}
+		p := api.Plan{}
+		p.Spec.Provider.Source = source
+		p.Status.Migration.VMs = []*plan.VMStatus{vm}
+		return p
+	}
+	newStep := func(name string, mb int64, elapsed time.Duration) *plan.Step {
+		started := meta.NewTime(time.Now().Add(-elapsed))
+		completed := meta.NewTime(started.Add(elapsed))
+		return &plan.Step{
+			Task: plan.Task{
+				Name:  name,
+				Timed: plan.Timed{Started: &started, Completed: &completed},
+			},
+			Tasks: []*plan.Task{
+				{
+					Annotations: map[string]string{"unit": "MB"},
+					Progress:    libitr.Progress{Completed: mb},
+				},
+			},
+		}
+	}
+
+	ginkgo.It("should measure the throughput of the provider", func() {
+		failed := newStep("DiskTransfer", 50000, time.Second*10)
+		failed.AddError("failed")
+		plans := []api.Plan{
+			newPlan(provider, newStep("DiskTransfer", 20000, time.Second*100)),
+			newPlan(provider, newStep(DiskTransferV2v, 10000, time.Second*200), failed),
+			newPlan(core.ObjectReference{Namespace: "ns", Name: "other"}, newStep("DiskTransfer", 90000, time.Second)),
+		}
+		gomega.Expect(base.MeasureThroughput(plans, provider)).To(gomega.Equal(int64(100)))
+		gomega.Expect(base.MeasureThroughput(plans[2:], provider)).To(gomega.BeZero())
+	})
+
+	ginkgo.It("should not assess the readiness again until the spec changes", func() {
+		p := &api.Plan{}
+		p.Generation = 2
+		assessed := &plan.ReadinessSummary{VMs: 1, ObservedGeneration: 2}
+		p.Status.Readiness = assessed
+		r := &Reconciler{}
+		err := r.assessReadiness(&plancontext.Context{Plan: p})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(p.Status.Readiness).To(gomega.BeIdenticalTo(assessed))
+	})
+
+	ginkgo.It("should skip the VMs that cannot be assessed", func() {
+		providerType := api.VSphere
+		p := &api.Plan{}
+		p.Generation = 3
+		p.Spec.VMs = []plan.VM{
+			{Ref: ref.Ref{ID: "vm-1"}},
+			{Ref: ref.Ref{ID: "vm-2"}},
+		}
+		scheme := runtime.NewScheme()
+		_ = api.SchemeBuilder.AddToScheme(scheme)
+		r := &Reconciler{
+			Reconciler: basecontroller.Reconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+				Log:    logging.WithName("planReadiness"),
+			},
+		}
+		ctx := &plancontext.Context{Plan: p}
+		ctx.Source.Provider = &api.Provider{Spec: api.ProviderSpec{Type: &providerType}}
+		ctx.Source.Inventory = &readinessInventory{
+			readiness: map[string]*base.Readiness{
+				"vm-1": {Score: 80, Complexity: base.ComplexityLow},
+			},
+		}
+		err := r.assessReadiness(ctx)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		summary := p.Status.Readiness
+		gomega.Expect(summary).ToNot(gomega.BeNil())
+		gomega.Expect(summary.VMs).To(gomega.Equal(1))
+		gomega.Expect(summary.Unassessed).To(gomega.Equal(1))
+		gomega.Expect(summary.Score).To(gomega.Equal(80))
+		gomega.Expect(summary.ObservedGeneration).To(gomega.Equal(int64(3)))
+	})
+
+	ginkgo.It("should summarize the readiness of the VMs", func() {
+		summary := newReadinessSummary(100, plan.ThroughputHistory)
+		addReadiness(summary, &base.Readiness{
+			Score:        90,
+			Complexity:   base.ComplexityLow,
+			Warnings:     []string{"w1"},
+			TransferSize: 100 * GiB,
+		})
+		addReadiness(summary, &base.Readiness{
+			Score:        0,
+			Complexity:   base.ComplexityBlocked,
+			TransferSize: 50 * GiB,
+		})
+		estimate(summary)
+		gomega.Expect(summary.VMs).To(gomega.Equal(2))
+		gomega.Expect(summary.Low).To(gomega.Equal(1))
+		gomega.Expect(summary.Blocked).To(gomega.Equal(1))
+		gomega.Expect(summary.Score).To(gomega.Equal(45))
+		gomega.Expect(summary.Warnings).To(gomega.Equal(1))
+		gomega.Expect(summary.TransferSize).To(gomega.Equal(150 * GiB))
+		gomega.Expect(summary.EstimatedTransferTime).To(gomega.Equal("25m36s"))
+	})
+})
+
+// Inventory serving the readiness of the VMs.
+type readinessInventory struct {
+	web.Client
+	readiness map[string]*base.Readiness
+}
+
+func (r *readinessInventory) VM(ref *base.Ref) (object interface{}, err error) {
+	if _, found := r.readiness[ref.ID]; !found {
+		err = base.NotFoundError{Ref: *ref}
+		return
+	}
+	object = ref
+	return
+}
+
+func (r *readinessInventory) Get(resource interface{}, id string) (err error) {
+	*resource.(*base.Readiness) = *r.readiness[id]
+	return
+}
@@ -0,0 +1,178 @@
+package plan
+
+import (
+	"errors"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+)
+
+// Rollback phases in the order they are run.
+// Each phase is reported as a pipeline step of the same name.
+var rollbackPhases = []string{
+	api.PhaseRollbackDeleteTarget,
+	api.PhaseRollbackRemoveSnapshots,
+	api.PhaseRollbackRestorePowerState,
+}
+
+// Rollback step descriptions.
+var rollbackDescriptions = map[string]string{
+	api.PhaseRollbackDeleteTarget:      "Delete target VM and disks.",
+	api.PhaseRollbackRemoveSnapshots:   "Remove source VM snapshots.",
+	api.PhaseRollbackRestorePowerState: "Restore source VM power state.",
+}
+
+// Determine whether the phase belongs to the rollback itinerary.
+func isRollbackPhase(phase string) bool {
+	for _, p := range rollbackPhases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// Roll back the failed migration of a VM when automatic
+// rollback is enabled on the plan. The rollback steps are
+// appended to the pipeline and the VM is moved to the first
+// rollback phase. Returns true while the rollback is running.
+func (r *Migration) rollback(vm *plan.VMStatus) (rollingBack bool) {
+	if r.Plan.Spec.Rollback != plan.RollbackAutomatic {
+		return
+	}
+	if isRollbackPhase(vm.Phase) {
+		rollingBack = true
+		return
+	}
+	last := rollbackPhases[len(rollbackPhases)-1]
+	if step, found := vm.FindStep(last); found && step.MarkedCompleted() {
+		return
+	}
+	for _, phase := range rollbackPhases {
+		vm.Pipeline = append(
+			vm.Pipeline,
+			&plan.Step{
+				Task: plan.Task{
+					Name:        phase,
+					Description: rollbackDescriptions[phase],
+					Progress:    libitr.Progress{Total: 1},
+					Phase:       api.StepPending,
+				},
+			})
+	}
+	vm.Phase = rollbackPhases[0]
+	r.Log.Info(
+		"Migration [ROLLBACK]",
+		"vm",
+		vm.String())
+	rollingBack = true
+	return
+}
+
+// Step a VM through the rollback itinerary.
+// Rollback is best effort: a failed step is recorded on the
+// step and the rollback proceeds to the next phase.
+func (r *Migration) executeRollback(vm *plan.VMStatus) (err error) {
+	step, found := vm.FindStep(vm.Phase)
+	if !found {
+		vm.AddError("Step '" + vm.Phase + "' not found")
+		vm.Phase = api.PhaseCompleted
+		return
+	}
+	step.MarkStarted()
+	step.Phase = api.StepRunning
+	switch vm.Phase {
+	case api.PhaseRollbackDeleteTarget:
+		err = r.rollbackTarget(vm)
+	case api.PhaseRollbackRemoveSnapshots:
+		err = r.rollbackSnapshots(vm)
+	case api.PhaseRollbackRestorePowerState:
+		err = r.rollbackPowerState(vm)
+	}
+	if err != nil {
+		if errors.As(err, &web.ProviderNotReadyError{}) {
+			return
+		}
+		r.Log.Error(
+			err,
+			"Rollback step failed.",
+			"vm",
+			vm.String(),
+			"phase",
+			vm.Phase)
+		step.AddError(err.Error())
+		err = nil
+	}
+	step.Progress.Completed = step.Progress.Total
+	step.Phase = api.StepCompleted
+	step.MarkCompleted()
+	vm.Phase = r.nextRollbackPhase(vm.Phase)
+	return
+}
+
+// Next rollback phase.
+// The VM is completed after the last rollback phase.
+func (r *Migration) nextRollbackPhase(phase string) string {
+	for i, p := range rollbackPhases {
+		if p == phase && i+1 < len(rollbackPhases) {
+			return rollbackPhases[i+1]
+		}
+	}
+	return api.PhaseCompleted
+}
+
+// Delete the target VM, DataVolumes and PVCs.
+func (r *Migration) rollbackTarget(vm *plan.VMStatus) (err error) {
+	err = r.kubevirt.DeleteVM(vm)
+	if err != nil {
+		return
+	}
+	err = r.deletePopulatorPVCs(vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.kubevirt.DeleteDataVolumes(vm)
+	if err != nil {
+		return
+	}
+	err = r.kubevirt.DeletePVCs(vm)
+	return
+}
+
+// Remove the source VM snapshots created by the migration
+// that have not already been removed.
+func (r *Migration) rollbackSnapshots(vm *plan.VMStatus) (err error) {
+	if vm.Warm == nil {
+		return
+	}
+	for i := range vm.Warm.Precopies {
+		precopy := &vm.Warm.Precopies[i]
+		if precopy.Snapshot == "" || precopy.RemoveTaskId != "" {
+			continue
+		}
+		var taskId string
+		taskId, err = r.provider.RemoveSnapshot(vm.Ref, precopy.Snapshot, r.kubevirt.loadHosts)
+		if err != nil {
+			return
+		}
+		precopy.RemoveTaskId = taskId
+	}
+	return
+}
+
+// Restore the recorded power state of the source VM.
+func (r *Migration) rollbackPowerState(vm *plan.VMStatus) (err error) {
+	if vm.RestorePowerState != plan.VMPowerStateOn {
+		return
+	}
+	off, err := r.provider.PoweredOff(vm.Ref)
+	if err != nil || !off {
+		return
+	}
+	err = r.provider.PowerOn(vm.Ref)
+	return
+}
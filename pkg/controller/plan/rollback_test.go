@@ -0,0 +1,154 @@
+package plan
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+// Source provider client that records the rollback calls.
+type rollbackClient struct {
+	adapter.Client
+	poweredOff bool
+	poweredOn  bool
+	removed    []string
+	removeErr  error
+}
+
+func (r *rollbackClient) PoweredOff(vmRef ref.Ref) (bool, error) {
+	return r.poweredOff, nil
+}
+
+func (r *rollbackClient) PowerOn(vmRef ref.Ref) error {
+	r.poweredOn = true
+	return nil
+}
+
+func (r *rollbackClient) RemoveSnapshot(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (string, error) {
+	if r.removeErr != nil {
+		return "", r.removeErr
+	}
+	r.removed = append(r.removed, snapshot)
+	return "task-" + snapshot, nil
+}
+
+var _ = ginkgo.Describe("Plan Rollback", func() {
+	var client *rollbackClient
+	newMigration := func(policy plan.RollbackPolicy) *Migration {
+		client = &rollbackClient{}
+		p := &api.Plan{}
+		p.Spec.Rollback = policy
+		return &Migration{
+			Context: &plancontext.Context{
+				Plan: p,
+				Log:  logging.WithName("test"),
+			},
+			provider: client,
+		}
+	}
+	newVM := func() *plan.VMStatus {
+		vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1", Name: "vm"}}}
+		vm.Pipeline = []*plan.Step{{Task: plan.Task{Name: "Initialize"}}}
+		vm.Phase = api.PhaseCopyDisks
+		return vm
+	}
+
+	ginkgo.Describe("rollback", func() {
+		ginkgo.It("should not roll back when disabled", func() {
+			for _, policy := range []plan.RollbackPolicy{"", plan.RollbackNone} {
+				runner := newMigration(policy)
+				vm := newVM()
+				gomega.Expect(runner.rollback(vm)).To(gomega.BeFalse())
+				gomega.Expect(vm.Pipeline).To(gomega.HaveLen(1))
+				gomega.Expect(vm.Phase).To(gomega.Equal(api.PhaseCopyDisks))
+			}
+		})
+
+		ginkgo.It("should append the rollback steps", func() {
+			runner := newMigration(plan.RollbackAutomatic)
+			vm := newVM()
+			gomega.Expect(runner.rollback(vm)).To(gomega.BeTrue())
+			gomega.Expect(vm.Phase).To(gomega.Equal(api.PhaseRollbackDeleteTarget))
+			gomega.Expect(vm.Pipeline).To(gomega.HaveLen(4))
+			for _, phase := range rollbackPhases {
+				step, found := vm.FindStep(phase)
+				gomega.Expect(found).To(gomega.BeTrue())
+				gomega.Expect(step.Phase).To(gomega.Equal(api.StepPending))
+			}
+			// Running.
+			gomega.Expect(runner.rollback(vm)).To(gomega.BeTrue())
+			gomega.Expect(vm.Pipeline).To(gomega.HaveLen(4))
+		})
+
+		ginkgo.It("should roll back only once", func() {
+			runner := newMigration(plan.RollbackAutomatic)
+			vm := newVM()
+			runner.rollback(vm)
+			for _, step := range vm.Pipeline {
+				step.MarkCompleted()
+			}
+			vm.Phase = api.PhaseCompleted
+			gomega.Expect(runner.rollback(vm)).To(gomega.BeFalse())
+			gomega.Expect(vm.Pipeline).To(gomega.HaveLen(4))
+		})
+	})
+
+	ginkgo.Describe("executeRollback", func() {
+		ginkgo.It("should remove the snapshots not already removed", func() {
+			runner := newMigration(plan.RollbackAutomatic)
+			vm := newVM()
+			vm.Warm = &plan.Warm{
+				Precopies: []plan.Precopy{
+					{Snapshot: "snap-1", RemoveTaskId: "task"},
+					{Snapshot: "snap-2"},
+				},
+			}
+			runner.rollback(vm)
+			vm.Phase = api.PhaseRollbackRemoveSnapshots
+			gomega.Expect(runner.executeRollback(vm)).To(gomega.Succeed())
+			gomega.Expect(client.removed).To(gomega.Equal([]string{"snap-2"}))
+			gomega.Expect(vm.Warm.Precopies[1].RemoveTaskId).To(gomega.Equal("task-snap-2"))
+			step, _ := vm.FindStep(api.PhaseRollbackRemoveSnapshots)
+			gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+			gomega.Expect(vm.Phase).To(gomega.Equal(api.PhaseRollbackRestorePowerState))
+		})
+
+		ginkgo.It("should record a failed step and proceed", func() {
+			runner := newMigration(plan.RollbackAutomatic)
+			client.removeErr = liberr.New("failed")
+			vm := newVM()
+			vm.Warm = &plan.Warm{Precopies: []plan.Precopy{{Snapshot: "snap-1"}}}
+			runner.rollback(vm)
+			vm.Phase = api.PhaseRollbackRemoveSnapshots
+			gomega.Expect(runner.executeRollback(vm)).To(gomega.Succeed())
+			step, _ := vm.FindStep(api.PhaseRollbackRemoveSnapshots)
+			gomega.Expect(step.HasError()).To(gomega.BeTrue())
+			gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+			gomega.Expect(vm.Phase).To(gomega.Equal(api.PhaseRollbackRestorePowerState))
+		})
+
+		ginkgo.DescribeTable("should restore the power state",
+			func(state plan.VMPowerState, poweredOff, poweredOn bool) {
+				runner := newMigration(plan.RollbackAutomatic)
+				client.poweredOff = poweredOff
+				vm := newVM()
+				vm.RestorePowerState = state
+				runner.rollback(vm)
+				vm.Phase = api.PhaseRollbackRestorePowerState
+				gomega.Expect(runner.executeRollback(vm)).To(gomega.Succeed())
+				gomega.Expect(client.poweredOn).To(gomega.Equal(poweredOn))
+				gomega.Expect(vm.Phase).To(gomega.Equal(api.PhaseCompleted))
+			},
+			ginkgo.Entry("when on and powered off", plan.VMPowerStateOn, true, true),
+			ginkgo.Entry("when on and still running", plan.VMPowerStateOn, false, false),
+			ginkgo.Entry("when off", plan.VMPowerStateOff, true, false),
+		)
+	})
+})
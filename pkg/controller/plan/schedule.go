@@ -0,0 +1,198 @@
+package plan
+
+import (
+	"context"
+	"path"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reconcile the plan schedule.
+//  1. When the (effective) start is reached, create the migration
+//     unless one is already active or pending, in which case it is adopted.
+//  2. Keep the cutover of the scheduled migration in sync with the spec.
+//  3. Report the next scheduled action.
+//
+// Returns: the duration until the next scheduled action.
+func (r *Reconciler) schedule(plan *api.Plan) (reQ time.Duration, err error) {
+	schedule := plan.Spec.Schedule
+	if schedule == nil {
+		plan.Status.Schedule = nil
+		return
+	}
+	status := plan.Status.Schedule
+	if status == nil {
+		status = &planapi.ScheduleStatus{}
+		plan.Status.Schedule = status
+	}
+	status.NextAction = ""
+	status.NextActionTime = nil
+	now := time.Now()
+	var cutover *meta.Time
+	if schedule.Cutover != nil && plan.IsWarm() {
+		var at time.Time
+		at, err = schedule.NextAllowed(schedule.Cutover.Time)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		cutover = &meta.Time{Time: at}
+	}
+	//
+	// Start.
+	if schedule.Start != nil && !schedule.Start.Equal(status.Started) {
+		var start time.Time
+		start, err = schedule.NextAllowed(schedule.Start.Time)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if now.Before(start) {
+			status.NextAction = planapi.ScheduleActionStart
+			status.NextActionTime = &meta.Time{Time: start}
+			reQ = start.Sub(now)
+			return
+		}
+		var migration *api.Migration
+		migration, err = r.scheduledMigration(plan, cutover)
+		if err != nil {
+			return
+		}
+		status.Started = schedule.Start.DeepCopy()
+		status.Migration = &core.ObjectReference{
+			Namespace: migration.Namespace,
+			Name:      migration.Name,
+			UID:       migration.UID,
+		}
+	}
+	//
+	// Cutover.
+	if cutover == nil || status.Migration == nil {
+		return
+	}
+	err = r.scheduleCutover(plan, cutover)
+	if err != nil {
+		return
+	}
+	if now.Before(cutover.Time) {
+		status.NextAction = planapi.ScheduleActionCutover
+		status.NextActionTime = cutover
+		reQ = cutover.Sub(now)
+	}
+
+	return
+}
+
+// Find the migration to be adopted by the schedule or
+// create a new one with the specified cutover.
+func (r *Reconciler) scheduledMigration(plan *api.Plan, cutover *meta.Time) (migration *api.Migration, err error) {
+	snapshot := plan.Status.Migration.ActiveSnapshot()
+	if snapshot.HasCondition(Executing) {
+		migration = &api.Migration{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: snapshot.Migration.Namespace,
+				Name:      snapshot.Migration.Name,
+				UID:       snapshot.Migration.UID,
+			},
+		}
+		r.Log.Info(
+			"Schedule: active migration adopted.",
+			"migration",
+			path.Join(
+				migration.Namespace,
+				migration.Name))
+		return
+	}
+	pending, err := r.pendingMigrations(plan)
+	if err != nil {
+		return
+	}
+	if len(pending) > 0 {
+		migration = pending[0]
+		r.Log.Info(
+			"Schedule: pending migration adopted.",
+			"migration",
+			path.Join(
+				migration.Namespace,
+				migration.Name))
+		return
+	}
+	migration = &api.Migration{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:    plan.Namespace,
+			GenerateName: plan.Name + "-",
+		},
+		Spec: api.MigrationSpec{
+			Plan: core.ObjectReference{
+				Namespace: plan.Namespace,
+				Name:      plan.Name,
+				UID:       plan.UID,
+			},
+			Cutover: cutover,
+		},
+	}
+	err = r.Create(context.TODO(), migration)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Schedule: migration created.",
+		"migration",
+		path.Join(
+			migration.Namespace,
+			migration.Name))
+
+	return
+}
+
+// Set the cutover on the scheduled migration.
+func (r *Reconciler) scheduleCutover(plan *api.Plan, cutover *meta.Time) (err error) {
+	ref := plan.Status.Schedule.Migration
+	migration := &api.Migration{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		migration)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if migration.UID != ref.UID || migration.Status.HasAnyCondition(Succeeded, Failed, Canceled) {
+		return
+	}
+	if migration.Spec.Cutover.Equal(cutover) {
+		return
+	}
+	original := migration.DeepCopy()
+	migration.Spec.Cutover = cutover
+	err = r.Patch(context.TODO(), migration, client.MergeFrom(original))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Schedule: migration cutover set.",
+		"migration",
+		path.Join(
+			migration.Namespace,
+			migration.Name),
+		"cutover",
+		cutover)
+
+	return
+}
@@ -0,0 +1,225 @@
+package plan
+
+import (
+	"context"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = ginkgo.Describe("Plan Schedule", func() {
+	at := func(value string) time.Time {
+		t, err := time.Parse(time.RFC3339, value)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		return t
+	}
+	metaAt := func(value string) *meta.Time {
+		return &meta.Time{Time: at(value)}
+	}
+
+	ginkgo.Describe("NextAllowed", func() {
+		ginkgo.It("should not move a time outside of blackouts", func() {
+			schedule := planapi.Schedule{
+				Blackouts: []planapi.Blackout{
+					{From: "08:00", To: "18:00"},
+				},
+			}
+			next, err := schedule.NextAllowed(at("2025-06-02T20:00:00Z"))
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(next).To(gomega.Equal(at("2025-06-02T20:00:00Z")))
+		})
+
+		ginkgo.It("should defer to the end of an absolute blackout", func() {
+			schedule := planapi.Schedule{
+				Blackouts: []planapi.Blackout{
+					{Start: metaAt("2025-06-02T00:00:00Z"), End: metaAt("2025-06-03T00:00:00Z")},
+				},
+			}
+			next, err := schedule.NextAllowed(at("2025-06-02T12:00:00Z"))
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(next).To(gomega.Equal(at("2025-06-03T00:00:00Z")))
+		})
+
+		ginkgo.It("should evaluate recurring blackouts in the timezone", func() {
+			schedule := planapi.Schedule{
+				Timezone: "Europe/Prague",
+				Blackouts: []planapi.Blackout{
+					{Days: []string{"Mon"}, From: "08:00", To: "18:00"},
+				},
+			}
+			// Monday 10:00 in Prague (UTC+2).
+			next, err := schedule.NextAllowed(at("2025-06-02T08:00:00Z"))
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(next.Equal(at("2025-06-02T16:00:00Z"))).To(gomega.BeTrue())
+			// Tuesday is not blacked out.
+			next, err = schedule.NextAllowed(at("2025-06-03T08:00:00Z"))
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(next.Equal(at("2025-06-03T08:00:00Z"))).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should handle windows spanning midnight", func() {
+			schedule := planapi.Schedule{
+				Blackouts: []planapi.Blackout{
+					{Days: []string{"Friday"}, From: "22:00", To: "02:00"},
+				},
+			}
+			// Saturday 01:00, window began Friday.
+			next, err := schedule.NextAllowed(at("2025-06-07T01:00:00Z"))
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(next).To(gomega.Equal(at("2025-06-07T02:00:00Z")))
+		})
+
+		ginkgo.It("should chain adjacent blackouts", func() {
+			schedule := planapi.Schedule{
+				Blackouts: []planapi.Blackout{
+					{From: "08:00", To: "12:00"},
+					{Start: metaAt("2025-06-02T12:00:00Z"), End: metaAt("2025-06-02T14:00:00Z")},
+				},
+			}
+			next, err := schedule.NextAllowed(at("2025-06-02T09:00:00Z"))
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(next).To(gomega.Equal(at("2025-06-02T14:00:00Z")))
+		})
+	})
+
+	ginkgo.Describe("validateSchedule", func() {
+		ginkgo.DescribeTable("should validate the schedule",
+			func(schedule *planapi.Schedule, valid bool) {
+				plan := &api.Plan{}
+				plan.Spec.Schedule = schedule
+				reconciler := createFakeReconciler()
+				gomega.Expect(reconciler.validateSchedule(plan)).To(gomega.Succeed())
+				gomega.Expect(plan.Status.HasCondition(ScheduleNotValid)).To(gomega.Equal(!valid))
+			},
+			ginkgo.Entry("when not set", nil, true),
+			ginkgo.Entry("when valid", &planapi.Schedule{
+				Start:    metaAt("2025-06-02T20:00:00Z"),
+				Cutover:  metaAt("2025-06-03T02:00:00Z"),
+				Timezone: "America/New_York",
+				Blackouts: []planapi.Blackout{
+					{Days: []string{"sat", "Sun"}, From: "00:00", To: "23:59"},
+				},
+			}, true),
+			ginkgo.Entry("when timezone is unknown", &planapi.Schedule{
+				Timezone: "Mars/Olympus",
+			}, false),
+			ginkgo.Entry("when cutover is before start", &planapi.Schedule{
+				Start:   metaAt("2025-06-03T02:00:00Z"),
+				Cutover: metaAt("2025-06-02T20:00:00Z"),
+			}, false),
+			ginkgo.Entry("when time of day is malformed", &planapi.Schedule{
+				Blackouts: []planapi.Blackout{{From: "8am", To: "18:00"}},
+			}, false),
+			ginkgo.Entry("when day is unknown", &planapi.Schedule{
+				Blackouts: []planapi.Blackout{{Days: []string{"Someday"}, From: "08:00", To: "18:00"}},
+			}, false),
+			ginkgo.Entry("when absolute and recurring are mixed", &planapi.Schedule{
+				Blackouts: []planapi.Blackout{
+					{Start: metaAt("2025-06-02T00:00:00Z"), End: metaAt("2025-06-03T00:00:00Z"), From: "08:00", To: "18:00"},
+				},
+			}, false),
+			ginkgo.Entry("when the whole week is blacked out", &planapi.Schedule{
+				Start: metaAt("2025-06-02T20:00:00Z"),
+				Blackouts: []planapi.Blackout{
+					{From: "00:00", To: "12:00"},
+					{From: "12:00", To: "00:00"},
+				},
+			}, false),
+			ginkgo.Entry("when end is before start", &planapi.Schedule{
+				Blackouts: []planapi.Blackout{
+					{Start: metaAt("2025-06-03T00:00:00Z"), End: metaAt("2025-06-02T00:00:00Z")},
+				},
+			}, false),
+		)
+	})
+
+	ginkgo.Describe("schedule", func() {
+		newPlan := func(schedule *planapi.Schedule) *api.Plan {
+			plan := &api.Plan{
+				ObjectMeta: meta.ObjectMeta{
+					Name:      testPlanName,
+					Namespace: testNamespace,
+				},
+			}
+			plan.Spec.Type = api.MigrationWarm
+			plan.Spec.Schedule = schedule
+			return plan
+		}
+		migrations := func(reconciler *Reconciler) []api.Migration {
+			list := &api.MigrationList{}
+			gomega.Expect(reconciler.List(context.TODO(), list)).To(gomega.Succeed())
+			return list.Items
+		}
+
+		ginkgo.It("should report the start when not reached", func() {
+			start := meta.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+			plan := newPlan(&planapi.Schedule{Start: &start})
+			reconciler := createFakeReconciler()
+			reQ, err := reconciler.schedule(plan)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(reQ).To(gomega.BeNumerically(">", 59*time.Minute))
+			gomega.Expect(plan.Status.Schedule.NextAction).To(gomega.Equal(planapi.ScheduleActionStart))
+			gomega.Expect(plan.Status.Schedule.NextActionTime.Equal(&start)).To(gomega.BeTrue())
+			gomega.Expect(migrations(reconciler)).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("should create the migration with the cutover", func() {
+			start := meta.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+			cutover := meta.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+			plan := newPlan(&planapi.Schedule{Start: &start, Cutover: &cutover})
+			reconciler := createFakeReconciler()
+			_, err := reconciler.schedule(plan)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			list := migrations(reconciler)
+			gomega.Expect(list).To(gomega.HaveLen(1))
+			gomega.Expect(list[0].Match(plan)).To(gomega.BeTrue())
+			gomega.Expect(list[0].Spec.Cutover.Equal(&cutover)).To(gomega.BeTrue())
+			status := plan.Status.Schedule
+			gomega.Expect(status.Started.Equal(&start)).To(gomega.BeTrue())
+			gomega.Expect(status.Migration.Name).To(gomega.Equal(list[0].Name))
+			gomega.Expect(status.NextAction).To(gomega.Equal(planapi.ScheduleActionCutover))
+			// Started only once.
+			_, err = reconciler.schedule(plan)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(migrations(reconciler)).To(gomega.HaveLen(1))
+		})
+
+		ginkgo.It("should update the cutover of the scheduled migration", func() {
+			start := meta.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+			cutover := meta.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+			plan := newPlan(&planapi.Schedule{Start: &start, Cutover: &cutover})
+			reconciler := createFakeReconciler()
+			_, err := reconciler.schedule(plan)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			later := meta.NewTime(cutover.Add(time.Hour))
+			plan.Spec.Schedule.Cutover = &later
+			_, err = reconciler.schedule(plan)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			list := migrations(reconciler)
+			gomega.Expect(list).To(gomega.HaveLen(1))
+			gomega.Expect(list[0].Spec.Cutover.Equal(&later)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should adopt a pending migration", func() {
+			start := meta.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+			plan := newPlan(&planapi.Schedule{Start: &start})
+			pending := &api.Migration{
+				ObjectMeta: meta.ObjectMeta{
+					Name:      "pending",
+					Namespace: testNamespace,
+				},
+			}
+			pending.Spec.Plan.Name = testPlanName
+			pending.Spec.Plan.Namespace = testNamespace
+			reconciler := createFakeReconciler(pending)
+			_, err := reconciler.schedule(plan)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(migrations(reconciler)).To(gomega.HaveLen(1))
+			gomega.Expect(plan.Status.Schedule.Migration.Name).To(gomega.Equal("pending"))
+		})
+	})
+})
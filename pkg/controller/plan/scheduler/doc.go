@@ -4,6 +4,7 @@ import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/limiter"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/ova"
@@ -27,32 +28,32 @@ func New(ctx *plancontext.Context) (scheduler Scheduler, err error) {
 	case api.VSphere:
 		scheduler = &vsphere.Scheduler{
 			Context:     ctx,
-			MaxInFlight: settings.Settings.MaxInFlight,
+			MaxInFlight: limiter.MaxInFlight(ctx, settings.Settings.MaxInFlight),
 		}
 	case api.OVirt:
 		scheduler = &ovirt.Scheduler{
 			Context:     ctx,
-			MaxInFlight: settings.Settings.MaxInFlight,
+			MaxInFlight: limiter.MaxInFlight(ctx, settings.Settings.MaxInFlight),
 		}
 	case api.OpenStack:
 		scheduler = &openstack.Scheduler{
 			Context:     ctx,
-			MaxInFlight: settings.Settings.MaxInFlight,
+			MaxInFlight: limiter.MaxInFlight(ctx, settings.Settings.MaxInFlight),
 		}
 	case api.OpenShift:
 		scheduler = &ocp.Scheduler{
 			Context:     ctx,
-			MaxInFlight: settings.Settings.MaxInFlight,
+			MaxInFlight: limiter.MaxInFlight(ctx, settings.Settings.MaxInFlight),
 		}
 	case api.Ova, api.HyperV:
 		scheduler = &ova.Scheduler{
 			Context:     ctx,
-			MaxInFlight: settings.Settings.MaxInFlight,
+			MaxInFlight: limiter.MaxInFlight(ctx, settings.Settings.MaxInFlight),
 		}
 	case api.EC2:
 		scheduler = &ec2scheduler.Scheduler{
 			Context:     ctx,
-			MaxInFlight: settings.Settings.MaxInFlight,
+			MaxInFlight: limiter.MaxInFlight(ctx, settings.Settings.MaxInFlight),
 		}
 	default:
 		err = liberr.New("provider not supported.")
@@ -0,0 +1,330 @@
+package limiter
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+)
+
+// Conditions.
+const (
+	Executing = "Executing"
+)
+
+// Steps.
+const (
+	DiskTransfer    = "DiskTransfer"
+	DiskTransferV2v = "DiskTransferV2v"
+)
+
+// Resources in flight.
+type usage struct {
+	vms   int
+	disks int
+	bytes int64
+}
+
+// Add the usage.
+func (r *usage) add(u usage) {
+	r.vms += u.vms
+	r.disks += u.disks
+	r.bytes += u.bytes
+}
+
+// Determine whether anything is in flight.
+func (r *usage) empty() bool {
+	return r.vms == 0 && r.disks == 0 && r.bytes == 0
+}
+
+// Determine whether adding the usage exceeds the limit.
+// The limit is never exceeded when nothing is in flight so
+// that a VM larger than the limit is migrated on its own.
+func (r *usage) exceeds(limit *provider.Limit, u usage) bool {
+	if r.empty() {
+		return false
+	}
+	return limit.Exceeded(r.vms+u.vms, r.disks+u.disks, r.bytes+u.bytes)
+}
+
+// Limiter enforces the concurrency limits set on the
+// source and destination providers across all plans.
+type Limiter struct {
+	*plancontext.Context
+	// Source provider limits.
+	source *provider.Limits
+	// Destination provider limits.
+	destination *provider.Limits
+	// Source provider aggregate transfer rate limit in MB/s.
+	rate int
+	// Transfer rate in flight on the source provider.
+	inFlightRate int
+	// In flight on the source provider.
+	provider usage
+	// In flight by index of the source storage limit.
+	storage map[int]*usage
+	// In flight by index of the destination storage class limit.
+	storageClass map[int]*usage
+}
+
+// New limiter.
+// Builds the resources in flight for the plans sharing
+// the source or destination provider with the context plan.
+func New(ctx *plancontext.Context, plans []api.Plan) (limiter *Limiter) {
+	limiter = &Limiter{
+		Context:      ctx,
+		storage:      make(map[int]*usage),
+		storageClass: make(map[int]*usage),
+	}
+	if ctx.Source.Provider != nil {
+		limiter.source = ctx.Source.Provider.Spec.Limits
+		limiter.rate = ctx.Source.Provider.TransferRateLimit()
+	}
+	if ctx.Destination.Provider != nil {
+		limiter.destination = ctx.Destination.Provider.Spec.Limits
+	}
+	if !limiter.Enabled() {
+		return
+	}
+	// Since the plan VMStatuses are modified in memory,
+	// the plan from the context is used rather than
+	// from the list.
+	limiter.addPlan(ctx.Plan)
+	for i := range plans {
+		p := &plans[i]
+		if p.Name == ctx.Plan.Name && p.Namespace == ctx.Plan.Namespace {
+			continue
+		}
+		if p.Spec.Archived {
+			continue
+		}
+		snapshot := p.Status.Migration.ActiveSnapshot()
+		if !snapshot.HasCondition(Executing) {
+			continue
+		}
+		limiter.addPlan(p)
+	}
+
+	return
+}
+
+// Enabled determines whether limits are set on
+// either the source or the destination provider.
+func (r *Limiter) Enabled() bool {
+	return r.source != nil || r.destination != nil || r.rate > 0
+}
+
+// MaxInFlight returns the provider override of
+// the specified (global) MaxInFlight.
+func MaxInFlight(ctx *plancontext.Context, maxInFlight int) int {
+	if ctx.Source.Provider != nil {
+		limits := ctx.Source.Provider.Spec.Limits
+		if limits != nil && limits.MaxInFlight > 0 {
+			return limits.MaxInFlight
+		}
+	}
+	return maxInFlight
+}
+
+// Admit determines whether the migration of a VM with
+// the specified footprint can be started within the limits.
+func (r *Limiter) Admit(footprint *plan.Footprint) (admitted bool) {
+	u := usage{vms: 1}
+	if footprint != nil {
+		u.disks = len(footprint.Disks)
+		u.bytes = footprint.Bytes()
+	}
+	if r.rate > 0 && footprint != nil && r.inFlightRate > 0 {
+		if r.inFlightRate+footprint.Rate > r.rate {
+			return
+		}
+	}
+	if r.source != nil {
+		if r.provider.exceeds(&r.source.Limit, u) {
+			return
+		}
+		for index, storage := range r.byStorage(footprint) {
+			inFlight := r.storage[index]
+			if inFlight == nil {
+				inFlight = &usage{}
+			}
+			if inFlight.exceeds(&r.source.Storage[index].Limit, storage) {
+				return
+			}
+		}
+	}
+	if r.destination != nil {
+		for index, class := range r.byStorageClass(footprint) {
+			inFlight := r.storageClass[index]
+			if inFlight == nil {
+				inFlight = &usage{}
+			}
+			if inFlight.exceeds(&r.destination.StorageClasses[index].Limit, class) {
+				return
+			}
+		}
+	}
+	admitted = true
+	return
+}
+
+// Add the resources held by the plan VMs.
+func (r *Limiter) addPlan(p *api.Plan) {
+	sameSource := p.Spec.Provider.Source == r.Plan.Spec.Provider.Source
+	sameDestination := p.Spec.Provider.Destination == r.Plan.Spec.Provider.Destination
+	for _, vm := range p.Status.Migration.VMs {
+		if !vm.Running() {
+			continue
+		}
+		footprint := held(vm)
+		if sameSource && r.rate > 0 && footprint != nil {
+			r.inFlightRate += footprint.Rate
+		}
+		if sameSource && r.source != nil {
+			u := usage{vms: 1}
+			if footprint != nil {
+				u.disks = len(footprint.Disks)
+				u.bytes = footprint.Bytes()
+			}
+			r.provider.add(u)
+			for index, storage := range r.byStorage(footprint) {
+				r.add(r.storage, index, storage)
+			}
+		}
+		if sameDestination && r.destination != nil {
+			for index, class := range r.byStorageClass(footprint) {
+				r.add(r.storageClass, index, class)
+			}
+		}
+	}
+}
+
+// Add usage to the indexed map.
+func (r *Limiter) add(m map[int]*usage, index int, u usage) {
+	inFlight, found := m[index]
+	if !found {
+		inFlight = &usage{}
+		m[index] = inFlight
+	}
+	inFlight.add(u)
+}
+
+// Usage of the footprint by index of the matching source storage limit.
+func (r *Limiter) byStorage(footprint *plan.Footprint) (m map[int]usage) {
+	m = make(map[int]usage)
+	if footprint == nil || r.source == nil {
+		return
+	}
+	seen := make(map[int]bool)
+	for _, disk := range footprint.Disks {
+		index, found := r.storageIndex(disk.Storage)
+		if !found {
+			continue
+		}
+		u := m[index]
+		if !seen[index] {
+			u.vms++
+			seen[index] = true
+		}
+		u.disks++
+		u.bytes += disk.Capacity
+		m[index] = u
+	}
+	return
+}
+
+// Usage of the footprint by index of the matching storage class limit.
+func (r *Limiter) byStorageClass(footprint *plan.Footprint) (m map[int]usage) {
+	m = make(map[int]usage)
+	if footprint == nil || r.destination == nil {
+		return
+	}
+	seen := make(map[int]bool)
+	for _, disk := range footprint.Disks {
+		index, found := r.storageClassIndex(disk.StorageClass)
+		if !found {
+			continue
+		}
+		u := m[index]
+		if !seen[index] {
+			u.vms++
+			seen[index] = true
+		}
+		u.disks++
+		u.bytes += disk.Capacity
+		m[index] = u
+	}
+	return
+}
+
+// Index of the source storage limit.
+func (r *Limiter) storageIndex(storage ref.Ref) (index int, found bool) {
+	for i := range r.source.Storage {
+		l := &r.source.Storage[i]
+		if (l.ID != "" && l.ID == storage.ID) || (l.Name != "" && l.Name == storage.Name) {
+			index = i
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// Index of the destination storage class limit.
+func (r *Limiter) storageClassIndex(name string) (index int, found bool) {
+	if name == "" {
+		return
+	}
+	for i := range r.destination.StorageClasses {
+		if r.destination.StorageClasses[i].Name == name {
+			index = i
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// Footprint still held by a running VM.
+// Disks are released as their transfer completes and
+// all disks and the transfer rate are released once
+// the disk transfer is done.
+func held(vm *plan.VMStatus) (footprint *plan.Footprint) {
+	if vm.Footprint == nil {
+		return
+	}
+	footprint = &plan.Footprint{}
+	completed := 0
+	for _, step := range vm.Pipeline {
+		if step.Name != DiskTransfer && step.Name != DiskTransferV2v {
+			continue
+		}
+		if step.MarkedCompleted() {
+			return
+		}
+		for _, task := range step.Tasks {
+			if task.MarkedCompleted() {
+				completed++
+			}
+		}
+	}
+	footprint.Rate = vm.Footprint.Rate
+	disks := vm.Footprint.Disks
+	if completed < len(disks) {
+		footprint.Disks = disks[completed:]
+	}
+	return
+}
+
+// StorageClass mapped to the source storage by the plan.
+func StorageClass(ctx *plancontext.Context, storage ref.Ref) (name string) {
+	storageMap := ctx.Map.Storage
+	if storageMap == nil {
+		return
+	}
+	if pair, found := storageMap.FindStorageByRef(storage); found {
+		name = pair.Destination.StorageClass
+	}
+	return
+}
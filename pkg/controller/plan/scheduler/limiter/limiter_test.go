@@ -0,0 +1,179 @@
+package limiter
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func context(source, destination *provider.Limits) *plancontext.Context {
+	ctx := &plancontext.Context{
+		Plan: &api.Plan{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "plan"},
+		},
+	}
+	ctx.Plan.Spec.Provider.Source = core.ObjectReference{Namespace: "test", Name: "source"}
+	ctx.Plan.Spec.Provider.Destination = core.ObjectReference{Namespace: "test", Name: "destination"}
+	ctx.Source.Provider = &api.Provider{}
+	ctx.Source.Provider.Spec.Limits = source
+	ctx.Destination.Provider = &api.Provider{}
+	ctx.Destination.Provider.Spec.Limits = destination
+	return ctx
+}
+
+func disk(storage, class string, capacity int64) plan.DiskFootprint {
+	return plan.DiskFootprint{
+		Storage:      ref.Ref{Name: storage},
+		StorageClass: class,
+		Capacity:     capacity,
+	}
+}
+
+func running(disks ...plan.DiskFootprint) *plan.VMStatus {
+	vm := &plan.VMStatus{}
+	vm.MarkStarted()
+	vm.Footprint = &plan.Footprint{Disks: disks}
+	return vm
+}
+
+func TestDisabled(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx := context(nil, nil)
+	ctx.Plan.Status.Migration.VMs = []*plan.VMStatus{running(), running()}
+	limiter := New(ctx, nil)
+	g.Expect(limiter.Enabled()).To(gomega.BeFalse())
+	g.Expect(limiter.Admit(nil)).To(gomega.BeTrue())
+	g.Expect(MaxInFlight(ctx, 20)).To(gomega.Equal(20))
+}
+
+func TestMaxInFlight(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx := context(&provider.Limits{MaxInFlight: 3}, nil)
+	g.Expect(MaxInFlight(ctx, 20)).To(gomega.Equal(3))
+}
+
+func TestProviderLimit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	maxBytes := resource.MustParse("100")
+	ctx := context(
+		&provider.Limits{
+			Limit: provider.Limit{MaxVMs: 2, MaxBytes: &maxBytes},
+		},
+		nil)
+	ctx.Plan.Status.Migration.VMs = []*plan.VMStatus{
+		running(disk("ds1", "", 60)),
+		{},
+	}
+	limiter := New(ctx, nil)
+	g.Expect(limiter.Admit(&plan.Footprint{Disks: []plan.DiskFootprint{disk("ds1", "", 40)}})).To(gomega.BeTrue())
+	g.Expect(limiter.Admit(&plan.Footprint{Disks: []plan.DiskFootprint{disk("ds1", "", 41)}})).To(gomega.BeFalse())
+
+	// The VM limit counts the other executing plans.
+	other := api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "other"}}
+	other.Spec.Provider = ctx.Plan.Spec.Provider
+	other.Status.Migration.History = []plan.Snapshot{{}}
+	other.Status.Migration.History[0].SetCondition(libcnd.Condition{Type: Executing, Status: libcnd.True})
+	other.Status.Migration.VMs = []*plan.VMStatus{running()}
+	limiter = New(ctx, []api.Plan{other})
+	g.Expect(limiter.Admit(&plan.Footprint{})).To(gomega.BeFalse())
+}
+
+func TestStorageLimit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx := context(
+		&provider.Limits{
+			Storage: []provider.StorageLimit{
+				{Ref: ref.Ref{Name: "ds1"}, Limit: provider.Limit{MaxDisks: 2}},
+			},
+		},
+		nil)
+	ctx.Plan.Status.Migration.VMs = []*plan.VMStatus{
+		running(disk("ds1", "", 1), disk("ds2", "", 1)),
+	}
+	limiter := New(ctx, nil)
+	g.Expect(limiter.Admit(&plan.Footprint{Disks: []plan.DiskFootprint{disk("ds1", "", 1)}})).To(gomega.BeTrue())
+	g.Expect(limiter.Admit(&plan.Footprint{Disks: []plan.DiskFootprint{disk("ds1", "", 1), disk("ds1", "", 1)}})).To(gomega.BeFalse())
+	g.Expect(limiter.Admit(&plan.Footprint{Disks: []plan.DiskFootprint{disk("ds2", "", 1), disk("ds2", "", 1)}})).To(gomega.BeTrue())
+}
+
+func TestStorageClassLimit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx := context(
+		nil,
+		&provider.Limits{
+			StorageClasses: []provider.StorageClassLimit{
+				{Name: "fast", Limit: provider.Limit{MaxVMs: 1}},
+			},
+		})
+	ctx.Plan.Status.Migration.VMs = []*plan.VMStatus{
+		running(disk("ds1", "fast", 1)),
+	}
+	limiter := New(ctx, nil)
+	g.Expect(limiter.Admit(&plan.Footprint{Disks: []plan.DiskFootprint{disk("ds1", "fast", 1)}})).To(gomega.BeFalse())
+	g.Expect(limiter.Admit(&plan.Footprint{Disks: []plan.DiskFootprint{disk("ds1", "slow", 1)}})).To(gomega.BeTrue())
+}
+
+func TestOversized(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx := context(
+		&provider.Limits{
+			Limit: provider.Limit{MaxDisks: 1},
+		},
+		nil)
+	limiter := New(ctx, nil)
+	// Nothing in flight.
+	g.Expect(limiter.Admit(&plan.Footprint{Disks: []plan.DiskFootprint{disk("ds1", "", 1), disk("ds1", "", 1)}})).To(gomega.BeTrue())
+}
+
+func TestTransferRateLimit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx := context(nil, nil)
+	ctx.Source.Provider.Spec.Settings = map[string]string{api.TransferRateLimit: "100"}
+	vm := running(disk("ds1", "", 1))
+	vm.Footprint.Rate = 60
+	ctx.Plan.Status.Migration.VMs = []*plan.VMStatus{vm}
+	limiter := New(ctx, nil)
+	g.Expect(limiter.Enabled()).To(gomega.BeTrue())
+	g.Expect(limiter.Admit(&plan.Footprint{Rate: 40})).To(gomega.BeTrue())
+	g.Expect(limiter.Admit(&plan.Footprint{Rate: 41})).To(gomega.BeFalse())
+
+	// The rate is released once the disk transfer is done.
+	step := &plan.Step{Task: plan.Task{Name: DiskTransfer}}
+	step.MarkCompleted()
+	vm.Pipeline = []*plan.Step{step}
+	limiter = New(ctx, nil)
+	g.Expect(limiter.Admit(&plan.Footprint{Rate: 100})).To(gomega.BeTrue())
+}
+
+func TestHeld(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := running(disk("ds1", "", 10), disk("ds1", "", 20))
+	g.Expect(held(vm).Disks).To(gomega.HaveLen(2))
+
+	step := &plan.Step{Task: plan.Task{Name: DiskTransfer}}
+	step.Tasks = []*plan.Task{{}, {}}
+	step.Tasks[0].MarkCompleted()
+	vm.Pipeline = []*plan.Step{step}
+	g.Expect(held(vm).Disks).To(gomega.HaveLen(1))
+	g.Expect(held(vm).Bytes()).To(gomega.Equal(int64(20)))
+
+	step.MarkCompleted()
+	g.Expect(held(vm).Disks).To(gomega.BeEmpty())
+}
@@ -7,6 +7,7 @@ import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/limiter"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 )
 
@@ -41,12 +42,28 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		return
 	}
 
+	limits := limiter.New(r.Context, planList.Items)
 	for _, vmStatus := range r.Plan.Status.Migration.VMs {
 		if vmStatus.HasCondition(Canceled) {
 			continue
 		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
+			if !r.Plan.Status.Migration.Released(vmStatus) {
+				continue
+			}
+			var footprint *plan.Footprint
+			if limits.Enabled() {
+				footprint, err = r.footprint(vmStatus)
+				if err != nil {
+					vm = vmStatus
+					return
+				}
+				if !limits.Admit(footprint) {
+					continue
+				}
+			}
 			vm = vmStatus
+			vm.Footprint = footprint
 			hasNext = true
 			return
 		}
@@ -77,3 +94,11 @@ func (r *Scheduler) calcInFlight(planList *api.PlanList) int {
 	}
 	return inFlight
 }
+
+// Storage used by the VM.
+// The storage of the source VM is not tracked so
+// only the VM counts against the limits.
+func (r *Scheduler) footprint(vmStatus *plan.VMStatus) (footprint *plan.Footprint, err error) {
+	footprint = &plan.Footprint{Rate: r.Plan.TransferRateLimit()}
+	return
+}
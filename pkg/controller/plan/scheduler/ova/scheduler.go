@@ -6,7 +6,10 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/limiter"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 )
 
@@ -61,12 +64,28 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		return
 	}
 
+	limits := limiter.New(r.Context, planList.Items)
 	for _, vmStatus := range r.Plan.Status.Migration.VMs {
 		if vmStatus.HasCondition(Canceled) {
 			continue
 		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
+			if !r.Plan.Status.Migration.Released(vmStatus) {
+				continue
+			}
+			var footprint *plan.Footprint
+			if limits.Enabled() {
+				footprint, err = r.footprint(vmStatus)
+				if err != nil {
+					vm = vmStatus
+					return
+				}
+				if !limits.Admit(footprint) {
+					continue
+				}
+			}
 			vm = vmStatus
+			vm.Footprint = footprint
 			hasNext = true
 			return
 		}
@@ -74,3 +93,26 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 
 	return
 }
+
+// Storage used by the VM disks.
+// The storage is the disk.
+func (r *Scheduler) footprint(vmStatus *plan.VMStatus) (footprint *plan.Footprint, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmStatus.Ref)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmStatus.Ref.String())
+		return
+	}
+	footprint = &plan.Footprint{Rate: r.Plan.TransferRateLimit()}
+	for _, disk := range vm.Disks {
+		storage := ref.Ref{ID: disk.ID, Name: disk.Name}
+		footprint.Disks = append(
+			footprint.Disks,
+			plan.DiskFootprint{
+				Storage:      storage,
+				StorageClass: limiter.StorageClass(r.Context, storage),
+				Capacity:     disk.Capacity,
+			})
+	}
+	return
+}
@@ -6,7 +6,10 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/limiter"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 )
 
@@ -41,12 +44,28 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		return
 	}
 
+	limits := limiter.New(r.Context, planList.Items)
 	for _, vmStatus := range r.Plan.Status.Migration.VMs {
 		if vmStatus.HasCondition(Canceled) {
 			continue
 		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
+			if !r.Plan.Status.Migration.Released(vmStatus) {
+				continue
+			}
+			var footprint *plan.Footprint
+			if limits.Enabled() {
+				footprint, err = r.footprint(vmStatus)
+				if err != nil {
+					vm = vmStatus
+					return
+				}
+				if !limits.Admit(footprint) {
+					continue
+				}
+			}
 			vm = vmStatus
+			vm.Footprint = footprint
 			hasNext = true
 			return
 		}
@@ -77,3 +96,40 @@ func (r *Scheduler) calcInFlight(planList *api.PlanList) int {
 	}
 	return inFlight
 }
+
+// Storage used by the VM disks.
+// LUNs are not transferred and are omitted.
+func (r *Scheduler) footprint(vmStatus *plan.VMStatus) (footprint *plan.Footprint, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmStatus.Ref)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmStatus.Ref.String())
+		return
+	}
+	footprint = &plan.Footprint{Rate: r.Plan.TransferRateLimit()}
+	domains := make(map[string]*model.StorageDomain)
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.StorageType == "lun" {
+			continue
+		}
+		sd, found := domains[da.Disk.StorageDomain]
+		if !found {
+			sd = &model.StorageDomain{}
+			err = r.Source.Inventory.Find(sd, ref.Ref{ID: da.Disk.StorageDomain})
+			if err != nil {
+				err = liberr.Wrap(err, "storageDomain", da.Disk.StorageDomain)
+				return
+			}
+			domains[da.Disk.StorageDomain] = sd
+		}
+		storage := ref.Ref{ID: sd.ID, Name: sd.Name}
+		footprint.Disks = append(
+			footprint.Disks,
+			plan.DiskFootprint{
+				Storage:      storage,
+				StorageClass: limiter.StorageClass(r.Context, storage),
+				Capacity:     da.Disk.ProvisionedSize,
+			})
+	}
+	return
+}
@@ -7,7 +7,9 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/limiter"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
@@ -17,6 +19,7 @@ import (
 const (
 	CopyingPaused            = "CopyingPaused"
 	CreateGuestConversionPod = "CreateGuestConversionPod"
+	PreConversionHook        = "PreConversionHook"
 	ConvertGuest             = "ConvertGuest"
 	CreateVM                 = "CreateVM"
 	PostHook                 = "PostHook"
@@ -48,14 +51,17 @@ type Scheduler struct {
 	// Mapping of hosts by ID to lists of VMs
 	// that are waiting to be migrated.
 	pending map[string][]*pendingVM
+	// Enforces the provider concurrency limits.
+	limiter *limiter.Limiter
 }
 
 // Convenience struct to package a
 // VMStatus with a cost that is calculated
 // from the inventory VM object.
 type pendingVM struct {
-	status *plan.VMStatus
-	cost   int
+	status    *plan.VMStatus
+	cost      int
+	footprint *plan.Footprint
 }
 
 // Return the next VM to migrate.
@@ -69,6 +75,7 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 	for _, vms := range r.schedulable() {
 		if len(vms) > 0 {
 			vm = vms[0].status
+			vm.Footprint = vms[0].footprint
 			hasNext = true
 		}
 	}
@@ -135,6 +142,7 @@ func (r *Scheduler) buildInFlight() (err error) {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	r.limiter = limiter.New(r.Context, planList.Items)
 	for _, p := range planList.Items {
 		// skip this plan, it's already done.
 		if p.Name == r.Plan.Name && p.Namespace == r.Plan.Namespace {
@@ -182,6 +190,7 @@ func (r *Scheduler) buildInFlight() (err error) {
 // Build the map of pending VMs belonging to each host.
 func (r *Scheduler) buildPending() (err error) {
 	r.pending = make(map[string][]*pendingVM)
+	datastores := make(map[string]*model.Datastore)
 
 	for _, vmStatus := range r.Plan.Status.Migration.VMs {
 		if vmStatus.HasCondition(Canceled) {
@@ -194,16 +203,52 @@ func (r *Scheduler) buildPending() (err error) {
 		}
 
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
+			if !r.Plan.Status.Migration.Released(vmStatus) {
+				continue
+			}
 			pending := &pendingVM{
 				status: vmStatus,
 				cost:   r.cost(vm, vmStatus),
 			}
+			if r.limiter.Enabled() {
+				pending.footprint, err = r.footprint(vm, datastores)
+				if err != nil {
+					return
+				}
+			}
 			r.pending[vm.Host] = append(r.pending[vm.Host], pending)
 		}
 	}
 	return
 }
 
+// Footprint of the VM migration.
+// Datastores are cached by ID.
+func (r *Scheduler) footprint(vm *model.VM, datastores map[string]*model.Datastore) (footprint *plan.Footprint, err error) {
+	footprint = &plan.Footprint{Rate: r.Plan.TransferRateLimit()}
+	for _, disk := range vm.Disks {
+		ds, found := datastores[disk.Datastore.ID]
+		if !found {
+			ds = &model.Datastore{}
+			err = r.Source.Inventory.Find(ds, ref.Ref{ID: disk.Datastore.ID})
+			if err != nil {
+				err = liberr.Wrap(err, "datastore", disk.Datastore.ID)
+				return
+			}
+			datastores[disk.Datastore.ID] = ds
+		}
+		storage := ref.Ref{ID: ds.ID, Name: ds.Name}
+		footprint.Disks = append(
+			footprint.Disks,
+			plan.DiskFootprint{
+				Storage:      storage,
+				StorageClass: limiter.StorageClass(r.Context, storage),
+				Capacity:     disk.Capacity,
+			})
+	}
+	return
+}
+
 func (r *Scheduler) cost(vm *model.VM, vmStatus *plan.VMStatus) int {
 	useV2vForTransfer, _ := r.Plan.ShouldUseV2vForTransfer()
 	if useV2vForTransfer {
@@ -217,7 +262,7 @@ func (r *Scheduler) cost(vm *model.VM, vmStatus *plan.VMStatus) int {
 		}
 	} else {
 		switch vmStatus.Phase {
-		case CreateVM, PostHook, Completed, CopyingPaused, ConvertGuest, CreateGuestConversionPod:
+		case CreateVM, PostHook, Completed, CopyingPaused, ConvertGuest, CreateGuestConversionPod, PreConversionHook:
 			// The warm/remote migrations this is done on already transferred disks,
 			// and we can start other VM migrations at these point.
 			// By setting the cost to 0 other VMs can start migrating
@@ -254,6 +299,9 @@ func (r *Scheduler) schedulable() (schedulable map[string][]*pendingVM) {
 			continue
 		}
 		for i := range vms {
+			if !r.admit(vms[i]) {
+				continue
+			}
 			if vms[i].cost+r.inFlight[host] <= r.MaxInFlight {
 				schedulable[host] = append(schedulable[host], vms[i])
 			}
@@ -267,3 +315,12 @@ func (r *Scheduler) schedulable() (schedulable map[string][]*pendingVM) {
 
 	return
 }
+
+// Determine whether the VM migration can be
+// started within the provider limits.
+func (r *Scheduler) admit(vm *pendingVM) bool {
+	if r.limiter == nil || !r.limiter.Enabled() {
+		return true
+	}
+	return r.limiter.Admit(vm.footprint)
+}
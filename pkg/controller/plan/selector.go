@@ -0,0 +1,357 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/hyperv"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Types
+const (
+	VMSelectorNotValid = "VMSelectorNotValid"
+	VMSelectorEmpty    = "VMSelectorEmpty"
+)
+
+// VM selector not valid.
+type SelectorNotValidError struct {
+	Reason string
+}
+
+func (e SelectorNotValidError) Error() string {
+	return e.Reason
+}
+
+// Resolve the VM selector.
+// The VMs selected in the source inventory are recorded in the
+// status and the frozen selection is not resolved again. The
+// selected VMs not listed explicitly are added to the plan VMs
+// (not persisted).
+func (r *Reconciler) resolveSelector(plan *api.Plan) (err error) {
+	selector := plan.Spec.VMSelector
+	if selector == nil || plan.Referenced.Provider.Source == nil {
+		plan.Status.Selection = nil
+		return
+	}
+	selection := plan.Status.Selection
+	if selection == nil || selection.Frozen == nil {
+		inventory, nErr := web.NewClient(plan.Referenced.Provider.Source)
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		resolver := SelectorResolver{
+			Provider:  plan.Referenced.Provider.Source,
+			Inventory: inventory,
+		}
+		var vms []ref.Ref
+		vms, err = resolver.Resolve(selector)
+		if err != nil {
+			if errors.As(err, &SelectorNotValidError{}) {
+				plan.Status.SetCondition(libcnd.Condition{
+					Type:     VMSelectorNotValid,
+					Status:   True,
+					Reason:   NotValid,
+					Category: api.CategoryCritical,
+					Message:  "VM selector not valid: " + err.Error(),
+				})
+				err = nil
+			}
+			return
+		}
+		selection = &planapi.Selection{VMs: vms}
+		plan.Status.Selection = selection
+	}
+	if len(selection.VMs) == 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     VMSelectorEmpty,
+			Status:   True,
+			Reason:   NotFound,
+			Category: api.CategoryWarn,
+			Message:  "The VM selector did not select any VM.",
+		})
+	}
+	plan.Spec.VMs = addSelected(plan.Spec.VMs, selection.VMs)
+	return
+}
+
+// Freeze the VM selection when a migration is started.
+func (r *Reconciler) freezeSelection(plan *api.Plan) {
+	selection := plan.Status.Selection
+	if selection == nil || selection.Frozen != nil {
+		return
+	}
+	selection.Freeze()
+	r.Log.Info(
+		"VM selection frozen.",
+		"vms",
+		len(selection.VMs))
+}
+
+// Add the selected VMs not listed explicitly.
+// The listed VMs are matched by ID when specified,
+// otherwise by name.
+func addSelected(listed []planapi.VM, selected []ref.Ref) (vms []planapi.VM) {
+	vms = listed
+next:
+	for _, vmRef := range selected {
+		for i := range listed {
+			vm := &listed[i]
+			if vm.ID != "" && vm.ID == vmRef.ID {
+				continue next
+			}
+			if vm.ID == "" && vm.Namespace == vmRef.Namespace && vm.Name == vmRef.Name {
+				continue next
+			}
+		}
+		vms = append(vms, planapi.VM{Ref: vmRef})
+	}
+	return
+}
+
+// Resolves the VM selector using the source inventory.
+type SelectorResolver struct {
+	// Source provider.
+	Provider *api.Provider
+	// Inventory client.
+	Inventory web.Client
+}
+
+// Resolve the selected VMs.
+// Sorted by name.
+func (r *SelectorResolver) Resolve(selector *planapi.VMSelector) (vms []ref.Ref, err error) {
+	err = r.Validate(selector)
+	if err != nil {
+		return
+	}
+	var clusters []string
+	if selector.Cluster != "" {
+		clusters, err = r.clusters(selector.Cluster)
+		if err != nil || len(clusters) == 0 {
+			return
+		}
+	}
+	vms, err = r.list(r.Filter(selector, clusters))
+	if err != nil {
+		return
+	}
+	sort.Slice(
+		vms,
+		func(i, j int) bool {
+			return vms[i].Namespace+"/"+vms[i].Name < vms[j].Namespace+"/"+vms[j].Name
+		})
+	return
+}
+
+// Validate the selector.
+// The criteria must be supported by the provider and
+// the regular expressions must compile. The labels can
+// only be matched by the inventory DB, the OpenShift VMs
+// are not listed from it.
+func (r *SelectorResolver) Validate(selector *planapi.VMSelector) (err error) {
+	providerType := r.Provider.Type()
+	notSupported := func(field string) error {
+		return liberr.Wrap(
+			SelectorNotValidError{
+				Reason: fmt.Sprintf("%s not supported by provider type: %s.", field, providerType),
+			})
+	}
+	switch providerType {
+	case api.VSphere:
+	case api.OVirt:
+		if selector.Folder != "" {
+			return notSupported("folder")
+		}
+	case api.OpenShift:
+		if selector.Folder != "" {
+			return notSupported("folder")
+		}
+		if selector.Cluster != "" {
+			return notSupported("cluster")
+		}
+		if selector.GuestOS != "" {
+			return notSupported("guestOS")
+		}
+		if len(selector.Labels) > 0 {
+			return notSupported("labels")
+		}
+	case api.OpenStack, api.Ova, api.HyperV:
+		if selector.Folder != "" {
+			return notSupported("folder")
+		}
+		if selector.Cluster != "" {
+			return notSupported("cluster")
+		}
+		if selector.GuestOS != "" {
+			return notSupported("guestOS")
+		}
+	default:
+		return notSupported("VM selector")
+	}
+	if _, pErr := regexp.Compile(selector.Name); pErr != nil {
+		return liberr.Wrap(SelectorNotValidError{Reason: "name: " + pErr.Error()})
+	}
+	if _, pErr := regexp.Compile(selector.GuestOS); pErr != nil {
+		return liberr.Wrap(SelectorNotValidError{Reason: "guestOS: " + pErr.Error()})
+	}
+	return
+}
+
+// Build the inventory (query) filter.
+// The cluster is matched by the IDs of the hosts (vSphere)
+// or the clusters (oVirt).
+func (r *SelectorResolver) Filter(selector *planapi.VMSelector, clusters []string) (filter string) {
+	terms := []string{}
+	if selector.Name != "" {
+		terms = append(terms, "name ~= "+quote(selector.Name))
+	}
+	if selector.Folder != "" {
+		folder := strings.TrimSuffix(selector.Folder, "/")
+		terms = append(terms, "path ~= "+quote("^"+regexp.QuoteMeta(folder)+"/"))
+	}
+	if len(clusters) > 0 {
+		quoted := []string{}
+		for _, id := range clusters {
+			quoted = append(quoted, quote(id))
+		}
+		field := "cluster"
+		if r.Provider.Type() == api.VSphere {
+			field = "host"
+		}
+		terms = append(terms, field+" in ("+strings.Join(quoted, ",")+")")
+	}
+	if selector.GuestOS != "" {
+		pattern := quote(selector.GuestOS)
+		switch r.Provider.Type() {
+		case api.VSphere:
+			terms = append(terms, "(guestId ~= "+pattern+" or guestName ~= "+pattern+")")
+		case api.OVirt:
+			terms = append(terms, "(osType ~= "+pattern+" or guest.distribution ~= "+pattern+")")
+		}
+	}
+	keys := []string{}
+	for k := range selector.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		terms = append(terms, "label("+quote(k)+") = "+quote(selector.Labels[k]))
+	}
+	filter = strings.Join(terms, " and ")
+	return
+}
+
+// Find the IDs used to match the cluster.
+// vSphere: the hosts in the cluster.
+// oVirt: the cluster.
+func (r *SelectorResolver) clusters(name string) (ids []string, err error) {
+	byName := r.params("name = " + quote(name))
+	switch r.Provider.Type() {
+	case api.VSphere:
+		clusters := []vsphere.Cluster{}
+		err = r.Inventory.List(&clusters, byName...)
+		if err != nil || len(clusters) == 0 {
+			return
+		}
+		quoted := []string{}
+		for _, m := range clusters {
+			quoted = append(quoted, quote(m.ID))
+		}
+		hosts := []vsphere.Host{}
+		err = r.Inventory.List(&hosts, r.params("cluster in ("+strings.Join(quoted, ",")+")")...)
+		for _, m := range hosts {
+			ids = append(ids, m.ID)
+		}
+	case api.OVirt:
+		clusters := []ovirt.Cluster{}
+		err = r.Inventory.List(&clusters, byName...)
+		for _, m := range clusters {
+			ids = append(ids, m.ID)
+		}
+	}
+	return
+}
+
+// List the VMs matched by the filter.
+func (r *SelectorResolver) list(filter string) (vms []ref.Ref, err error) {
+	params := r.params(filter)
+	switch r.Provider.Type() {
+	case api.VSphere:
+		list := []vsphere.VM{}
+		err = r.Inventory.List(&list, params...)
+		for _, m := range list {
+			vms = append(vms, ref.Ref{ID: m.ID, Name: m.Name})
+		}
+	case api.OVirt:
+		list := []ovirt.VM{}
+		err = r.Inventory.List(&list, params...)
+		for _, m := range list {
+			vms = append(vms, ref.Ref{ID: m.ID, Name: m.Name})
+		}
+	case api.OpenStack:
+		list := []openstack.VM{}
+		err = r.Inventory.List(&list, params...)
+		for _, m := range list {
+			vms = append(vms, ref.Ref{ID: m.ID, Name: m.Name})
+		}
+	case api.Ova:
+		list := []ova.VM{}
+		err = r.Inventory.List(&list, params...)
+		for _, m := range list {
+			vms = append(vms, ref.Ref{ID: m.ID, Name: m.Name})
+		}
+	case api.HyperV:
+		list := []hyperv.VM{}
+		err = r.Inventory.List(&list, params...)
+		for _, m := range list {
+			vms = append(vms, ref.Ref{ID: m.ID, Name: m.Name})
+		}
+	case api.OpenShift:
+		list := []ocp.VM{}
+		err = r.Inventory.List(&list, params...)
+		for _, m := range list {
+			vms = append(vms, ref.Ref{Namespace: m.Namespace, Name: m.Name})
+		}
+	}
+	return
+}
+
+// Build the list parameters.
+func (r *SelectorResolver) params(filter string) (params []web.Param) {
+	params = []web.Param{
+		{
+			Key:   base.DetailParam,
+			Value: "all",
+		},
+	}
+	if filter != "" {
+		params = append(
+			params,
+			web.Param{
+				Key:   libweb.FilterParam,
+				Value: filter,
+			})
+	}
+	return
+}
+
+// Quote a (query) string literal.
+func quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,126 @@
+package plan
+
+import (
+	"errors"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/base"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Plan VM selector", func() {
+	newProvider := func(providerType api.ProviderType) *api.Provider {
+		return &api.Provider{Spec: api.ProviderSpec{Type: &providerType}}
+	}
+
+	ginkgo.Describe("Filter", func() {
+		ginkgo.It("should combine the vSphere criteria", func() {
+			resolver := SelectorResolver{Provider: newProvider(api.VSphere)}
+			filter := resolver.Filter(
+				&plan.VMSelector{
+					Name:    `^db-\d+$`,
+					Folder:  "/DC/vm/prod/",
+					GuestOS: "rhel",
+					Labels:  map[string]string{"tier": "gold", "env": "prod"},
+				},
+				[]string{"host-1", "host-2"})
+			gomega.Expect(filter).To(gomega.Equal(
+				`name ~= '^db-\\d+$' and path ~= '^/DC/vm/prod/' and host in ('host-1','host-2')` +
+					` and (guestId ~= 'rhel' or guestName ~= 'rhel')` +
+					` and label('env') = 'prod' and label('tier') = 'gold'`))
+		})
+
+		ginkgo.It("should match the oVirt cluster and guest OS", func() {
+			resolver := SelectorResolver{Provider: newProvider(api.OVirt)}
+			filter := resolver.Filter(
+				&plan.VMSelector{GuestOS: "it's"},
+				[]string{"cluster-1"})
+			gomega.Expect(filter).To(gomega.Equal(
+				`cluster in ('cluster-1') and (osType ~= 'it\'s' or guest.distribution ~= 'it\'s')`))
+		})
+	})
+
+	ginkgo.Describe("Validate", func() {
+		ginkgo.It("should reject criteria not supported by the provider", func() {
+			resolver := SelectorResolver{Provider: newProvider(api.OpenStack)}
+			err := resolver.Validate(&plan.VMSelector{Cluster: "a"})
+			gomega.Expect(errors.As(err, &SelectorNotValidError{})).To(gomega.BeTrue())
+			resolver = SelectorResolver{Provider: newProvider(api.OVirt)}
+			err = resolver.Validate(&plan.VMSelector{Folder: "/a"})
+			gomega.Expect(errors.As(err, &SelectorNotValidError{})).To(gomega.BeTrue())
+			resolver = SelectorResolver{Provider: newProvider(api.OpenShift)}
+			err = resolver.Validate(&plan.VMSelector{Labels: map[string]string{"app": "db"}})
+			gomega.Expect(errors.As(err, &SelectorNotValidError{})).To(gomega.BeTrue())
+			gomega.Expect(resolver.Validate(&plan.VMSelector{Name: "db-.*"})).To(gomega.Succeed())
+			resolver = SelectorResolver{Provider: newProvider(api.EC2)}
+			err = resolver.Validate(&plan.VMSelector{Name: "a"})
+			gomega.Expect(errors.As(err, &SelectorNotValidError{})).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should reject a regex not valid", func() {
+			resolver := SelectorResolver{Provider: newProvider(api.VSphere)}
+			err := resolver.Validate(&plan.VMSelector{Name: "("})
+			gomega.Expect(errors.As(err, &SelectorNotValidError{})).To(gomega.BeTrue())
+			gomega.Expect(resolver.Validate(&plan.VMSelector{Name: "db-.*", Cluster: "a"})).To(gomega.Succeed())
+		})
+	})
+
+	ginkgo.Describe("resolveSelector", func() {
+		reconciler := &Reconciler{
+			Reconciler: base.Reconciler{Log: logging.WithName("planSelector")},
+		}
+		newPlan := func() *api.Plan {
+			p := &api.Plan{}
+			p.Referenced.Provider.Source = newProvider(api.VSphere)
+			p.Spec.VMSelector = &plan.VMSelector{Name: "db-.*"}
+			p.Spec.VMs = []plan.VM{{Ref: ref.Ref{Name: "db-1"}}}
+			return p
+		}
+
+		ginkgo.It("should add the frozen selection not listed explicitly", func() {
+			p := newPlan()
+			p.Status.Selection = &plan.Selection{
+				VMs: []ref.Ref{{ID: "vm-1", Name: "db-1"}, {ID: "vm-2", Name: "db-2"}},
+			}
+			p.Status.Selection.Freeze()
+			gomega.Expect(reconciler.resolveSelector(p)).To(gomega.Succeed())
+			gomega.Expect(p.Spec.VMs).To(gomega.Equal([]plan.VM{
+				{Ref: ref.Ref{Name: "db-1"}},
+				{Ref: ref.Ref{ID: "vm-2", Name: "db-2"}},
+			}))
+			gomega.Expect(p.Status.HasCondition(VMSelectorEmpty)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should warn when the frozen selection is empty", func() {
+			p := newPlan()
+			p.Status.Selection = &plan.Selection{}
+			p.Status.Selection.Freeze()
+			gomega.Expect(reconciler.resolveSelector(p)).To(gomega.Succeed())
+			gomega.Expect(p.Spec.VMs).To(gomega.HaveLen(1))
+			gomega.Expect(p.Status.HasCondition(VMSelectorEmpty)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should clear the selection without a selector", func() {
+			p := newPlan()
+			p.Spec.VMSelector = nil
+			p.Status.Selection = &plan.Selection{VMs: []ref.Ref{{ID: "vm-2"}}}
+			gomega.Expect(reconciler.resolveSelector(p)).To(gomega.Succeed())
+			gomega.Expect(p.Status.Selection).To(gomega.BeNil())
+			gomega.Expect(p.Spec.VMs).To(gomega.HaveLen(1))
+		})
+
+		ginkgo.It("should freeze the selection once", func() {
+			p := newPlan()
+			p.Status.Selection = &plan.Selection{VMs: []ref.Ref{{ID: "vm-2"}}}
+			reconciler.freezeSelection(p)
+			frozen := p.Status.Selection.Frozen
+			gomega.Expect(frozen).ToNot(gomega.BeNil())
+			reconciler.freezeSelection(p)
+			gomega.Expect(p.Status.Selection.Frozen).To(gomega.BeIdenticalTo(frozen))
+		})
+	})
+})
@@ -11,10 +11,10 @@ import (
 )
 
 // Ensure the namespace exists on the destination.
-func ensureNamespace(plan *api.Plan, client client.Client) error {
+func ensureNamespace(namespace string, client client.Client) error {
 	ns := &core.Namespace{
 		ObjectMeta: meta.ObjectMeta{
-			Name: plan.Spec.TargetNamespace,
+			Name: namespace,
 		},
 	}
 	err := client.Create(context.TODO(), ns)
@@ -25,10 +25,10 @@ func ensureNamespace(plan *api.Plan, client client.Client) error {
 }
 
 // Ensure the config map exists on the destination
-func ensureConfigMap(cm *core.ConfigMap, name func(plan *api.Plan) string, plan *api.Plan, client client.Client) error {
+func ensureConfigMap(cm *core.ConfigMap, name func(plan *api.Plan) string, plan *api.Plan, namespace string, client client.Client) error {
 	cm.ObjectMeta = meta.ObjectMeta{
 		Name:      name(plan),
-		Namespace: plan.Spec.TargetNamespace,
+		Namespace: namespace,
 	}
 	err := client.Create(context.TODO(), cm)
 	if err != nil && k8serr.IsAlreadyExists(err) {
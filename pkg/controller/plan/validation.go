@@ -99,6 +99,11 @@ const (
 	VDDKAndOffloadMixedUsage        = "VDDKAndOffloadMixedUsage"
 	RestrictedPodSecurity           = "RestrictedPodSecurity"
 	NetMapDestinationNADNotValid    = "NetMapDestinationNADNotValid"
+	ScheduleNotValid                = "ScheduleNotValid"
+	VMDependencyNotFound            = "VMDependencyNotFound"
+	VMDependencyInLaterWave         = "VMDependencyInLaterWave"
+	VMDependencyCircular            = "VMDependencyCircular"
+	InsufficientCapacity            = "InsufficientCapacity"
 )
 
 // Categories
@@ -128,6 +133,7 @@ const (
 	NotValid                    = "NotValid"
 	Modified                    = "Modified"
 	UserRequested               = "UserRequested"
+	DependencyFailed            = "DependencyFailed"
 	InMaintenanceMode           = "InMaintenanceMode"
 	MissingGuestInfo            = "MissingGuestInformation"
 	MissingChangedBlockTracking = "MissingChangedBlockTracking"
@@ -160,6 +166,14 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 	plan.Referenced.Provider.Source = pv.Referenced.Source
 	plan.Referenced.Provider.Destination = pv.Referenced.Destination
 
+	if err = r.resolveSelector(plan); err != nil {
+		return err
+	}
+
+	if err = r.resolvePlacement(plan); err != nil {
+		return err
+	}
+
 	if err = r.ensureSecretForProvider(plan); err != nil {
 		return err
 	}
@@ -205,6 +219,18 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	if err = r.validateVMDependencies(plan); err != nil {
+		return err
+	}
+
+	if err = r.validateCapacity(ctx); err != nil {
+		return err
+	}
+
+	if err = r.assessReadiness(ctx); err != nil {
+		return err
+	}
+
 	if err = r.validateTransferNetwork(plan); err != nil {
 		return err
 	}
@@ -213,6 +239,15 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	// Validate the health check
+	if err = r.validateHealthCheck(plan); err != nil {
+		return err
+	}
+
+	if err = r.validateGuestScripts(ctx); err != nil {
+		return err
+	}
+
 	if err = r.validateVddkImage(plan); err != nil {
 		return err
 	}
@@ -232,6 +267,16 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	// Validate the migration schedule
+	if err = r.validateSchedule(plan); err != nil {
+		return err
+	}
+
+	// Validate the metadata mapping
+	if err = r.validateMetadataMapping(plan); err != nil {
+		return err
+	}
+
 	// Validate SSH readiness for plans using xcopy with SSH-enabled providers
 	if err = r.validateSSHReadiness(plan); err != nil {
 		return err
@@ -287,6 +332,116 @@ func (r *Reconciler) validateNetworkNameTemplate(plan *api.Plan) error {
 	return nil
 }
 
+func (r *Reconciler) validateSchedule(plan *api.Plan) error {
+	schedule := plan.Spec.Schedule
+	if schedule == nil {
+		return nil
+	}
+	if err := schedule.Validate(); err != nil {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     ScheduleNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryCritical,
+			Message:  "Schedule is not valid: " + err.Error(),
+			Items:    []string{},
+		})
+		r.Log.Info("Schedule is invalid", "error", err.Error(), "plan", plan.Name, "namespace", plan.Namespace)
+	}
+
+	return nil
+}
+
+// Validate the VM dependencies.
+// Dependencies must be VMs in the plan, in the same or a lower
+// wave and must not be circular.
+func (r *Reconciler) validateVMDependencies(plan *api.Plan) error {
+	notFound := libcnd.Condition{
+		Type:     VMDependencyNotFound,
+		Status:   True,
+		Reason:   NotFound,
+		Category: api.CategoryCritical,
+		Message:  "VM depends on a VM not in the plan.",
+		Items:    []string{},
+	}
+	laterWave := libcnd.Condition{
+		Type:     VMDependencyInLaterWave,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "VM depends on a VM in a later wave.",
+		Items:    []string{},
+	}
+	circular := libcnd.Condition{
+		Type:     VMDependencyCircular,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "VM dependencies are circular.",
+		Items:    []string{},
+	}
+	vms := plan.Spec.VMs
+	find := func(dependency refapi.Ref) (index int, found bool) {
+		for i := range vms {
+			if vms[i].Matches(dependency) {
+				index = i
+				found = true
+				return
+			}
+		}
+		return
+	}
+	for i := range vms {
+		vm := &vms[i]
+		for _, dependency := range vm.DependsOn {
+			j, found := find(dependency)
+			if !found || j == i {
+				notFound.Items = append(notFound.Items, vm.String())
+				continue
+			}
+			if vms[j].Wave > vm.Wave {
+				laterWave.Items = append(laterWave.Items, vm.String())
+			}
+		}
+	}
+	// Depth first search for cycles.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make([]int, len(vms))
+	var visit func(i int) bool
+	visit = func(i int) (cycle bool) {
+		switch state[i] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+		state[i] = visiting
+		for _, dependency := range vms[i].DependsOn {
+			if j, found := find(dependency); found && j != i && visit(j) {
+				cycle = true
+				break
+			}
+		}
+		state[i] = visited
+		return
+	}
+	for i := range vms {
+		if state[i] == 0 && visit(i) {
+			circular.Items = append(circular.Items, vms[i].String())
+		}
+	}
+	for _, cnd := range []libcnd.Condition{notFound, laterWave, circular} {
+		if len(cnd.Items) > 0 {
+			plan.Status.SetCondition(cnd)
+		}
+	}
+
+	return nil
+}
+
 func (r *Reconciler) validateOpenShiftVersion(plan *api.Plan) error {
 	source := plan.Referenced.Provider.Source
 	if source == nil {
@@ -444,14 +599,17 @@ func (r *Reconciler) validateUserDefinedNetwork(ctx *plancontext.Context) (err e
 
 func (r *Reconciler) getDestinationNamespaceNads(ctx *plancontext.Context) (*k8snet.NetworkAttachmentDefinitionList, error) {
 	nadList := &k8snet.NetworkAttachmentDefinitionList{}
-	listOpts := []client.ListOption{
-		client.InNamespace(ctx.Plan.Spec.TargetNamespace),
-		client.MatchingLabels{"k8s.ovn.org/user-defined-network": ""},
-	}
-
-	err := ctx.Destination.Client.List(context.TODO(), nadList, listOpts...)
-	if err != nil {
-		return nil, err
+	for _, namespace := range placedNamespaces(ctx.Plan) {
+		list := &k8snet.NetworkAttachmentDefinitionList{}
+		listOpts := []client.ListOption{
+			client.InNamespace(namespace),
+			client.MatchingLabels{"k8s.ovn.org/user-defined-network": ""},
+		}
+		err := ctx.Destination.Client.List(context.TODO(), list, listOpts...)
+		if err != nil {
+			return nil, err
+		}
+		nadList.Items = append(nadList.Items, list.Items...)
 	}
 	return nadList, nil
 }
@@ -486,6 +644,8 @@ func (r *Reconciler) validateNetworkMap(plan *api.Plan) (err error) {
 		err = liberr.Wrap(err)
 		return
 	}
+	// The pairs generated by the rules are used with the pairs of the map.
+	mp.Resolve()
 	if !mp.Status.HasCondition(libcnd.Ready) {
 		plan.Status.SetCondition(libcnd.Condition{
 			Type:     NetMapNotReady,
@@ -519,21 +679,23 @@ func (r *Reconciler) validateNetworkMap(plan *api.Plan) (err error) {
 			continue
 		}
 
-		if pair.Destination.Namespace != plan.Spec.TargetNamespace &&
-			pair.Destination.Namespace != core.NamespaceDefault {
-			plan.Status.SetCondition(libcnd.Condition{
-				Type:     NetMapDestinationNADNotValid,
-				Status:   True,
-				Category: api.CategoryCritical,
-				Reason:   NotValid,
-				Message: fmt.Sprintf(
-					"Destination NAD %s/%s must be in either the target namespace (%s) or the default namespace. "+
-						"Pods cannot reference network attachment definitions from other namespaces.",
-					pair.Destination.Namespace,
-					pair.Destination.Name,
-					plan.Spec.TargetNamespace),
-			})
-			return
+		for _, namespace := range placedNamespaces(plan) {
+			if pair.Destination.Namespace != namespace &&
+				pair.Destination.Namespace != core.NamespaceDefault {
+				plan.Status.SetCondition(libcnd.Condition{
+					Type:     NetMapDestinationNADNotValid,
+					Status:   True,
+					Category: api.CategoryCritical,
+					Reason:   NotValid,
+					Message: fmt.Sprintf(
+						"Destination NAD %s/%s must be in either the target namespace (%s) or the default namespace. "+
+							"Pods cannot reference network attachment definitions from other namespaces.",
+						pair.Destination.Namespace,
+						pair.Destination.Name,
+						namespace),
+				})
+				return
+			}
 		}
 	}
 	plan.Referenced.Map.Network = mp
@@ -575,6 +737,8 @@ func (r *Reconciler) validateStorageMap(plan *api.Plan) (err error) {
 		err = liberr.Wrap(err)
 		return
 	}
+	// The pairs generated by the rules are used with the pairs of the map.
+	mp.Resolve()
 	if !mp.Status.HasCondition(libcnd.Ready) {
 		plan.Status.SetCondition(libcnd.Condition{
 			Type:     DsMapNotReady,
@@ -1103,7 +1267,7 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		}
 		vmRef := &refapi.Ref{
 			Name:      vmName,
-			Namespace: plan.Spec.TargetNamespace,
+			Namespace: plan.PlacedNamespace(*ref),
 		}
 		_, pErr = inventory.VM(vmRef)
 		if pErr == nil {
@@ -1344,21 +1508,23 @@ func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 		return
 	}
 
-	if plan.Spec.TransferNetwork.Namespace != plan.Spec.TargetNamespace &&
-		plan.Spec.TransferNetwork.Namespace != core.NamespaceDefault {
-		plan.Status.SetCondition(libcnd.Condition{
-			Type:     TransferNetNotValid,
-			Status:   True,
-			Category: api.CategoryCritical,
-			Reason:   NotValid,
-			Message: fmt.Sprintf(
-				"Transfer network %s/%s is in a different namespace than the target namespace %s. "+
-					"Pods cannot reference network attachment definitions across namespaces.",
-				plan.Spec.TransferNetwork.Namespace,
-				plan.Spec.TransferNetwork.Name,
-				plan.Spec.TargetNamespace),
-		})
-		return
+	for _, namespace := range placedNamespaces(plan) {
+		if plan.Spec.TransferNetwork.Namespace != namespace &&
+			plan.Spec.TransferNetwork.Namespace != core.NamespaceDefault {
+			plan.Status.SetCondition(libcnd.Condition{
+				Type:     TransferNetNotValid,
+				Status:   True,
+				Category: api.CategoryCritical,
+				Reason:   NotValid,
+				Message: fmt.Sprintf(
+					"Transfer network %s/%s is in a different namespace than the target namespace %s. "+
+						"Pods cannot reference network attachment definitions across namespaces.",
+					plan.Spec.TransferNetwork.Namespace,
+					plan.Spec.TransferNetwork.Name,
+					namespace),
+			})
+			return
+		}
 	}
 	route, found := netAttachDef.Annotations[AnnForkliftNetworkRoute]
 	if !found {
@@ -1376,6 +1542,36 @@ func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 	return
 }
 
+// Hook steps supported by the plan.
+// The pre, post and failure hooks are supported by all migration
+// types. The other steps are not supported by live migrations and
+// EC2 source providers. The precopy and cutover hooks require warm
+// migration, and the pre-conversion hook requires the guest to be
+// converted after the disks have been transferred.
+func hookSteps(plan *api.Plan) (steps map[string]bool) {
+	steps = map[string]bool{
+		api.PhasePreHook:     true,
+		api.PhasePostHook:    true,
+		api.PhaseFailureHook: true,
+	}
+	source := plan.Referenced.Provider.Source
+	if source == nil || source.Type() == api.EC2 || plan.Spec.Type == api.MigrationLive {
+		return
+	}
+	steps[api.PhasePrePowerOffHook] = true
+	if plan.IsWarm() {
+		steps[api.PhasePrecopyHook] = true
+		steps[api.PhaseCutoverHook] = true
+	}
+	if source.RequiresConversion() && !plan.Spec.SkipGuestConversion {
+		useV2vForTransfer, err := plan.ShouldUseV2vForTransfer()
+		if err == nil && !useV2vForTransfer {
+			steps[api.PhasePreConversionHook] = true
+		}
+	}
+	return
+}
+
 // Validate referenced hooks.
 func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 	notSet := libcnd.Condition{
@@ -1407,13 +1603,14 @@ func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 		Status:   True,
 		Reason:   NotValid,
 		Category: api.CategoryCritical,
-		Message:  "Hook step not valid.",
+		Message:  "Hook step not valid or not supported by the migration.",
 		Items:    []string{},
 	}
+	steps := hookSteps(plan)
 	for _, vm := range plan.Spec.VMs {
 		for _, ref := range vm.Hooks {
 			// Step not valid.
-			if _, found := map[string]int{api.PhasePreHook: 1, api.PhasePostHook: 1}[ref.Step]; !found {
+			if !steps[ref.Step] {
 				description := fmt.Sprintf(
 					"VM: %s step: %s",
 					vm.String(),
@@ -1709,7 +1906,7 @@ func (r *Reconciler) ensureVddkImageValidationJob(plan *api.Plan) (*batchv1.Job,
 }
 
 func (r *Reconciler) ensureNamespace(ctx *plancontext.Context) error {
-	err := ensureNamespace(ctx.Plan, ctx.Destination.Client)
+	err := ensureNamespace(ctx.Plan.Spec.TargetNamespace, ctx.Destination.Client)
 	if err == nil {
 		r.Log.Info(
 			"Created namespace.",
@@ -0,0 +1,136 @@
+package plan
+
+import (
+	"slices"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Plan Waves", func() {
+	newVM := func(name string, wave int, dependsOn ...string) plan.VM {
+		vm := plan.VM{
+			Ref:  ref.Ref{ID: "id-" + name, Name: name},
+			Wave: wave,
+		}
+		for _, d := range dependsOn {
+			vm.DependsOn = append(vm.DependsOn, ref.Ref{Name: d})
+		}
+		return vm
+	}
+	succeed := func(vm *plan.VMStatus) {
+		vm.MarkCompleted()
+		vm.SetCondition(libcnd.Condition{Type: api.ConditionSucceeded, Status: libcnd.True})
+	}
+	fail := func(vm *plan.VMStatus) {
+		vm.MarkCompleted()
+		vm.SetCondition(libcnd.Condition{Type: api.ConditionFailed, Status: libcnd.True})
+	}
+
+	ginkgo.Describe("Released", func() {
+		ginkgo.It("should release a wave once the lower waves have completed", func() {
+			db := &plan.VMStatus{VM: newVM("db", 0)}
+			app := &plan.VMStatus{VM: newVM("app", 1)}
+			status := plan.MigrationStatus{VMs: []*plan.VMStatus{db, app}}
+			gomega.Expect(status.Released(db)).To(gomega.BeTrue())
+			gomega.Expect(status.Released(app)).To(gomega.BeFalse())
+			fail(db)
+			gomega.Expect(status.Released(app)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should release a VM once its dependencies have succeeded", func() {
+			db := &plan.VMStatus{VM: newVM("db", 0)}
+			app := &plan.VMStatus{VM: newVM("app", 0, "db")}
+			status := plan.MigrationStatus{VMs: []*plan.VMStatus{db, app}}
+			gomega.Expect(status.Released(app)).To(gomega.BeFalse())
+			db.MarkStarted()
+			gomega.Expect(status.Released(app)).To(gomega.BeFalse())
+			succeed(db)
+			gomega.Expect(status.Released(app)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("ReflectWaves", func() {
+		ginkgo.It("should not report waves when not used", func() {
+			status := plan.MigrationStatus{
+				VMs: []*plan.VMStatus{{VM: newVM("a", 0)}, {VM: newVM("b", 0)}},
+			}
+			status.ReflectWaves()
+			gomega.Expect(status.Waves).To(gomega.BeNil())
+		})
+
+		ginkgo.It("should report the wave layout", func() {
+			db := &plan.VMStatus{VM: newVM("db", 0)}
+			app := &plan.VMStatus{VM: newVM("app", 2)}
+			web := &plan.VMStatus{VM: newVM("web", 2, "app")}
+			status := plan.MigrationStatus{VMs: []*plan.VMStatus{web, app, db}}
+			succeed(db)
+			app.MarkStarted()
+			status.ReflectWaves()
+			gomega.Expect(status.Waves).To(gomega.HaveLen(2))
+			gomega.Expect(status.Waves[0].Wave).To(gomega.Equal(0))
+			gomega.Expect(status.Waves[0].VMs).To(gomega.Equal([]ref.Ref{db.Ref}))
+			gomega.Expect(status.Waves[0].MarkedCompleted()).To(gomega.BeTrue())
+			gomega.Expect(status.Waves[1].Wave).To(gomega.Equal(2))
+			gomega.Expect(status.Waves[1].VMs).To(gomega.Equal([]ref.Ref{web.Ref, app.Ref}))
+			gomega.Expect(status.Waves[1].Running()).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("cancelBlockedVMs", func() {
+		ginkgo.It("should cancel VMs depending on a failed VM", func() {
+			db := &plan.VMStatus{VM: newVM("db", 0)}
+			app := &plan.VMStatus{VM: newVM("app", 0, "db")}
+			web := &plan.VMStatus{VM: newVM("web", 0, "app")}
+			other := &plan.VMStatus{VM: newVM("other", 0)}
+			p := &api.Plan{}
+			p.Status.Migration.VMs = []*plan.VMStatus{db, app, web, other}
+			runner := Migration{
+				Context: &plancontext.Context{
+					Plan: p,
+					Log:  logging.WithName("test"),
+				},
+			}
+			runner.cancelBlockedVMs()
+			gomega.Expect(app.HasCondition(api.ConditionCanceled)).To(gomega.BeFalse())
+			fail(db)
+			runner.cancelBlockedVMs()
+			gomega.Expect(app.HasCondition(api.ConditionCanceled)).To(gomega.BeTrue())
+			gomega.Expect(app.FindCondition(api.ConditionCanceled).Reason).To(gomega.Equal(DependencyFailed))
+			gomega.Expect(web.HasCondition(api.ConditionCanceled)).To(gomega.BeTrue())
+			gomega.Expect(other.HasCondition(api.ConditionCanceled)).To(gomega.BeFalse())
+		})
+	})
+
+	ginkgo.Describe("validateVMDependencies", func() {
+		ginkgo.DescribeTable("should validate the dependencies",
+			func(vms []plan.VM, conditions ...string) {
+				p := &api.Plan{}
+				p.Spec.VMs = vms
+				reconciler := createFakeReconciler()
+				gomega.Expect(reconciler.validateVMDependencies(p)).To(gomega.Succeed())
+				for _, cndType := range []string{VMDependencyNotFound, VMDependencyInLaterWave, VMDependencyCircular} {
+					gomega.Expect(p.Status.HasCondition(cndType)).To(gomega.Equal(slices.Contains(conditions, cndType)), cndType)
+				}
+			},
+			ginkgo.Entry("when valid",
+				[]plan.VM{newVM("db", 0), newVM("app", 1, "db"), newVM("web", 1, "app")}),
+			ginkgo.Entry("when not in the plan",
+				[]plan.VM{newVM("app", 0, "db")}, VMDependencyNotFound),
+			ginkgo.Entry("when self",
+				[]plan.VM{newVM("app", 0, "app")}, VMDependencyNotFound),
+			ginkgo.Entry("when in a later wave",
+				[]plan.VM{newVM("db", 1), newVM("app", 0, "db")}, VMDependencyInLaterWave),
+			ginkgo.Entry("when circular",
+				[]plan.VM{newVM("a", 0, "c"), newVM("b", 0, "a"), newVM("c", 0, "b")}, VMDependencyCircular),
+			ginkgo.Entry("when not in the plan, in a later wave and circular",
+				[]plan.VM{newVM("a", 1, "b", "x"), newVM("b", 0, "a")}, VMDependencyNotFound, VMDependencyInLaterWave, VMDependencyCircular),
+		)
+	})
+})
@@ -56,6 +56,8 @@ type Collector struct {
 	cancel func()
 	// Last event ID.
 	lastEvent int
+	// Last event ID stored as checkpoint.
+	checkpoint string
 	// Phase
 	phase string
 	// List of watches.
@@ -204,6 +206,11 @@ func (r *Collector) run(ctx *Context) (err error) {
 		r.phase)
 	switch r.phase {
 	case Started:
+		var resumed bool
+		resumed, err = r.resume()
+		if err != nil || resumed {
+			break
+		}
 		err = r.noteLastEvent()
 		if err == nil {
 			r.phase = Load
@@ -256,6 +263,48 @@ func (r *Collector) Shutdown() {
 	}
 }
 
+// Resume from the checkpoint stored in a persistent DB.
+// The initial load is skipped and the events since
+// the checkpoint are applied by the refresh.
+func (r *Collector) resume() (resumed bool, err error) {
+	value, found, err := libmodel.GetCheckpoint(r.db, libmodel.CollectorCheckpoint)
+	if err != nil || !found {
+		return
+	}
+	lastEvent, err := strconv.Atoi(value)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.lastEvent = lastEvent
+	r.checkpoint = value
+	r.phase = Loaded
+	resumed = true
+
+	r.log.Info(
+		"Resumed.",
+		"id",
+		r.lastEvent)
+
+	return
+}
+
+// Store the last event ID as checkpoint.
+func (r *Collector) storeCheckpoint() (err error) {
+	value := strconv.Itoa(r.lastEvent)
+	if value == r.checkpoint {
+		return
+	}
+	err = r.db.With(func(tx *libmodel.Tx) error {
+		return libmodel.SetCheckpoint(tx, libmodel.CollectorCheckpoint, value)
+	})
+	if err == nil {
+		r.checkpoint = value
+	}
+
+	return
+}
+
 // Fetch and note that last event.
 func (r *Collector) noteLastEvent() (err error) {
 	err = r.connect()
@@ -474,6 +523,8 @@ func (r *Collector) refresh(ctx *Context) (err error) {
 			event)
 	}
 
+	err = r.storeCheckpoint()
+
 	return
 }
 
@@ -3,6 +3,10 @@ package ovirt
 import (
 	"strings"
 
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ovirt"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -18,3 +22,40 @@ var _ = ginkgo.Describe("ovirt collector", func() {
 		table.Entry("get version when revision is in the 4th element", "4.5.3.4-1.el8ev", Equal("4.5.3.4")),
 	)
 })
+
+var _ = ginkgo.Describe("ovirt collector checkpoint", func() {
+	var db libmodel.DB
+	var collector *Collector
+
+	ginkgo.BeforeEach(func() {
+		db = libmodel.New("/tmp/test-ovirt-checkpoint.db", model.All()...)
+		Expect(db.Open(true)).To(Succeed())
+		collector = &Collector{
+			db:  db,
+			log: logging.WithName("collector|ovirt|test"),
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		_ = db.Close(true)
+	})
+
+	ginkgo.It("should not resume without a checkpoint", func() {
+		resumed, err := collector.resume()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resumed).To(BeFalse())
+		Expect(collector.phase).To(Equal(Started))
+	})
+
+	ginkgo.It("should resume from the stored last event", func() {
+		collector.lastEvent = 42
+		Expect(collector.storeCheckpoint()).To(Succeed())
+
+		resumed := &Collector{db: db, log: collector.log}
+		ok, err := resumed.resume()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(resumed.lastEvent).To(Equal(42))
+		Expect(resumed.phase).To(Equal(Loaded))
+	})
+})
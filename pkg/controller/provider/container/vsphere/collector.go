@@ -2,6 +2,7 @@ package vsphere
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	liburl "net/url"
@@ -53,6 +54,7 @@ const (
 	DVSwitch        = "VmwareDistributedVirtualSwitch"
 	Datastore       = "Datastore"
 	ResourcePool    = "ResourcePool"
+	VirtualApp      = "VirtualApp"
 )
 
 // Fields
@@ -152,6 +154,9 @@ const (
 	fToolsRunningStatus = "guest.toolsRunningStatus"
 	// fToolsVersionStatus is deprecated since vSphere API 5.1; use fToolsVersionStatus2 for more detailed status
 	fToolsVersionStatus = "guest.toolsVersionStatus2"
+	fCustomValue        = "customValue"
+	// CustomFieldsManager
+	fField = "field"
 )
 
 // Selections
@@ -201,6 +206,17 @@ var TsComputeResourceHost = &types.TraversalSpec{
 	},
 }
 
+// ComputeResource/ResourcePool traversal Spec.
+var TsComputeResourcePool = &types.TraversalSpec{
+	Type: ComputeResource,
+	Path: fResourcePool,
+	SelectSet: []types.BaseSelectionSpec{
+		&types.SelectionSpec{
+			Name: TraverseVApps,
+		},
+	},
+}
+
 // Datacenter/Host traversal Spec.
 var TsDatacenterNet = &types.TraversalSpec{
 	Type: Datacenter,
@@ -261,6 +277,7 @@ var TsRootFolder = &types.TraversalSpec{
 			Name: TraverseFolders,
 		},
 		TsComputeResourceHost,
+		TsComputeResourcePool,
 		TsDatacenterVM,
 		TsDatacenterHost,
 		TsDatacenterNet,
@@ -288,6 +305,11 @@ type Collector struct {
 	cancel func()
 	// has parity.
 	parity bool
+	// Objects seen while reconciling a persistent DB.
+	// Keyed by model kind and PK.
+	seen map[string]bool
+	// Custom field (attribute) names.
+	fields *CustomFields
 }
 
 // New collector.
@@ -303,6 +325,7 @@ func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) *Collector
 		secret:   secret,
 		db:       db,
 		log:      nlog,
+		fields:   &CustomFields{},
 	}
 }
 
@@ -416,6 +439,14 @@ func (r *Collector) Shutdown() {
 //  2. apply updates.
 //
 // Blocks waiting on updates until canceled.
+//
+// The version of the property collector is stored as checkpoint
+// once the initial update set has been applied. The versions are
+// only valid for the property collector (and session) that issued them
+// so the updates cannot resume from the checkpoint after a restart.
+// Instead, when the DB already holds the inventory (checkpoint found),
+// it is served (parity) while the initial update set is reconciled
+// with it and the objects that no longer exist are deleted.
 func (r *Collector) getUpdates(ctx context.Context) error {
 	_, err := r.connect(ctx)
 	if err != nil {
@@ -423,15 +454,32 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 	}
 	defer r.close()
 	about := r.client.ServiceContent.About
-	err = r.db.Insert(
-		&model.About{
+	err = r.db.With(func(tx *libmodel.Tx) error {
+		m := &model.About{
 			APIVersion:   about.ApiVersion,
 			Product:      about.LicenseProductName,
 			InstanceUuid: about.InstanceUuid,
-		})
+		}
+		err := tx.Update(m)
+		if errors.Is(err, libmodel.NotFound) {
+			err = tx.Insert(m)
+		}
+		return err
+	})
 	if err != nil {
 		return err
 	}
+	_, resumed, err := libmodel.GetCheckpoint(r.db, libmodel.CollectorCheckpoint)
+	if err != nil {
+		return err
+	}
+	_, err = r.fields.Load(ctx, r.client.Client)
+	if err != nil {
+		r.log.Error(err, "load custom fields failed.")
+	}
+	tagCtx, tagCancel := context.WithCancel(ctx)
+	defer tagCancel()
+	tagging := false
 	pc := property.DefaultCollector(r.client.Client)
 	pc, err = pc.Create(ctx)
 	if err != nil {
@@ -456,8 +504,15 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 	}
 	var tx *libmodel.Tx
 	watchList := []*libmodel.Watch{}
+	if resumed {
+		r.seen = make(map[string]bool)
+		r.parity = true
+		r.log.Info("Resumed, reconciling.")
+		watchList = r.watch()
+	}
 	defer func() {
 		r.parity = false
+		r.seen = nil
 		for _, w := range watchList {
 			w.End()
 		}
@@ -488,6 +543,7 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 			continue
 		}
 		req.Version = updateSet.Version
+		complete := updateSet.Truncated == nil || !*updateSet.Truncated
 		tx, err = r.db.Begin()
 		if err != nil {
 			return err
@@ -501,6 +557,19 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 				break
 			}
 		}
+		if err == nil && complete && r.seen != nil {
+			err = r.sweep(tx)
+			if err == nil {
+				r.seen = nil
+				r.log.Info(
+					"Reconciled.",
+					"duration",
+					time.Since(mark))
+			}
+		}
+		if err == nil && (complete || r.parity) {
+			err = libmodel.SetCheckpoint(tx, libmodel.CollectorCheckpoint, req.Version)
+		}
 		if err == nil {
 			err = tx.Commit()
 		} else {
@@ -511,7 +580,7 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 				err,
 				"tx commit failed.")
 		}
-		if updateSet.Truncated == nil || !*updateSet.Truncated {
+		if complete {
 			if !r.parity {
 				r.parity = true
 				r.log.Info(
@@ -520,6 +589,10 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 					time.Since(mark))
 				watchList = r.watch()
 			}
+			if !tagging {
+				tagging = true
+				go r.refreshTags(tagCtx, r.client.Client)
+			}
 		}
 	}
 
@@ -822,6 +895,13 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fHost,
 			},
 		},
+		{ // ResourcePool
+			Type: ResourcePool,
+			PathSet: []string{
+				fName,
+				fParent,
+			},
+		},
 		{ // VM
 			Type:    VirtualMachine,
 			PathSet: r.vmPathSet(),
@@ -858,6 +938,7 @@ func (r *Collector) vmPathSet() []string {
 		fDatastore,
 		fNetwork,
 		fRuntimeHost,
+		fResourcePool,
 		fPowerState,
 		fConnectionState,
 		fIsTemplate,
@@ -868,6 +949,7 @@ func (r *Collector) vmPathSet() []string {
 		fToolsStatus,
 		fToolsRunningStatus,
 		fToolsVersionStatus,
+		fCustomValue,
 	}
 
 	apiVer := strings.Split(r.client.ServiceContent.About.ApiVersion, ".")
@@ -995,6 +1077,14 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 				},
 			},
 		}
+	case ResourcePool, VirtualApp:
+		adapter = &ResourcePoolAdapter{
+			model: model.ResourcePool{
+				Base: model.Base{
+					ID: u.Obj.Value,
+				},
+			},
+		}
 	case VirtualMachine:
 		adapter = &VmAdapter{
 			model: model.VM{
@@ -1002,6 +1092,7 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 					ID: u.Obj.Value,
 				},
 			},
+			fields: r.fields,
 		}
 	default:
 		r.log.Info("Unknown", "kind", u.Obj.Type)
@@ -1011,7 +1102,55 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 	return adapter, true
 }
 
+// Delete the objects not seen while reconciling.
+func (r *Collector) sweep(tx *libmodel.Tx) (err error) {
+	kinds := []libmodel.Model{
+		&model.Folder{},
+		&model.Datacenter{},
+		&model.Cluster{},
+		&model.Network{},
+		&model.Datastore{},
+		&model.ResourcePool{},
+		&model.Host{},
+		&model.VM{},
+	}
+	for _, kind := range kinds {
+		itr, fErr := tx.Find(kind, libmodel.ListOptions{})
+		if fErr != nil {
+			err = liberr.Wrap(fErr)
+			return
+		}
+		for {
+			object, hasNext := itr.Next()
+			if !hasNext {
+				break
+			}
+			m := object.(libmodel.Model)
+			if r.seen[r.seenKey(m)] {
+				continue
+			}
+			err = tx.Delete(m)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			r.log.V(1).Info(
+				"Deleted (not seen).",
+				"model",
+				libmodel.Describe(m))
+		}
+	}
+
+	return
+}
+
+// Key of an object seen while reconciling.
+func (r *Collector) seenKey(m libmodel.Model) string {
+	return fmt.Sprintf("%T/%s", m, m.Pk())
+}
+
 // Object created.
+// While reconciling, the object may already be stored.
 func (r Collector) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
 	adapter, selected := r.selectAdapter(u)
 	if !selected {
@@ -1019,6 +1158,16 @@ func (r Collector) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
 	}
 	adapter.Apply(u)
 	m := adapter.Model()
+	if r.seen != nil {
+		r.seen[r.seenKey(m)] = true
+		err := tx.Update(m)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, libmodel.NotFound) {
+			return liberr.Wrap(err)
+		}
+	}
 	err := tx.Insert(m)
 	if err != nil {
 		return liberr.Wrap(err)
@@ -1088,6 +1237,12 @@ func (r Collector) applyLeave(tx *libmodel.Tx, u types.ObjectUpdate) error {
 				ID: datastoreId,
 			},
 		}
+	case ResourcePool, VirtualApp:
+		deleted = &model.ResourcePool{
+			Base: model.Base{
+				ID: u.Obj.Value,
+			},
+		}
 	case VirtualMachine:
 		deleted = &model.VM{
 			Base: model.Base{
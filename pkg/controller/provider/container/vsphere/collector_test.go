@@ -1,8 +1,13 @@
 package vsphere
 
 import (
+	"context"
 	liburl "net/url"
 
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -35,3 +40,60 @@ var _ = Describe("vSphere collector", func() {
 		table.Entry("collect TPM from vSphere > 6.7", "7.0", ContainElements(fTpmPresent)),
 	)
 })
+
+var _ = Describe("vSphere collector reconcile", func() {
+	var db libmodel.DB
+	var collector *Collector
+
+	enter := func(kind, id string) types.ObjectUpdate {
+		return types.ObjectUpdate{
+			Kind: Enter,
+			Obj: types.ManagedObjectReference{
+				Type:  kind,
+				Value: id,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		db = libmodel.New("/tmp/test-vsphere-reconcile.db", model.All()...)
+		Expect(db.Open(true)).To(Succeed())
+		for _, m := range []libmodel.Model{
+			&model.Folder{Base: model.Base{ID: "group-1"}},
+			&model.VM{Base: model.Base{ID: "vm-1", Name: "old"}},
+			&model.VM{Base: model.Base{ID: "vm-2"}},
+		} {
+			Expect(db.Insert(m)).To(Succeed())
+		}
+		collector = &Collector{
+			db:   db,
+			log:  logging.WithName("collector|vsphere|test"),
+			seen: make(map[string]bool),
+		}
+	})
+
+	AfterEach(func() {
+		_ = db.Close(true)
+	})
+
+	It("should update and insert the entered objects and delete the others", func() {
+		tx, err := db.Begin()
+		Expect(err).ToNot(HaveOccurred())
+		updates := []types.ObjectUpdate{
+			enter(VirtualMachine, "vm-1"),
+			enter(VirtualMachine, "vm-3"),
+		}
+		Expect(collector.apply(context.TODO(), tx, updates)).To(Succeed())
+		Expect(collector.sweep(tx)).To(Succeed())
+		Expect(tx.Commit()).To(Succeed())
+
+		vms := []model.VM{}
+		Expect(db.List(&vms, libmodel.ListOptions{})).To(Succeed())
+		ids := []string{}
+		for _, vm := range vms {
+			ids = append(ids, vm.ID)
+		}
+		Expect(ids).To(ConsistOf("vm-1", "vm-3"))
+		Expect(db.Get(&model.Folder{Base: model.Base{ID: "group-1"}})).ToNot(Succeed())
+	})
+})
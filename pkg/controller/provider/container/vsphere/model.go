@@ -70,6 +70,9 @@ func (b *Base) Ref(in types.AnyType) (ref model.Ref) {
 			ref.Kind = model.NetKind
 		case Datastore:
 			ref.Kind = model.DsKind
+		case ResourcePool,
+			VirtualApp:
+			ref.Kind = model.PoolKind
 		case Host:
 			ref.Kind = model.HostKind
 		case VirtualMachine:
@@ -626,11 +629,30 @@ func (v *DatastoreAdapter) Apply(u types.ObjectUpdate) {
 	}
 }
 
+// ResourcePool model adapter.
+type ResourcePoolAdapter struct {
+	Base
+	// The adapter model.
+	model model.ResourcePool
+}
+
+// The adapter model.
+func (v *ResourcePoolAdapter) Model() model.Model {
+	return &v.model
+}
+
+// Apply the update to the model.
+func (v *ResourcePoolAdapter) Apply(u types.ObjectUpdate) {
+	v.Base.Apply(&v.model.Base, u)
+}
+
 // VM model adapter.
 type VmAdapter struct {
 	Base
 	// The adapter model.
 	model model.VM
+	// Custom field (attribute) names.
+	fields *CustomFields
 }
 
 // The adapter model.
@@ -788,6 +810,8 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				}
 			case fRuntimeHost:
 				v.model.Host = v.Ref(p.Val).ID
+			case fResourcePool:
+				v.model.ResourcePool = v.Ref(p.Val).ID
 			case fVmIpAddress:
 				if s, cast := p.Val.(string); cast {
 					v.model.IpAddress = s
@@ -798,6 +822,21 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				}
 			case fNetwork:
 				v.model.Networks = v.RefList(p.Val)
+			case fCustomValue:
+				v.model.CustomAttributes = nil
+				if values, cast := p.Val.(types.ArrayOfCustomFieldValue); cast {
+					for _, value := range values.CustomFieldValue {
+						if s, cast := value.(*types.CustomFieldStringValue); cast {
+							v.model.CustomAttributes = append(
+								v.model.CustomAttributes,
+								model.CustomAttribute{
+									Key:   s.Key,
+									Name:  v.fields.Name(s.Key),
+									Value: s.Value,
+								})
+						}
+					}
+				}
 			case fExtraConfig:
 				if options, cast := p.Val.(types.ArrayOfOptionValue); cast {
 					for _, val := range options.OptionValue {
@@ -0,0 +1,276 @@
+package vsphere
+
+import (
+	"context"
+	"errors"
+	liburl "net/url"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapitags "github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Settings
+const (
+	// Tags (and custom attribute names) refresh interval.
+	TagRefreshInterval = time.Minute * 5
+	// Number of VMs listed by a tag association request.
+	TagBatchSize = 1000
+)
+
+// Custom field (attribute) names.
+// Keyed by field key.
+type CustomFields struct {
+	mutex sync.RWMutex
+	names map[int32]string
+}
+
+// Load the field definitions.
+// Not supported by ESXi.
+// Returns true when the names have changed.
+func (r *CustomFields) Load(ctx context.Context, client *vim25.Client) (changed bool, err error) {
+	ref := client.ServiceContent.CustomFieldsManager
+	if ref == nil {
+		return
+	}
+	manager := mo.CustomFieldsManager{}
+	err = property.DefaultCollector(client).RetrieveOne(ctx, *ref, []string{fField}, &manager)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	names := map[int32]string{}
+	for _, field := range manager.Field {
+		names[field.Key] = field.Name
+	}
+	r.mutex.Lock()
+	changed = !reflect.DeepEqual(r.names, names)
+	r.names = names
+	r.mutex.Unlock()
+	return
+}
+
+// Name of the field.
+func (r *CustomFields) Name(key int32) (name string) {
+	if r == nil {
+		return
+	}
+	r.mutex.RLock()
+	name = r.names[key]
+	r.mutex.RUnlock()
+	return
+}
+
+// Refresh the tags (and the custom attribute names)
+// of the VMs periodically until canceled.
+// The tags are not reported by the property collector.
+func (r *Collector) refreshTags(ctx context.Context, client *vim25.Client) {
+	var synced map[string][]model.Tag
+	for {
+		tags, err := r.syncTags(ctx, client, synced)
+		if err == nil {
+			synced = tags
+		} else if ctx.Err() == nil {
+			r.log.Error(err, "tags refresh failed.")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(TagRefreshInterval):
+		}
+	}
+}
+
+// Sync the tags (and the custom attribute names) of the VMs.
+// Only the VMs with tags changed since the last sync (synced) are
+// updated. All of the VMs are updated by the first sync and when the
+// custom attribute names have changed. Returns the synced tags.
+func (r *Collector) syncTags(ctx context.Context, client *vim25.Client, synced map[string][]model.Tag) (tags map[string][]model.Tag, err error) {
+	renamed, err := r.fields.Load(ctx, client)
+	if err != nil {
+		return
+	}
+	list := []model.VM{}
+	err = r.db.List(&list, libmodel.ListOptions{})
+	if err != nil {
+		return
+	}
+	tags = map[string][]model.Tag{}
+	if client.IsVC() {
+		tags, err = r.listTags(ctx, client, list)
+		if err != nil {
+			return
+		}
+	}
+	ids := []string{}
+	for i := range list {
+		id := list[i].ID
+		if renamed || synced == nil || !reflect.DeepEqual(synced[id], tags[id]) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 {
+		err = r.updateTags(ids, tags)
+	}
+	return
+}
+
+// Update the tags (and the custom attribute names) of the VMs.
+func (r *Collector) updateTags(ids []string, tags map[string][]model.Tag) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	updated := 0
+	for _, id := range ids {
+		vm := &model.VM{Base: model.Base{ID: id}}
+		err = tx.Get(vm)
+		if err != nil {
+			if errors.Is(err, libmodel.NotFound) {
+				err = nil
+				continue
+			}
+			return
+		}
+		if !r.applyTags(vm, tags[id]) {
+			continue
+		}
+		err = tx.Update(vm)
+		if err != nil {
+			return
+		}
+		updated++
+	}
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+	r.log.V(3).Info(
+		"Tags refreshed.",
+		"updated",
+		updated)
+	return
+}
+
+// Apply the tags and the custom attribute names to the VM.
+// Returns true when the VM has been changed.
+func (r *Collector) applyTags(vm *model.VM, tags []model.Tag) (changed bool) {
+	if !reflect.DeepEqual(vm.Tags, tags) {
+		vm.Tags = tags
+		changed = true
+	}
+	for i := range vm.CustomAttributes {
+		attribute := &vm.CustomAttributes[i]
+		name := r.fields.Name(attribute.Key)
+		if name != "" && name != attribute.Name {
+			attribute.Name = name
+			changed = true
+		}
+	}
+	return
+}
+
+// List the tags attached to the VMs.
+// Keyed by VM ID and sorted by category and name.
+func (r *Collector) listTags(ctx context.Context, client *vim25.Client, vms []model.VM) (tags map[string][]model.Tag, err error) {
+	rc := rest.NewClient(client)
+	err = rc.Login(ctx, liburl.UserPassword(r.user(), r.password()))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = rc.Logout(context.Background())
+	}()
+	manager := vapitags.NewManager(rc)
+	categories, err := manager.GetCategories(ctx)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	infos, err := manager.GetTags(ctx)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	attached := []vapitags.AttachedTags{}
+	if len(infos) > 0 {
+		refs := []mo.Reference{}
+		for i := range vms {
+			refs = append(refs, types.ManagedObjectReference{Type: VirtualMachine, Value: vms[i].ID})
+		}
+		for len(refs) > 0 {
+			batch := refs[:min(len(refs), TagBatchSize)]
+			refs = refs[len(batch):]
+			var found []vapitags.AttachedTags
+			found, err = manager.ListAttachedTagsOnObjects(ctx, batch)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			attached = append(attached, found...)
+		}
+	}
+	tags = vmTags(categories, infos, attached)
+	return
+}
+
+// Build the tags attached to the VMs.
+// Keyed by VM ID and sorted by category and name.
+func vmTags(categories []vapitags.Category, infos []vapitags.Tag, attached []vapitags.AttachedTags) (tags map[string][]model.Tag) {
+	categoryNames := map[string]string{}
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+	byID := map[string]vapitags.Tag{}
+	for _, info := range infos {
+		byID[info.ID] = info
+	}
+	tags = map[string][]model.Tag{}
+	for _, object := range attached {
+		if object.ObjectID == nil {
+			continue
+		}
+		ref := object.ObjectID.Reference()
+		if ref.Type != VirtualMachine {
+			continue
+		}
+		for _, id := range object.TagIDs {
+			info, found := byID[id]
+			if !found {
+				continue
+			}
+			tags[ref.Value] = append(
+				tags[ref.Value],
+				model.Tag{
+					ID:       info.ID,
+					Name:     info.Name,
+					Category: categoryNames[info.CategoryID],
+				})
+		}
+	}
+	for _, list := range tags {
+		sort.Slice(
+			list,
+			func(i, j int) bool {
+				if list[i].Category != list[j].Category {
+					return list[i].Category < list[j].Category
+				}
+				return list[i].Name < list[j].Name
+			})
+	}
+	return
+}
@@ -0,0 +1,160 @@
+package vsphere
+
+import (
+	"context"
+	liburl "net/url"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	vapitags "github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+var _ = Describe("VM tags and custom attributes", func() {
+	Describe("vmTags", func() {
+		It("should attach the tags to the VMs sorted by category and name", func() {
+			attached := func(kind, id string, tags ...string) vapitags.AttachedTags {
+				return vapitags.AttachedTags{
+					ObjectID: types.ManagedObjectReference{Type: kind, Value: id},
+					TagIDs:   tags,
+				}
+			}
+			tags := vmTags(
+				[]vapitags.Category{
+					{ID: "c1", Name: "env"},
+					{ID: "c2", Name: "app"},
+				},
+				[]vapitags.Tag{
+					{ID: "t1", Name: "prod", CategoryID: "c1"},
+					{ID: "t2", Name: "web", CategoryID: "c2"},
+					{ID: "t3", Name: "db", CategoryID: "c2"},
+				},
+				[]vapitags.AttachedTags{
+					attached(VirtualMachine, "vm-1", "t1", "t2", "t3"),
+					attached(VirtualMachine, "vm-2", "t3", "t4"),
+					attached(VirtualMachine, "vm-3"),
+					attached("HostSystem", "host-1", "t1"),
+				})
+			Expect(tags).To(Equal(map[string][]model.Tag{
+				"vm-1": {
+					{ID: "t3", Name: "db", Category: "app"},
+					{ID: "t2", Name: "web", Category: "app"},
+					{ID: "t1", Name: "prod", Category: "env"},
+				},
+				"vm-2": {
+					{ID: "t3", Name: "db", Category: "app"},
+				},
+			}))
+		})
+	})
+
+	Describe("syncTags", func() {
+		var db libmodel.DB
+		var collector *Collector
+		url, _ := liburl.Parse("https://fake.com/sdk")
+		// Not a vCenter, the VMs have no tags.
+		client := &vim25.Client{Client: soap.NewClient(url, false)}
+		tagged := []model.Tag{{ID: "t1", Name: "prod", Category: "env"}}
+
+		BeforeEach(func() {
+			db = libmodel.New("/tmp/test-vsphere-tags.db", model.All()...)
+			Expect(db.Open(true)).To(Succeed())
+			Expect(db.Insert(&model.VM{Base: model.Base{ID: "vm-1"}, Tags: tagged})).To(Succeed())
+			Expect(db.Insert(&model.VM{Base: model.Base{ID: "vm-2"}})).To(Succeed())
+			collector = &Collector{
+				db:     db,
+				log:    logging.WithName("collector|vsphere|test"),
+				fields: &CustomFields{},
+			}
+		})
+
+		AfterEach(func() {
+			_ = db.Close(true)
+		})
+
+		It("should only update the VMs with changed tags", func() {
+			// The first sync updates all of the VMs.
+			synced, err := collector.syncTags(context.TODO(), client, nil)
+			Expect(err).ToNot(HaveOccurred())
+			vm := &model.VM{Base: model.Base{ID: "vm-1"}}
+			Expect(db.Get(vm)).To(Succeed())
+			Expect(vm.Tags).To(BeEmpty())
+			// The tags have not changed since.
+			vm = &model.VM{Base: model.Base{ID: "vm-2"}}
+			Expect(db.Get(vm)).To(Succeed())
+			vm.Tags = tagged
+			Expect(db.Update(vm)).To(Succeed())
+			_, err = collector.syncTags(context.TODO(), client, synced)
+			Expect(err).ToNot(HaveOccurred())
+			vm = &model.VM{Base: model.Base{ID: "vm-2"}}
+			Expect(db.Get(vm)).To(Succeed())
+			Expect(vm.Tags).To(Equal(tagged))
+		})
+	})
+
+	Describe("applyTags", func() {
+		It("should report the VM changed", func() {
+			collector := &Collector{fields: &CustomFields{names: map[int32]string{1: "owner"}}}
+			vm := &model.VM{
+				CustomAttributes: []model.CustomAttribute{{Key: 1, Value: "alice"}},
+			}
+			tags := []model.Tag{{ID: "t1", Name: "prod", Category: "env"}}
+			Expect(collector.applyTags(vm, tags)).To(BeTrue())
+			Expect(vm.Tags).To(Equal(tags))
+			Expect(vm.CustomAttributes[0].Name).To(Equal("owner"))
+			Expect(collector.applyTags(vm, tags)).To(BeFalse())
+		})
+	})
+
+	Describe("VM labels", func() {
+		It("should include the attributes and the tags", func() {
+			vm := &model.VM{
+				CustomAttributes: []model.CustomAttribute{
+					{Key: 1, Name: "owner", Value: "alice"},
+					{Key: 2, Name: "env", Value: "overridden"},
+				},
+				Tags: []model.Tag{
+					{Name: "prod", Category: "env"},
+					{Name: "blue", Category: "env"},
+				},
+			}
+			Expect(vm.Labels()).To(Equal(libmodel.Labels{
+				"owner": "alice",
+				"env":   "blue,prod",
+			}))
+			Expect((&model.VM{}).Labels()).To(BeNil())
+		})
+	})
+
+	Describe("VmAdapter", func() {
+		It("should collect the custom attributes", func() {
+			adapter := &VmAdapter{fields: &CustomFields{names: map[int32]string{7: "owner"}}}
+			adapter.Apply(types.ObjectUpdate{
+				Kind: types.ObjectUpdateKindModify,
+				Obj:  types.ManagedObjectReference{Type: VirtualMachine, Value: "vm-1"},
+				ChangeSet: []types.PropertyChange{
+					{
+						Op:   Assign,
+						Name: fCustomValue,
+						Val: types.ArrayOfCustomFieldValue{
+							CustomFieldValue: []types.BaseCustomFieldValue{
+								&types.CustomFieldStringValue{
+									CustomFieldValue: types.CustomFieldValue{Key: 7},
+									Value:            "alice",
+								},
+							},
+						},
+					},
+				},
+			})
+			Expect(adapter.model.CustomAttributes).To(Equal([]model.CustomAttribute{
+				{Key: 7, Name: "owner", Value: "alice"},
+			}))
+		})
+	})
+})
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	"github.com/kubev2v/forklift/pkg/controller/provider/container"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	webbase "github.com/kubev2v/forklift/pkg/controller/provider/web/base"
 	"github.com/kubev2v/forklift/pkg/controller/validation/policy"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
@@ -64,6 +66,9 @@ const (
 	Name               = "provider"
 	OvaTimeout         = 10 * time.Minute
 	OvaReconcilerRetry = 5 * time.Second
+	// Checkpoint of the provider generation
+	// the persistent DB has been built for.
+	GenerationCheckpoint = "provider.generation"
 )
 
 // Package logger.
@@ -84,6 +89,7 @@ func Add(mgr manager.Manager) error {
 		web.TLS.Key = Settings.Inventory.TLS.Key
 		web.AllowedOrigins = Settings.Inventory.CORS.AllowedOrigins
 	}
+	webbase.Measured.Reader = mgr.GetAPIReader()
 	reconciler := &Reconciler{
 		Reconciler: base.Reconciler{
 			EventRecorder: mgr.GetEventRecorderFor(Name),
@@ -363,8 +369,7 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	}
 
 	log.Info("Update container.")
-	db := r.getDB(provider)
-	err = db.Open(true)
+	db, err := r.openDB(provider)
 	if err != nil {
 		return
 	}
@@ -376,7 +381,7 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 		return
 	}
 	if found {
-		_ = old.DB().Close(true)
+		_ = old.DB().Close(!Settings.Inventory.Persistent)
 		r.Log.V(2).Info("Replaced collector.")
 	} else {
 		r.Log.V(2).Info("Added new collector.")
@@ -391,6 +396,58 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	return
 }
 
+// Open the DB for provider.
+// A persistent DB is kept when it was built for the
+// current generation of the provider and the collector
+// completed the initial load so the collector can resume
+// from its checkpoint. Otherwise, it is rebuilt.
+func (r *Reconciler) openDB(provider *api.Provider) (db libmodel.DB, err error) {
+	db = r.getDB(provider)
+	if !Settings.Inventory.Persistent {
+		err = db.Open(true)
+		return
+	}
+	err = db.Open(false)
+	if err != nil {
+		return
+	}
+	generation := strconv.FormatInt(provider.Generation, 10)
+	stored, found, err := libmodel.GetCheckpoint(db, GenerationCheckpoint)
+	if err != nil {
+		_ = db.Close(true)
+		return
+	}
+	if found && stored == generation {
+		_, found, err = libmodel.GetCheckpoint(db, libmodel.CollectorCheckpoint)
+		if err != nil {
+			_ = db.Close(true)
+			return
+		}
+		if found {
+			r.Log.Info("Persistent DB kept.")
+			return
+		}
+	}
+	r.Log.Info(
+		"Persistent DB rebuilt.",
+		"generation",
+		generation)
+	_ = db.Close(true)
+	db = r.getDB(provider)
+	err = db.Open(true)
+	if err != nil {
+		return
+	}
+	err = db.With(func(tx *libmodel.Tx) error {
+		return libmodel.SetCheckpoint(tx, GenerationCheckpoint, generation)
+	})
+	if err != nil {
+		_ = db.Close(true)
+	}
+
+	return
+}
+
 // Build DB for provider.
 func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
 	dir := Settings.Inventory.WorkingDir
@@ -402,7 +459,19 @@ func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
 	file := string(provider.UID) + ".db"
 	path := filepath.Join(dir, file)
 	models := model.Models(provider)
-	db = libmodel.New(path, models...)
+	history := Settings.Inventory.History
+	if history.Enabled {
+		db = libmodel.NewWithHistory(
+			path,
+			&libmodel.HistoryOptions{
+				Models:    model.History(provider),
+				Excluded:  model.HistoryExcluded,
+				Retention: time.Duration(history.Retention) * time.Hour,
+			},
+			models...)
+	} else {
+		db = libmodel.New(path, models...)
+	}
 	r.Log.Info(
 		"Opening DB.",
 		"path",
@@ -41,3 +41,28 @@ func Models(provider *api.Provider) (all []interface{}) {
 
 	return
 }
+
+// Models with the change history recorded.
+func History(provider *api.Provider) (all []interface{}) {
+	switch provider.Type() {
+	case api.VSphere:
+		all = append(all, &vsphere.VM{})
+	case api.OVirt:
+		all = append(all, &ovirt.VM{})
+	case api.OpenStack:
+		all = append(all, &openstack.VM{})
+	case api.Ova, api.HyperV:
+		all = append(all, &ovf.VM{})
+	case api.EC2:
+		all = append(all, &ec2model.Instance{})
+	}
+
+	return
+}
+
+// Fields excluded from the change history.
+// Updated when the VMs are validated.
+var HistoryExcluded = []string{
+	"RevisionValidated",
+	"PolicyVersion",
+}
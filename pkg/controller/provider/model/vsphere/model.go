@@ -1,6 +1,9 @@
 package vsphere
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
 	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
 )
@@ -280,6 +283,10 @@ type DVSHost struct {
 	PNIC []string
 }
 
+type ResourcePool struct {
+	Base
+}
+
 type Datastore struct {
 	Base
 	Type                string   `sql:""`
@@ -291,49 +298,52 @@ type Datastore struct {
 
 type VM struct {
 	Base
-	Folder                   string           `sql:"d0,index(folder)"`
-	Host                     string           `sql:"d0,index(host)"`
-	RevisionValidated        int64            `sql:"d0,index(revisionValidated)"`
-	PolicyVersion            int              `sql:"d0,index(policyVersion)"`
-	UUID                     string           `sql:""`
-	Firmware                 string           `sql:""`
-	PowerState               string           `sql:""`
-	ConnectionState          string           `sql:""`
-	CpuAffinity              []int32          `sql:""`
-	CpuHotAddEnabled         bool             `sql:""`
-	CpuHotRemoveEnabled      bool             `sql:""`
-	MemoryHotAddEnabled      bool             `sql:""`
-	FaultToleranceEnabled    bool             `sql:""`
-	CpuCount                 int32            `sql:""`
-	CoresPerSocket           int32            `sql:""`
-	MemoryMB                 int32            `sql:""`
-	GuestName                string           `sql:""`
-	GuestNameFromVmwareTools string           `sql:""`
-	HostName                 string           `sql:""`
-	GuestID                  string           `sql:""`
-	BalloonedMemory          int32            `sql:""`
-	IpAddress                string           `sql:""`
-	NumaNodeAffinity         []string         `sql:""`
-	StorageUsed              int64            `sql:""`
-	Snapshot                 Ref              `sql:""`
-	IsTemplate               bool             `sql:""`
-	ChangeTrackingEnabled    bool             `sql:""`
-	TpmEnabled               bool             `sql:""`
-	Devices                  []Device         `sql:""`
-	NICs                     []NIC            `sql:""`
-	Disks                    []Disk           `sql:""`
-	Controllers              []Controller     `sql:""`
-	Networks                 []Ref            `sql:""`
-	Concerns                 []Concern        `sql:""`
-	GuestNetworks            []GuestNetwork   `sql:""`
-	GuestDisks               []DiskMountPoint `sql:""`
-	GuestIpStacks            []GuestIpStack   `sql:""`
-	SecureBoot               bool             `sql:""`
-	ToolsStatus              string           `sql:""`
-	ToolsRunningStatus       string           `sql:""`
-	ToolsVersionStatus       string           `sql:""`
-	DiskEnableUuid           bool             `sql:""`
-	NestedHVEnabled          bool             `sql:""`
+	Folder                   string            `sql:"d0,index(folder)"`
+	Host                     string            `sql:"d0,index(host)"`
+	ResourcePool             string            `sql:"d0,index(resourcePool)"`
+	RevisionValidated        int64             `sql:"d0,index(revisionValidated)"`
+	PolicyVersion            int               `sql:"d0,index(policyVersion)"`
+	UUID                     string            `sql:""`
+	Firmware                 string            `sql:""`
+	PowerState               string            `sql:""`
+	ConnectionState          string            `sql:""`
+	CpuAffinity              []int32           `sql:""`
+	CpuHotAddEnabled         bool              `sql:""`
+	CpuHotRemoveEnabled      bool              `sql:""`
+	MemoryHotAddEnabled      bool              `sql:""`
+	FaultToleranceEnabled    bool              `sql:""`
+	CpuCount                 int32             `sql:""`
+	CoresPerSocket           int32             `sql:""`
+	MemoryMB                 int32             `sql:""`
+	GuestName                string            `sql:""`
+	GuestNameFromVmwareTools string            `sql:""`
+	HostName                 string            `sql:""`
+	GuestID                  string            `sql:""`
+	BalloonedMemory          int32             `sql:""`
+	IpAddress                string            `sql:""`
+	NumaNodeAffinity         []string          `sql:""`
+	StorageUsed              int64             `sql:""`
+	Snapshot                 Ref               `sql:""`
+	IsTemplate               bool              `sql:""`
+	ChangeTrackingEnabled    bool              `sql:""`
+	TpmEnabled               bool              `sql:""`
+	Devices                  []Device          `sql:""`
+	NICs                     []NIC             `sql:""`
+	Disks                    []Disk            `sql:""`
+	Controllers              []Controller      `sql:""`
+	Networks                 []Ref             `sql:""`
+	Concerns                 []Concern         `sql:""`
+	GuestNetworks            []GuestNetwork    `sql:""`
+	GuestDisks               []DiskMountPoint  `sql:""`
+	GuestIpStacks            []GuestIpStack    `sql:""`
+	SecureBoot               bool              `sql:""`
+	ToolsStatus              string            `sql:""`
+	ToolsRunningStatus       string            `sql:""`
+	ToolsVersionStatus       string            `sql:""`
+	DiskEnableUuid           bool              `sql:""`
+	NestedHVEnabled          bool              `sql:""`
+	Tags                     []Tag             `sql:""`
+	CustomAttributes         []CustomAttribute `sql:""`
 }
 
 // Determine if current revision has been validated.
@@ -341,6 +351,31 @@ func (m *VM) Validated() bool {
 	return m.RevisionValidated == m.Revision
 }
 
+// Get labels.
+// The tags (category=tag) and the custom attributes (name=value).
+// The tags of a multiple cardinality category are joined by a comma.
+// A tag category takes precedence over an attribute of the same name.
+func (m *VM) Labels() libmodel.Labels {
+	if len(m.Tags) == 0 && len(m.CustomAttributes) == 0 {
+		return nil
+	}
+	labels := libmodel.Labels{}
+	for _, attribute := range m.CustomAttributes {
+		if attribute.Name != "" {
+			labels[attribute.Name] = attribute.Value
+		}
+	}
+	tags := map[string][]string{}
+	for _, tag := range m.Tags {
+		tags[tag.Category] = append(tags[tag.Category], tag.Name)
+	}
+	for category, names := range tags {
+		sort.Strings(names)
+		labels[category] = strings.Join(names, ",")
+	}
+	return labels
+}
+
 // Virtual Controller.
 type Controller struct {
 	Key   int32   `json:"key"`
@@ -379,6 +414,20 @@ type NIC struct {
 	DeviceKey int32  `json:"deviceKey"`
 }
 
+// Tag (attached).
+type Tag struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// Custom attribute (value).
+type CustomAttribute struct {
+	Key   int32  `json:"key"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 // Guest network.
 type GuestNetwork struct {
 	Device         string   `json:"device"`
@@ -13,6 +13,7 @@ var (
 	HostKind       = libref.ToKind(Host{})
 	NetKind        = libref.ToKind(Network{})
 	DsKind         = libref.ToKind(Datastore{})
+	PoolKind       = libref.ToKind(ResourcePool{})
 	VmKind         = libref.ToKind(VM{})
 )
 
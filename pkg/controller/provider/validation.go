@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -100,6 +101,7 @@ func (r *Reconciler) validate(provider *api.Provider) error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	r.validateSettings(provider)
 	secret, err := r.validateSecret(provider)
 	if err != nil {
 		return liberr.Wrap(err)
@@ -220,6 +222,23 @@ func (r *Reconciler) validateURL(provider *api.Provider) error {
 	return nil
 }
 
+// Validate settings.
+func (r *Reconciler) validateSettings(provider *api.Provider) {
+	if setting, found := provider.Spec.Settings[api.TransferRateLimit]; found {
+		if limit, err := strconv.Atoi(setting); err != nil || limit < 0 {
+			provider.Status.Phase = ValidationFailed
+			provider.Status.SetCondition(
+				libcnd.Condition{
+					Type:     SettingsNotValid,
+					Status:   True,
+					Reason:   Malformed,
+					Category: Critical,
+					Message:  "The `transferRateLimit` setting must be a non-negative number of MB/s.",
+				})
+		}
+	}
+}
+
 func (r *Reconciler) validateConnectionStatus(provider *api.Provider, secret *core.Secret, insecureSkipVerify bool) {
 	if insecureSkipVerify {
 		provider.Status.SetCondition(libcnd.Condition{
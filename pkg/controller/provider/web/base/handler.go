@@ -1,6 +1,7 @@
 package base
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -53,6 +54,7 @@ type Handler struct {
 	libweb.Parity
 	libweb.Watched
 	libweb.Paged
+	libweb.Queried
 	// Container
 	Container *libcontainer.Container
 	// Provider referenced in the request.
@@ -73,6 +75,10 @@ func (h *Handler) Prepare(ctx *gin.Context) (int, error) {
 	if status != http.StatusOK {
 		return status, nil
 	}
+	status = h.Queried.Prepare(ctx)
+	if status != http.StatusOK {
+		return status, nil
+	}
 	status = h.setDetail(ctx)
 	if status != http.StatusOK {
 		return status, nil
@@ -89,6 +95,27 @@ func (h *Handler) Prepare(ctx *gin.Context) (int, error) {
 	return http.StatusOK, nil
 }
 
+// Reply with the content of a collection.
+// The query is applied to the content.
+func (h *Handler) ReplyList(ctx *gin.Context, content []interface{}) {
+	content, err := h.Apply(content)
+	if err != nil {
+		if errors.Is(err, libweb.QueryErr) {
+			ctx.Status(http.StatusBadRequest)
+			SetForkliftError(ctx, err)
+		} else {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			ctx.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
 // Build link.
 func (h *Handler) Link(path string, params Params) string {
 	return Link(path, params)
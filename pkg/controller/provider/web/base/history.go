@@ -0,0 +1,56 @@
+package base
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	HistoryCollection = "history"
+)
+
+// Reply with the change history of a model.
+// The history is kept for deleted models and is
+// empty when not enabled.
+func (h *Handler) ReplyHistory(ctx *gin.Context, m libmodel.Model) {
+	list, err := libmodel.ListHistory(h.Collector.DB(), m)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for i := range list {
+		r := &History{}
+		r.With(&list[i])
+		content = append(content, r)
+	}
+
+	h.Page.Slice(&content)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Change history REST resource.
+type History struct {
+	// Action: created|updated|deleted.
+	Action string `json:"action"`
+	// Time of the change.
+	Time time.Time `json:"time"`
+	// Changed fields.
+	Changes []libmodel.Change `json:"changes,omitempty"`
+}
+
+// Build the resource using the model.
+func (r *History) With(m *libmodel.History) {
+	r.Action = m.Action
+	r.Time = time.Unix(0, m.Time).UTC()
+	r.Changes = m.Changes
+}
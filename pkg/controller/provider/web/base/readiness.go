@@ -0,0 +1,335 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// Routes.
+const (
+	ReadinessCollection = "readiness"
+	ThroughputParam     = "throughput"
+)
+
+// Default transfer throughput (MB/s) used to
+// estimate the transfer duration when no transfer
+// from the provider has been completed.
+const DefaultThroughput = 100
+
+// Disk transfer steps of the VM pipeline.
+const (
+	DiskTransfer    = "DiskTransfer"
+	DiskTransferV2v = "DiskTransferV2v"
+)
+
+// Default throughput measured by the completed migrations.
+var Measured = &MeasuredThroughput{TTL: time.Minute * 5}
+
+// Complexity.
+const (
+	ComplexityLow     = "Low"
+	ComplexityMedium  = "Medium"
+	ComplexityHigh    = "High"
+	ComplexityBlocked = "Blocked"
+)
+
+// Concern categories.
+const (
+	ConcernCritical = "Critical"
+	ConcernWarning  = "Warning"
+)
+
+// Score penalties.
+const (
+	// Per warning concern.
+	WarningPenalty    = 10
+	MaxWarningPenalty = 40
+	// Per disk above DiskThreshold.
+	DiskThreshold   = 4
+	DiskPenalty     = 5
+	MaxDiskPenalty  = 15
+	NICThreshold    = 2
+	NICPenalty      = 5
+	MaxNICPenalty   = 10
+	LargeTransfer   = int64(1) << 40
+	LargePenalty    = 15
+	MediumTransfer  = int64(250) << 30
+	MediumPenalty   = 5
+	TrackingPenalty = 5
+	ToolsPenalty    = 10
+)
+
+// Readiness assessment of a VM.
+// Built by the providers from the inventory.
+type Assessment struct {
+	// Policy concerns.
+	Concerns []base.Concern
+	// Number of disks.
+	Disks int
+	// Number of NICs.
+	NICs int
+	// Bytes to transfer.
+	TransferSize int64
+	// Change block tracking enabled.
+	// Nil when not applicable.
+	ChangeTracking *bool
+	// Guest tools (agent) running.
+	// Nil when not applicable.
+	GuestTools *bool
+	// Throughput (MB/s).
+	Throughput int64
+}
+
+// Score the VM.
+// The score starts at 100 and each factor subtracts a penalty.
+// Critical concerns are blocking and the score is 0.
+func (r *Assessment) Readiness() (readiness Readiness) {
+	readiness = Readiness{
+		Score:        100,
+		Blocking:     []string{},
+		Warnings:     []string{},
+		Factors:      []Factor{},
+		TransferSize: r.TransferSize,
+		Throughput:   r.Throughput,
+	}
+	if r.Throughput > 0 {
+		readiness.EstimatedSeconds = (r.TransferSize >> 20) / r.Throughput
+	}
+	penalize := func(name string, penalty int, detail string) {
+		if penalty <= 0 {
+			return
+		}
+		readiness.Score -= penalty
+		readiness.Factors = append(
+			readiness.Factors,
+			Factor{
+				Name:    name,
+				Penalty: penalty,
+				Detail:  detail,
+			})
+	}
+	for _, concern := range r.Concerns {
+		switch concern.Category {
+		case ConcernCritical:
+			readiness.Blocking = append(readiness.Blocking, concern.Label)
+		case ConcernWarning:
+			readiness.Warnings = append(readiness.Warnings, concern.Label)
+		}
+	}
+	penalize(
+		"Warnings",
+		min(len(readiness.Warnings)*WarningPenalty, MaxWarningPenalty),
+		fmt.Sprintf("%d warning concerns.", len(readiness.Warnings)))
+	penalize(
+		"Disks",
+		min((r.Disks-DiskThreshold)*DiskPenalty, MaxDiskPenalty),
+		fmt.Sprintf("%d disks.", r.Disks))
+	penalize(
+		"NICs",
+		min((r.NICs-NICThreshold)*NICPenalty, MaxNICPenalty),
+		fmt.Sprintf("%d NICs.", r.NICs))
+	switch {
+	case r.TransferSize > LargeTransfer:
+		penalize("TransferSize", LargePenalty, "More than 1TiB to transfer.")
+	case r.TransferSize > MediumTransfer:
+		penalize("TransferSize", MediumPenalty, "More than 250GiB to transfer.")
+	}
+	if r.ChangeTracking != nil && !*r.ChangeTracking {
+		penalize("ChangeTracking", TrackingPenalty, "Change block tracking disabled, warm migration not possible.")
+	}
+	if r.GuestTools != nil && !*r.GuestTools {
+		penalize("GuestTools", ToolsPenalty, "Guest tools not running.")
+	}
+	switch {
+	case len(readiness.Blocking) > 0:
+		readiness.Score = 0
+		readiness.Complexity = ComplexityBlocked
+	case readiness.Score >= 80:
+		readiness.Complexity = ComplexityLow
+	case readiness.Score >= 50:
+		readiness.Complexity = ComplexityMedium
+	default:
+		readiness.Complexity = ComplexityHigh
+	}
+	return
+}
+
+// Throughput (MB/s) passed in the request.
+// Defaults to the throughput measured by the completed
+// migrations from the provider, otherwise DefaultThroughput.
+func (h *Handler) Throughput(ctx *gin.Context) (throughput int64, status int) {
+	status = http.StatusOK
+	s := ctx.Request.URL.Query().Get(ThroughputParam)
+	if s == "" {
+		throughput = DefaultThroughput
+		if h.Provider != nil {
+			if measured := Measured.Get(h.Provider); measured > 0 {
+				throughput = measured
+			}
+		}
+		return
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 1 {
+		status = http.StatusBadRequest
+		return
+	}
+	throughput = n
+	return
+}
+
+// Readiness score factor.
+type Factor struct {
+	// Factor name.
+	Name string `json:"name"`
+	// Subtracted from the score.
+	Penalty int `json:"penalty"`
+	// Detail.
+	Detail string `json:"detail"`
+}
+
+// Readiness REST resource.
+type Readiness struct {
+	// VM ID.
+	ID string `json:"id"`
+	// VM name.
+	Name string `json:"name"`
+	// Score 0-100, 100 is ready.
+	Score int `json:"score"`
+	// Complexity: Low|Medium|High|Blocked.
+	Complexity string `json:"complexity"`
+	// Blocking (critical) concerns.
+	Blocking []string `json:"blocking"`
+	// Warning concerns.
+	Warnings []string `json:"warnings"`
+	// Score factors.
+	Factors []Factor `json:"factors"`
+	// Estimated bytes to transfer.
+	TransferSize int64 `json:"transferSize"`
+	// Throughput (MB/s) used for the estimate.
+	Throughput int64 `json:"throughput"`
+	// Estimated transfer duration (seconds).
+	EstimatedSeconds int64 `json:"estimatedSeconds"`
+	// VM link.
+	SelfLink string `json:"selfLink"`
+}
+
+// Estimated transfer duration.
+func (r *Readiness) EstimatedDuration() time.Duration {
+	return time.Duration(r.EstimatedSeconds) * time.Second
+}
+
+// Measure the disk transfer throughput (MB/s) of the
+// completed migrations of VMs from the provider.
+// Returns 0 when no transfer has been completed.
+func MeasureThroughput(plans []api.Plan, provider core.ObjectReference) (throughput int64) {
+	var bytes int64
+	var elapsed time.Duration
+	for i := range plans {
+		plan := &plans[i]
+		source := plan.Spec.Provider.Source
+		if source.Namespace != provider.Namespace || source.Name != provider.Name {
+			continue
+		}
+		for _, vm := range plan.Status.Migration.VMs {
+			for _, step := range vm.Pipeline {
+				if step.Name != DiskTransfer && step.Name != DiskTransferV2v {
+					continue
+				}
+				if !step.MarkedCompleted() || step.HasError() || step.Started == nil {
+					continue
+				}
+				for _, task := range step.Tasks {
+					if task.Annotations["unit"] == "MB" {
+						bytes += task.Progress.Completed << 20
+					}
+				}
+				elapsed += step.Completed.Sub(step.Started.Time)
+			}
+		}
+	}
+	seconds := int64(elapsed / time.Second)
+	if seconds > 0 {
+		throughput = (bytes >> 20) / seconds
+	}
+	return
+}
+
+// Throughput measured by the completed migrations.
+// The plans are listed at most once per TTL.
+type MeasuredThroughput struct {
+	// k8s API reader.
+	Reader client.Reader
+	// Time the measurement is kept.
+	TTL time.Duration
+	// Mutex.
+	mutex sync.Mutex
+	// Throughput (MB/s) by source provider.
+	throughput map[core.ObjectReference]int64
+	// Time measured.
+	measured time.Time
+}
+
+// Throughput (MB/s) of the completed migrations from the
+// provider. Returns 0 when unknown.
+func (r *MeasuredThroughput) Get(provider *api.Provider) (throughput int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.measured.IsZero() || time.Since(r.measured) > r.TTL {
+		err := r.measure()
+		if err != nil {
+			log.Error(err, "Could not list the plans (throughput not measured).")
+		}
+		r.measured = time.Now()
+	}
+	throughput = r.throughput[core.ObjectReference{
+		Namespace: provider.Namespace,
+		Name:      provider.Name,
+	}]
+	return
+}
+
+// Measure the throughput of the source providers of the plans.
+func (r *MeasuredThroughput) measure() (err error) {
+	if r.Reader == nil {
+		cfg, cErr := config.GetConfig()
+		if cErr != nil {
+			err = cErr
+			return
+		}
+		r.Reader, err = client.New(
+			cfg,
+			client.Options{
+				Scheme: scheme.Scheme,
+			})
+		if err != nil {
+			return
+		}
+	}
+	list := &api.PlanList{}
+	err = r.Reader.List(context.TODO(), list)
+	if err != nil {
+		return
+	}
+	r.throughput = make(map[core.ObjectReference]int64)
+	for i := range list.Items {
+		source := list.Items[i].Spec.Provider.Source
+		provider := core.ObjectReference{Namespace: source.Namespace, Name: source.Name}
+		if _, found := r.throughput[provider]; !found {
+			r.throughput[provider] = MeasureThroughput(list.Items, provider)
+		}
+	}
+	return
+}
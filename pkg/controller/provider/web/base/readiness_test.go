@@ -0,0 +1,144 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAssessmentReadiness(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name       string
+		assessment Assessment
+		score      int
+		complexity string
+		seconds    int64
+	}{
+		{
+			name:       "ready",
+			assessment: Assessment{Disks: 1, NICs: 1, TransferSize: 10 << 30, Throughput: 100},
+			score:      100,
+			complexity: ComplexityLow,
+			seconds:    102,
+		},
+		{
+			name: "warnings and size",
+			assessment: Assessment{
+				Concerns: []base.Concern{
+					{Category: ConcernWarning, Label: "w1"},
+					{Category: ConcernWarning, Label: "w2"},
+					{Category: "Information", Label: "i1"},
+				},
+				Disks:          6,
+				NICs:           3,
+				TransferSize:   300 << 30,
+				ChangeTracking: &disabled,
+				Throughput:     100,
+			},
+			score:      55,
+			complexity: ComplexityMedium,
+			seconds:    3072,
+		},
+		{
+			name: "capped penalties",
+			assessment: Assessment{
+				Concerns: []base.Concern{
+					{Category: ConcernWarning}, {Category: ConcernWarning},
+					{Category: ConcernWarning}, {Category: ConcernWarning},
+					{Category: ConcernWarning},
+				},
+				Disks:      20,
+				GuestTools: &disabled,
+			},
+			score:      35,
+			complexity: ComplexityHigh,
+		},
+		{
+			name: "blocked",
+			assessment: Assessment{
+				Concerns: []base.Concern{{Category: ConcernCritical, Label: "c1"}},
+			},
+			score:      0,
+			complexity: ComplexityBlocked,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.assessment.Readiness()
+			if got.Score != tt.score {
+				t.Errorf("score = %d, want %d (%v)", got.Score, tt.score, got.Factors)
+			}
+			if got.Complexity != tt.complexity {
+				t.Errorf("complexity = %s, want %s", got.Complexity, tt.complexity)
+			}
+			if got.EstimatedSeconds != tt.seconds {
+				t.Errorf("seconds = %d, want %d", got.EstimatedSeconds, tt.seconds)
+			}
+		})
+	}
+}
+
+func TestMeasuredThroughput(t *testing.T) {
+	newPlan := func(name string, mb int64, elapsed time.Duration) *api.Plan {
+		started := meta.NewTime(time.Now().Add(-elapsed))
+		completed := meta.NewTime(started.Add(elapsed))
+		p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: name}}
+		p.Spec.Provider.Source = core.ObjectReference{Namespace: "ns", Name: "vcenter"}
+		p.Status.Migration.VMs = []*plan.VMStatus{
+			{
+				Pipeline: []*plan.Step{
+					{
+						Task: plan.Task{
+							Name:  DiskTransfer,
+							Timed: plan.Timed{Started: &started, Completed: &completed},
+						},
+						Tasks: []*plan.Task{
+							{
+								Annotations: map[string]string{"unit": "MB"},
+								Progress:    libitr.Progress{Completed: mb},
+							},
+						},
+					},
+				},
+			},
+		}
+		return p
+	}
+	scheme := runtime.NewScheme()
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	reader := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newPlan("p1", 20000, time.Second*100)).
+		Build()
+	measured := &MeasuredThroughput{Reader: reader, TTL: time.Hour}
+	provider := &api.Provider{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "vcenter"}}
+	if got := measured.Get(provider); got != 200 {
+		t.Fatalf("throughput = %d, want 200", got)
+	}
+	// Kept until the TTL expires.
+	err := reader.Create(context.TODO(), newPlan("p2", 0, time.Second*100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := measured.Get(provider); got != 200 {
+		t.Errorf("throughput = %d, want 200 (cached)", got)
+	}
+	measured.TTL = 0
+	if got := measured.Get(provider); got != 100 {
+		t.Errorf("throughput = %d, want 100", got)
+	}
+	other := &api.Provider{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "other"}}
+	if got := measured.Get(other); got != 0 {
+		t.Errorf("throughput = %d, want 0", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/search"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
 	"github.com/kubev2v/forklift/pkg/lib/inventory/container"
 	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
@@ -44,5 +45,8 @@ func All(container *container.Container) (all []libweb.RequestHandler) {
 	all = append(
 		all,
 		hyperv.Handlers(container)...)
+	all = append(
+		all,
+		search.Handlers(container)...)
 	return
 }
@@ -61,9 +61,9 @@ func (h ClusterInstanceHandler) List(ctx *gin.Context) {
 		r.Link(h.Provider)
 		content = append(content, r.Content(h.Detail))
 	}
-	h.Page.Slice(&content)
+	h.DeferPage(&h.Page)
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
@@ -59,7 +59,7 @@ func (h DataVolumeHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
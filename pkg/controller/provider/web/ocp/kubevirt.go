@@ -58,9 +58,9 @@ func (h KubeVirtHandler) List(ctx *gin.Context) {
 		r.Link(h.Provider)
 		content = append(content, r.Content(h.Detail))
 	}
-	h.Page.Slice(&content)
+	h.DeferPage(&h.Page)
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
@@ -58,7 +58,7 @@ func (h PersistentVolumeClaimHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
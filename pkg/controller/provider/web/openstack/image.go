@@ -102,7 +102,9 @@ func (h ImageHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.Image{}
-	err = db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Pushdown(&options, &model.Image{})
+	err = db.List(&list, options)
 	if err != nil {
 		log.Trace(
 			err,
@@ -119,7 +121,7 @@ func (h ImageHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
@@ -59,7 +59,9 @@ func (h NetworkHandler) List(ctx *gin.Context) {
 	}()
 	db := h.Collector.DB()
 	list := []model.Network{}
-	err = db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Pushdown(&options, &model.Network{})
+	err = db.List(&list, options)
 	if err != nil {
 		return
 	}
@@ -77,7 +79,7 @@ func (h NetworkHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
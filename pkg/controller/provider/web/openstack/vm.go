@@ -15,10 +15,11 @@ import (
 
 // Routes.
 const (
-	VMParam      = "vm"
-	VMCollection = "vms"
-	VMsRoot      = ProviderRoot + "/" + VMCollection
-	VMRoot       = VMsRoot + "/:" + VMParam
+	VMParam       = "vm"
+	VMCollection  = "vms"
+	VMsRoot       = ProviderRoot + "/" + VMCollection
+	VMRoot        = VMsRoot + "/:" + VMParam
+	VMHistoryRoot = VMRoot + "/" + base.HistoryCollection
 )
 
 // Virtual Machine handler.
@@ -31,6 +32,7 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 	e.GET(VMsRoot, h.List)
 	e.GET(VMsRoot+"/", h.List)
 	e.GET(VMRoot, h.Get)
+	e.GET(VMHistoryRoot, h.History)
 }
 
 // List resources in a REST collection.
@@ -61,7 +63,9 @@ func (h VMHandler) List(ctx *gin.Context) {
 	}()
 	db := h.Collector.DB()
 	list := []model.VM{}
-	err = db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Pushdown(&options, &model.VM{})
+	err = db.List(&list, options)
 	if err != nil {
 		return
 	}
@@ -79,7 +83,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
@@ -122,6 +126,22 @@ func (h VMHandler) Get(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, content)
 }
 
+// Get the change history of a specific VM.
+func (h VMHandler) History(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	m := &model.VM{
+		Base: model.Base{
+			ID: ctx.Param(VMParam),
+		},
+	}
+	h.ReplyHistory(ctx, m)
+}
+
 // Watch.
 func (h *VMHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
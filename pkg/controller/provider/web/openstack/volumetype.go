@@ -58,7 +58,9 @@ func (h VolumeTypeHandler) List(ctx *gin.Context) {
 	}()
 	db := h.Collector.DB()
 	list := []model.VolumeType{}
-	err = db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Pushdown(&options, &model.VolumeType{})
+	err = db.List(&list, options)
 	if err != nil {
 		return
 	}
@@ -76,7 +78,7 @@ func (h VolumeTypeHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
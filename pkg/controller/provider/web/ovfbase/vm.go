@@ -29,6 +29,7 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 	e.GET(root, h.List)
 	e.GET(root+"/", h.List)
 	e.GET(root+"/:"+VMParam, h.Get)
+	e.GET(root+"/:"+VMParam+"/"+base.HistoryCollection, h.History)
 }
 
 // List resources in a REST collection.
@@ -57,7 +58,9 @@ func (h VMHandler) List(ctx *gin.Context) {
 	}()
 	db := h.Collector.DB()
 	list := []model.VM{}
-	err = db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Pushdown(&options, &model.VM{})
+	err = db.List(&list, options)
 	if err != nil {
 		return
 	}
@@ -75,7 +78,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
@@ -115,6 +118,22 @@ func (h VMHandler) Get(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, content)
 }
 
+// Get the change history of a specific VM.
+func (h VMHandler) History(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	m := &model.VM{
+		Base: model.Base{
+			ID: ctx.Param(VMParam),
+		},
+	}
+	h.ReplyHistory(ctx, m)
+}
+
 // Watch.
 func (h *VMHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
@@ -48,7 +48,9 @@ func (h DataCenterHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.DataCenter{}
-	err = db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Pushdown(&options, &model.DataCenter{})
+	err = db.List(&list, options)
 	if err != nil {
 		log.Trace(
 			err,
@@ -67,7 +69,7 @@ func (h DataCenterHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
@@ -55,7 +55,9 @@ func (h DiskHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.Disk{}
-	err = db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Pushdown(&options, &model.Disk{})
+	err = db.List(&list, options)
 	if err != nil {
 		log.Trace(
 			err,
@@ -72,7 +74,7 @@ func (h DiskHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
@@ -48,7 +48,9 @@ func (h DiskProfileHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.DiskProfile{}
-	err = db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Pushdown(&options, &model.DiskProfile{})
+	err = db.List(&list, options)
 	if err != nil {
 		log.Trace(
 			err,
@@ -65,7 +67,7 @@ func (h DiskProfileHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
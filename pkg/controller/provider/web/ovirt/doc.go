@@ -80,5 +80,10 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&ReadinessHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 	}
 }
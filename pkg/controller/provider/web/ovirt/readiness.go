@@ -0,0 +1,171 @@
+package ovirt
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	ReadinessRoot   = ProviderRoot + "/" + base.ReadinessCollection
+	VMReadinessRoot = VMRoot + "/" + base.ReadinessCollection
+)
+
+// VM status up.
+const StatusUp = "up"
+
+// VM readiness handler.
+type ReadinessHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *ReadinessHandler) AddRoutes(e *gin.Engine) {
+	e.GET(ReadinessRoot, h.List)
+	e.GET(ReadinessRoot+"/", h.List)
+	e.GET(VMReadinessRoot, h.Get)
+}
+
+// List the readiness of the VMs.
+func (h ReadinessHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.WatchRequest {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	throughput, status := h.Throughput(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			ctx.Status(http.StatusInternalServerError)
+		}
+	}()
+	db := h.Collector.DB()
+	list := []model.VM{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		return
+	}
+	content := []interface{}{}
+	for i := range list {
+		r := Readiness{}
+		err = r.With(db, &list[i], throughput)
+		if err != nil {
+			return
+		}
+		r.Link(h.Provider)
+		content = append(content, &r)
+	}
+
+	h.ReplyList(ctx, content)
+}
+
+// Get the readiness of a specific VM.
+func (h ReadinessHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	throughput, status := h.Throughput(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.VM{
+		Base: model.Base{
+			ID: ctx.Param(VMParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	r := Readiness{}
+	if err == nil {
+		err = r.With(db, m, throughput)
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r.Link(h.Provider)
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+// REST Resource.
+type Readiness base.Readiness
+
+// Build the resource using the model.
+// The transfer size is the allocated size of
+// the attached disks.
+func (r *Readiness) With(db libmodel.DB, m *model.VM, throughput int64) (err error) {
+	assessment := base.Assessment{
+		Concerns:   m.Concerns,
+		Disks:      len(m.DiskAttachments),
+		NICs:       len(m.NICs),
+		Throughput: throughput,
+	}
+	for _, da := range m.DiskAttachments {
+		disk := &model.Disk{
+			Base: model.Base{ID: da.Disk},
+		}
+		err = db.Get(disk)
+		if err != nil {
+			if errors.Is(err, model.NotFound) {
+				err = nil
+				continue
+			}
+			return
+		}
+		size := disk.ActualSize
+		if size <= 0 {
+			size = disk.ProvisionedSize
+		}
+		assessment.TransferSize += size
+	}
+	if m.Status == StatusUp {
+		running := m.Guest.Distribution != ""
+		assessment.GuestTools = &running
+	}
+	*r = Readiness(assessment.Readiness())
+	r.ID = m.ID
+	r.Name = m.Name
+	return
+}
+
+// Build self link (URI).
+func (r *Readiness) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		VMReadinessRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			VMParam:            r.ID,
+		})
+}
@@ -0,0 +1,33 @@
+// The search package provides a VM search across all
+// providers in the inventory. The VMs are indexed by name,
+// UUID, IP address, MAC address and guest host name. The
+// index is kept up to date by watching the VM model in the
+// DB of each collector.
+package search
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Package logger.
+var log = logging.WithName("web|search")
+
+// Routes.
+const (
+	Root = "search"
+)
+
+// Build all handlers.
+func Handlers(container *container.Container) []libweb.RequestHandler {
+	return []libweb.RequestHandler{
+		&VMHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Index: &Index{},
+		},
+	}
+}
@@ -0,0 +1,353 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Searched fields.
+const (
+	FieldName     = "name"
+	FieldUUID     = "uuid"
+	FieldIP       = "ip"
+	FieldMAC      = "mac"
+	FieldHostName = "hostname"
+)
+
+// All searched fields.
+var Fields = []string{
+	FieldName,
+	FieldUUID,
+	FieldIP,
+	FieldMAC,
+	FieldHostName,
+}
+
+// Indexed VM.
+type Entry struct {
+	// VM ID.
+	ID string
+	// VM name.
+	Name string
+	// BIOS (or instance) UUID.
+	UUID string
+	// IP addresses.
+	IPs []string
+	// MAC addresses.
+	MACs []string
+	// Guest host names.
+	HostNames []string
+}
+
+// Match the (lower case) text with the fields.
+// Returns the matched field values.
+func (r *Entry) Match(text string, fields []string) (matched []Matched) {
+	match := func(field, value string) {
+		if value == "" {
+			return
+		}
+		if strings.Contains(strings.ToLower(value), text) {
+			matched = append(
+				matched,
+				Matched{
+					Field: field,
+					Value: value,
+				})
+		}
+	}
+	for _, field := range fields {
+		switch field {
+		case FieldName:
+			match(field, r.Name)
+		case FieldUUID:
+			match(field, r.UUID)
+		case FieldIP:
+			for _, ip := range r.IPs {
+				match(field, ip)
+			}
+		case FieldMAC:
+			for _, mac := range r.MACs {
+				match(field, mac)
+			}
+		case FieldHostName:
+			for _, name := range r.HostNames {
+				match(field, name)
+			}
+		}
+	}
+	return
+}
+
+// Add an IP address.
+func (r *Entry) addIP(ip string) {
+	r.IPs = appendUnique(r.IPs, ip)
+}
+
+// Add a MAC address.
+// Normalized to lower case with colon separators.
+func (r *Entry) addMAC(mac string) {
+	mac = strings.ReplaceAll(strings.ToLower(mac), "-", ":")
+	r.MACs = appendUnique(r.MACs, mac)
+}
+
+// Add a host name.
+func (r *Entry) addHostName(name string) {
+	r.HostNames = appendUnique(r.HostNames, name)
+}
+
+// Matched field.
+type Matched struct {
+	// Field name.
+	Field string `json:"field"`
+	// Matched value.
+	Value string `json:"value"`
+}
+
+// VM search index.
+// Kept up to date by watching the VM model in the DB
+// of each collector in the container.
+type Index struct {
+	mutex sync.Mutex
+	// Indexed providers.
+	providers map[types.UID]*ProviderIndex
+}
+
+// Synchronize the indexed providers with the collectors.
+// The index of a provider is (re)built when the collector
+// is added, replaced or the watch has ended. The index of
+// a deleted provider is dropped.
+func (r *Index) Sync(collectors []libcontainer.Collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.providers == nil {
+		r.providers = make(map[types.UID]*ProviderIndex)
+	}
+	wanted := make(map[types.UID]bool)
+	for _, collector := range collectors {
+		provider, cast := collector.Owner().(*api.Provider)
+		if !cast {
+			continue
+		}
+		source, found := Sources[provider.Type()]
+		if !found {
+			continue
+		}
+		wanted[provider.UID] = true
+		current, found := r.providers[provider.UID]
+		if found {
+			if current.collector == collector && current.Alive() {
+				continue
+			}
+			current.Stop()
+			delete(r.providers, provider.UID)
+		}
+		index := &ProviderIndex{
+			Provider:  provider,
+			Source:    source,
+			collector: collector,
+		}
+		err := index.Start()
+		if err != nil {
+			log.Error(
+				err,
+				"search index not started.",
+				"provider",
+				provider.Namespace+"/"+provider.Name)
+			continue
+		}
+		r.providers[provider.UID] = index
+	}
+	for uid, index := range r.providers {
+		if !wanted[uid] {
+			index.Stop()
+			delete(r.providers, uid)
+		}
+	}
+}
+
+// List the indexed providers.
+// Filtered by namespace when specified.
+func (r *Index) List(namespace string) (list []*ProviderIndex) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, index := range r.providers {
+		if namespace != "" && namespace != index.Provider.Namespace {
+			continue
+		}
+		list = append(list, index)
+	}
+	sort.Slice(
+		list,
+		func(i, j int) bool {
+			pi := list[i].Provider
+			pj := list[j].Provider
+			if pi.Namespace != pj.Namespace {
+				return pi.Namespace < pj.Namespace
+			}
+			return pi.Name < pj.Name
+		})
+	return
+}
+
+// Index of the VMs of a provider.
+type ProviderIndex struct {
+	libmodel.StockEventHandler
+	// Provider.
+	Provider *api.Provider
+	// VM source.
+	Source Source
+	// Collector responsible for the provider.
+	collector libcontainer.Collector
+	// Model watch.
+	watch *libmodel.Watch
+	// Indexed VMs keyed by ID.
+	entries map[string]*Entry
+	// Closed when the initial snapshot has been indexed.
+	parity chan struct{}
+	// The watch has ended.
+	ended bool
+	mutex sync.RWMutex
+}
+
+// Start watching the VM model.
+func (r *ProviderIndex) Start() (err error) {
+	r.entries = make(map[string]*Entry)
+	r.parity = make(chan struct{})
+	r.watch, err = r.collector.DB().Watch(r.Source.Model(), r)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Stop watching the VM model.
+func (r *ProviderIndex) Stop() {
+	if r.watch != nil {
+		r.watch.End()
+	}
+}
+
+// The watch has not ended.
+func (r *ProviderIndex) Alive() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return !r.ended
+}
+
+// Wait for the initial snapshot to be indexed.
+// Returns false on timeout.
+func (r *ProviderIndex) WaitParity(timeout time.Duration) bool {
+	select {
+	case <-r.parity:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// The collector has achieved parity.
+func (r *ProviderIndex) HasParity() bool {
+	return r.collector.HasParity()
+}
+
+// Search the indexed VMs.
+func (r *ProviderIndex) Search(text string, fields []string) (found []VM) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, entry := range r.entries {
+		matched := entry.Match(text, fields)
+		if len(matched) == 0 {
+			continue
+		}
+		vm := VM{
+			ID:      entry.ID,
+			Name:    entry.Name,
+			Matched: matched,
+		}
+		vm.With(r.Provider, r.Source)
+		found = append(found, vm)
+	}
+	return
+}
+
+// Watch options.
+func (r *ProviderIndex) Options() libmodel.WatchOptions {
+	return libmodel.WatchOptions{
+		Snapshot: true,
+	}
+}
+
+// The snapshot has been indexed.
+func (r *ProviderIndex) Parity() {
+	close(r.parity)
+}
+
+// VM created.
+func (r *ProviderIndex) Created(event libmodel.Event) {
+	r.put(event.Model)
+}
+
+// VM updated.
+func (r *ProviderIndex) Updated(event libmodel.Event) {
+	r.put(event.Updated)
+}
+
+// VM deleted.
+func (r *ProviderIndex) Deleted(event libmodel.Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.entries, event.Model.Pk())
+}
+
+// Report errors.
+// An event may have been discarded so the watch is
+// ended and the index rebuilt on the next sync.
+func (r *ProviderIndex) Error(err error) {
+	log.Error(
+		liberr.Wrap(err),
+		"search index event failed.",
+		"provider",
+		r.Provider.Namespace+"/"+r.Provider.Name)
+	r.End()
+}
+
+// The watch has ended.
+// Includes the DB being closed when the
+// collector is replaced or deleted.
+func (r *ProviderIndex) End() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.ended = true
+}
+
+// Index a VM.
+func (r *ProviderIndex) put(m libmodel.Model) {
+	entry := r.Source.Entry(m)
+	if entry == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries[entry.ID] = entry
+}
+
+// Append a non-empty value not already in the list.
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,150 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	openstackmodel "github.com/kubev2v/forklift/pkg/controller/provider/model/openstack"
+	vspheremodel "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	ec2model "github.com/kubev2v/forklift/pkg/provider/ec2/inventory/model"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeCollector struct {
+	libcontainer.Collector
+	owner *api.Provider
+	db    libmodel.DB
+}
+
+func (r *fakeCollector) Owner() meta.Object {
+	return r.owner
+}
+
+func (r *fakeCollector) DB() libmodel.DB {
+	return r.db
+}
+
+func (r *fakeCollector) HasParity() bool {
+	return true
+}
+
+func TestEntry(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	entry := openStackEntry(&openstackmodel.VM{
+		Base:       openstackmodel.Base{ID: "8d5a4b7e", Name: "web"},
+		AccessIPv4: "10.0.0.5",
+		Addresses: map[string]interface{}{
+			"private": []interface{}{
+				map[string]interface{}{
+					"addr":                    "192.168.1.10",
+					"OS-EXT-IPS-MAC:mac_addr": "FA:16:3E:00:00:01",
+				},
+			},
+		},
+	})
+	g.Expect(entry.UUID).To(gomega.Equal("8d5a4b7e"))
+	g.Expect(entry.IPs).To(gomega.Equal([]string{"10.0.0.5", "192.168.1.10"}))
+	g.Expect(entry.MACs).To(gomega.Equal([]string{"fa:16:3e:00:00:01"}))
+
+	entry = ec2Entry(&ec2model.Instance{
+		Base: ec2model.Base{UID: "i-0abc", Name: "db"},
+		Object: ec2types.Instance{
+			PrivateDnsName:   aws.String("ip-10-0-0-7.ec2.internal"),
+			PrivateIpAddress: aws.String("10.0.0.7"),
+			NetworkInterfaces: []ec2types.InstanceNetworkInterface{
+				{
+					MacAddress: aws.String("0a:00:00:00:00:07"),
+					PrivateIpAddresses: []ec2types.InstancePrivateIpAddress{
+						{PrivateIpAddress: aws.String("10.0.0.7")},
+						{PrivateIpAddress: aws.String("10.0.0.8")},
+					},
+				},
+			},
+		},
+	})
+	g.Expect(entry.HostNames).To(gomega.Equal([]string{"ip-10-0-0-7.ec2.internal"}))
+	g.Expect(entry.IPs).To(gomega.Equal([]string{"10.0.0.7", "10.0.0.8"}))
+
+	g.Expect(entry.Match("0a:00", Fields)).To(gomega.Equal([]Matched{
+		{Field: FieldMAC, Value: "0a:00:00:00:00:07"},
+	}))
+	g.Expect(entry.Match("10.0.0.7", []string{FieldIP, FieldHostName})).To(gomega.HaveLen(1))
+	g.Expect(entry.Match("db", []string{FieldUUID})).To(gomega.BeEmpty())
+}
+
+func TestIndex(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := libmodel.New("/tmp/test-search.db", &vspheremodel.VM{})
+	err := db.Open(true)
+	g.Expect(err).To(gomega.BeNil())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	vm := &vspheremodel.VM{
+		Base:     vspheremodel.Base{ID: "vm-1", Name: "web-01"},
+		UUID:     "4206a2b3-0000-0000-0000-000000000001",
+		HostName: "web-01.example.com",
+		GuestNetworks: []vspheremodel.GuestNetwork{
+			{MAC: "00:50:56:aa:bb:01", IP: "10.1.0.15"},
+		},
+	}
+	err = db.Insert(vm)
+	g.Expect(err).To(gomega.BeNil())
+
+	vSphere := api.VSphere
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "vcenter", UID: "p1"},
+		Spec:       api.ProviderSpec{Type: &vSphere},
+	}
+	openShift := api.OpenShift
+	host := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "host", UID: "p2"},
+		Spec:       api.ProviderSpec{Type: &openShift},
+	}
+	collector := &fakeCollector{owner: provider, db: db}
+	index := &Index{}
+	index.Sync([]libcontainer.Collector{collector, &fakeCollector{owner: host}})
+	list := index.List("")
+	g.Expect(list).To(gomega.HaveLen(1))
+	g.Expect(list[0].WaitParity(time.Second)).To(gomega.BeTrue())
+	g.Expect(index.List("other")).To(gomega.BeEmpty())
+
+	// Snapshot.
+	found := list[0].Search("10.1.0.15", Fields)
+	g.Expect(found).To(gomega.HaveLen(1))
+	g.Expect(found[0].ID).To(gomega.Equal("vm-1"))
+	g.Expect(found[0].Provider.Name).To(gomega.Equal("vcenter"))
+	g.Expect(found[0].SelfLink).To(gomega.Equal("providers/vsphere/p1/vms/vm-1"))
+
+	// Events.
+	search := func(text string) func() []VM {
+		return func() []VM {
+			return list[0].Search(text, Fields)
+		}
+	}
+	vm.GuestNetworks[0].IP = "10.1.0.16"
+	err = db.Update(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Eventually(search("10.1.0.16")).Should(gomega.HaveLen(1))
+	g.Expect(search("10.1.0.15")()).To(gomega.BeEmpty())
+	err = db.Insert(&vspheremodel.VM{Base: vspheremodel.Base{ID: "vm-2", Name: "web-02"}})
+	g.Expect(err).To(gomega.BeNil())
+	g.Eventually(search("web-")).Should(gomega.HaveLen(2))
+	err = db.Delete(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Eventually(search("web-")).Should(gomega.HaveLen(1))
+
+	// Unchanged collector is kept, deleted collector is dropped.
+	index.Sync([]libcontainer.Collector{collector})
+	g.Expect(index.List("")).To(gomega.Equal(list))
+	index.Sync([]libcontainer.Collector{})
+	g.Expect(index.List("")).To(gomega.BeEmpty())
+	g.Eventually(list[0].Alive).Should(gomega.BeFalse())
+}
@@ -0,0 +1,198 @@
+package search
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	openstackmodel "github.com/kubev2v/forklift/pkg/controller/provider/model/openstack"
+	ovfmodel "github.com/kubev2v/forklift/pkg/controller/provider/model/ovf"
+	ovirtmodel "github.com/kubev2v/forklift/pkg/controller/provider/model/ovirt"
+	vspheremodel "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/hyperv"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	ec2model "github.com/kubev2v/forklift/pkg/provider/ec2/inventory/model"
+	ec2web "github.com/kubev2v/forklift/pkg/provider/ec2/inventory/web"
+)
+
+// Source of the indexed VMs for a provider type.
+type Source struct {
+	// The watched VM model.
+	Model func() libmodel.Model
+	// Build the entry for a VM model.
+	Entry func(m libmodel.Model) *Entry
+	// VM route used to build the links.
+	VMRoot string
+	// VM route parameter.
+	VMParam string
+}
+
+// Sources by provider type.
+// OpenShift is not indexed since its VMs are not
+// stored in the inventory DB.
+var Sources = map[api.ProviderType]Source{
+	api.VSphere: {
+		Model:   func() libmodel.Model { return &vspheremodel.VM{} },
+		Entry:   vSphereEntry,
+		VMRoot:  vsphere.VMRoot,
+		VMParam: vsphere.VMParam,
+	},
+	api.OVirt: {
+		Model:   func() libmodel.Model { return &ovirtmodel.VM{} },
+		Entry:   oVirtEntry,
+		VMRoot:  ovirt.VMRoot,
+		VMParam: ovirt.VMParam,
+	},
+	api.OpenStack: {
+		Model:   func() libmodel.Model { return &openstackmodel.VM{} },
+		Entry:   openStackEntry,
+		VMRoot:  openstack.VMRoot,
+		VMParam: openstack.VMParam,
+	},
+	api.Ova: {
+		Model:   func() libmodel.Model { return &ovfmodel.VM{} },
+		Entry:   ovfEntry,
+		VMRoot:  ova.VMRoot,
+		VMParam: ova.VMParam,
+	},
+	api.HyperV: {
+		Model:   func() libmodel.Model { return &ovfmodel.VM{} },
+		Entry:   ovfEntry,
+		VMRoot:  hyperv.VMRoot,
+		VMParam: hyperv.VMParam,
+	},
+	api.EC2: {
+		Model:   func() libmodel.Model { return &ec2model.Instance{} },
+		Entry:   ec2Entry,
+		VMRoot:  ec2web.VMRoot,
+		VMParam: ec2web.VMParam,
+	},
+}
+
+// vSphere VM.
+func vSphereEntry(m libmodel.Model) (entry *Entry) {
+	vm, cast := m.(*vspheremodel.VM)
+	if !cast {
+		return
+	}
+	entry = &Entry{
+		ID:   vm.ID,
+		Name: vm.Name,
+		UUID: vm.UUID,
+	}
+	entry.addHostName(vm.HostName)
+	entry.addIP(vm.IpAddress)
+	for _, nic := range vm.NICs {
+		entry.addMAC(nic.MAC)
+	}
+	for _, network := range vm.GuestNetworks {
+		entry.addIP(network.IP)
+		entry.addMAC(network.MAC)
+	}
+	return
+}
+
+// oVirt VM.
+// The VM ID is a UUID.
+func oVirtEntry(m libmodel.Model) (entry *Entry) {
+	vm, cast := m.(*ovirtmodel.VM)
+	if !cast {
+		return
+	}
+	entry = &Entry{
+		ID:   vm.ID,
+		Name: vm.Name,
+		UUID: vm.ID,
+	}
+	for _, nic := range vm.NICs {
+		entry.addMAC(nic.MAC)
+		for _, ip := range nic.IpAddress {
+			entry.addIP(ip.Address)
+		}
+	}
+	return
+}
+
+// OpenStack VM.
+// The VM ID is a UUID. The addresses are keyed by network
+// and reported by nova as a list of objects.
+func openStackEntry(m libmodel.Model) (entry *Entry) {
+	vm, cast := m.(*openstackmodel.VM)
+	if !cast {
+		return
+	}
+	entry = &Entry{
+		ID:   vm.ID,
+		Name: vm.Name,
+		UUID: vm.ID,
+	}
+	entry.addIP(vm.AccessIPv4)
+	entry.addIP(vm.AccessIPv6)
+	for _, addresses := range vm.Addresses {
+		list, cast := addresses.([]interface{})
+		if !cast {
+			continue
+		}
+		for _, address := range list {
+			object, cast := address.(map[string]interface{})
+			if !cast {
+				continue
+			}
+			if ip, cast := object["addr"].(string); cast {
+				entry.addIP(ip)
+			}
+			if mac, cast := object["OS-EXT-IPS-MAC:mac_addr"].(string); cast {
+				entry.addMAC(mac)
+			}
+		}
+	}
+	return
+}
+
+// OVA and HyperV VM.
+func ovfEntry(m libmodel.Model) (entry *Entry) {
+	vm, cast := m.(*ovfmodel.VM)
+	if !cast {
+		return
+	}
+	entry = &Entry{
+		ID:   vm.ID,
+		Name: vm.Name,
+		UUID: vm.UUID,
+	}
+	entry.addIP(vm.IpAddress)
+	for _, nic := range vm.NICs {
+		entry.addMAC(nic.MAC)
+	}
+	return
+}
+
+// EC2 instance.
+// The DNS names are indexed as host names.
+func ec2Entry(m libmodel.Model) (entry *Entry) {
+	instance, cast := m.(*ec2model.Instance)
+	if !cast {
+		return
+	}
+	entry = &Entry{
+		ID:   instance.UID,
+		Name: instance.Name,
+	}
+	object := instance.Object
+	entry.addHostName(aws.ToString(object.PrivateDnsName))
+	entry.addHostName(aws.ToString(object.PublicDnsName))
+	entry.addIP(aws.ToString(object.PrivateIpAddress))
+	entry.addIP(aws.ToString(object.PublicIpAddress))
+	for _, nic := range object.NetworkInterfaces {
+		entry.addMAC(aws.ToString(nic.MacAddress))
+		for _, ip := range nic.PrivateIpAddresses {
+			entry.addIP(aws.ToString(ip.PrivateIpAddress))
+		}
+		for _, ip := range nic.Ipv6Addresses {
+			entry.addIP(aws.ToString(ip.Ipv6Address))
+		}
+	}
+	return
+}
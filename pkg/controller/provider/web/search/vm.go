@@ -0,0 +1,157 @@
+package search
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Routes.
+const (
+	TextParam  = "q"
+	FieldParam = "field"
+	VMsRoot    = Root + "/vms"
+)
+
+// VM search handler.
+type VMHandler struct {
+	base.Handler
+	// Search index.
+	Index *Index
+}
+
+// Add routes to the `gin` router.
+func (h *VMHandler) AddRoutes(e *gin.Engine) {
+	e.GET(VMsRoot, h.List)
+	e.GET(VMsRoot+"/", h.List)
+}
+
+// Search VMs across providers.
+// Responds with 206 (partial content) when the inventory
+// of a searched provider has not achieved parity.
+func (h VMHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.WatchRequest {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	q := ctx.Request.URL.Query()
+	text := strings.ToLower(strings.TrimSpace(q.Get(TextParam)))
+	if text == "" {
+		ctx.Status(http.StatusBadRequest)
+		base.SetForkliftError(ctx, liberr.New("search text (q) required."))
+		return
+	}
+	fields, err := h.fields(ctx)
+	if err != nil {
+		ctx.Status(http.StatusBadRequest)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	h.Index.Sync(h.Container.List())
+	status = http.StatusOK
+	content := []interface{}{}
+	deadline := time.Now().Add(time.Second * 10)
+	for _, index := range h.Index.List(q.Get(base.NsParam)) {
+		if !index.WaitParity(time.Until(deadline)) || !index.HasParity() {
+			status = http.StatusPartialContent
+		}
+		found := index.Search(text, fields)
+		sort.Slice(
+			found,
+			func(i, j int) bool {
+				return found[i].Name < found[j].Name
+			})
+		for i := range found {
+			content = append(content, &found[i])
+		}
+	}
+
+	h.Page.Slice(&content)
+
+	ctx.JSON(status, content)
+}
+
+// Searched fields.
+// Defaults to all fields.
+func (h *VMHandler) fields(ctx *gin.Context) (fields []string, err error) {
+	q := ctx.Request.URL.Query()
+	for _, p := range q[FieldParam] {
+		for _, field := range strings.Split(p, ",") {
+			field = strings.ToLower(strings.TrimSpace(field))
+			if field == "" {
+				continue
+			}
+			valid := false
+			for _, known := range Fields {
+				if field == known {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				err = liberr.New(
+					"unknown search field.",
+					"field",
+					field,
+					"expected",
+					Fields)
+				return
+			}
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		fields = Fields
+	}
+	return
+}
+
+// Provider reference.
+type Provider struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+	Type      string `json:"type"`
+}
+
+// REST resource.
+type VM struct {
+	// VM ID.
+	ID string `json:"id"`
+	// VM name.
+	Name string `json:"name"`
+	// Provider containing the VM.
+	Provider Provider `json:"provider"`
+	// Matched fields.
+	Matched []Matched `json:"matched"`
+	// VM resource link.
+	SelfLink string `json:"selfLink"`
+}
+
+// Set fields with the provider.
+func (r *VM) With(p *api.Provider, source Source) {
+	r.Provider = Provider{
+		Namespace: p.Namespace,
+		Name:      p.Name,
+		UID:       string(p.UID),
+		Type:      string(p.Type()),
+	}
+	r.SelfLink = base.Link(
+		source.VMRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			source.VMParam:     r.ID,
+		})
+}
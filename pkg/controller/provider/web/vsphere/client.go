@@ -57,6 +57,11 @@ func (r *Resolver) Path(resource interface{}, id string) (path string, err error
 		r.ID = id
 		r.Link(provider)
 		path = r.SelfLink
+	case *ResourcePool:
+		r := ResourcePool{}
+		r.ID = id
+		r.Link(provider)
+		path = r.SelfLink
 	case *VM:
 		r := VM{}
 		r.ID = id
@@ -67,6 +72,11 @@ func (r *Resolver) Path(resource interface{}, id string) (path string, err error
 		r.ID = id
 		r.Link(provider)
 		path = r.SelfLink
+	case *base.Readiness:
+		r := Readiness{}
+		r.ID = id
+		r.Link(provider)
+		path = r.SelfLink
 	default:
 		err = liberr.Wrap(
 			base.ResourceNotResolvedError{
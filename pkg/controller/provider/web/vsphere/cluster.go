@@ -67,6 +67,7 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 	} else {
 		options.Predicate = realCluster
 	}
+	h.Pushdown(&options, &model.Cluster{})
 	err = db.List(&list, options)
 	if err != nil {
 		return
@@ -85,7 +86,7 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
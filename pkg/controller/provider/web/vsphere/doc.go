@@ -56,6 +56,11 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&ResourcePoolHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 		&VMHandler{
 			Handler: Handler{
 				base.Handler{Container: container},
@@ -66,6 +71,11 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&ReadinessHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 	}
 
 	if settings.Settings.OpenShift {
@@ -0,0 +1,150 @@
+package vsphere
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Routes.
+const (
+	ReadinessRoot   = ProviderRoot + "/" + base.ReadinessCollection
+	VMReadinessRoot = VMRoot + "/" + base.ReadinessCollection
+)
+
+// VM tools running.
+const ToolsRunning = "guestToolsRunning"
+
+// VM readiness handler.
+type ReadinessHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *ReadinessHandler) AddRoutes(e *gin.Engine) {
+	e.GET(ReadinessRoot, h.List)
+	e.GET(ReadinessRoot+"/", h.List)
+	e.GET(VMReadinessRoot, h.Get)
+}
+
+// List the readiness of the VMs.
+// Templates are skipped.
+func (h ReadinessHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.WatchRequest {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	throughput, status := h.Throughput(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Collector.DB()
+	list := []model.VM{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for i := range list {
+		m := &list[i]
+		if m.IsTemplate {
+			continue
+		}
+		r := Readiness{}
+		r.With(m, throughput)
+		r.Link(h.Provider)
+		content = append(content, &r)
+	}
+
+	h.ReplyList(ctx, content)
+}
+
+// Get the readiness of a specific VM.
+func (h ReadinessHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	throughput, status := h.Throughput(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.VM{
+		Base: model.Base{
+			ID: ctx.Param(VMParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := Readiness{}
+	r.With(m, throughput)
+	r.Link(h.Provider)
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+// REST Resource.
+type Readiness base.Readiness
+
+// Build the resource using the model.
+func (r *Readiness) With(m *model.VM, throughput int64) {
+	assessment := base.Assessment{
+		Concerns:       m.Concerns,
+		Disks:          len(m.Disks),
+		NICs:           len(m.NICs),
+		ChangeTracking: &m.ChangeTrackingEnabled,
+		Throughput:     throughput,
+	}
+	for _, disk := range m.Disks {
+		assessment.TransferSize += disk.Capacity
+	}
+	if m.PowerState == string(types.VirtualMachinePowerStatePoweredOn) {
+		running := m.ToolsRunningStatus == ToolsRunning
+		assessment.GuestTools = &running
+	}
+	*r = Readiness(assessment.Readiness())
+	r.ID = m.ID
+	r.Name = m.Name
+}
+
+// Build self link (URI).
+func (r *Readiness) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		VMReadinessRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			VMParam:            r.ID,
+		})
+}
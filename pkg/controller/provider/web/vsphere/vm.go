@@ -14,10 +14,11 @@ import (
 
 // Routes.
 const (
-	VMParam      = "vm"
-	VMCollection = "vms"
-	VMsRoot      = ProviderRoot + "/" + VMCollection
-	VMRoot       = VMsRoot + "/:" + VMParam
+	VMParam       = "vm"
+	VMCollection  = "vms"
+	VMsRoot       = ProviderRoot + "/" + VMCollection
+	VMRoot        = VMsRoot + "/:" + VMParam
+	VMHistoryRoot = VMRoot + "/" + base.HistoryCollection
 )
 
 // Virtual Machine handler.
@@ -30,6 +31,7 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 	e.GET(VMsRoot, h.List)
 	e.GET(VMsRoot+"/", h.List)
 	e.GET(VMRoot, h.Get)
+	e.GET(VMHistoryRoot, h.History)
 }
 
 // List resources in a REST collection.
@@ -61,7 +63,9 @@ func (h VMHandler) List(ctx *gin.Context) {
 	h.Detail = model.MaxDetail
 	db := h.Collector.DB()
 	list := []model.VM{}
-	err = db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Pushdown(&options, &model.VM{})
+	err = db.List(&list, options)
 	if err != nil {
 		return
 	}
@@ -86,7 +90,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.ReplyList(ctx, content)
 }
 
 // Get a specific REST resource.
@@ -126,6 +130,22 @@ func (h VMHandler) Get(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, content)
 }
 
+// Get the change history of a specific VM.
+func (h VMHandler) History(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	m := &model.VM{
+		Base: model.Base{
+			ID: ctx.Param(VMParam),
+		},
+	}
+	h.ReplyHistory(ctx, m)
+}
+
 // Watch.
 func (h *VMHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
@@ -191,6 +211,7 @@ type VM1 struct {
 	IsTemplate        bool            `json:"isTemplate"`
 	PowerState        string          `json:"powerState"`
 	Host              string          `json:"host"`
+	ResourcePool      string          `json:"resourcePool"`
 	Networks          []model.Ref     `json:"networks"`
 	Disks             []model.Disk    `json:"disks"`
 	Concerns          []model.Concern `json:"concerns"`
@@ -203,6 +224,7 @@ func (r *VM1) With(m *model.VM) {
 	r.IsTemplate = m.IsTemplate
 	r.PowerState = m.PowerState
 	r.Host = m.Host
+	r.ResourcePool = m.ResourcePool
 	r.Networks = m.Networks
 	r.Disks = m.Disks
 	r.Concerns = m.Concerns
@@ -253,9 +275,11 @@ type VM struct {
 	ToolsRunningStatus       string                 `json:"toolsRunningStatus"`
 	// Note: vSphere reports version as "toolsVersionStatus2"; we keep the Go field
 	// name ToolsVersionStatus for continuity while serializing as toolsVersionStatus2.
-	ToolsVersionStatus string `json:"toolsVersionStatus2"`
-	DiskEnableUuid     bool   `json:"diskEnableUuid"`
-	NestedHVEnabled    bool   `json:"nestedHVEnabled"`
+	ToolsVersionStatus string                  `json:"toolsVersionStatus2"`
+	DiskEnableUuid     bool                    `json:"diskEnableUuid"`
+	NestedHVEnabled    bool                    `json:"nestedHVEnabled"`
+	Tags               []model.Tag             `json:"tags"`
+	CustomAttributes   []model.CustomAttribute `json:"customAttributes"`
 }
 
 // Build the resource using the model.
@@ -295,6 +319,8 @@ func (r *VM) With(m *model.VM) {
 	r.ToolsVersionStatus = m.ToolsVersionStatus
 	r.DiskEnableUuid = m.DiskEnableUuid
 	r.NestedHVEnabled = m.NestedHVEnabled
+	r.Tags = m.Tags
+	r.CustomAttributes = m.CustomAttributes
 }
 
 // Build self link (URI).
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	migrationctl "github.com/kubev2v/forklift/pkg/controller/migration"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Report formats: content type and renderer.
+var reportFormats = map[string]struct {
+	contentType string
+	render      func(*migrationctl.Report) (string, error)
+}{
+	"json": {contentType: "application/json", render: (*migrationctl.Report).JSON},
+	"csv":  {contentType: "text/csv", render: (*migrationctl.Report).CSV},
+	"html": {contentType: "text/html", render: (*migrationctl.Report).HTML},
+}
+
+// Serve the report of a completed migration.
+// The report is stored as JSON by the migration controller when
+// the migration completes and rendered in the format selected by
+// the `format` parameter (json, csv or html), json by default. The
+// caller must be permitted to get the migration.
+func serveMigrationReport(resp http.ResponseWriter, req *http.Request, client client.Client) {
+	query := req.URL.Query()
+	namespace := query.Get("namespace")
+	name := query.Get("name")
+	if namespace == "" || name == "" {
+		http.Error(resp, "Required parameters are missing: namespace, name", http.StatusBadRequest)
+		return
+	}
+	formatName := query.Get("format")
+	if formatName == "" {
+		formatName = "json"
+	}
+	format, found := reportFormats[formatName]
+	if !found {
+		http.Error(resp, "The format is not valid: json, csv, html", http.StatusBadRequest)
+		return
+	}
+	status, err := permit(req, client, "migrations", namespace, name)
+	if err != nil {
+		log.Error(err, "failed to authorize migration report request", "namespace", namespace, "name", name)
+		http.Error(resp, http.StatusText(status), status)
+		return
+	}
+	if status != http.StatusOK {
+		http.Error(resp, http.StatusText(status), status)
+		return
+	}
+	migration := &api.Migration{}
+	err = client.Get(context.TODO(), clientKey(namespace, name), migration)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			http.Error(resp, "Migration not found.", http.StatusNotFound)
+		} else {
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err = client.Get(context.TODO(), clientKey(namespace, migration.ReportName()), configMap)
+	if err != nil {
+		if cnd := migration.Status.FindCondition(migrationctl.ReportNotStored); cnd != nil {
+			http.Error(resp, cnd.Message, http.StatusNotFound)
+		} else if k8serr.IsNotFound(err) {
+			http.Error(resp, "The report is created when the migration completes.", http.StatusAccepted)
+		} else {
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	report := &migrationctl.Report{}
+	err = json.Unmarshal([]byte(configMap.Data[migrationctl.ReportJSON]), report)
+	if err != nil {
+		log.Error(err, "failed to parse migration report", "namespace", namespace, "name", name)
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	document, err := format.render(report)
+	if err != nil {
+		log.Error(err, "failed to render migration report", "namespace", namespace, "name", name)
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", format.contentType)
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write([]byte(document)); err != nil {
+		log.Error(err, "failed to write migration report", "namespace", namespace, "name", name)
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	authn "k8s.io/api/authentication/v1"
+	authz "k8s.io/api/authorization/v1"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Serve the rendered preview of the resources created by a plan.
+// The preview is rendered by the plan controller when the plan is
+// annotated with forklift.konveyor.io/preview=true. The caller must
+// be permitted to get the plan.
+func servePlanPreview(resp http.ResponseWriter, req *http.Request, client client.Client) {
+	query := req.URL.Query()
+	namespace := query.Get("namespace")
+	name := query.Get("name")
+	if namespace == "" || name == "" {
+		http.Error(resp, "Required parameters are missing: namespace, name", http.StatusBadRequest)
+		return
+	}
+	status, err := permit(req, client, "plans", namespace, name)
+	if err != nil {
+		log.Error(err, "failed to authorize plan preview request", "namespace", namespace, "name", name)
+		http.Error(resp, http.StatusText(status), status)
+		return
+	}
+	if status != http.StatusOK {
+		http.Error(resp, http.StatusText(status), status)
+		return
+	}
+	plan := &api.Plan{}
+	err = client.Get(context.TODO(), clientKey(namespace, name), plan)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			http.Error(resp, "Plan not found.", http.StatusNotFound)
+		} else {
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if plan.Annotations[api.AnnPreview] != "true" {
+		msg := fmt.Sprintf("Preview not requested: annotate the plan with %s=true.", api.AnnPreview)
+		http.Error(resp, msg, http.StatusNotFound)
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err = client.Get(context.TODO(), clientKey(namespace, plan.PreviewName()), configMap)
+	if err != nil && !k8serr.IsNotFound(err) {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err != nil || configMap.Annotations[api.AnnPreview] != strconv.FormatInt(plan.Generation, 10) {
+		http.Error(resp, "The preview is being rendered.", http.StatusAccepted)
+		return
+	}
+	keys := []string{}
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	documents := []string{}
+	for _, key := range keys {
+		documents = append(documents, configMap.Data[key])
+	}
+	resp.Header().Set("Content-Type", "application/yaml")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write([]byte(strings.Join(documents, "---\n"))); err != nil {
+		log.Error(err, "failed to write plan preview", "namespace", namespace, "name", name)
+	}
+}
+
+// Authorize the bearer token of the request to get the resource.
+func permit(req *http.Request, client client.Client, resource, namespace, name string) (int, error) {
+	fields := strings.Fields(req.Header.Get("Authorization"))
+	if len(fields) != 2 || fields[0] != "Bearer" {
+		return http.StatusUnauthorized, nil
+	}
+	tr := &authn.TokenReview{
+		Spec: authn.TokenReviewSpec{
+			Token: fields[1],
+		},
+	}
+	err := client.Create(context.TODO(), tr)
+	if err != nil {
+		return http.StatusInternalServerError, liberr.Wrap(err)
+	}
+	if !tr.Status.Authenticated {
+		return http.StatusUnauthorized, nil
+	}
+	user := tr.Status.User
+	extra := map[string]authz.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = append(
+			authz.ExtraValue{},
+			v...)
+	}
+	review := &authz.SubjectAccessReview{
+		Spec: authz.SubjectAccessReviewSpec{
+			ResourceAttributes: &authz.ResourceAttributes{
+				Group:     api.SchemeGroupVersion.Group,
+				Resource:  resource,
+				Namespace: namespace,
+				Name:      name,
+				Verb:      "get",
+			},
+			Extra:  extra,
+			Groups: user.Groups,
+			User:   user.Username,
+			UID:    user.UID,
+		},
+	}
+	err = client.Create(context.TODO(), review)
+	if err != nil {
+		return http.StatusInternalServerError, liberr.Wrap(err)
+	}
+	if !review.Status.Allowed {
+		return http.StatusForbidden, nil
+	}
+	return http.StatusOK, nil
+}
+
+func clientKey(namespace, name string) client.ObjectKey {
+	return client.ObjectKey{Namespace: namespace, Name: name}
+}
@@ -9,6 +9,8 @@ import (
 )
 
 const TLS_CERTIFICATE_PATH = "/tls-certificate"
+const PLAN_PREVIEW_PATH = "/plan-preview"
+const MIGRATION_REPORT_PATH = "/migration-report"
 
 var log = logging.WithName("services")
 
@@ -17,4 +19,12 @@ func RegisterServices(mux *http.ServeMux, client client.Client) {
 	mux.HandleFunc(TLS_CERTIFICATE_PATH, func(w http.ResponseWriter, r *http.Request) {
 		serveTlsCertificate(w, r, client)
 	})
+	log.Info("register plan preview service")
+	mux.HandleFunc(PLAN_PREVIEW_PATH, func(w http.ResponseWriter, r *http.Request) {
+		servePlanPreview(w, r, client)
+	})
+	log.Info("register migration report service")
+	mux.HandleFunc(MIGRATION_REPORT_PATH, func(w http.ResponseWriter, r *http.Request) {
+		serveMigrationReport(w, r, client)
+	})
 }
@@ -0,0 +1,116 @@
+package mutators
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kubev2v/forklift/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Limits the ingress bandwidth of a pod.
+	AnnIngressBandwidth = "kubernetes.io/ingress-bandwidth"
+)
+
+// Sets the transfer rate limit of the disk on the CDI importer pods.
+// CDI copies the DataVolume annotations to the PVC but not the
+// ingress bandwidth of the PVC to the importer pod, which is the
+// pod that transfers the disk.
+type PodMutator struct {
+	ar     *admissionv1.AdmissionReview
+	pod    core.Pod
+	Client client.Client
+}
+
+func (mutator *PodMutator) Mutate(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	mutator.ar = ar
+	raw := ar.Request.Object.Raw
+	if err := json.Unmarshal(raw, &mutator.pod); err != nil {
+		log.Error(err, "mutating webhook error, failed to unmarshel pod")
+		return util.ToAdmissionResponseError(err)
+	}
+	if _, found := mutator.pod.Annotations[AnnIngressBandwidth]; found {
+		return util.ToAdmissionResponseAllow()
+	}
+	bandwidth, err := mutator.ingressBandwidth()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+	if bandwidth == "" {
+		return util.ToAdmissionResponseAllow()
+	}
+	if mutator.pod.Annotations == nil {
+		mutator.pod.Annotations = make(map[string]string)
+	}
+	mutator.pod.Annotations[AnnIngressBandwidth] = bandwidth
+	log.Info("Limiting the ingress bandwidth of the importer pod",
+		"namespace", ar.Request.Namespace, "pod", mutator.pod.Name, "bandwidth", bandwidth)
+	patchBytes, err := util.GeneratePatchPayload(util.PatchOperation{
+		Op:    "add",
+		Path:  "/metadata/annotations",
+		Value: mutator.pod.Annotations,
+	})
+	if err != nil {
+		log.Error(err, "mutating webhook error, failed to generate payload for patch request")
+		return util.ToAdmissionResponseError(err)
+	}
+	jsonPatchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patchBytes,
+		PatchType: &jsonPatchType,
+	}
+}
+
+// Find the ingress bandwidth set on the PVCs the pod writes to.
+// The importer pods of CDI populators write to a prime PVC owned
+// by the PVC of the DataVolume.
+func (mutator *PodMutator) ingressBandwidth() (bandwidth string, err error) {
+	for _, volume := range mutator.pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc := &core.PersistentVolumeClaim{}
+		err = mutator.Client.Get(
+			context.TODO(),
+			client.ObjectKey{Namespace: mutator.ar.Request.Namespace, Name: volume.PersistentVolumeClaim.ClaimName},
+			pvc)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			log.Error(err, "Couldn't get the PVC of the pod")
+			return
+		}
+		if bandwidth = pvc.Annotations[AnnIngressBandwidth]; bandwidth != "" {
+			return
+		}
+		for _, owner := range pvc.OwnerReferences {
+			if owner.Kind != "PersistentVolumeClaim" {
+				continue
+			}
+			target := &core.PersistentVolumeClaim{}
+			err = mutator.Client.Get(
+				context.TODO(),
+				client.ObjectKey{Namespace: pvc.Namespace, Name: owner.Name},
+				target)
+			if err != nil {
+				if k8serr.IsNotFound(err) {
+					err = nil
+					continue
+				}
+				log.Error(err, "Couldn't get the owner of the PVC")
+				return
+			}
+			if bandwidth = target.Annotations[AnnIngressBandwidth]; bandwidth != "" {
+				return
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,129 @@
+package mutators
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kubev2v/forklift/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/gomega"
+)
+
+func importerPodReview(g *WithT, claimName string) *admissionv1.AdmissionReview {
+	pod := &core.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "importer-prime-1234",
+			Labels: map[string]string{
+				"app":             "containerized-data-importer",
+				"cdi.kubevirt.io": "importer",
+			},
+		},
+		Spec: core.PodSpec{
+			Volumes: []core.Volume{
+				{
+					Name: "cdi-data-vol",
+					VolumeSource: core.VolumeSource{
+						PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+							ClaimName: claimName,
+						},
+					},
+				},
+			},
+		},
+	}
+	podBytes, err := json.Marshal(pod)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	return &admissionv1.AdmissionReview{
+		Request: &admissionv1.AdmissionRequest{
+			Namespace: "test-namespace",
+			Object: runtime.RawExtension{
+				Raw: podBytes,
+			},
+		},
+	}
+}
+
+func TestMutateImporterPodIngressBandwidth(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// Create the target PVC with the limit and the prime PVC it owns
+	target := &core.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "disk-1",
+			Namespace: "test-namespace",
+			Annotations: map[string]string{
+				AnnIngressBandwidth: "80M",
+			},
+		},
+	}
+	prime := &core.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prime-1234",
+			Namespace: "test-namespace",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "PersistentVolumeClaim",
+					Name:       "disk-1",
+				},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(target, prime).
+		Build()
+
+	mutator := &PodMutator{
+		Client: fakeClient,
+	}
+
+	response := mutator.Mutate(importerPodReview(g, "prime-1234"))
+
+	// Verify the pod is annotated with the limit of the target PVC
+	g.Expect(response.Allowed).To(BeTrue())
+	g.Expect(response.PatchType).ToNot(BeNil())
+	g.Expect(*response.PatchType).To(Equal(admissionv1.PatchTypeJSONPatch))
+	patches := []util.PatchOperation{}
+	g.Expect(json.Unmarshal(response.Patch, &patches)).To(Succeed())
+	g.Expect(patches).To(HaveLen(1))
+	g.Expect(patches[0].Path).To(Equal("/metadata/annotations"))
+	g.Expect(patches[0].Value).To(HaveKeyWithValue(AnnIngressBandwidth, "80M"))
+}
+
+func TestMutateImporterPodWithoutLimit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pvc := &core.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "disk-1",
+			Namespace: "test-namespace",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pvc).
+		Build()
+
+	mutator := &PodMutator{
+		Client: fakeClient,
+	}
+
+	response := mutator.Mutate(importerPodReview(g, "disk-1"))
+
+	// Verify the response - should be allowed but no patch since no limit is set
+	g.Expect(response.Allowed).To(BeTrue())
+	g.Expect(response.Patch).To(BeNil())
+}
@@ -60,7 +60,7 @@ func (admitter *PlanAdmitter) validateStorage() error {
 		log.Error(err, "Couldn't get the storage map")
 		return err
 	}
-	storagePairList := storageMap.Spec.Map
+	storagePairList := storageMap.Pairs()
 	var badStorageClasses []string
 	for _, storagePair := range storagePairList {
 		scName := storagePair.Destination.StorageClass
@@ -182,6 +182,12 @@ func (admitter *PlanAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv
 
 	// Check whether user has permission to access the VMs from the plan
 	if admitter.sourceProvider.IsHost() {
+		// The selected VMs cannot be checked.
+		if admitter.plan.Spec.VMSelector != nil {
+			err = liberr.New("VM selector is not supported when the source provider is the host cluster")
+			log.Error(err, "Unable to check access to the selected VMs")
+			return util.ToAdmissionResponseError(err)
+		}
 		for _, planvm := range admitter.plan.Spec.VMs {
 			err = util.PermitUser(ar.Request, admitter.Client, cnv.Resource("virtualmachines"), planvm.Name, planvm.Namespace, util.Get)
 			if err != nil {
@@ -77,6 +77,7 @@ const (
 	reasonPVCCreationError   = "PopulatorPVCCreationError"
 	reasonPopulatorProgress  = "PopulatorProgress"
 	AnnTransferNetwork       = "k8s.v1.cni.cncf.io/networks"
+	AnnIngressBandwidth      = "kubernetes.io/ingress-bandwidth"
 	AnnPopulatorReCreations  = "recreations"
 
 	qemuGroup = 107
@@ -618,6 +619,14 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 				// Join the transfer network namespace and name
 				annotations[AnnTransferNetwork] = fmt.Sprintf("%s/%s", transferNetwork["namespace"], transferNetwork["name"])
 			}
+			transferRateLimit, found, err := unstructured.NestedInt64(crInstance.Object, "spec", "transferRateLimit")
+			if err != nil {
+				return err
+			}
+			if found && transferRateLimit > 0 {
+				// Limit the ingress bandwidth (bits/s) to the transfer rate (MB/s)
+				annotations[AnnIngressBandwidth] = fmt.Sprintf("%dM", transferRateLimit*8)
+			}
 			migration, found, err := unstructured.NestedString(crInstance.Object, "metadata", "labels", "migration")
 			if err != nil {
 				return err
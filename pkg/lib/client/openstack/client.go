@@ -69,15 +69,16 @@ var supportedAuthTypes = map[string]clientconfig.AuthType{
 
 // Client struct
 type Client struct {
-	URL                 string
-	Options             map[string]string
-	Log                 logging.LevelLogger
-	provider            *gophercloud.ProviderClient
-	identityService     *gophercloud.ServiceClient
-	computeService      *gophercloud.ServiceClient
-	imageService        *gophercloud.ServiceClient
-	networkService      *gophercloud.ServiceClient
-	blockStorageService *gophercloud.ServiceClient
+	URL                  string
+	Options              map[string]string
+	Log                  logging.LevelLogger
+	provider             *gophercloud.ProviderClient
+	identityService      *gophercloud.ServiceClient
+	computeService       *gophercloud.ServiceClient
+	imageService         *gophercloud.ServiceClient
+	networkService       *gophercloud.ServiceClient
+	blockStorageService  *gophercloud.ServiceClient
+	objectStorageService *gophercloud.ServiceClient
 }
 
 func (c *Client) LoadOptionsFromSecret(secret *core.Secret) {
@@ -275,7 +276,7 @@ func (c *Client) List(object interface{}, opts interface{}) (err error) {
 		err = c.computeServiceAPI(object, opts)
 	case *[]Image:
 		err = c.imageServiceAPI(object, opts)
-	case *[]Volume, *[]VolumeType, *[]Snapshot:
+	case *[]Volume, *[]VolumeType, *[]Snapshot, *[]Backup:
 		err = c.blockStorageServiceAPI(object, opts)
 	case *[]Network, *[]Subnet:
 		err = c.networkServiceAPI(object, opts)
@@ -297,7 +298,7 @@ func (c *Client) Get(object interface{}, ID string) (err error) {
 		err = c.computeServiceAPI(object, &GetOpts{ID: ID})
 	case *Image:
 		err = c.imageServiceAPI(object, &GetOpts{ID: ID})
-	case *Volume, *VolumeType, *Snapshot:
+	case *Volume, *VolumeType, *Snapshot, *Backup:
 		err = c.blockStorageServiceAPI(object, &GetOpts{ID: ID})
 	case *Network, *Subnet:
 		err = c.networkServiceAPI(object, &GetOpts{ID: ID})
@@ -319,7 +320,7 @@ func (c *Client) Create(object interface{}, opts interface{}) (err error) {
 		err = c.computeServiceAPI(object, opts)
 	case *Image:
 		err = c.imageServiceAPI(object, opts)
-	case *Volume, *VolumeType, *Snapshot:
+	case *Volume, *VolumeType, *Snapshot, *Backup:
 		err = c.blockStorageServiceAPI(object, opts)
 	case *Network, *Subnet:
 		err = c.networkServiceAPI(object, opts)
@@ -363,7 +364,7 @@ func (c *Client) Delete(object interface{}) (err error) {
 		err = c.computeServiceAPI(object, &DeleteOpts{})
 	case *Image:
 		err = c.imageServiceAPI(object, &DeleteOpts{})
-	case *Volume, *VolumeType, *Snapshot:
+	case *Volume, *VolumeType, *Snapshot, *Backup:
 		err = c.blockStorageServiceAPI(object, &DeleteOpts{})
 	case *Network, *Subnet:
 		err = c.networkServiceAPI(object, &DeleteOpts{})
@@ -863,6 +864,8 @@ func (c *Client) blockStorageServiceAPI(object interface{}, opts interface{}) (e
 		err = c.volumeTypeAPI(object, opts)
 	case *Snapshot, *[]Snapshot:
 		err = c.snapshotAPI(object, opts)
+	case *Backup, *[]Backup:
+		err = c.backupAPI(object, opts)
 	default:
 		err = c.unsupportedTypeError(object)
 	}
@@ -1070,6 +1073,105 @@ func (c *Client) snapshotList(object *[]Snapshot, opts *SnapshotListOpts) (err e
 	return
 }
 
+// The backups are accessed through the block storage service
+// client directly as the gophercloud backups package is not
+// vendored.
+func (c *Client) backupAPI(object interface{}, opts interface{}) (err error) {
+	switch object.(type) {
+	case *[]Backup:
+		object := object.(*[]Backup)
+		switch opts := opts.(type) {
+		case *BackupListOpts:
+			err = c.backupList(object, opts)
+		default:
+			err = c.unsupportedTypeError(object)
+		}
+	case *Backup:
+		object := object.(*Backup)
+		switch opts := opts.(type) {
+		case *GetOpts:
+			result := struct {
+				Backup Backup `json:"backup"`
+			}{}
+			_, err = c.blockStorageService.Get(
+				c.blockStorageService.ServiceURL("backups", opts.ID),
+				&result,
+				nil)
+			if err != nil {
+				return
+			}
+			*object = result.Backup
+		case *BackupCreateOpts:
+			var body map[string]interface{}
+			body, err = gophercloud.BuildRequestBody(opts, "backup")
+			if err != nil {
+				return
+			}
+			result := struct {
+				Backup Backup `json:"backup"`
+			}{}
+			_, err = c.blockStorageService.Post(
+				c.blockStorageService.ServiceURL("backups"),
+				body,
+				&result,
+				&gophercloud.RequestOpts{OkCodes: []int{http.StatusAccepted}})
+			if err != nil {
+				return
+			}
+			err = c.Get(object, result.Backup.ID)
+		case *DeleteOpts:
+			_, err = c.blockStorageService.Delete(
+				c.blockStorageService.ServiceURL("backups", object.ID),
+				&gophercloud.RequestOpts{OkCodes: []int{http.StatusAccepted}})
+		default:
+			err = c.unsupportedTypeError(object)
+		}
+	default:
+		err = c.unsupportedTypeError(object)
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+func (c *Client) backupList(object *[]Backup, opts *BackupListOpts) (err error) {
+	query, err := gophercloud.BuildQueryString(opts)
+	if err != nil {
+		return
+	}
+	result := struct {
+		Backups []Backup `json:"backups"`
+	}{}
+	_, err = c.blockStorageService.Get(
+		c.blockStorageService.ServiceURL("backups", "detail")+query.String(),
+		&result,
+		nil)
+	if err != nil {
+		return
+	}
+	*object = result.Backups
+	return
+}
+
+func (c *Client) connectObjectStorageServiceAPI() (err error) {
+	err = c.Authenticate()
+	if err != nil {
+		return
+	}
+	if c.objectStorageService == nil {
+		var objectStorageService *gophercloud.ServiceClient
+		endpointOpts := c.getEndpointOpts()
+		objectStorageService, err = openstack.NewObjectStorageV1(c.provider, endpointOpts)
+		if err != nil {
+			c.Log.Error(err, "creating the object storage service client", "provider", c.provider, "options", endpointOpts)
+			return
+		}
+		c.objectStorageService = objectStorageService
+	}
+	return
+}
+
 func (c *Client) connectNetworkServiceAPI() (err error) {
 	err = c.Authenticate()
 	if err != nil {
@@ -1470,3 +1572,56 @@ func (c *Client) UnsetImageMetadata(volumeID, key string) (err error) {
 	err = volumeactions.UnsetImageMetadata(c.blockStorageService, volumeID, volumeactions.UnsetImageMetadataOpts{Key: key}).ExtractErr()
 	return
 }
+
+// List the names of the objects of a container starting with the prefix.
+func (c *Client) ListObjects(container, prefix string) (names []string, err error) {
+	err = c.connectObjectStorageServiceAPI()
+	if err != nil {
+		return
+	}
+	marker := ""
+	for {
+		query := url.Values{}
+		query.Set("format", "json")
+		query.Set("prefix", prefix)
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		page := []struct {
+			Name string `json:"name"`
+		}{}
+		_, err = c.objectStorageService.Get(
+			c.objectStorageService.ServiceURL(url.PathEscape(container))+"?"+query.Encode(),
+			&page,
+			&gophercloud.RequestOpts{OkCodes: []int{http.StatusOK, http.StatusNoContent}})
+		if err != nil {
+			err = liberr.Wrap(err, "container", container, "prefix", prefix)
+			return
+		}
+		if len(page) == 0 {
+			return
+		}
+		for _, object := range page {
+			names = append(names, object.Name)
+		}
+		marker = page[len(page)-1].Name
+	}
+}
+
+// Download an object of a container.
+func (c *Client) DownloadObject(container, name string) (data io.ReadCloser, err error) {
+	err = c.connectObjectStorageServiceAPI()
+	if err != nil {
+		return
+	}
+	response, err := c.objectStorageService.Get(
+		c.objectStorageService.ServiceURL(url.PathEscape(container), name),
+		nil,
+		&gophercloud.RequestOpts{KeepResponseBody: true, OkCodes: []int{http.StatusOK}})
+	if err != nil {
+		err = liberr.Wrap(err, "container", container, "object", name)
+		return
+	}
+	data = response.Body
+	return
+}
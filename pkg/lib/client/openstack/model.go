@@ -162,6 +162,39 @@ type Snapshot struct {
 	snapshots.Snapshot
 }
 
+const (
+	BackupStatusCreating  = "creating"
+	BackupStatusAvailable = "available"
+	BackupStatusDeleting  = "deleting"
+	BackupStatusError     = "error"
+	BackupStatusRestoring = "restoring"
+)
+
+type BackupCreateOpts struct {
+	Name        string `json:"name,omitempty"`
+	VolumeID    string `json:"volume_id" required:"true"`
+	SnapshotID  string `json:"snapshot_id,omitempty"`
+	Incremental bool   `json:"incremental,omitempty"`
+	Force       bool   `json:"force,omitempty"`
+}
+
+type BackupListOpts struct {
+	Name     string `q:"name"`
+	VolumeID string `q:"volume_id"`
+}
+
+type Backup struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	VolumeID         string `json:"volume_id"`
+	SnapshotID       string `json:"snapshot_id"`
+	Status           string `json:"status"`
+	AvailabilityZone string `json:"availability_zone"`
+	Container        string `json:"container"`
+	IsIncremental    bool   `json:"is_incremental"`
+	FailReason       string `json:"fail_reason"`
+}
+
 const (
 	VolumeStatusCreating         = "creating"
 	VolumeStatusAvailable        = "available"
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"errors"
+)
+
+// Checkpoint names.
+const (
+	// Position of the inventory collector.
+	// Stored once the initial load has completed.
+	CollectorCheckpoint = "collector"
+)
+
+// Checkpoint model.
+// Records the position from which a persistent DB
+// can be refreshed incrementally after a restart.
+// For example: the last event ID of a collector.
+type Checkpoint struct {
+	Name  string `sql:"pk"`
+	Value string `sql:""`
+}
+
+func (m *Checkpoint) Pk() string {
+	return m.Name
+}
+
+func (m *Checkpoint) String() string {
+	return m.Name
+}
+
+// Get a checkpoint.
+// Returns found=false when the checkpoint has not been stored.
+func GetCheckpoint(db DB, name string) (value string, found bool, err error) {
+	m := &Checkpoint{Name: name}
+	err = db.Get(m)
+	if err != nil {
+		if errors.Is(err, NotFound) {
+			err = nil
+		}
+		return
+	}
+	value = m.Value
+	found = true
+	return
+}
+
+// Store a checkpoint within the transaction.
+// Storing it with the changes it refers to ensures
+// the checkpoint never gets ahead of the DB content.
+func SetCheckpoint(tx *Tx, name, value string) (err error) {
+	m := &Checkpoint{Name: name, Value: value}
+	err = tx.Update(m)
+	if errors.Is(err, NotFound) {
+		err = tx.Insert(m)
+	}
+	return
+}
@@ -2,7 +2,10 @@ package model
 
 import (
 	"database/sql"
+	"fmt"
+	"hash/fnv"
 	"os"
+	"sort"
 	"time"
 
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
@@ -54,16 +57,29 @@ type Client struct {
 	pool Pool
 	// Journal
 	journal Journal
+	// History options.
+	history *HistoryOptions
 	// Logger
 	log logging.LevelLogger
 }
 
 // Create the database.
 // Build the schema to support the specified models.
+// When not deleted, an existing DB is kept unless it was
+// built for a different schema.
 // See: Pool.Open().
 func (r *Client) Open(delete bool) (err error) {
+	ddls, err := r.ddl()
+	if err != nil {
+		panic(err)
+	}
+	version := schemaVersion(ddls)
+	if !delete && !r.schemaMatched(version) {
+		r.log.Info("DB schema changed.")
+		delete = true
+	}
 	if delete {
-		_ = os.Remove(r.path)
+		r.remove()
 		r.log.V(3).Info("DB file deleted.")
 	}
 	err = r.pool.Open(1, 10, r.path, &r.journal)
@@ -74,10 +90,10 @@ func (r *Client) Open(delete bool) (err error) {
 	defer func() {
 		if err != nil {
 			_ = r.pool.Close()
-			_ = os.Remove(r.path)
+			r.remove()
 		}
 	}()
-	err = r.build()
+	err = r.build(ddls, version)
 	if err != nil {
 		panic(err)
 	}
@@ -103,7 +119,7 @@ func (r *Client) Close(delete bool) (err error) {
 			"Error closing the session pool.")
 	}
 	if delete {
-		_ = os.Remove(r.path)
+		r.remove()
 		r.log.V(3).Info("DB file deleted.")
 	}
 
@@ -215,6 +231,11 @@ func (r *Client) Begin(labels ...string) (tx *Tx, error error) {
 			tx:  realTx,
 			log: r.log,
 		},
+		historian: Historian{
+			tx:      realTx,
+			options: r.history,
+			log:     r.log,
+		},
 		started: time.Now(),
 		labels:  labels,
 		log:     r.log,
@@ -347,16 +368,7 @@ func (r *Client) EndWatch(watch *Watch) {
 }
 
 // Build the data model.
-func (r *Client) build() (err error) {
-	r.models = append(r.models, &Label{})
-	r.dm, err = NewModel(r.models)
-	if err != nil {
-		return err
-	}
-	ddls, err := r.dm.DDL()
-	if err != nil {
-		return err
-	}
+func (r *Client) build(ddls []string, version int32) (err error) {
 	session := r.pool.Writer()
 	defer session.Return()
 	for _, ddl := range ddls {
@@ -374,10 +386,75 @@ func (r *Client) build() (err error) {
 				ddl)
 		}
 	}
+	_, err = session.db.Exec(fmt.Sprintf("PRAGMA user_version = %d", version))
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 
 	return nil
 }
 
+// Build the data model and the DDL.
+func (r *Client) ddl() (ddls []string, err error) {
+	if r.dm == nil {
+		r.models = append(r.models, &Label{}, &Checkpoint{}, &History{})
+		r.dm, err = NewModel(r.models)
+		if err != nil {
+			return
+		}
+	}
+	ddls, err = r.dm.DDL()
+	return
+}
+
+// Determine whether an existing DB file was built
+// for the schema version. Stored in the sqlite `user_version`.
+// A missing file matches.
+func (r *Client) schemaMatched(version int32) (matched bool) {
+	if _, err := os.Stat(r.path); err != nil {
+		matched = true
+		return
+	}
+	db, err := sql.Open("sqlite", r.path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	stored := int32(0)
+	err = db.QueryRow("PRAGMA user_version").Scan(&stored)
+	if err != nil {
+		return
+	}
+	matched = stored == version
+	return
+}
+
+// Delete the DB file and the sqlite WAL files.
+func (r *Client) remove() {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		_ = os.Remove(r.path + suffix)
+	}
+}
+
+// Schema version.
+// Digest of the DDL, never zero (the sqlite default).
+// The DDL is sorted since the order of the tables may vary.
+func schemaVersion(ddls []string) (version int32) {
+	sorted := append([]string{}, ddls...)
+	sort.Strings(sorted)
+	h := fnv.New32a()
+	for _, ddl := range sorted {
+		_, _ = h.Write([]byte(ddl))
+	}
+	version = int32(h.Sum32() & 0x7fffffff)
+	if version == 0 {
+		version = 1
+	}
+	return
+}
+
 // Database transaction.
 type Tx struct {
 	// DB session.
@@ -390,6 +467,8 @@ type Tx struct {
 	staged *fb.List
 	// Manage labels associated with models.
 	labeler Labeler
+	// Record the change history of models.
+	historian Historian
 	// DataModel.
 	dm *DataModel
 	// Logger.
@@ -500,6 +579,10 @@ func (r *Tx) Insert(model Model) (err error) {
 	if err != nil {
 		return
 	}
+	err = r.historian.Record(&event)
+	if err != nil {
+		return
+	}
 
 	r.log.V(3).Info(
 		"insert succeeded.",
@@ -535,6 +618,10 @@ func (r *Tx) Update(model Model, predicate ...Predicate) (err error) {
 	if err != nil {
 		return
 	}
+	err = r.historian.Record(&event)
+	if err != nil {
+		return
+	}
 
 	r.log.V(3).Info(
 		"update succeeded.",
@@ -659,6 +746,10 @@ func (r *Tx) delete(model Model) (err error) {
 	if err != nil {
 		return
 	}
+	err = r.historian.Record(&event)
+	if err != nil {
+		return
+	}
 
 	r.log.V(3).Info(
 		"delete succeeded.",
@@ -167,3 +167,11 @@ func New(path string, models ...interface{}) DB {
 
 	return client
 }
+
+// New database with the change history
+// of models recorded. See: HistoryOptions.
+func NewWithHistory(path string, history *HistoryOptions, models ...interface{}) DB {
+	client := New(path, models...).(*Client)
+	client.history = history
+	return client
+}
@@ -0,0 +1,255 @@
+package model
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// History actions.
+const (
+	HistoryCreated = "created"
+	HistoryUpdated = "updated"
+	HistoryDeleted = "deleted"
+)
+
+// History model.
+// Records a change of a model. Updates record the
+// (top level) fields that changed with the old and new
+// values. Only recorded for the models listed in the
+// HistoryOptions of the DB.
+type History struct {
+	PK      string   `sql:"pk(kind;subject;time)"`
+	Kind    string   `sql:"key,index(subject)"`
+	Subject string   `sql:"key,index(subject)"`
+	Time    int64    `sql:"key,index(time)"`
+	Action  string   `sql:""`
+	Changes []Change `sql:""`
+}
+
+func (m *History) Pk() string {
+	return m.PK
+}
+
+func (m *History) String() string {
+	return m.Kind + "/" + m.Subject
+}
+
+// Changed field.
+type Change struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// History options.
+type HistoryOptions struct {
+	// Models with the history recorded.
+	Models []interface{}
+	// Fields excluded from the changes.
+	// For example: bookkeeping fields.
+	Excluded []string
+	// Records older than the retention are purged.
+	// Zero = never purged.
+	Retention time.Duration
+	// Last purge.
+	purged time.Time
+	// Protect the last purge.
+	mutex sync.Mutex
+}
+
+// Get whether the history of the model is recorded.
+func (r *HistoryOptions) recorded(kind string) bool {
+	for _, m := range r.Models {
+		if (Table{}).Name(m) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Get whether the field is excluded.
+func (r *HistoryOptions) excluded(name string) bool {
+	for _, excluded := range r.Excluded {
+		if excluded == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Get whether the records need to be purged.
+// Purged at most every tenth of the retention.
+func (r *HistoryOptions) purge() (cutoff int64, needed bool) {
+	if r.Retention <= 0 {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	if now.Sub(r.purged) < r.Retention/10 {
+		return
+	}
+	r.purged = now
+	cutoff = now.Add(-r.Retention).UnixNano()
+	needed = true
+	return
+}
+
+// Historian.
+// Records the change history of models.
+type Historian struct {
+	// DB transaction.
+	tx *sql.Tx
+	// Options. History not recorded when nil.
+	options *HistoryOptions
+	// Logger.
+	log logging.LevelLogger
+}
+
+// Record the event.
+func (r *Historian) Record(event *Event) (err error) {
+	if r.options == nil {
+		return
+	}
+	table := Table{r.tx}
+	kind := table.Name(event.Model)
+	if !r.options.recorded(kind) {
+		return
+	}
+	m := &History{
+		Kind:    kind,
+		Subject: event.Model.Pk(),
+		Time:    time.Now().UnixNano(),
+	}
+	switch event.Action {
+	case Created:
+		m.Action = HistoryCreated
+	case Deleted:
+		m.Action = HistoryDeleted
+	case Updated:
+		m.Action = HistoryUpdated
+		m.Changes, err = r.diff(event.Model, event.Updated)
+		if err != nil {
+			return
+		}
+		if len(m.Changes) == 0 {
+			return
+		}
+	}
+	err = table.Insert(m)
+	if err != nil {
+		return
+	}
+	r.log.V(4).Info(
+		"history recorded.",
+		"model",
+		Describe(event.Model),
+		"action",
+		m.Action)
+
+	err = r.purge()
+
+	return
+}
+
+// Changed (mutable) fields.
+func (r *Historian) diff(old, new Model) (changes []Change, err error) {
+	mdOld, err := Inspect(old)
+	if err != nil {
+		return
+	}
+	mdNew, err := Inspect(new)
+	if err != nil {
+		return
+	}
+	for i, f := range mdOld.Fields {
+		if !f.Mutable() || f.Incremented() || r.options.excluded(f.Name) {
+			continue
+		}
+		oldValue := f.Value.Interface()
+		newValue := mdNew.Fields[i].Value.Interface()
+		if r.equal(oldValue, newValue) {
+			continue
+		}
+		changes = append(
+			changes,
+			Change{
+				Field: f.Name,
+				Old:   oldValue,
+				New:   newValue,
+			})
+	}
+	return
+}
+
+// Compare field values by encoding.
+// Empty lists and maps are equal to nil since
+// they are not distinguished once stored.
+func (r *Historian) equal(a, b interface{}) bool {
+	encode := func(v interface{}) []byte {
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Map:
+			if rv.Len() == 0 {
+				return nil
+			}
+		}
+		encoded, _ := json.Marshal(v)
+		return encoded
+	}
+	return bytes.Equal(encode(a), encode(b))
+}
+
+// Purge the records older than the retention.
+func (r *Historian) purge() (err error) {
+	cutoff, needed := r.options.purge()
+	if !needed {
+		return
+	}
+	stmt := fmt.Sprintf(
+		"DELETE FROM %s WHERE Time < ?;",
+		Table{}.Name(&History{}))
+	result, err := r.tx.Exec(stmt, cutoff)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	n, _ := result.RowsAffected()
+	r.log.V(3).Info(
+		"history purged.",
+		"count",
+		n)
+	return
+}
+
+// List the history of a model.
+// Ordered by time, oldest first.
+func ListHistory(db DB, model Model) (list []History, err error) {
+	list = []History{}
+	err = db.List(
+		&list,
+		ListOptions{
+			Detail: MaxDetail,
+			Predicate: And(
+				Eq("Kind", Table{}.Name(model)),
+				Eq("Subject", model.Pk())),
+		})
+	if err != nil {
+		return
+	}
+	sort.SliceStable(
+		list,
+		func(i, j int) bool {
+			return list[i].Time < list[j].Time
+		})
+	return
+}
@@ -1182,6 +1182,113 @@ func TestCloseDB(t *testing.T) {
 	g.Expect(handler.done).To(gomega.BeTrue())
 }
 
+func TestPersistentDB(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	path := "/tmp/test-persistent-db.db"
+	DB := New(path, &PlainObject{})
+	err := DB.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Insert(&PlainObject{ID: 1, Name: "Elmer"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.With(func(tx *Tx) error {
+		return SetCheckpoint(tx, "collector", "42")
+	})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.With(func(tx *Tx) error {
+		return SetCheckpoint(tx, "collector", "43")
+	})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	_ = DB.Close(false)
+
+	// Kept.
+	DB = New(path, &PlainObject{})
+	err = DB.Open(false)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	object := &PlainObject{ID: 1}
+	err = DB.Get(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(object.Name).To(gomega.Equal("Elmer"))
+	value, found, err := GetCheckpoint(DB, "collector")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(value).To(gomega.Equal("43"))
+	_, found, err = GetCheckpoint(DB, "other")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(found).To(gomega.BeFalse())
+	_ = DB.Close(false)
+
+	// Schema changed.
+	DB = New(path, &PlainObject{}, &TestObject{})
+	err = DB.Open(false)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Get(&PlainObject{ID: 1})
+	g.Expect(errors.Is(err, NotFound)).To(gomega.BeTrue())
+	_, found, err = GetCheckpoint(DB, "collector")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(found).To(gomega.BeFalse())
+	_ = DB.Close(true)
+}
+
+func TestHistory(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	history := &HistoryOptions{
+		Models:    []interface{}{&TestObject{}},
+		Excluded:  []string{"Age"},
+		Retention: time.Hour,
+	}
+	DB := NewWithHistory("/tmp/test-history.db", history, &PlainObject{}, &TestObject{})
+	err := DB.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		_ = DB.Close(true)
+	}()
+	object := &TestObject{
+		PK:    "1",
+		ID:    1,
+		Name:  "Elmer",
+		Slice: []string{"hello"},
+	}
+	err = DB.Insert(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	object.Name = "Bugs"
+	object.Age = 20
+	object.Slice = []string{"hello", "world"}
+	err = DB.Update(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	// Only excluded fields changed.
+	object.Age = 21
+	err = DB.Update(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Delete(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	// Not recorded.
+	err = DB.Insert(&PlainObject{ID: 1, Name: "Elmer"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	list, err := ListHistory(DB, &TestObject{PK: "1"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(list).To(gomega.HaveLen(3))
+	g.Expect(list[0].Action).To(gomega.Equal(HistoryCreated))
+	g.Expect(list[1].Action).To(gomega.Equal(HistoryUpdated))
+	g.Expect(list[1].Changes).To(gomega.Equal([]Change{
+		{Field: "Name", Old: "Elmer", New: "Bugs"},
+		{Field: "Slice", Old: []interface{}{"hello"}, New: []interface{}{"hello", "world"}},
+	}))
+	g.Expect(list[2].Action).To(gomega.Equal(HistoryDeleted))
+	list, err = ListHistory(DB, &PlainObject{ID: 1})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(list).To(gomega.BeEmpty())
+
+	// Purged.
+	history.Retention = time.Nanosecond
+	history.purged = time.Time{}
+	err = DB.Insert(&TestObject{PK: "2", ID: 2})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	list, err = ListHistory(DB, &TestObject{PK: "1"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(list).To(gomega.BeEmpty())
+}
+
 func TestMutatingWatch(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	DB := New("/tmp/test-mutating-watch.db", &TestObject{})
@@ -344,7 +344,8 @@ func (p *OrPredicate) Expr() string {
 		predicates = append(predicates, p.Expr())
 	}
 
-	expr := strings.Join(predicates, " OR ")
+	// Grouped since AND takes precedence when nested.
+	expr := "(" + strings.Join(predicates, " OR ") + ")"
 
 	return expr
 }
@@ -0,0 +1,1090 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Query parameters.
+const (
+	// Filter expression.
+	FilterParam = "filter"
+	// Sort by (comma-separated) fields.
+	// A field prefixed by `-` is sorted descending.
+	SortParam = "sort"
+	// Projected (comma-separated) fields.
+	FieldsParam = "fields"
+)
+
+// Filter operators.
+const (
+	OpEq    = "="
+	OpNeq   = "!="
+	OpGt    = ">"
+	OpGe    = ">="
+	OpLt    = "<"
+	OpLe    = "<="
+	OpMatch = "~="
+	OpIn    = "in"
+)
+
+// Filter functions.
+const (
+	// Length of a list, map or string.
+	FnLen = "len"
+	// Label value.
+	FnLabel = "label"
+)
+
+// Queried handler.
+// Supports server-side filtering, sorting and projection
+// of the resources in a collection. The filter is an expression
+// of comparisons combined with `and`, `or`, `not` and parentheses:
+//
+//	filter=name = 'db' and (cpuCount > 4 or len(disks) >= 2)
+//	filter=disks.datastore.id in ('ds-1','ds-2') and guestId ~= '^windows'
+//	filter=label('env') = 'prod'
+//
+// Fields are JSON paths in the resource. A path through a list
+// matches when any of the elements matches.
+// The comparisons on the columns of the model (and labels) are pushed
+// down to the DB as predicates. The rest is evaluated on the resources.
+type Queried struct {
+	// The query passed in the request.
+	Query Query
+	// Filter evaluated on the resources.
+	residual Expr
+	// Page applied on the resources.
+	page *model.Page
+}
+
+// Prepare the handler to fulfil the request.
+// Set the `Query` field using passed parameters.
+func (h *Queried) Prepare(ctx *gin.Context) int {
+	q := ctx.Request.URL.Query()
+	query, err := ParseQuery(
+		q.Get(FilterParam),
+		q.Get(SortParam),
+		q.Get(FieldsParam))
+	if err != nil {
+		log.V(3).Info(
+			"query not valid.",
+			"url",
+			ctx.Request.URL,
+			"reason",
+			err.Error())
+		return http.StatusBadRequest
+	}
+
+	h.Query = query
+	h.residual = query.Filter
+	h.page = nil
+
+	return http.StatusOK
+}
+
+// Push the query down to the list options of the model.
+// The part of the filter that maps onto the model columns is
+// added to the predicate. Paging is deferred to the resources
+// when the filter is not fully pushed down or a sort is requested.
+// Labels can only be pushed down, see Apply().
+func (h *Queried) Pushdown(options *model.ListOptions, m model.Model) {
+	h.residual = h.Query.Filter
+	if h.Query.Filter != nil {
+		md, err := model.Inspect(m)
+		if err != nil {
+			return
+		}
+		var pushed model.Predicate
+		pushed, h.residual = split(h.Query.Filter, md.Fields)
+		if pushed != nil {
+			if options.Predicate != nil {
+				options.Predicate = model.And(options.Predicate, pushed)
+			} else {
+				options.Predicate = pushed
+			}
+		}
+	}
+	if options.Page != nil && (h.residual != nil || len(h.Query.Sort) > 0) {
+		page := *options.Page
+		h.page = &page
+		options.Page = nil
+	}
+}
+
+// Defer paging to Apply().
+// For collections that are not listed from the DB.
+func (h *Queried) DeferPage(page *model.Page) {
+	deferred := *page
+	h.page = &deferred
+}
+
+// Apply the query to the resources.
+// Filter (not pushed down), sort, page and project.
+// Returns QueryErr when the filter references labels
+// that have not been pushed down.
+func (h *Queried) Apply(content []interface{}) (filtered []interface{}, err error) {
+	filtered = content
+	if h.Query.Empty() && h.page == nil {
+		return
+	}
+	if h.residual != nil && hasLabel(h.residual) {
+		err = liberr.Wrap(QueryErr, "reason", "label() must be pushed down, combine with 'and'.")
+		return
+	}
+	objects := make([]interface{}, 0, len(content))
+	kept := make([]interface{}, 0, len(content))
+	for _, r := range content {
+		var object interface{}
+		object, err = asObject(r)
+		if err != nil {
+			return
+		}
+		if h.residual == nil || h.residual.Eval(object) {
+			objects = append(objects, object)
+			kept = append(kept, r)
+		}
+	}
+	if len(h.Query.Sort) > 0 {
+		sort.Stable(&sorter{keys: h.Query.Sort, objects: objects, content: kept})
+	}
+	if h.page != nil {
+		h.page.Slice(&objects)
+		h.page.Slice(&kept)
+	}
+	if len(h.Query.Fields) > 0 {
+		filtered = make([]interface{}, 0, len(objects))
+		for _, object := range objects {
+			filtered = append(filtered, project(object, h.Query.Fields))
+		}
+	} else {
+		filtered = kept
+	}
+
+	return
+}
+
+// Query errors.
+var (
+	QueryErr = liberr.New("query not valid.")
+)
+
+// Query.
+type Query struct {
+	// Filter expression.
+	Filter Expr
+	// Sort keys.
+	Sort []SortKey
+	// Projected fields.
+	Fields []string
+}
+
+// Empty query.
+func (q *Query) Empty() bool {
+	return q.Filter == nil && len(q.Sort) == 0 && len(q.Fields) == 0
+}
+
+// Sort key.
+type SortKey struct {
+	// Field path.
+	Path string
+	// Descending.
+	Descending bool
+}
+
+// Parse the query parameters.
+func ParseQuery(filter, sortBy, fields string) (q Query, err error) {
+	if strings.TrimSpace(filter) != "" {
+		p := parser{}
+		q.Filter, err = p.parse(filter)
+		if err != nil {
+			return
+		}
+	}
+	for _, key := range strings.Split(sortBy, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		sk := SortKey{Path: key}
+		if strings.HasPrefix(key, "-") {
+			sk.Path = key[1:]
+			sk.Descending = true
+		}
+		sk.Path = strings.TrimPrefix(sk.Path, "+")
+		if sk.Path == "" {
+			err = liberr.Wrap(QueryErr, "reason", "sort field expected.")
+			return
+		}
+		q.Sort = append(q.Sort, sk)
+	}
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			q.Fields = append(q.Fields, field)
+		}
+	}
+
+	return
+}
+
+// Filter expression.
+type Expr interface {
+	// Evaluate on the (JSON decoded) resource.
+	Eval(object interface{}) bool
+}
+
+// AND expression.
+type AndExpr struct {
+	List []Expr
+}
+
+// Evaluate.
+func (e *AndExpr) Eval(object interface{}) bool {
+	for _, x := range e.List {
+		if !x.Eval(object) {
+			return false
+		}
+	}
+	return true
+}
+
+// OR expression.
+type OrExpr struct {
+	List []Expr
+}
+
+// Evaluate.
+func (e *OrExpr) Eval(object interface{}) bool {
+	for _, x := range e.List {
+		if x.Eval(object) {
+			return true
+		}
+	}
+	return false
+}
+
+// NOT expression.
+type NotExpr struct {
+	Expr Expr
+}
+
+// Evaluate.
+func (e *NotExpr) Eval(object interface{}) bool {
+	return !e.Expr.Eval(object)
+}
+
+// Comparison expression.
+type CmpExpr struct {
+	// Function applied to the field.
+	Fn string
+	// Field path (label name).
+	Path string
+	// Operator.
+	Op string
+	// Values (literals).
+	Values []interface{}
+	// Compiled regex (match operator).
+	regex *regexp.Regexp
+}
+
+// Evaluate.
+// A field path through a list yields the values of all the elements,
+// the comparison is true when any of them matches. A missing field
+// matches only the `!=` operator.
+func (e *CmpExpr) Eval(object interface{}) bool {
+	var found []interface{}
+	switch e.Fn {
+	case FnLen:
+		n := 0
+		for _, v := range resolve(object, e.Path, false) {
+			switch v := v.(type) {
+			case []interface{}:
+				n += len(v)
+			case map[string]interface{}:
+				n += len(v)
+			case string:
+				n += len(v)
+			}
+		}
+		found = []interface{}{float64(n)}
+	case FnLabel:
+		return false
+	default:
+		found = resolve(object, e.Path, true)
+	}
+	if e.Op == OpNeq {
+		for _, v := range found {
+			if equal(v, e.Values[0]) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range found {
+		if e.match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match a value.
+func (e *CmpExpr) match(v interface{}) bool {
+	switch e.Op {
+	case OpEq, OpIn:
+		for _, x := range e.Values {
+			if equal(v, x) {
+				return true
+			}
+		}
+	case OpMatch:
+		if s, cast := v.(string); cast {
+			return e.regex.MatchString(s)
+		}
+	case OpGt, OpGe, OpLt, OpLe:
+		n, comparable := compare(v, e.Values[0])
+		if !comparable {
+			return false
+		}
+		switch e.Op {
+		case OpGt:
+			return n > 0
+		case OpGe:
+			return n >= 0
+		case OpLt:
+			return n < 0
+		case OpLe:
+			return n <= 0
+		}
+	}
+	return false
+}
+
+// Split the filter into the predicate pushed down
+// and the (residual) expression evaluated on the resources.
+// The conjuncts of a top-level AND are split individually.
+func split(e Expr, fields []*model.Field) (pushed model.Predicate, residual Expr) {
+	and, cast := e.(*AndExpr)
+	if !cast {
+		if p, ok := predicate(e, fields); ok {
+			pushed = p
+		} else {
+			residual = e
+		}
+		return
+	}
+	predicates := []model.Predicate{}
+	residuals := []Expr{}
+	for _, x := range and.List {
+		if p, ok := predicate(x, fields); ok {
+			predicates = append(predicates, p)
+		} else {
+			residuals = append(residuals, x)
+		}
+	}
+	switch len(predicates) {
+	case 0:
+	case 1:
+		pushed = predicates[0]
+	default:
+		pushed = model.And(predicates...)
+	}
+	switch len(residuals) {
+	case 0:
+	case 1:
+		residual = residuals[0]
+	default:
+		residual = &AndExpr{List: residuals}
+	}
+
+	return
+}
+
+// Map an expression onto a model predicate.
+func predicate(e Expr, fields []*model.Field) (p model.Predicate, ok bool) {
+	switch e := e.(type) {
+	case *AndExpr:
+		list := []model.Predicate{}
+		for _, x := range e.List {
+			px, pushed := predicate(x, fields)
+			if !pushed {
+				return
+			}
+			list = append(list, px)
+		}
+		p, ok = model.And(list...), true
+	case *OrExpr:
+		list := []model.Predicate{}
+		for _, x := range e.List {
+			px, pushed := predicate(x, fields)
+			if !pushed {
+				return
+			}
+			list = append(list, px)
+		}
+		p, ok = model.Or(list...), true
+	case *NotExpr:
+		if cmp, cast := e.Expr.(*CmpExpr); cast && cmp.Op == OpEq && cmp.Fn == "" {
+			negated := *cmp
+			negated.Op = OpNeq
+			p, ok = predicate(&negated, fields)
+		}
+	case *CmpExpr:
+		p, ok = e.predicate(fields)
+	}
+
+	return
+}
+
+// Map the comparison onto a model predicate.
+// Only comparisons of scalar columns with a literal of
+// the same type are mapped.
+func (e *CmpExpr) predicate(fields []*model.Field) (p model.Predicate, ok bool) {
+	switch e.Fn {
+	case FnLabel:
+		if e.Op == OpEq {
+			if s, cast := e.Values[0].(string); cast {
+				p, ok = model.Match(model.Labels{e.Path: s}), true
+			}
+		}
+		return
+	case FnLen:
+		return
+	}
+	if strings.Contains(e.Path, ".") {
+		return
+	}
+	var field *model.Field
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, e.Path) {
+			field = f
+			break
+		}
+	}
+	if field == nil {
+		return
+	}
+	values := []interface{}{}
+	for _, v := range e.Values {
+		v, matched := columnValue(field, v)
+		if !matched {
+			return
+		}
+		values = append(values, v)
+	}
+	ordered := false
+	switch field.Value.Kind() {
+	case reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64:
+		ordered = true
+	}
+	switch e.Op {
+	case OpEq:
+		p = model.Eq(field.Name, values[0])
+	case OpIn:
+		p = model.Eq(field.Name, values)
+	case OpNeq:
+		p = model.Neq(field.Name, values[0])
+	case OpGt, OpGe, OpLt, OpLe:
+		if !ordered {
+			return
+		}
+		switch e.Op {
+		case OpGt:
+			p = model.Gt(field.Name, values[0])
+		case OpGe:
+			p = model.Or(model.Gt(field.Name, values[0]), model.Eq(field.Name, values[0]))
+		case OpLt:
+			p = model.Lt(field.Name, values[0])
+		case OpLe:
+			p = model.Or(model.Lt(field.Name, values[0]), model.Eq(field.Name, values[0]))
+		}
+	default:
+		return
+	}
+
+	ok = true
+	return
+}
+
+// The literal as a value of the column.
+func columnValue(field *model.Field, v interface{}) (value interface{}, matched bool) {
+	switch field.Value.Kind() {
+	case reflect.String:
+		value, matched = v.(string)
+	case reflect.Bool:
+		value, matched = v.(bool)
+	case reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64:
+		if n, cast := v.(float64); cast && n == float64(int64(n)) {
+			value, matched = int64(n), true
+		}
+	}
+
+	return
+}
+
+// The expression references labels.
+func hasLabel(e Expr) bool {
+	switch e := e.(type) {
+	case *AndExpr:
+		for _, x := range e.List {
+			if hasLabel(x) {
+				return true
+			}
+		}
+	case *OrExpr:
+		for _, x := range e.List {
+			if hasLabel(x) {
+				return true
+			}
+		}
+	case *NotExpr:
+		return hasLabel(e.Expr)
+	case *CmpExpr:
+		return e.Fn == FnLabel
+	}
+	return false
+}
+
+// The resource as a (JSON decoded) object.
+func asObject(r interface{}) (object interface{}, err error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = json.Unmarshal(b, &object)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Resolve the values of a field path.
+// Lists are traversed, the values of all the elements are returned.
+// When flatten, the resolved lists are expanded as well.
+// Keys are matched exactly, then case-insensitive.
+func resolve(object interface{}, path string, flatten bool) (values []interface{}) {
+	current := []interface{}{object}
+	for _, key := range strings.Split(path, ".") {
+		next := []interface{}{}
+		for _, v := range current {
+			for _, v := range expand(v) {
+				m, cast := v.(map[string]interface{})
+				if !cast {
+					continue
+				}
+				if found, ok := lookup(m, key); ok {
+					next = append(next, found)
+				}
+			}
+		}
+		current = next
+	}
+	if !flatten {
+		values = current
+		return
+	}
+	for _, v := range current {
+		values = append(values, expand(v)...)
+	}
+
+	return
+}
+
+// Expand a list into its elements.
+func expand(v interface{}) []interface{} {
+	if list, cast := v.([]interface{}); cast {
+		return list
+	}
+	return []interface{}{v}
+}
+
+// Lookup a key in a map.
+func lookup(m map[string]interface{}, key string) (v interface{}, found bool) {
+	v, found = m[key]
+	if found {
+		return
+	}
+	for k, kv := range m {
+		if strings.EqualFold(k, key) {
+			v, found = kv, true
+			return
+		}
+	}
+	return
+}
+
+// Values are equal.
+func equal(a, b interface{}) bool {
+	if n, comparable := compare(a, b); comparable {
+		return n == 0
+	}
+	return false
+}
+
+// Compare values of the same type.
+func compare(a, b interface{}) (n int, comparable bool) {
+	switch a := a.(type) {
+	case float64:
+		if b, cast := b.(float64); cast {
+			comparable = true
+			switch {
+			case a < b:
+				n = -1
+			case a > b:
+				n = 1
+			}
+		}
+	case string:
+		if b, cast := b.(string); cast {
+			comparable = true
+			n = strings.Compare(a, b)
+		}
+	case bool:
+		if b, cast := b.(bool); cast {
+			comparable = true
+			switch {
+			case a == b:
+			case !a:
+				n = -1
+			default:
+				n = 1
+			}
+		}
+	}
+
+	return
+}
+
+// Resource sorter.
+type sorter struct {
+	keys    []SortKey
+	objects []interface{}
+	content []interface{}
+}
+
+func (s *sorter) Len() int {
+	return len(s.objects)
+}
+
+func (s *sorter) Swap(i, j int) {
+	s.objects[i], s.objects[j] = s.objects[j], s.objects[i]
+	s.content[i], s.content[j] = s.content[j], s.content[i]
+}
+
+// Compare by the first value of each key.
+// Missing values sort first.
+func (s *sorter) Less(i, j int) bool {
+	for _, key := range s.keys {
+		a := resolve(s.objects[i], key.Path, true)
+		b := resolve(s.objects[j], key.Path, true)
+		n := 0
+		switch {
+		case len(a) == 0 && len(b) == 0:
+		case len(a) == 0:
+			n = -1
+		case len(b) == 0:
+			n = 1
+		default:
+			n, _ = compare(a[0], b[0])
+		}
+		if key.Descending {
+			n = -n
+		}
+		if n != 0 {
+			return n < 0
+		}
+	}
+	return false
+}
+
+// Project the fields of an object.
+// Paths through lists are projected on each element.
+func project(object interface{}, paths []string) interface{} {
+	switch v := object.(type) {
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, element := range v {
+			list = append(list, project(element, paths))
+		}
+		return list
+	case map[string]interface{}:
+		nested := map[string][]string{}
+		keys := []string{}
+		for _, path := range paths {
+			key, rest, _ := strings.Cut(path, ".")
+			if _, found := nested[key]; !found {
+				keys = append(keys, key)
+				nested[key] = []string{}
+			}
+			if rest != "" {
+				nested[key] = append(nested[key], rest)
+			} else {
+				nested[key] = nil
+			}
+		}
+		projected := map[string]interface{}{}
+		for _, key := range keys {
+			for k, kv := range v {
+				if k != key && !strings.EqualFold(k, key) {
+					continue
+				}
+				if sub := nested[key]; len(sub) > 0 {
+					projected[k] = project(kv, sub)
+				} else {
+					projected[k] = kv
+				}
+				break
+			}
+		}
+		return projected
+	default:
+		return object
+	}
+}
+
+// Filter tokens.
+const (
+	tkEnd = iota
+	tkIdent
+	tkString
+	tkNumber
+	tkOp
+	tkLParen
+	tkRParen
+	tkComma
+)
+
+// Filter token.
+type token struct {
+	kind  int
+	value string
+	pos   int
+}
+
+// Filter expression parser.
+//
+//	expr    = and { "or" and }
+//	and     = unary { "and" unary }
+//	unary   = "not" unary | "(" expr ")" | cmp
+//	cmp     = operand op literal | operand "in" "(" literal { "," literal } ")"
+//	operand = path | "len" "(" path ")" | "label" "(" string ")"
+//	literal = string | number | "true" | "false"
+type parser struct {
+	tokens []token
+	next   int
+}
+
+// Parse the filter.
+func (p *parser) parse(filter string) (e Expr, err error) {
+	p.tokens, err = tokenize(filter)
+	if err != nil {
+		return
+	}
+	p.next = 0
+	e, err = p.or()
+	if err != nil {
+		return
+	}
+	if t := p.peek(); t.kind != tkEnd {
+		err = p.error(t, "unexpected token")
+	}
+	return
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) pop() token {
+	t := p.tokens[p.next]
+	if t.kind != tkEnd {
+		p.next++
+	}
+	return t
+}
+
+// Next token is the keyword.
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tkIdent && strings.EqualFold(t.value, word) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *parser) error(t token, reason string) error {
+	if t.kind == tkEnd {
+		return liberr.Wrap(QueryErr, "reason", reason+" at end.")
+	}
+	return liberr.Wrap(
+		QueryErr,
+		"reason",
+		fmt.Sprintf("%s '%s' at %d.", reason, t.value, t.pos))
+}
+
+func (p *parser) or() (e Expr, err error) {
+	e, err = p.and()
+	if err != nil {
+		return
+	}
+	list := []Expr{e}
+	for p.keyword("or") {
+		e, err = p.and()
+		if err != nil {
+			return
+		}
+		list = append(list, e)
+	}
+	if len(list) > 1 {
+		e = &OrExpr{List: list}
+	}
+	return
+}
+
+func (p *parser) and() (e Expr, err error) {
+	e, err = p.unary()
+	if err != nil {
+		return
+	}
+	list := []Expr{e}
+	for p.keyword("and") {
+		e, err = p.unary()
+		if err != nil {
+			return
+		}
+		list = append(list, e)
+	}
+	if len(list) > 1 {
+		e = &AndExpr{List: list}
+	}
+	return
+}
+
+func (p *parser) unary() (e Expr, err error) {
+	if p.keyword("not") {
+		e, err = p.unary()
+		if err == nil {
+			e = &NotExpr{Expr: e}
+		}
+		return
+	}
+	if p.peek().kind == tkLParen {
+		p.pop()
+		e, err = p.or()
+		if err != nil {
+			return
+		}
+		if t := p.pop(); t.kind != tkRParen {
+			err = p.error(t, "')' expected, found")
+		}
+		return
+	}
+	e, err = p.cmp()
+	return
+}
+
+func (p *parser) cmp() (e Expr, err error) {
+	cmp := &CmpExpr{}
+	t := p.pop()
+	if t.kind != tkIdent {
+		err = p.error(t, "field expected, found")
+		return
+	}
+	cmp.Path = t.value
+	if p.peek().kind == tkLParen {
+		cmp.Fn = strings.ToLower(t.value)
+		p.pop()
+		arg := p.pop()
+		switch cmp.Fn {
+		case FnLen:
+			if arg.kind != tkIdent {
+				err = p.error(arg, "field expected, found")
+				return
+			}
+		case FnLabel:
+			if arg.kind != tkString {
+				err = p.error(arg, "label name expected, found")
+				return
+			}
+		default:
+			err = p.error(t, "unknown function")
+			return
+		}
+		cmp.Path = arg.value
+		if t = p.pop(); t.kind != tkRParen {
+			err = p.error(t, "')' expected, found")
+			return
+		}
+	}
+	if p.keyword(OpIn) {
+		cmp.Op = OpIn
+		if t = p.pop(); t.kind != tkLParen {
+			err = p.error(t, "'(' expected, found")
+			return
+		}
+		for {
+			var v interface{}
+			v, err = p.literal()
+			if err != nil {
+				return
+			}
+			cmp.Values = append(cmp.Values, v)
+			t = p.pop()
+			if t.kind == tkRParen {
+				break
+			}
+			if t.kind != tkComma {
+				err = p.error(t, "',' or ')' expected, found")
+				return
+			}
+		}
+	} else {
+		t = p.pop()
+		if t.kind != tkOp {
+			err = p.error(t, "operator expected, found")
+			return
+		}
+		cmp.Op = t.value
+		var v interface{}
+		v, err = p.literal()
+		if err != nil {
+			return
+		}
+		cmp.Values = []interface{}{v}
+	}
+	if cmp.Op == OpMatch {
+		s, cast := cmp.Values[0].(string)
+		if !cast {
+			err = liberr.Wrap(QueryErr, "reason", "'~=' expects a string.")
+			return
+		}
+		cmp.regex, err = regexp.Compile(s)
+		if err != nil {
+			err = liberr.Wrap(QueryErr, "reason", err.Error())
+			return
+		}
+	}
+	if cmp.Fn == FnLabel && cmp.Op != OpEq {
+		err = liberr.Wrap(QueryErr, "reason", "label() supports '=' only.")
+		return
+	}
+
+	e = cmp
+	return
+}
+
+func (p *parser) literal() (v interface{}, err error) {
+	t := p.pop()
+	switch t.kind {
+	case tkString:
+		v = t.value
+	case tkNumber:
+		v, err = strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			err = p.error(t, "number not valid")
+		}
+	case tkIdent:
+		switch strings.ToLower(t.value) {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		default:
+			err = p.error(t, "literal expected, found")
+		}
+	default:
+		err = p.error(t, "literal expected, found")
+	}
+	return
+}
+
+// Tokenize the filter.
+func tokenize(filter string) (tokens []token, err error) {
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tkLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tkRParen, value: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tkComma, value: ",", pos: i})
+			i++
+		case r == '\'' || r == '"':
+			start := i
+			value := []rune{}
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value = append(value, runes[i])
+			}
+			if i == len(runes) {
+				err = liberr.Wrap(QueryErr, "reason", fmt.Sprintf("string not terminated at %d.", start))
+				return
+			}
+			i++
+			tokens = append(tokens, token{kind: tkString, value: string(value), pos: start})
+		case strings.ContainsRune("=!<>~", r):
+			start := i
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			switch op {
+			case OpEq, OpNeq, OpGt, OpGe, OpLt, OpLe, OpMatch:
+			default:
+				err = liberr.Wrap(QueryErr, "reason", fmt.Sprintf("operator '%s' not valid at %d.", op, start))
+				return
+			}
+			i += len(op)
+			tokens = append(tokens, token{kind: tkOp, value: op, pos: start})
+		case r == '-' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tkNumber, value: string(runes[start:i]), pos: start})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '.' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tkIdent, value: string(runes[start:i]), pos: start})
+		default:
+			err = liberr.Wrap(QueryErr, "reason", fmt.Sprintf("character '%c' not valid at %d.", r, i))
+			return
+		}
+	}
+	tokens = append(tokens, token{kind: tkEnd, pos: len(runes)})
+	return
+}
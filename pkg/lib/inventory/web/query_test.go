@@ -0,0 +1,175 @@
+package web
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/onsi/gomega"
+)
+
+type TestDisk struct {
+	Datastore string `json:"datastore"`
+	Capacity  int64  `json:"capacity"`
+}
+
+type TestVM struct {
+	ID      string     `sql:"pk" json:"id"`
+	Name    string     `sql:"" json:"name"`
+	GuestID string     `sql:"" json:"guestId"`
+	CPUs    int        `sql:"" json:"cpus"`
+	Disks   []TestDisk `sql:"" json:"disks"`
+	Labeled model.Labels
+}
+
+func (m *TestVM) Pk() string {
+	return m.ID
+}
+
+func (m *TestVM) Labels() model.Labels {
+	return m.Labeled
+}
+
+func TestParseQuery(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	for _, filter := range []string{
+		"name = 'a'",
+		"name = \"a\" and cpus > 4",
+		"(name = 'a' or name != 'b') and not cpus <= 2",
+		"disks.datastore in ('ds-1', 'ds-2')",
+		"len(disks) >= 2 and guestId ~= '^windows'",
+		"label('env') = 'prod'",
+		"name = 'it\\'s'",
+		"cpus = -1.5 or template = true",
+	} {
+		_, err := ParseQuery(filter, "", "")
+		g.Expect(err).To(gomega.BeNil(), filter)
+	}
+
+	for _, filter := range []string{
+		"name",
+		"name =",
+		"name = 'a",
+		"name == 'a'",
+		"(name = 'a'",
+		"name = 'a' and",
+		"size(disks) > 1",
+		"guestId ~= '('",
+		"label('env') != 'prod'",
+		"name = 'a' cpus = 1",
+		"name = $",
+	} {
+		_, err := ParseQuery(filter, "", "")
+		g.Expect(err).ToNot(gomega.BeNil(), filter)
+	}
+
+	q, err := ParseQuery("", "name, -cpus", "id,disks.capacity")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(q.Sort).To(gomega.Equal([]SortKey{
+		{Path: "name"},
+		{Path: "cpus", Descending: true},
+	}))
+	g.Expect(q.Fields).To(gomega.Equal([]string{"id", "disks.capacity"}))
+}
+
+func TestQuery(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := model.New("/tmp/test-query.db", &TestVM{})
+	err := db.Open(true)
+	g.Expect(err).To(gomega.BeNil())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	for _, m := range []*TestVM{
+		{
+			ID:      "1",
+			Name:    "web",
+			GuestID: "windows2019srv_64Guest",
+			CPUs:    8,
+			Disks:   []TestDisk{{"ds-1", 10}, {"ds-2", 20}, {"ds-2", 30}, {"ds-1", 40}, {"ds-1", 50}},
+			Labeled: model.Labels{"env": "prod"},
+		},
+		{
+			ID:      "2",
+			Name:    "db",
+			GuestID: "rhel8_64Guest",
+			CPUs:    16,
+			Disks:   []TestDisk{{"ds-1", 10}},
+			Labeled: model.Labels{"env": "prod"},
+		},
+		{
+			ID:      "3",
+			Name:    "cache",
+			GuestID: "windows9_64Guest",
+			CPUs:    2,
+			Disks:   []TestDisk{{"ds-2", 10}, {"ds-2", 10}, {"ds-2", 10}, {"ds-2", 10}, {"ds-2", 10}},
+			Labeled: model.Labels{"env": "test"},
+		},
+	} {
+		err = db.Insert(m)
+		g.Expect(err).To(gomega.BeNil())
+	}
+
+	list := func(filter, sortBy, fields string, page *model.Page) (content []interface{}, options model.ListOptions) {
+		h := Queried{}
+		h.Query, err = ParseQuery(filter, sortBy, fields)
+		g.Expect(err).To(gomega.BeNil())
+		options = model.ListOptions{Detail: model.MaxDetail, Page: page}
+		h.Pushdown(&options, &TestVM{})
+		models := []TestVM{}
+		err = db.List(&models, options)
+		g.Expect(err).To(gomega.BeNil())
+		for i := range models {
+			content = append(content, &models[i])
+		}
+		content, err = h.Apply(content)
+		g.Expect(err).To(gomega.BeNil())
+		return
+	}
+	ids := func(content []interface{}) (ids []string) {
+		for _, r := range content {
+			ids = append(ids, r.(*TestVM).ID)
+		}
+		return
+	}
+
+	// Pushed down.
+	content, options := list("cpus >= 8 and name != 'db'", "", "", nil)
+	g.Expect(ids(content)).To(gomega.Equal([]string{"1"}))
+	g.Expect(options.Predicate).ToNot(gomega.BeNil())
+	content, _ = list("label('env') = 'prod'", "", "", nil)
+	g.Expect(ids(content)).To(gomega.ConsistOf("1", "2"))
+	content, options = list("name in ('db', 'cache')", "", "", &model.Page{Limit: 1})
+	g.Expect(content).To(gomega.HaveLen(1))
+	g.Expect(options.Page).ToNot(gomega.BeNil())
+	// Windows VMs with more than 4 disks on ds-1.
+	content, _ = list("guestId ~= '^windows' and len(disks) > 4 and disks.datastore = 'ds-1'", "", "", nil)
+	g.Expect(ids(content)).To(gomega.Equal([]string{"1"}))
+	content, _ = list("not (disks.datastore = 'ds-1') or cpus > 10", "", "", nil)
+	g.Expect(ids(content)).To(gomega.ConsistOf("2", "3"))
+	content, _ = list("disks.datastore != 'ds-1'", "", "", nil)
+	g.Expect(ids(content)).To(gomega.Equal([]string{"3"}))
+	// Sorted and paged.
+	content, options = list("", "-cpus", "", &model.Page{Offset: 1, Limit: 1})
+	g.Expect(ids(content)).To(gomega.Equal([]string{"1"}))
+	g.Expect(options.Page).To(gomega.BeNil())
+	content, _ = list("len(disks) > 1", "name", "", nil)
+	g.Expect(ids(content)).To(gomega.Equal([]string{"3", "1"}))
+	// Projected.
+	content, _ = list("name = 'db'", "", "id,disks.capacity", nil)
+	g.Expect(content).To(gomega.Equal([]interface{}{
+		map[string]interface{}{
+			"id": "2",
+			"disks": []interface{}{
+				map[string]interface{}{"capacity": float64(10)},
+			},
+		},
+	}))
+	// Labels cannot be evaluated on the resources.
+	h := Queried{}
+	h.Query, _ = ParseQuery("label('env') = 'prod' or len(disks) > 1", "", "")
+	h.Pushdown(&model.ListOptions{}, &TestVM{})
+	_, err = h.Apply([]interface{}{})
+	g.Expect(errors.Is(err, QueryErr)).To(gomega.BeTrue())
+}
@@ -0,0 +1,187 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	ec2client "github.com/kubev2v/forklift/pkg/provider/ec2/inventory/client"
+	"github.com/kubev2v/forklift/pkg/settings"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// DeltaCopyApp is the app label value of the resources copying the precopy deltas.
+	DeltaCopyApp = "ec2-delta-copy"
+	// AppLabel is the label identifying the resources copying the precopy deltas.
+	AppLabel = "app"
+	// PrecopyLabel holds the name of the precopy copied by a delta copy pod.
+	PrecopyLabel = "forklift.konveyor.io/precopy"
+	// VolumeIDLabel holds the source EC2 volume ID of a PVC.
+	VolumeIDLabel = "forklift.konveyor.io/volume-id"
+	// AnnIngressBandwidth limits the ingress bandwidth of a pod.
+	AnnIngressBandwidth = "kubernetes.io/ingress-bandwidth"
+)
+
+// Delta copy pod settings.
+const (
+	deltaCopyContainerName = "delta-copy"
+	deltaCopyVolumeName    = "target"
+	deltaCopyDevicePath    = "/dev/block"
+	// UID and GID of the qemu user owning the disks.
+	qemuUser  = int64(107)
+	qemuGroup = int64(107)
+)
+
+// DeltaCopyLabels returns the labels of the delta copy resources of a VM.
+// The pod labels also hold the precopy and the source volume ID.
+func (r *Builder) DeltaCopyLabels(vmRef ref.Ref) map[string]string {
+	labels := r.Labeler.VMLabels(vmRef)
+	labels[AppLabel] = DeltaCopyApp
+	return labels
+}
+
+// BuildDeltaCopySecret builds the secret holding the AWS credentials of the delta copy pods.
+// The credentials are exposed as the standard AWS environment variables. When the provider
+// secret has no access keys, the pods rely on the default credential chain (e.g. pod identity).
+func (r *Builder) BuildDeltaCopySecret(vmRef ref.Ref) (*core.Secret, error) {
+	region, accessKeyID, secretAccessKey, err := ec2client.ExtractCredentials(r.Source.Secret)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+
+	data := map[string][]byte{
+		"AWS_REGION": []byte(region),
+	}
+	if accessKeyID != "" && secretAccessKey != "" {
+		data["AWS_ACCESS_KEY_ID"] = []byte(accessKeyID)
+		data["AWS_SECRET_ACCESS_KEY"] = []byte(secretAccessKey)
+	}
+
+	secret := &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-%s-delta-copy-", r.Plan.Name, vmRef.ID),
+			Namespace:    r.Plan.Spec.TargetNamespace,
+			Labels:       r.DeltaCopyLabels(vmRef),
+		},
+		Data: data,
+		Type: core.SecretTypeOpaque,
+	}
+
+	return secret, nil
+}
+
+// BuildDeltaCopyPod builds the pod writing the blocks that changed between two EBS snapshots
+// of a source volume to the block PVC of the volume. The pod runs in the target availability
+// zone so the EBS volume backing the PVC can be attached. The rate limit is in MB/s, zero is unlimited.
+func (r *Builder) BuildDeltaCopyPod(
+	vmRef ref.Ref,
+	pvc *core.PersistentVolumeClaim,
+	precopy, firstSnapshot, secondSnapshot, secretName string,
+	rateLimit int) (*core.Pod, error) {
+	if settings.Settings.Migration.EC2DeltaCopyImage == "" {
+		return nil, liberr.New("The EC2 delta copy image is not configured.")
+	}
+
+	labels := r.DeltaCopyLabels(vmRef)
+	labels[PrecopyLabel] = precopy
+	labels[VolumeIDLabel] = pvc.Labels[VolumeIDLabel]
+
+	annotations := make(map[string]string)
+	if rateLimit > 0 {
+		annotations[AnnIngressBandwidth] = fmt.Sprintf("%dM", rateLimit*8)
+	}
+
+	var nodeSelector map[string]string
+	if targetAZ, err := r.getTargetAZ(); err == nil {
+		nodeSelector = map[string]string{TopologyZoneLabel: targetAZ}
+	}
+
+	args := []string{
+		"--volume-path=" + deltaCopyDevicePath,
+		"--second-snapshot=" + secondSnapshot,
+	}
+	if firstSnapshot != "" {
+		args = append(args, "--first-snapshot="+firstSnapshot)
+	}
+
+	migrationSettings := settings.Settings.Migration
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-%s-delta-copy-", r.Plan.Name, vmRef.ID),
+			Namespace:    pvc.Namespace,
+			Labels:       labels,
+			Annotations:  annotations,
+		},
+		Spec: core.PodSpec{
+			RestartPolicy: core.RestartPolicyNever,
+			NodeSelector:  nodeSelector,
+			Containers: []core.Container{
+				{
+					Name:  deltaCopyContainerName,
+					Image: migrationSettings.EC2DeltaCopyImage,
+					Args:  args,
+					EnvFrom: []core.EnvFromSource{
+						{
+							SecretRef: &core.SecretEnvSource{
+								LocalObjectReference: core.LocalObjectReference{Name: secretName},
+							},
+						},
+					},
+					VolumeDevices: []core.VolumeDevice{
+						{
+							Name:       deltaCopyVolumeName,
+							DevicePath: deltaCopyDevicePath,
+						},
+					},
+					Resources: core.ResourceRequirements{
+						Requests: core.ResourceList{
+							core.ResourceCPU:    migrationSettings.PopulatorContainerRequestsCpu,
+							core.ResourceMemory: migrationSettings.PopulatorContainerRequestsMemory,
+						},
+						Limits: core.ResourceList{
+							core.ResourceCPU:    migrationSettings.PopulatorContainerLimitsCpu,
+							core.ResourceMemory: migrationSettings.PopulatorContainerLimitsMemory,
+						},
+					},
+					TerminationMessagePolicy: core.TerminationMessageFallbackToLogsOnError,
+					SecurityContext: &core.SecurityContext{
+						AllowPrivilegeEscalation: ptr.To(false),
+						RunAsNonRoot:             ptr.To(true),
+						RunAsUser:                ptr.To(qemuUser),
+						Capabilities: &core.Capabilities{
+							Drop: []core.Capability{"ALL"},
+						},
+					},
+				},
+			},
+			Volumes: []core.Volume{
+				{
+					Name: deltaCopyVolumeName,
+					VolumeSource: core.VolumeSource{
+						PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvc.Name,
+						},
+					},
+				},
+			},
+			SecurityContext: &core.PodSecurityContext{
+				FSGroup: ptr.To(qemuGroup),
+				SeccompProfile: &core.SeccompProfile{
+					Type: core.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+		},
+	}
+
+	r.log.Info("Built delta copy pod spec",
+		"vm", vmRef.Name,
+		"pvc", pvc.Name,
+		"precopy", precopy,
+		"firstSnapshot", firstSnapshot,
+		"secondSnapshot", secondSnapshot)
+
+	return pod, nil
+}
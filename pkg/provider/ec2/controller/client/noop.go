@@ -18,15 +18,10 @@ func (r *Client) DetachDisks(vmRef ref.Ref) error {
 	return nil
 }
 
-// SetCheckpoints is a no-op for EC2 - only cold migration supported, no checkpoints or incremental tracking.
+// SetCheckpoints is a no-op for EC2 - the changed blocks are tracked by the EBS snapshots, no checkpoints.
 func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool, hostsFunc util.HostsFunc) error {
 	return nil
 }
 
-// GetSnapshotDeltas is a no-op for EC2 - uses complete EBS snapshots, not incremental deltas.
-func (r *Client) GetSnapshotDeltas(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (map[string]string, error) {
-	return make(map[string]string), nil
-}
-
 // Compile-time interface check. Ensures Client implements required base.Client interface.
 var _ base.Client = &Client{}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// PrecopyTag identifies the incremental snapshots taken for the precopies of a warm migration.
+// The tag value is the name of the precopy so the snapshots of a precopy can be found again.
+const PrecopyTag = "forklift.konveyor.io/precopy"
+
+// CreatePrecopySnapshots creates incremental EBS snapshots of all volumes attached to an EC2 instance
+// for a warm migration precopy. EBS snapshots of a volume are incremental, only the blocks that
+// changed since the previous snapshot are stored. Returns the snapshots already created for the
+// precopy, if any, so the operation can be retried.
+// Returns comma-separated snapshot IDs.
+func (r *Client) CreatePrecopySnapshots(vmRef ref.Ref, precopy string) (string, error) {
+	snapshotMap, err := r.getPrecopySnapshots(vmRef, precopy)
+	if err != nil {
+		return "", err
+	}
+	if len(snapshotMap) > 0 {
+		log.Info("Precopy snapshots already exist in AWS, skipping creation",
+			"vm", vmRef.Name,
+			"precopy", precopy,
+			"snapshotCount", len(snapshotMap))
+		return joinSnapshotIDs(snapshotMap), nil
+	}
+
+	return r.createVolumeSnapshots(
+		vmRef,
+		ec2types.Tag{Key: aws.String(PrecopyTag), Value: aws.String(precopy)})
+}
+
+// GetPrecopySnapshotIDsForVM returns a comma-separated string of the IDs of all the
+// precopy snapshots of the VM, for cleanup.
+func (r *Client) GetPrecopySnapshotIDsForVM(vmRef ref.Ref) (string, error) {
+	snapshotMap, err := r.getPrecopySnapshots(vmRef, "")
+	if err != nil {
+		return "", err
+	}
+	return joinSnapshotIDs(snapshotMap), nil
+}
+
+// GetSnapshotDeltas maps the source volume ID to the ID of its snapshot for
+// the comma-separated snapshot IDs of a precopy.
+func (r *Client) GetSnapshotDeltas(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (map[string]string, error) {
+	deltas := make(map[string]string)
+	snapshotIDs := splitSnapshotIDs(snapshot)
+	if len(snapshotIDs) == 0 {
+		return deltas, nil
+	}
+
+	client, err := r.getSourceClient()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.DescribeSnapshots(context.Background(), &ec2.DescribeSnapshotsInput{
+		SnapshotIds: snapshotIDs,
+	})
+	if err != nil {
+		log.Error(err, "Failed to describe snapshots", "vm", vmRef.Name)
+		return nil, liberr.Wrap(err)
+	}
+
+	for _, snapshot := range result.Snapshots {
+		volumeID := tagValue(snapshot.Tags, "forklift.konveyor.io/volume")
+		if volumeID == "" {
+			volumeID = aws.ToString(snapshot.VolumeId)
+		}
+		deltas[volumeID] = aws.ToString(snapshot.SnapshotId)
+	}
+
+	return deltas, nil
+}
+
+// getPrecopySnapshots queries AWS for the precopy snapshots of the VM.
+// All the precopy snapshots are returned when the precopy is empty.
+// Returns a map of snapshotID -> volumeID.
+func (r *Client) getPrecopySnapshots(vmRef ref.Ref, precopy string) (map[string]string, error) {
+	client, err := r.getSourceClient()
+	if err != nil {
+		return nil, err
+	}
+
+	precopyFilter := ec2types.Filter{
+		Name:   aws.String("tag-key"),
+		Values: []string{PrecopyTag},
+	}
+	if precopy != "" {
+		precopyFilter = ec2types.Filter{
+			Name:   aws.String("tag:" + PrecopyTag),
+			Values: []string{precopy},
+		}
+	}
+
+	result, err := client.DescribeSnapshots(context.Background(), &ec2.DescribeSnapshotsInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("tag:forklift.konveyor.io/vmID"),
+				Values: []string{vmRef.ID},
+			},
+			precopyFilter,
+		},
+	})
+	if err != nil {
+		log.Error(err, "Failed to query precopy snapshots for VM", "vm", vmRef.Name, "precopy", precopy)
+		return nil, liberr.Wrap(err)
+	}
+
+	snapshotMap := make(map[string]string)
+	for _, snapshot := range result.Snapshots {
+		snapshotMap[aws.ToString(snapshot.SnapshotId)] = tagValue(snapshot.Tags, "forklift.konveyor.io/volume")
+	}
+
+	return snapshotMap, nil
+}
+
+// joinSnapshotIDs joins the snapshot IDs keys of a map with commas.
+func joinSnapshotIDs(snapshotMap map[string]string) string {
+	snapshotIDs := make([]string, 0, len(snapshotMap))
+	for snapshotID := range snapshotMap {
+		snapshotIDs = append(snapshotIDs, snapshotID)
+	}
+	return strings.Join(snapshotIDs, ",")
+}
+
+// hasTag returns whether the tags contain the key.
+func hasTag(tags []ec2types.Tag, key string) bool {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// tagValue returns the value of the tag with the key, or an empty string.
+func tagValue(tags []ec2types.Tag, key string) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
@@ -18,10 +18,21 @@ import (
 // Returns comma-separated snapshot IDs for use in subsequent operations.
 // Tags each snapshot with VM ID (for lookups), VM name (for display), and source volume ID.
 func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (string, string, error) {
-	client, err := r.getSourceClient()
+	snapshotIDString, err := r.createVolumeSnapshots(vmRef)
 	if err != nil {
 		return "", "", err
 	}
+	return snapshotIDString, "", nil
+}
+
+// createVolumeSnapshots creates a snapshot of each EBS volume attached to an EC2 instance.
+// The extra tags are added to the VM ID, VM name and source volume ID tags.
+// Returns comma-separated snapshot IDs.
+func (r *Client) createVolumeSnapshots(vmRef ref.Ref, extraTags ...ec2types.Tag) (string, error) {
+	client, err := r.getSourceClient()
+	if err != nil {
+		return "", err
+	}
 
 	log.Info("Creating EBS volume snapshots", "vm", vmRef.Name, "id", vmRef.ID)
 
@@ -34,11 +45,11 @@ func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (string
 	result, err := client.DescribeInstances(ctx, describeInput)
 	if err != nil {
 		log.Error(err, "Failed to describe instance", "vm", vmRef.Name)
-		return "", "", liberr.Wrap(err)
+		return "", liberr.Wrap(err)
 	}
 
 	if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
-		return "", "", fmt.Errorf("instance not found: %s", vmRef.ID)
+		return "", fmt.Errorf("instance not found: %s", vmRef.ID)
 	}
 
 	instance := result.Reservations[0].Instances[0]
@@ -52,17 +63,20 @@ func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (string
 		volumeID := *mapping.Ebs.VolumeId
 		log.Info("Creating snapshot for volume", "vm", vmRef.Name, "volume", volumeID)
 
+		tags := []ec2types.Tag{
+			{Key: aws.String("forklift.konveyor.io/vmID"), Value: aws.String(vmRef.ID)},
+			{Key: aws.String("forklift.konveyor.io/vm-name"), Value: aws.String(vmRef.Name)},
+			{Key: aws.String("forklift.konveyor.io/volume"), Value: aws.String(volumeID)},
+		}
+		tags = append(tags, extraTags...)
+
 		snapshotInput := &ec2.CreateSnapshotInput{
 			VolumeId:    aws.String(volumeID),
 			Description: aws.String(fmt.Sprintf("Forklift migration snapshot for VM %s (%s)", vmRef.Name, vmRef.ID)),
 			TagSpecifications: []ec2types.TagSpecification{
 				{
 					ResourceType: ec2types.ResourceTypeSnapshot,
-					Tags: []ec2types.Tag{
-						{Key: aws.String("forklift.konveyor.io/vmID"), Value: aws.String(vmRef.ID)},
-						{Key: aws.String("forklift.konveyor.io/vm-name"), Value: aws.String(vmRef.Name)},
-						{Key: aws.String("forklift.konveyor.io/volume"), Value: aws.String(volumeID)},
-					},
+					Tags:         tags,
 				},
 			},
 		}
@@ -70,7 +84,7 @@ func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (string
 		snapshot, err := client.CreateSnapshot(ctx, snapshotInput)
 		if err != nil {
 			log.Error(err, "Failed to create snapshot", "volume", volumeID)
-			return "", "", liberr.Wrap(err)
+			return "", liberr.Wrap(err)
 		}
 
 		snapshotIDs = append(snapshotIDs, *snapshot.SnapshotId)
@@ -80,7 +94,7 @@ func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (string
 	snapshotIDString := strings.Join(snapshotIDs, ",")
 
 	log.Info("All snapshots created", "vm", vmRef.Name, "snapshots", snapshotIDString)
-	return snapshotIDString, "", nil
+	return snapshotIDString, nil
 }
 
 // RemoveSnapshot deletes EBS snapshots specified in comma-separated format.
@@ -202,8 +216,8 @@ func splitSnapshotIDs(snapshotIDString string) []string {
 	return strings.Split(snapshotIDString, ",")
 }
 
-// GetSnapshotsForVM queries AWS for all snapshots tagged with the given VM ID.
-// Returns a map of volumeID -> snapshotID by reading tags from each snapshot.
+// GetSnapshotsForVM queries AWS for all snapshots tagged with the given VM ID,
+// excluding the precopy snapshots of warm migration. Returns a map of volumeID -> snapshotID by reading tags from each snapshot.
 func (r *Client) GetSnapshotsForVM(vmRef ref.Ref) (map[string]string, error) {
 	client, err := r.getSourceClient()
 	if err != nil {
@@ -229,17 +243,14 @@ func (r *Client) GetSnapshotsForVM(vmRef ref.Ref) (map[string]string, error) {
 	// Build volumeID -> snapshotID mapping from snapshot tags
 	snapshotMap := make(map[string]string)
 	for _, snapshot := range result.Snapshots {
-		snapshotID := aws.ToString(snapshot.SnapshotId)
-		volumeID := ""
-
-		// Find the volume tag
-		for _, tag := range snapshot.Tags {
-			if aws.ToString(tag.Key) == "forklift.konveyor.io/volume" {
-				volumeID = aws.ToString(tag.Value)
-				break
-			}
+		// Skip the incremental snapshots of warm migration precopies
+		if hasTag(snapshot.Tags, PrecopyTag) {
+			continue
 		}
 
+		snapshotID := aws.ToString(snapshot.SnapshotId)
+		volumeID := tagValue(snapshot.Tags, "forklift.konveyor.io/volume")
+
 		if volumeID != "" && snapshotID != "" {
 			snapshotMap[volumeID] = snapshotID
 			log.V(2).Info("Found snapshot for volume",
@@ -0,0 +1,125 @@
+package ensurer
+
+import (
+	"context"
+
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// EnsureDeltaCopySecret creates the secret of the delta copy pods of a VM unless
+// a secret with the same labels already exists. Returns the name of the secret.
+func (r *Ensurer) EnsureDeltaCopySecret(ctx context.Context, vm *planapi.VMStatus, secret *core.Secret) (string, error) {
+	list := &core.SecretList{}
+	err := r.Client.List(ctx, list, &client.ListOptions{
+		Namespace:     secret.Namespace,
+		LabelSelector: k8slabels.SelectorFromSet(secret.Labels),
+	})
+	if err != nil {
+		r.log.Error(err, "Failed to list delta copy secrets", "vm", vm.Name)
+		return "", liberr.Wrap(err)
+	}
+	if len(list.Items) > 0 {
+		return list.Items[0].Name, nil
+	}
+
+	err = controllerutil.SetOwnerReference(r.Plan, secret, r.Client.Scheme())
+	if err != nil {
+		r.log.Error(err, "Failed to set owner reference on secret", "vm", vm.Name)
+	}
+
+	err = r.Client.Create(ctx, secret)
+	if err != nil {
+		r.log.Error(err, "Failed to create delta copy secret", "vm", vm.Name)
+		return "", liberr.Wrap(err)
+	}
+
+	r.log.Info("Created delta copy secret",
+		"vm", vm.Name,
+		"secret", secret.Name,
+		"namespace", secret.Namespace)
+
+	return secret.Name, nil
+}
+
+// EnsureDeltaCopyPod creates the pod copying a precopy delta to a PVC unless
+// a pod with the same labels already exists. Returns the existing or created pod.
+func (r *Ensurer) EnsureDeltaCopyPod(ctx context.Context, vm *planapi.VMStatus, pod *core.Pod) (*core.Pod, error) {
+	list := &core.PodList{}
+	err := r.Client.List(ctx, list, &client.ListOptions{
+		Namespace:     pod.Namespace,
+		LabelSelector: k8slabels.SelectorFromSet(pod.Labels),
+	})
+	if err != nil {
+		r.log.Error(err, "Failed to list delta copy pods", "vm", vm.Name)
+		return nil, liberr.Wrap(err)
+	}
+	if len(list.Items) > 0 {
+		return &list.Items[0], nil
+	}
+
+	err = controllerutil.SetOwnerReference(r.Plan, pod, r.Client.Scheme())
+	if err != nil {
+		r.log.Error(err, "Failed to set owner reference on pod", "vm", vm.Name)
+	}
+
+	err = r.Client.Create(ctx, pod)
+	if err != nil {
+		r.log.Error(err, "Failed to create delta copy pod", "vm", vm.Name)
+		return nil, liberr.Wrap(err)
+	}
+
+	r.log.Info("Created delta copy pod",
+		"vm", vm.Name,
+		"pod", pod.Name,
+		"namespace", pod.Namespace)
+
+	return pod, nil
+}
+
+// DeleteDeltaCopyPods deletes the delta copy pods matching the labels.
+func (r *Ensurer) DeleteDeltaCopyPods(ctx context.Context, vm *planapi.VMStatus, labels map[string]string) error {
+	list := &core.PodList{}
+	err := r.Client.List(ctx, list, &client.ListOptions{
+		Namespace:     r.Plan.Spec.TargetNamespace,
+		LabelSelector: k8slabels.SelectorFromSet(labels),
+	})
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range list.Items {
+		pod := &list.Items[i]
+		err = r.Client.Delete(ctx, pod, client.PropagationPolicy("Background"))
+		if err != nil && !k8serr.IsNotFound(err) {
+			return liberr.Wrap(err)
+		}
+		r.log.Info("Deleted delta copy pod", "vm", vm.Name, "pod", pod.Name)
+	}
+	return nil
+}
+
+// DeleteDeltaCopySecrets deletes the delta copy secrets matching the labels.
+func (r *Ensurer) DeleteDeltaCopySecrets(ctx context.Context, vm *planapi.VMStatus, labels map[string]string) error {
+	list := &core.SecretList{}
+	err := r.Client.List(ctx, list, &client.ListOptions{
+		Namespace:     r.Plan.Spec.TargetNamespace,
+		LabelSelector: k8slabels.SelectorFromSet(labels),
+	})
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range list.Items {
+		secret := &list.Items[i]
+		err = r.Client.Delete(ctx, secret)
+		if err != nil && !k8serr.IsNotFound(err) {
+			return liberr.Wrap(err)
+		}
+		r.log.Info("Deleted delta copy secret", "vm", vm.Name, "secret", secret.Name)
+	}
+	return nil
+}
@@ -200,3 +200,17 @@ func (r *Ensurer) CheckDirectPVCsBound(ctx context.Context, vm *planapi.VMStatus
 
 	return allBound, nil
 }
+
+// ListDirectPVCs returns the PVCs created for the EBS volumes of a VM.
+func (r *Ensurer) ListDirectPVCs(ctx context.Context, vm *planapi.VMStatus) ([]core.PersistentVolumeClaim, error) {
+	pvcList := &core.PersistentVolumeClaimList{}
+	err := r.Client.List(ctx, pvcList, &client.ListOptions{
+		Namespace:     r.Plan.Spec.TargetNamespace,
+		LabelSelector: k8slabels.SelectorFromSet(r.Labeler.VMLabels(vm.Ref)),
+	})
+	if err != nil {
+		r.log.Error(err, "Failed to list PVCs", "vm", vm.Name)
+		return nil, liberr.Wrap(err)
+	}
+	return pvcList.Items, nil
+}
@@ -10,7 +10,8 @@ import (
 )
 
 // ExecutePhase executes a specific migration phase based on VM's current phase.
-// Dispatches to appropriate handlers: power off, snapshot creation/waiting, data volume creation, cleanup.
+// Dispatches to appropriate handlers: power off, snapshot creation/waiting, data volume creation,
+// warm migration precopies, cleanup.
 // Returns ok=true when phase completes and VM should advance, false to retry same phase.
 // Updates pipeline step status (running, completed) and progress tracking during execution.
 func (r *Migrator) ExecutePhase(vm *planapi.VMStatus) (ok bool, err error) {
@@ -25,7 +26,7 @@ func (r *Migrator) ExecutePhase(vm *planapi.VMStatus) (ok bool, err error) {
 		ok = true
 		r.NextPhase(vm)
 	case api.PhasePowerOffSource:
-		if step, found := vm.FindStep(PrepareSource); found {
+		if step, found := vm.FindStep(r.Step(vm)); found {
 			if !step.MarkedStarted() {
 				step.MarkStarted()
 			}
@@ -33,7 +34,7 @@ func (r *Migrator) ExecutePhase(vm *planapi.VMStatus) (ok bool, err error) {
 		}
 		ok, err = r.adpClient.PreTransferActions(vm.Ref)
 		if ok && err == nil {
-			if step, found := vm.FindStep(PrepareSource); found {
+			if step, found := vm.FindStep(r.Step(vm)); found {
 				step.Progress.Completed = 1
 			}
 			r.NextPhase(vm)
@@ -41,7 +42,7 @@ func (r *Migrator) ExecutePhase(vm *planapi.VMStatus) (ok bool, err error) {
 	case api.PhaseWaitForPowerOff:
 		ok, err = r.adpClient.PoweredOff(vm.Ref)
 		if ok && err == nil {
-			if step, found := vm.FindStep(PrepareSource); found {
+			if step, found := vm.FindStep(r.Step(vm)); found {
 				step.Progress.Completed = 2
 			}
 			r.NextPhase(vm)
@@ -114,6 +115,43 @@ func (r *Migrator) ExecutePhase(vm *planapi.VMStatus) (ok bool, err error) {
 			break
 		}
 		if ready {
+			if vm.Warm != nil {
+				// The volumes created from the base snapshots are the first precopy
+				r.completePrecopy(vm)
+			}
+			r.NextPhase(vm)
+		}
+	case api.PhaseCopyingPaused:
+		ok = true
+		r.copyingPaused(vm)
+	case PhaseCreatePrecopySnapshots, PhaseCreateFinalSnapshots:
+		ok = true
+		err = r.createPrecopySnapshots(vm)
+		if err == nil {
+			r.NextPhase(vm)
+		}
+	case PhaseWaitForPrecopySnapshots, PhaseWaitForFinalSnapshots:
+		ok = true
+		var ready bool
+		ready, err = r.waitForPrecopySnapshots(vm)
+		if err != nil {
+			break
+		}
+		if ready {
+			r.NextPhase(vm)
+		}
+	case PhaseCopyChangedBlocks, PhaseCopyFinalChangedBlocks:
+		ok = true
+		var done bool
+		done, err = r.copyChangedBlocks(vm)
+		if err != nil || !done {
+			break
+		}
+		r.completePrecopy(vm)
+		if vm.Phase == PhaseCopyChangedBlocks {
+			// Wait for the next precopy or the cutover
+			r.jumpToPhase(vm, api.PhaseCopyingPaused)
+		} else {
 			r.NextPhase(vm)
 		}
 	case api.PhaseCreateGuestConversionPod, api.PhaseConvertGuest:
@@ -176,13 +214,20 @@ func (r *Migrator) Step(status *planapi.VMStatus) (step string) {
 	case api.PhasePreHook:
 		step = api.PhasePreHook
 	case api.PhasePowerOffSource, api.PhaseWaitForPowerOff:
-		step = PrepareSource
+		if r.Context.Plan.IsWarm() {
+			step = Cutover
+		} else {
+			step = PrepareSource
+		}
 	case PhaseCreateSnapshots, PhaseWaitForSnapshots:
 		step = CreateSnapshots
 	case PhaseShareSnapshots:
 		step = ShareSnapshots
-	case PhaseCreateVolumes, PhaseWaitForVolumes, PhaseCreatePVsAndPVCs:
+	case PhaseCreateVolumes, PhaseWaitForVolumes, PhaseCreatePVsAndPVCs,
+		api.PhaseCopyingPaused, PhaseCreatePrecopySnapshots, PhaseWaitForPrecopySnapshots, PhaseCopyChangedBlocks:
 		step = DiskTransfer
+	case PhaseCreateFinalSnapshots, PhaseWaitForFinalSnapshots, PhaseCopyFinalChangedBlocks:
+		step = Cutover
 	case api.PhaseCreateGuestConversionPod, api.PhaseConvertGuest:
 		step = ImageConversion
 	case api.PhaseFinalize, api.PhaseCreateVM:
@@ -15,14 +15,23 @@ const (
 	CrossAccountFlag = 1 << 3 // Include cross-account snapshot sharing phase
 )
 
-// Itinerary builds the EC2 cold migration workflow sequence defining phase order.
-// Includes: Initialize→PreHook→PowerOff→CreateSnapshots→WaitSnapshots→[ShareSnapshots]→CreateVolumes→WaitForVolumes→CreatePVsAndPVCs→CreateGuestConversionPod→ConvertGuest→Finalize→CreateVM→RemoveSnapshots→PostHook→Completed.
-// Pre/post hooks are conditionally included based on VM hook configuration.
-// ShareSnapshots phase is conditionally included for cross-account migrations.
+// Itinerary builds the EC2 migration workflow sequence defining phase order.
+// Returns the warm itinerary for warm plans, the cold itinerary otherwise.
 func (r *Migrator) Itinerary(vm planapi.VM) *libitr.Itinerary {
 	r.vm = &vm
 
-	itinerary := &libitr.Itinerary{
+	if r.Context.Plan.IsWarm() {
+		return r.warmItinerary(vm)
+	}
+	return r.coldItinerary(vm)
+}
+
+// coldItinerary builds the EC2 cold migration workflow sequence.
+// Includes: Initialize→PreHook→PowerOff→CreateSnapshots→WaitSnapshots→[ShareSnapshots]→CreateVolumes→WaitForVolumes→CreatePVsAndPVCs→CreateGuestConversionPod→ConvertGuest→Finalize→CreateVM→RemoveSnapshots→PostHook→Completed.
+// Pre/post hooks are conditionally included based on VM hook configuration.
+// ShareSnapshots phase is conditionally included for cross-account migrations.
+func (r *Migrator) coldItinerary(vm planapi.VM) *libitr.Itinerary {
+	return &libitr.Itinerary{
 		Name: "EC2 Cold Migration",
 		Pipeline: libitr.Pipeline{
 			{Name: api.PhaseStarted},
@@ -49,8 +58,51 @@ func (r *Migrator) Itinerary(vm planapi.VM) *libitr.Itinerary {
 			migrator: r,
 		},
 	}
+}
 
-	return itinerary
+// warmItinerary builds the EC2 warm migration workflow sequence.
+// The volumes are created from base snapshots taken while the instance is running, then the
+// precopy loop CopyingPaused→CreatePrecopySnapshots→WaitForPrecopySnapshots→CopyChangedBlocks
+// applies the blocks changed since the previous snapshots until the cutover, which stops the
+// instance and applies the final snapshots before the VM is created.
+// Includes: Initialize→PreHook→CreateSnapshots→WaitSnapshots→[ShareSnapshots]→CreateVolumes→WaitForVolumes→CreatePVsAndPVCs→(precopy loop)→PowerOff→CreateFinalSnapshots→WaitForFinalSnapshots→CopyFinalChangedBlocks→CreateGuestConversionPod→ConvertGuest→Finalize→CreateVM→RemoveSnapshots→PostHook→Completed.
+func (r *Migrator) warmItinerary(vm planapi.VM) *libitr.Itinerary {
+	return &libitr.Itinerary{
+		Name: "EC2 Warm Migration",
+		Pipeline: libitr.Pipeline{
+			{Name: api.PhaseStarted},
+			{Name: api.PhasePreHook, All: PreHookFlag},
+			{Name: PhaseCreateSnapshots},
+			{Name: PhaseWaitForSnapshots},
+			{Name: PhaseShareSnapshots, All: CrossAccountFlag},
+			{Name: PhaseCreateVolumes},
+			{Name: PhaseWaitForVolumes},
+			{Name: PhaseCreatePVsAndPVCs},
+			// Precopy loop start
+			{Name: api.PhaseCopyingPaused},
+			{Name: PhaseCreatePrecopySnapshots},
+			{Name: PhaseWaitForPrecopySnapshots},
+			{Name: PhaseCopyChangedBlocks},
+			// Precopy loop end
+			{Name: api.PhasePowerOffSource},
+			{Name: api.PhaseWaitForPowerOff},
+			{Name: PhaseCreateFinalSnapshots},
+			{Name: PhaseWaitForFinalSnapshots},
+			{Name: PhaseCopyFinalChangedBlocks},
+			{Name: api.PhaseCreateGuestConversionPod, All: ConversionFlag},
+			{Name: api.PhaseConvertGuest, All: ConversionFlag},
+			{Name: api.PhaseFinalize},
+			{Name: api.PhaseCreateVM},
+			{Name: PhaseRemoveSnapshots},
+			{Name: api.PhasePostHook, All: PostHookFlag},
+			{Name: api.PhaseCompleted},
+		},
+		Predicate: &EC2Predicate{
+			vm:       &vm,
+			context:  r.Context,
+			migrator: r,
+		},
+	}
 }
 
 // EC2Predicate implements conditional phase evaluation for the EC2 migration itinerary.
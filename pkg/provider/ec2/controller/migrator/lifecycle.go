@@ -21,26 +21,38 @@ func (r *Migrator) Begin() (err error) {
 
 // Complete performs final cleanup after VM migration finishes.
 // Minimal for EC2 since cleanup (snapshot deletion, secret removal) is handled by RemoveSnapshots phase.
+// A warm migration canceled during the precopies never reaches that phase, so its precopy
+// snapshots and delta copy resources are removed here.
 func (r *Migrator) Complete(vm *planapi.VMStatus) {
+	if r.Context.Plan.IsWarm() {
+		r.cleanupWarm(vm)
+	}
 	r.log.V(1).Info("EC2 migration complete", "vm", vm.Name)
 }
 
 // Status creates a new VMStatus object for tracking migration progress.
 // Returns a VMStatus initialized with the VM definition, ready to be populated with pipeline and progress.
 func (r *Migrator) Status(vm planapi.VM) *planapi.VMStatus {
-	return &planapi.VMStatus{
+	status := &planapi.VMStatus{
 		VM: vm,
 	}
+	if r.Context.Plan.IsWarm() {
+		status.Warm = &planapi.Warm{}
+	}
+	return status
 }
 
 // Reset re-initializes a VM's migration status for retry after failure or cancellation.
-// Replaces pipeline, resets phase to Started, clears errors and timestamps. Preserves VM reference.
+// Replaces pipeline, resets phase to Started, clears errors, timestamps and warm precopies. Preserves VM reference.
 func (r *Migrator) Reset(vm *planapi.VMStatus, pipeline []*planapi.Step) {
 	vm.Pipeline = pipeline
 	vm.Phase = api.PhaseStarted
 	vm.Error = nil
 	vm.Started = nil
 	vm.Completed = nil
+	if r.Context.Plan.IsWarm() {
+		vm.Warm = &planapi.Warm{}
+	}
 
 	r.log.V(1).Info("VM status reset", "vm", vm.Name)
 }
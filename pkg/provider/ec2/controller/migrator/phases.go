@@ -56,6 +56,42 @@ const (
 	// This phase advances to PhaseFinalize when all PVCs are bound.
 	PhaseCreatePVsAndPVCs = "CreatePVsAndPVCs"
 
+	// PhaseCreatePrecopySnapshots controls the incremental snapshot creation phase of warm migration.
+	// During this phase, the migrator:
+	//   - Creates snapshots of all EBS volumes of the running instance
+	//   - Tags snapshots with the precopy name to tell them from the base snapshots
+	//   - Records the snapshots as the latest precopy
+	// This phase advances to PhaseWaitForPrecopySnapshots when snapshots are initiated.
+	PhaseCreatePrecopySnapshots = "CreatePrecopySnapshots"
+
+	// PhaseWaitForPrecopySnapshots controls the incremental snapshot completion polling phase.
+	// During this phase, the migrator:
+	//   - Waits until all snapshots of the precopy reach "completed" state
+	//   - Records the snapshot of each source volume as the precopy deltas
+	// This phase advances to PhaseCopyChangedBlocks when all snapshots are ready.
+	PhaseWaitForPrecopySnapshots = "WaitForPrecopySnapshots"
+
+	// PhaseCopyChangedBlocks controls the precopy delta transfer phase.
+	// During this phase, the migrator:
+	//   - Creates a pod per PVC listing the blocks that changed between the previous
+	//     and the latest snapshot of the volume using the EBS direct APIs
+	//   - Waits until the pods have written the changed blocks to the PVCs
+	//   - Removes the snapshots of the previous precopy
+	// This phase returns to PhaseCopyingPaused until the next precopy or the cutover.
+	PhaseCopyChangedBlocks = "CopyChangedBlocks"
+
+	// PhaseCreateFinalSnapshots controls the final snapshot creation phase of warm migration.
+	// Same as PhaseCreatePrecopySnapshots, after the instance has been stopped.
+	PhaseCreateFinalSnapshots = "CreateFinalSnapshots"
+
+	// PhaseWaitForFinalSnapshots controls the final snapshot completion polling phase.
+	// Same as PhaseWaitForPrecopySnapshots for the final snapshots.
+	PhaseWaitForFinalSnapshots = "WaitForFinalSnapshots"
+
+	// PhaseCopyFinalChangedBlocks controls the final delta transfer phase.
+	// Same as PhaseCopyChangedBlocks for the final snapshots, then advances to the VM creation.
+	PhaseCopyFinalChangedBlocks = "CopyFinalChangedBlocks"
+
 	// PhaseRemoveSnapshots controls the cleanup phase for EBS snapshots.
 	// During this phase, the migrator:
 	//   - Queries AWS for snapshots tagged with VM name
@@ -87,10 +123,17 @@ const (
 
 	// DiskTransfer indicates disks are being transferred/populated.
 	// Corresponds to: PhaseCreateVolumes, PhaseWaitForVolumes, PhaseCreatePVsAndPVCs
+	// and, in warm migration, the precopy phases.
 	// This step creates EBS volumes from snapshots, then creates PV/PVC pairs
 	// with CSI volume sources pointing directly to the EBS volumes.
 	DiskTransfer = "DiskTransfer"
 
+	// Cutover indicates the final transfer of a warm migration.
+	// Corresponds to: PhasePowerOffSource, PhaseWaitForPowerOff, PhaseCreateFinalSnapshots,
+	// PhaseWaitForFinalSnapshots, PhaseCopyFinalChangedBlocks
+	// This step stops the EC2 instance and copies the blocks changed since the last precopy.
+	Cutover = "Cutover"
+
 	// CreateVM indicates the KubeVirt VirtualMachine is being created.
 	// Corresponds to: PhaseFinalize, PhaseCreateVM
 	// This step builds the VirtualMachine spec and creates it in the target cluster.
@@ -8,7 +8,7 @@ import (
 )
 
 // Pipeline converts itinerary phases into user-facing UI steps with progress tracking.
-// Maps internal phases to steps: Initialize, PrepareSource, CreateSnapshots, ShareSnapshots (cross-account only), DiskTransfer, ImageConversion, CreateVM, Cleanup.
+// Maps internal phases to steps: Initialize, PrepareSource (cold only), CreateSnapshots, ShareSnapshots (cross-account only), DiskTransfer, Cutover (warm only), ImageConversion, CreateVM, Cleanup.
 // Each step includes description, total progress units, and optional sub-tasks for detailed tracking.
 func (r *Migrator) Pipeline(vm planapi.VM) (pipeline []*planapi.Step, err error) {
 	itinerary := r.Itinerary(vm)
@@ -37,7 +37,7 @@ func (r *Migrator) Pipeline(vm planapi.VM) (pipeline []*planapi.Step, err error)
 			})
 
 		case api.PhasePowerOffSource, api.PhaseWaitForPowerOff:
-			if step.Name == api.PhasePowerOffSource {
+			if step.Name == api.PhasePowerOffSource && !r.Context.Plan.IsWarm() {
 				pipeline = append(pipeline, &planapi.Step{
 					Task: planapi.Task{
 						Name:        PrepareSource,
@@ -47,6 +47,17 @@ func (r *Migrator) Pipeline(vm planapi.VM) (pipeline []*planapi.Step, err error)
 					},
 				})
 			}
+			// Only create the Cutover step once (on the first phase) of a warm migration
+			if step.Name == api.PhasePowerOffSource && r.Context.Plan.IsWarm() {
+				pipeline = append(pipeline, &planapi.Step{
+					Task: planapi.Task{
+						Name:        Cutover,
+						Description: "Stop source EC2 instance and copy the final changed blocks.",
+						Progress:    libitr.Progress{Total: 4},
+						Phase:       api.StepPending,
+					},
+				})
+			}
 
 		case PhaseCreateSnapshots, PhaseWaitForSnapshots:
 			if step.Name == PhaseCreateSnapshots {
@@ -93,6 +93,12 @@ func (r *Migrator) waitForSnapshots(vm *planapi.VMStatus) (bool, error) {
 		if step, found := vm.FindStep(CreateSnapshots); found {
 			step.Progress.Completed = 2
 		}
+		// The base snapshots are the first precopy of a warm migration
+		if vm.Warm != nil && len(vm.Warm.Precopies) == 0 {
+			if err := r.recordBasePrecopy(vm, snapshotIDString); err != nil {
+				return false, err
+			}
+		}
 		return true, nil
 	}
 
@@ -175,6 +181,11 @@ func (r *Migrator) removeSnapshots(vm *planapi.VMStatus) (bool, error) {
 	// Clean up snapshots from AWS
 	r.cleanupSnapshots(vm)
 
+	// Clean up the precopy snapshots and delta copy resources of a warm migration
+	if r.Context.Plan.IsWarm() {
+		r.cleanupWarm(vm)
+	}
+
 	// Clean up created EBS volumes if migration failed
 	// On success, volumes are now backing PVCs and should not be deleted
 	if vm.Error != nil {
@@ -0,0 +1,299 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/provider/ec2/controller/builder"
+	"github.com/kubev2v/forklift/pkg/settings"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Warm migration of EC2 instances.
+//
+// The base snapshots are copied to the target like in a cold migration while the
+// instance keeps running: the EBS volumes created from them back the PVCs of the VM
+// and are the first precopy. Each following precopy takes incremental EBS snapshots of
+// the source volumes and runs a pod per PVC that writes the blocks that changed since
+// the previous precopy using the EBS direct APIs. At cutover the instance is stopped and
+// a final precopy copies the last changes.
+
+// recordBasePrecopy records the base snapshots of a warm migration as the first precopy.
+// The deltas map the source volume IDs to their base snapshot IDs.
+func (r *Migrator) recordBasePrecopy(vm *planapi.VMStatus, snapshotIDString string) error {
+	snapshotMap, err := r.getSnapshotIDs(vm)
+	if err != nil {
+		r.log.Error(err, "Failed to get snapshot IDs from AWS", "vm", vm.Name)
+		return liberr.Wrap(err)
+	}
+
+	now := meta.Now()
+	precopy := planapi.Precopy{
+		Start:    &now,
+		Snapshot: snapshotIDString,
+	}
+	precopy.WithDeltas(snapshotMap)
+	vm.Warm.Precopies = append(vm.Warm.Precopies, precopy)
+
+	r.log.Info("Recorded base snapshots as first precopy",
+		"vm", vm.Name,
+		"snapshotCount", len(snapshotMap))
+	return nil
+}
+
+// completePrecopy marks the last precopy completed and schedules the next one.
+func (r *Migrator) completePrecopy(vm *planapi.VMStatus) {
+	now := meta.Now()
+	next := meta.NewTime(now.Add(time.Duration(settings.Settings.PrecopyInterval) * time.Minute))
+	if n := len(vm.Warm.Precopies); n > 0 {
+		vm.Warm.Precopies[n-1].End = &now
+	}
+	vm.Warm.NextPrecopyAt = &next
+	vm.Warm.Successes++
+	vm.Warm.ConsecutiveFailures = 0
+
+	r.log.Info("Precopy completed",
+		"vm", vm.Name,
+		"precopies", len(vm.Warm.Precopies),
+		"nextPrecopyAt", next)
+}
+
+// copyingPaused waits for the next precopy or the cutover.
+// The cutover takes precedence so that a due precopy does not delay it.
+func (r *Migrator) copyingPaused(vm *planapi.VMStatus) {
+	if r.Migration.Spec.Cutover != nil && !r.Migration.Spec.Cutover.After(time.Now()) {
+		r.log.Info("Cutover time reached, stopping source instance", "vm", vm.Name)
+		r.jumpToPhase(vm, api.PhasePowerOffSource)
+	} else if vm.Warm.NextPrecopyAt != nil && !vm.Warm.NextPrecopyAt.After(time.Now()) {
+		r.NextPhase(vm)
+	}
+}
+
+// createPrecopySnapshots creates the incremental snapshots of the next precopy.
+// The precopy is named after its index so a retry finds the snapshots it already created.
+func (r *Migrator) createPrecopySnapshots(vm *planapi.VMStatus) error {
+	name := strconv.Itoa(len(vm.Warm.Precopies))
+	r.log.Info("Creating precopy snapshots", "vm", vm.Name, "precopy", name)
+
+	snapshotIDString, err := r.getEC2Client().CreatePrecopySnapshots(vm.Ref, name)
+	if err != nil {
+		r.log.Error(err, "Failed to create precopy snapshots", "vm", vm.Name, "precopy", name)
+		return liberr.Wrap(err)
+	}
+	if snapshotIDString == "" {
+		return fmt.Errorf("no precopy snapshots created for VM %s", vm.Name)
+	}
+
+	now := meta.Now()
+	vm.Warm.Precopies = append(vm.Warm.Precopies, planapi.Precopy{
+		Start:    &now,
+		Snapshot: snapshotIDString,
+	})
+	if step, found := vm.FindStep(r.Step(vm)); found && vm.Phase == PhaseCreateFinalSnapshots {
+		step.Progress.Completed = 3
+	}
+	return nil
+}
+
+// waitForPrecopySnapshots checks whether the snapshots of the last precopy are completed.
+// Once they are, the deltas of the precopy map the source volume IDs to their snapshot IDs.
+func (r *Migrator) waitForPrecopySnapshots(vm *planapi.VMStatus) (bool, error) {
+	n := len(vm.Warm.Precopies)
+	if n == 0 {
+		return false, liberr.New("no precopy to wait for")
+	}
+	precopy := &vm.Warm.Precopies[n-1]
+
+	ready, _, err := r.adpClient.CheckSnapshotReady(vm.Ref, *precopy, nil)
+	if err != nil {
+		r.log.Error(err, "Failed to check precopy snapshot status", "vm", vm.Name)
+		return false, liberr.Wrap(err)
+	}
+	if !ready {
+		r.log.Info("Precopy snapshots not yet ready", "vm", vm.Name)
+		return false, nil
+	}
+
+	deltas, err := r.getEC2Client().GetSnapshotDeltas(vm.Ref, precopy.Snapshot, nil)
+	if err != nil {
+		r.log.Error(err, "Failed to map precopy snapshots to volumes", "vm", vm.Name)
+		return false, liberr.Wrap(err)
+	}
+	precopy.Deltas = nil
+	precopy.WithDeltas(deltas)
+
+	r.log.Info("Precopy snapshots ready", "vm", vm.Name, "snapshotCount", len(deltas))
+	return true, nil
+}
+
+// copyChangedBlocks runs a pod per PVC writing the blocks that changed between the
+// snapshots of the previous and the last precopy. Returns true when all the pods succeeded.
+// A failed pod is reported as a step error, which fails the VM.
+func (r *Migrator) copyChangedBlocks(vm *planapi.VMStatus) (done bool, err error) {
+	ctx := context.TODO()
+
+	step, found := vm.FindStep(r.Step(vm))
+	if !found {
+		err = liberr.New(fmt.Sprintf("Step '%s' not found", r.Step(vm)))
+		return
+	}
+	ec2Builder, ok := r.builder.(*builder.Builder)
+	if !ok {
+		err = liberr.New("builder is not an EC2 builder")
+		return
+	}
+	ec2Ensurer := r.getEnsurer()
+
+	n := len(vm.Warm.Precopies)
+	if n < 2 {
+		err = liberr.New("no previous precopy to copy the changed blocks from")
+		return
+	}
+	previous := vm.Warm.Precopies[n-2].DeltaMap()
+	current := vm.Warm.Precopies[n-1].DeltaMap()
+	precopy := strconv.Itoa(n - 1)
+
+	secret, err := ec2Builder.BuildDeltaCopySecret(vm.Ref)
+	if err != nil {
+		return
+	}
+	secretName, err := ec2Ensurer.EnsureDeltaCopySecret(ctx, vm, secret)
+	if err != nil {
+		return
+	}
+
+	pvcs, err := ec2Ensurer.ListDirectPVCs(ctx, vm)
+	if err != nil {
+		return
+	}
+	if len(pvcs) == 0 {
+		err = fmt.Errorf("no PVCs found for VM %s", vm.Name)
+		return
+	}
+	rateLimit := r.Plan.DiskTransferRateLimit(len(pvcs))
+
+	done = true
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		volumeID := pvc.Labels[builder.VolumeIDLabel]
+		second, found := current[volumeID]
+		if !found {
+			err = fmt.Errorf("no precopy snapshot found for volume %s", volumeID)
+			return
+		}
+
+		var pod *core.Pod
+		pod, err = ec2Builder.BuildDeltaCopyPod(vm.Ref, pvc, precopy, previous[volumeID], second, secretName, rateLimit)
+		if err != nil {
+			return
+		}
+		pod, err = ec2Ensurer.EnsureDeltaCopyPod(ctx, vm, pod)
+		if err != nil {
+			return
+		}
+
+		switch pod.Status.Phase {
+		case core.PodSucceeded:
+		case core.PodFailed:
+			done = false
+			step.AddError(fmt.Sprintf(
+				"Copying the changed blocks of volume %s failed: %s",
+				volumeID,
+				terminationMessage(pod)))
+		default:
+			done = false
+		}
+	}
+	if !done {
+		return
+	}
+
+	r.log.Info("Changed blocks copied", "vm", vm.Name, "precopy", precopy)
+
+	labels := ec2Builder.DeltaCopyLabels(vm.Ref)
+	labels[builder.PrecopyLabel] = precopy
+	err = ec2Ensurer.DeleteDeltaCopyPods(ctx, vm, labels)
+	if err != nil {
+		return
+	}
+
+	// The snapshots of the previous precopy are no longer needed, the base
+	// snapshots are removed with the snapshots of a cold migration.
+	if n > 2 {
+		_, err = r.adpClient.RemoveSnapshot(vm.Ref, vm.Warm.Precopies[n-2].Snapshot, nil)
+		if err != nil {
+			r.log.Error(err, "Failed to remove previous precopy snapshots", "vm", vm.Name)
+			err = nil
+		}
+	}
+
+	if vm.Phase == PhaseCopyFinalChangedBlocks {
+		step.Progress.Completed = 4
+	}
+	return
+}
+
+// cleanupWarm deletes the delta copy pods and secrets and the precopy snapshots of a VM.
+// Best-effort, errors are logged.
+func (r *Migrator) cleanupWarm(vm *planapi.VMStatus) {
+	ctx := context.TODO()
+
+	ec2Builder, ok := r.builder.(*builder.Builder)
+	if !ok {
+		return
+	}
+	ec2Ensurer := r.getEnsurer()
+	labels := ec2Builder.DeltaCopyLabels(vm.Ref)
+
+	if err := ec2Ensurer.DeleteDeltaCopyPods(ctx, vm, labels); err != nil {
+		r.log.Error(err, "Failed to delete delta copy pods", "vm", vm.Name)
+	}
+	if err := ec2Ensurer.DeleteDeltaCopySecrets(ctx, vm, labels); err != nil {
+		r.log.Error(err, "Failed to delete delta copy secrets", "vm", vm.Name)
+	}
+
+	snapshotIDString, err := r.getEC2Client().GetPrecopySnapshotIDsForVM(vm.Ref)
+	if err != nil {
+		r.log.Info("Failed to query precopy snapshots from AWS", "vm", vm.Name, "error", err)
+		return
+	}
+	if snapshotIDString == "" {
+		return
+	}
+	if _, err = r.adpClient.RemoveSnapshot(vm.Ref, snapshotIDString, nil); err != nil {
+		r.log.Error(err, "Failed to remove precopy snapshots", "vm", vm.Name)
+	} else {
+		r.log.Info("Precopy snapshots removed", "vm", vm.Name)
+	}
+}
+
+// jumpToPhase moves the VM to a phase out of the itinerary order.
+// Like NextPhase, the current pipeline step is completed when the phase belongs to another step.
+func (r *Migrator) jumpToPhase(vm *planapi.VMStatus, phase string) {
+	currentStep, found := vm.FindStep(r.Step(vm))
+	vm.Phase = phase
+	nextStep, nextFound := vm.FindStep(r.Step(vm))
+	if found && nextFound && currentStep.Name != nextStep.Name {
+		currentStep.MarkCompleted()
+		currentStep.Phase = api.StepCompleted
+		nextStep.MarkStarted()
+		nextStep.Phase = api.StepRunning
+	}
+	r.log.V(1).Info("Jumped to phase", "vm", vm.Name, "phase", vm.Phase)
+}
+
+// terminationMessage returns the termination message of the first terminated container of a pod.
+func terminationMessage(pod *core.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+			return status.State.Terminated.Message
+		}
+	}
+	return pod.Status.Message
+}
@@ -0,0 +1,211 @@
+package migrator
+
+import (
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ec2client "github.com/kubev2v/forklift/pkg/provider/ec2/controller/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestMigrator returns a migrator for a plan of the migration type.
+func newTestMigrator(migrationType api.MigrationType) *Migrator {
+	providerType := api.EC2
+	plan := &api.Plan{}
+	plan.Spec.Type = migrationType
+	return &Migrator{
+		Context: &plancontext.Context{
+			Plan:      plan,
+			Migration: &api.Migration{},
+			Source: plancontext.Source{
+				Provider: &api.Provider{Spec: api.ProviderSpec{Type: &providerType}},
+			},
+		},
+		log:       logging.WithName("migrator|ec2|test"),
+		adpClient: &ec2client.Client{},
+	}
+}
+
+// itineraryPhases lists the phases of the itinerary of a VM.
+func itineraryPhases(migrator *Migrator, vm planapi.VM) (phases []string) {
+	itinerary := migrator.Itinerary(vm)
+	step, err := itinerary.First()
+	Expect(err).ToNot(HaveOccurred())
+	for {
+		phases = append(phases, step.Name)
+		next, done, err := itinerary.Next(step.Name)
+		Expect(err).ToNot(HaveOccurred())
+		if done {
+			return
+		}
+		step = next
+	}
+}
+
+// warmPipeline returns the pipeline steps of a warm VM.
+func warmPipeline() []*planapi.Step {
+	return []*planapi.Step{
+		{Task: planapi.Task{Name: Initialize}},
+		{Task: planapi.Task{Name: CreateSnapshots}},
+		{Task: planapi.Task{Name: DiskTransfer}},
+		{Task: planapi.Task{Name: Cutover}},
+		{Task: planapi.Task{Name: ImageConversion}},
+		{Task: planapi.Task{Name: CreateVM}},
+		{Task: planapi.Task{Name: Cleanup}},
+	}
+}
+
+var _ = Describe("Warm Migration", func() {
+	Describe("Type", func() {
+		It("should support cold and warm plans", func() {
+			Expect(newTestMigrator(api.MigrationCold).Supported()).To(BeTrue())
+			Expect(newTestMigrator(api.MigrationWarm).Supported()).To(BeTrue())
+			Expect(newTestMigrator(api.MigrationWarm).Type()).To(Equal(api.MigrationWarm))
+			Expect(newTestMigrator(api.MigrationLive).Supported()).To(BeFalse())
+		})
+	})
+
+	Describe("Itinerary", func() {
+		It("should stop the instance at cutover after the precopies", func() {
+			phases := itineraryPhases(newTestMigrator(api.MigrationWarm), planapi.VM{})
+			Expect(phases).To(Equal([]string{
+				api.PhaseStarted,
+				PhaseCreateSnapshots,
+				PhaseWaitForSnapshots,
+				PhaseCreateVolumes,
+				PhaseWaitForVolumes,
+				PhaseCreatePVsAndPVCs,
+				api.PhaseCopyingPaused,
+				PhaseCreatePrecopySnapshots,
+				PhaseWaitForPrecopySnapshots,
+				PhaseCopyChangedBlocks,
+				api.PhasePowerOffSource,
+				api.PhaseWaitForPowerOff,
+				PhaseCreateFinalSnapshots,
+				PhaseWaitForFinalSnapshots,
+				PhaseCopyFinalChangedBlocks,
+				api.PhaseCreateGuestConversionPod,
+				api.PhaseConvertGuest,
+				api.PhaseFinalize,
+				api.PhaseCreateVM,
+				PhaseRemoveSnapshots,
+				api.PhaseCompleted,
+			}))
+		})
+
+		It("should keep stopping the instance first for cold plans", func() {
+			phases := itineraryPhases(newTestMigrator(api.MigrationCold), planapi.VM{})
+			Expect(phases[1]).To(Equal(api.PhasePowerOffSource))
+			Expect(phases).ToNot(ContainElement(api.PhaseCopyingPaused))
+		})
+	})
+
+	Describe("Step", func() {
+		It("should map the precopy phases to the disk transfer and the final phases to the cutover", func() {
+			migrator := newTestMigrator(api.MigrationWarm)
+			for phase, step := range map[string]string{
+				api.PhaseCopyingPaused:            DiskTransfer,
+				PhaseCreatePrecopySnapshots:       DiskTransfer,
+				PhaseWaitForPrecopySnapshots:      DiskTransfer,
+				PhaseCopyChangedBlocks:            DiskTransfer,
+				api.PhasePowerOffSource:           Cutover,
+				api.PhaseWaitForPowerOff:          Cutover,
+				PhaseCreateFinalSnapshots:         Cutover,
+				PhaseWaitForFinalSnapshots:        Cutover,
+				PhaseCopyFinalChangedBlocks:       Cutover,
+				api.PhaseCreateGuestConversionPod: ImageConversion,
+			} {
+				Expect(migrator.Step(&planapi.VMStatus{Phase: phase})).To(Equal(step), phase)
+			}
+		})
+
+		It("should map the power off phases to prepare source for cold plans", func() {
+			migrator := newTestMigrator(api.MigrationCold)
+			Expect(migrator.Step(&planapi.VMStatus{Phase: api.PhasePowerOffSource})).To(Equal(PrepareSource))
+		})
+	})
+
+	Describe("Status", func() {
+		It("should track the precopies of warm plans only", func() {
+			Expect(newTestMigrator(api.MigrationWarm).Status(planapi.VM{}).Warm).ToNot(BeNil())
+			Expect(newTestMigrator(api.MigrationCold).Status(planapi.VM{}).Warm).To(BeNil())
+		})
+	})
+
+	Describe("Precopy loop", func() {
+		var migrator *Migrator
+		var vm *planapi.VMStatus
+
+		BeforeEach(func() {
+			migrator = newTestMigrator(api.MigrationWarm)
+			vm = &planapi.VMStatus{
+				Phase:    api.PhaseCopyingPaused,
+				Pipeline: warmPipeline(),
+				Warm:     &planapi.Warm{},
+			}
+			step, _ := vm.FindStep(DiskTransfer)
+			step.MarkStarted()
+			step.Phase = api.StepRunning
+		})
+
+		It("should complete the precopy and schedule the next one", func() {
+			vm.Warm.Precopies = []planapi.Precopy{{Snapshot: "snap-1"}}
+			migrator.completePrecopy(vm)
+			Expect(vm.Warm.Successes).To(Equal(1))
+			Expect(vm.Warm.Precopies[0].End).ToNot(BeNil())
+			Expect(vm.Warm.NextPrecopyAt).ToNot(BeNil())
+		})
+
+		It("should wait until the next precopy is due", func() {
+			next := meta.NewTime(time.Now().Add(time.Hour))
+			vm.Warm.NextPrecopyAt = &next
+			migrator.copyingPaused(vm)
+			Expect(vm.Phase).To(Equal(api.PhaseCopyingPaused))
+		})
+
+		It("should start the next precopy when it is due", func() {
+			next := meta.NewTime(time.Now().Add(-time.Minute))
+			vm.Warm.NextPrecopyAt = &next
+			migrator.copyingPaused(vm)
+			Expect(vm.Phase).To(Equal(PhaseCreatePrecopySnapshots))
+		})
+
+		It("should stop the instance at cutover", func() {
+			next := meta.NewTime(time.Now().Add(-time.Minute))
+			vm.Warm.NextPrecopyAt = &next
+			cutover := meta.NewTime(time.Now().Add(-time.Second))
+			migrator.Migration.Spec.Cutover = &cutover
+			migrator.copyingPaused(vm)
+			Expect(vm.Phase).To(Equal(api.PhasePowerOffSource))
+
+			diskTransfer, _ := vm.FindStep(DiskTransfer)
+			Expect(diskTransfer.Phase).To(Equal(api.StepCompleted))
+			cutoverStep, _ := vm.FindStep(Cutover)
+			Expect(cutoverStep.Phase).To(Equal(api.StepRunning))
+		})
+	})
+
+	Describe("terminationMessage", func() {
+		It("should return the message of the terminated container", func() {
+			pod := &core.Pod{
+				Status: core.PodStatus{
+					Phase: core.PodFailed,
+					ContainerStatuses: []core.ContainerStatus{
+						{
+							State: core.ContainerState{
+								Terminated: &core.ContainerStateTerminated{Message: "block checksum mismatch"},
+							},
+						},
+					},
+				},
+			}
+			Expect(terminationMessage(pod)).To(Equal("block checksum mismatch"))
+		})
+	})
+})
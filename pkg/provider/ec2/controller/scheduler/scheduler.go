@@ -6,8 +6,12 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/limiter"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/provider/ec2/controller/inventory"
+	"github.com/kubev2v/forklift/pkg/provider/ec2/controller/mapping"
 )
 
 // mutex protects concurrent Next() calls, preventing race conditions in VM scheduling.
@@ -44,12 +48,28 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		return
 	}
 
+	limits := limiter.New(r.Context, planList.Items)
 	for _, vmStatus := range r.Plan.Status.Migration.VMs {
 		if vmStatus.HasCondition(Canceled) {
 			continue
 		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
+			if !r.Plan.Status.Migration.Released(vmStatus) {
+				continue
+			}
+			var footprint *plan.Footprint
+			if limits.Enabled() {
+				footprint, err = r.footprint(vmStatus)
+				if err != nil {
+					vm = vmStatus
+					return
+				}
+				if !limits.Admit(footprint) {
+					continue
+				}
+			}
 			vm = vmStatus
+			vm.Footprint = footprint
 			hasNext = true
 			return
 		}
@@ -81,3 +101,31 @@ func (r *Scheduler) calcInFlight(planList *api.PlanList) int {
 	}
 	return inFlight
 }
+
+// footprint builds the storage used by the instance EBS volumes for the concurrency limits.
+// The storage is the EBS volume type (gp2, gp3, io1, etc). Instance store volumes are not migrated.
+func (r *Scheduler) footprint(vmStatus *plan.VMStatus) (footprint *plan.Footprint, err error) {
+	instance, err := inventory.GetAWSInstance(r.Source.Inventory, vmStatus.Ref)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmStatus.Ref.String())
+		return
+	}
+	footprint = &plan.Footprint{Rate: r.Plan.TransferRateLimit()}
+	devices, _ := inventory.GetBlockDevices(instance)
+	for _, device := range devices {
+		volumeID := inventory.ExtractEBSVolumeID(device)
+		if volumeID == "" {
+			continue
+		}
+		volumeType := inventory.GetVolumeType(r.Source.Inventory, volumeID)
+		footprint.Disks = append(
+			footprint.Disks,
+			plan.DiskFootprint{
+				Storage:      ref.Ref{Name: volumeType},
+				StorageClass: mapping.FindStorageClass(r.Map.Storage, volumeType),
+				// Size in GiB.
+				Capacity: inventory.GetVolumeSize(r.Source.Inventory, volumeID) << 30,
+			})
+	}
+	return
+}
@@ -4,10 +4,11 @@ import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 )
 
-// MigrationType validates migration type. EC2 only supports cold migration (or empty/default).
-// Warm/live migration not supported - EC2 requires instance shutdown for consistent EBS snapshots.
+// MigrationType validates migration type. EC2 supports cold (or empty/default) and warm migration.
+// Live migration not supported - the final EBS snapshots require instance shutdown for consistency.
 func (r *Validator) MigrationType() bool {
-	if r.Context.Plan.Spec.Type == "" || r.Context.Plan.Spec.Type == api.MigrationCold {
+	switch r.Context.Plan.Spec.Type {
+	case "", api.MigrationCold, api.MigrationWarm:
 		return true
 	}
 	return false
@@ -77,7 +77,7 @@ func (r *Validator) VMMigrationType(vmRef ref.Ref) (bool, error) {
 	return true, nil
 }
 
-// WarmMigration returns false - EC2 only supports cold migration with instance shutdown.
+// WarmMigration returns true - EC2 warm migration copies the changed blocks of incremental EBS snapshots.
 func (r *Validator) WarmMigration() bool {
-	return false
+	return true
 }
@@ -3,7 +3,9 @@ package validator
 import (
 	"fmt"
 
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	"github.com/kubev2v/forklift/pkg/provider/ec2/controller/inventory"
 	"github.com/kubev2v/forklift/pkg/provider/ec2/controller/mapping"
 )
@@ -141,3 +143,28 @@ func (r *Validator) UnSupportedDisks(vmRef ref.Ref) ([]string, error) {
 
 	return unsupported, nil
 }
+
+// Requirements returns the EBS volumes keyed by volume type.
+// The instance type CPU and memory are not available in the inventory.
+func (r *Validator) Requirements(vmRef ref.Ref) (requirements *base.Requirements, err error) {
+	awsInstance, err := r.getAWSInstance(vmRef)
+	if err != nil {
+		return
+	}
+	requirements = &base.Requirements{}
+	blockDevices, _ := getBlockDevices(awsInstance)
+	for _, dev := range blockDevices {
+		volumeID := inventory.ExtractEBSVolumeID(dev)
+		if volumeID == "" {
+			continue
+		}
+		requirements.Disks = append(
+			requirements.Disks,
+			plan.DiskFootprint{
+				Storage: ref.Ref{Name: inventory.GetVolumeType(r.Source.Inventory, volumeID)},
+				// Size in GiB.
+				Capacity: inventory.GetVolumeSize(r.Source.Inventory, volumeID) << 30,
+			})
+	}
+	return
+}
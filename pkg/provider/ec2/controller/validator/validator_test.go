@@ -178,12 +178,17 @@ var _ = Describe("EC2 Controller Validator", func() {
 			Expect(validator.MigrationType()).To(BeTrue())
 		})
 
+		It("should return true for warm migration", func() {
+			validator.Context.Plan.Spec.Type = api.MigrationWarm
+			Expect(validator.MigrationType()).To(BeTrue())
+		})
+
 		table.DescribeTable("should return false for unsupported migration types",
 			func(migrationType api.MigrationType) {
 				validator.Context.Plan.Spec.Type = migrationType
 				Expect(validator.MigrationType()).To(BeFalse())
 			},
-			table.Entry("warm migration", api.MigrationWarm),
+			table.Entry("live migration", api.MigrationLive),
 		)
 	})
 
@@ -0,0 +1,118 @@
+// Package ebs provides access to the EBS direct APIs used to read the
+// blocks of EBS snapshots and to list the blocks that changed between
+// two snapshots of the same volume.
+//
+// The types mirror the shapes of the AWS SDK ebs service so the client
+// can be swapped for the SDK one without changing the callers.
+package ebs
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// EBSAPI defines the EBS direct API operations used by the warm migration.
+// This interface allows for mocking the EBS direct API in unit tests.
+type EBSAPI interface {
+	// ListChangedBlocks lists the blocks that differ between two snapshots.
+	ListChangedBlocks(ctx context.Context, params *ListChangedBlocksInput) (*ListChangedBlocksOutput, error)
+	// GetSnapshotBlock reads the data of a block of a snapshot.
+	GetSnapshotBlock(ctx context.Context, params *GetSnapshotBlockInput) (*GetSnapshotBlockOutput, error)
+}
+
+// ChecksumAlgorithmSHA256 is the only checksum algorithm of the snapshot blocks.
+const ChecksumAlgorithmSHA256 = "SHA256"
+
+// ChangedBlock is a block that differs between two snapshots.
+// The first token is nil when the block was written after the first snapshot,
+// the second token is nil when the block no longer exists in the second snapshot.
+type ChangedBlock struct {
+	BlockIndex       *int32  `json:"BlockIndex,omitempty"`
+	FirstBlockToken  *string `json:"FirstBlockToken,omitempty"`
+	SecondBlockToken *string `json:"SecondBlockToken,omitempty"`
+}
+
+// ListChangedBlocksInput selects the snapshots to compare.
+type ListChangedBlocksInput struct {
+	// ID of the snapshot the blocks are compared with.
+	FirstSnapshotId *string
+	// ID of the snapshot the changed blocks are listed for.
+	SecondSnapshotId *string
+	// Maximum number of blocks returned by a page.
+	MaxResults *int32
+	// Token of the next page.
+	NextToken *string
+	// Index of the first block listed.
+	StartingBlockIndex *int32
+}
+
+// ListChangedBlocksOutput is a page of changed blocks.
+type ListChangedBlocksOutput struct {
+	BlockSize     *int32         `json:"BlockSize,omitempty"`
+	ChangedBlocks []ChangedBlock `json:"ChangedBlocks,omitempty"`
+	NextToken     *string        `json:"NextToken,omitempty"`
+	// Size of the volume in GiB.
+	VolumeSize *int64 `json:"VolumeSize,omitempty"`
+}
+
+// GetSnapshotBlockInput selects the block to read.
+type GetSnapshotBlockInput struct {
+	SnapshotId *string
+	BlockIndex *int32
+	BlockToken *string
+}
+
+// GetSnapshotBlockOutput is the data of a block.
+// The caller must close the BlockData.
+type GetSnapshotBlockOutput struct {
+	BlockData io.ReadCloser
+	// Base64 encoded checksum of the block data.
+	Checksum          *string
+	ChecksumAlgorithm string
+	DataLength        *int32
+}
+
+// ListChangedBlocksPaginator pages the changed blocks of a snapshot
+// like the paginator of the SDK ebs service.
+type ListChangedBlocksPaginator struct {
+	api       EBSAPI
+	params    *ListChangedBlocksInput
+	nextToken *string
+	firstPage bool
+}
+
+// NewListChangedBlocksPaginator returns a paginator of the changed blocks.
+func NewListChangedBlocksPaginator(api EBSAPI, params *ListChangedBlocksInput) *ListChangedBlocksPaginator {
+	if params == nil {
+		params = &ListChangedBlocksInput{}
+	}
+	return &ListChangedBlocksPaginator{
+		api:       api,
+		params:    params,
+		nextToken: params.NextToken,
+		firstPage: true,
+	}
+}
+
+// HasMorePages returns whether more pages are available.
+func (p *ListChangedBlocksPaginator) HasMorePages() bool {
+	return p.firstPage || (p.nextToken != nil && len(*p.nextToken) != 0)
+}
+
+// NextPage retrieves the next page of changed blocks.
+func (p *ListChangedBlocksPaginator) NextPage(ctx context.Context) (output *ListChangedBlocksOutput, err error) {
+	if !p.HasMorePages() {
+		err = errors.New("no more pages available")
+		return
+	}
+	params := *p.params
+	params.NextToken = p.nextToken
+	output, err = p.api.ListChangedBlocks(ctx, &params)
+	if err != nil {
+		return
+	}
+	p.firstPage = false
+	p.nextToken = output.NextToken
+	return
+}
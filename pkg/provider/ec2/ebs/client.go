@@ -0,0 +1,252 @@
+package ebs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Package logger.
+var log = logging.WithName("ebs|ec2")
+
+// Signing name of the EBS direct APIs.
+const signingName = "ebs"
+
+// Snapshot block response headers.
+const (
+	headerChecksum          = "x-amz-Checksum"
+	headerChecksumAlgorithm = "x-amz-Checksum-Algorithm"
+	headerDataLength        = "x-amz-Data-Length"
+	headerErrorType         = "x-amzn-ErrorType"
+)
+
+// Hash of the empty body of the GET requests.
+var emptyPayloadHash = func() string {
+	sum := sha256.Sum256(nil)
+	return hex.EncodeToString(sum[:])
+}()
+
+// Client calls the EBS direct APIs of a region over HTTPS.
+// The requests are signed with the credentials of the AWS config.
+type Client struct {
+	// Endpoint of the EBS direct APIs (https://ebs.<region>.amazonaws.com).
+	Endpoint string
+	// AWS region.
+	Region string
+	// Credentials used to sign the requests.
+	Credentials aws.CredentialsProvider
+	// HTTP client.
+	HTTPClient aws.HTTPClient
+	// Retryer of the throttled and failed requests.
+	Retryer aws.Retryer
+	// Request signer.
+	signer *v4.Signer
+}
+
+// NewFromConfig creates a client for the region of the AWS config.
+// The requests are retried by the retryer of the config, the SDK
+// standard retryer by default, as done by the SDK service clients.
+func NewFromConfig(cfg aws.Config) *Client {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	var retryer aws.Retryer
+	if cfg.Retryer != nil {
+		retryer = cfg.Retryer()
+	} else {
+		retryer = retry.NewStandard()
+	}
+	if cfg.RetryMaxAttempts > 0 {
+		retryer = retry.AddWithMaxAttempts(retryer, cfg.RetryMaxAttempts)
+	}
+	return &Client{
+		Endpoint:    fmt.Sprintf("https://ebs.%s.amazonaws.com", cfg.Region),
+		Region:      cfg.Region,
+		Credentials: cfg.Credentials,
+		HTTPClient:  httpClient,
+		Retryer:     retryer,
+		signer:      v4.NewSigner(),
+	}
+}
+
+// ListChangedBlocks lists the blocks that differ between two snapshots.
+func (r *Client) ListChangedBlocks(ctx context.Context, params *ListChangedBlocksInput) (output *ListChangedBlocksOutput, err error) {
+	if params == nil || params.SecondSnapshotId == nil {
+		err = liberr.New("second snapshot ID is required")
+		return
+	}
+	query := url.Values{}
+	if params.FirstSnapshotId != nil {
+		query.Set("firstSnapshotId", *params.FirstSnapshotId)
+	}
+	if params.MaxResults != nil {
+		query.Set("maxResults", strconv.Itoa(int(*params.MaxResults)))
+	}
+	if params.NextToken != nil {
+		query.Set("pageToken", *params.NextToken)
+	}
+	if params.StartingBlockIndex != nil {
+		query.Set("startingBlockIndex", strconv.Itoa(int(*params.StartingBlockIndex)))
+	}
+	response, err := r.get(ctx, fmt.Sprintf("/snapshots/%s/changedblocks", url.PathEscape(*params.SecondSnapshotId)), query)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	output = &ListChangedBlocksOutput{}
+	err = json.NewDecoder(response.Body).Decode(output)
+	if err != nil {
+		err = liberr.Wrap(err)
+		output = nil
+	}
+	return
+}
+
+// GetSnapshotBlock reads the data of a block of a snapshot.
+func (r *Client) GetSnapshotBlock(ctx context.Context, params *GetSnapshotBlockInput) (output *GetSnapshotBlockOutput, err error) {
+	if params == nil || params.SnapshotId == nil || params.BlockIndex == nil || params.BlockToken == nil {
+		err = liberr.New("snapshot ID, block index and block token are required")
+		return
+	}
+	query := url.Values{}
+	query.Set("blockToken", *params.BlockToken)
+	response, err := r.get(
+		ctx,
+		fmt.Sprintf("/snapshots/%s/blocks/%d", url.PathEscape(*params.SnapshotId), *params.BlockIndex),
+		query)
+	if err != nil {
+		return
+	}
+	output = &GetSnapshotBlockOutput{
+		BlockData:         response.Body,
+		Checksum:          aws.String(response.Header.Get(headerChecksum)),
+		ChecksumAlgorithm: response.Header.Get(headerChecksumAlgorithm),
+	}
+	if length := response.Header.Get(headerDataLength); length != "" {
+		n, pErr := strconv.ParseInt(length, 10, 32)
+		if pErr != nil {
+			response.Body.Close()
+			err = liberr.Wrap(pErr)
+			output = nil
+			return
+		}
+		output.DataLength = aws.Int32(int32(n))
+	}
+	return
+}
+
+// Send a signed GET request.
+// The failed requests the retryer deems retryable (throttling,
+// server and transport errors) are retried with its backoff until
+// its attempts or its retry quota are exhausted.
+// The caller must close the body of the returned response.
+func (r *Client) get(ctx context.Context, path string, query url.Values) (response *http.Response, err error) {
+	release := r.Retryer.GetInitialToken()
+	for attempt := 1; ; attempt++ {
+		response, err = r.send(ctx, path, query)
+		_ = release(err)
+		if err == nil || attempt >= r.Retryer.MaxAttempts() || !r.Retryer.IsErrorRetryable(err) {
+			break
+		}
+		delay, dErr := r.Retryer.RetryDelay(attempt, err)
+		if dErr != nil {
+			break
+		}
+		var tErr error
+		release, tErr = r.Retryer.GetRetryToken(ctx, err)
+		if tErr != nil {
+			break
+		}
+		log.V(1).Info(
+			"Retrying EBS direct API request.",
+			"path",
+			path,
+			"attempt",
+			attempt,
+			"delay",
+			delay,
+			"reason",
+			err.Error())
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		case <-time.After(delay):
+		}
+	}
+	if err != nil {
+		err = liberr.Wrap(err, "path", path)
+	}
+	return
+}
+
+// Send a signed GET request once.
+// A failed response is returned as an API error with the code
+// of the error type header, which the retryer classifies.
+func (r *Client) send(ctx context.Context, path string, query url.Values) (response *http.Response, err error) {
+	u, err := url.Parse(r.Endpoint + path)
+	if err != nil {
+		return
+	}
+	u.RawQuery = query.Encode()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return
+	}
+	credentials, err := r.Credentials.Retrieve(ctx)
+	if err != nil {
+		return
+	}
+	err = r.signer.SignHTTP(ctx, credentials, request, emptyPayloadHash, signingName, r.Region, time.Now())
+	if err != nil {
+		return
+	}
+	response, err = r.HTTPClient.Do(request)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		err = &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: response},
+			Err: &smithy.GenericAPIError{
+				Code:    errorCode(response),
+				Message: string(body),
+			},
+		}
+		response = nil
+	}
+	return
+}
+
+// Error code of a failed response.
+// The error type header holds the code followed by the
+// namespace of the error, e.g. ThrottlingException:http://...
+func errorCode(response *http.Response) (code string) {
+	code, _, _ = strings.Cut(response.Header.Get(headerErrorType), ":")
+	if code == "" && response.StatusCode == http.StatusTooManyRequests {
+		code = "TooManyRequestsException"
+	}
+	return
+}
+
+// Compile-time check to ensure *Client implements EBSAPI
+var _ EBSAPI = (*Client)(nil)
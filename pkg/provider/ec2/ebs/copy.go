@@ -0,0 +1,149 @@
+package ebs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"golang.org/x/sync/errgroup"
+)
+
+// Maximum number of changed blocks listed by a page.
+const maxResults = 10000
+
+// Default number of blocks fetched and written at once.
+const DefaultWorkers = 16
+
+// CopyChangedBlocks writes the blocks of the second snapshot that differ
+// from the first snapshot at their offset in the volume. The blocks that
+// no longer exist in the second snapshot are zeroed. When the first snapshot
+// is empty, all the blocks of the second snapshot are written.
+// The blocks are fetched and written by a pool of workers (DefaultWorkers
+// when not positive) while the changed blocks are listed, so the volume
+// must support concurrent writes at distinct offsets.
+// Returns the number of bytes written.
+func CopyChangedBlocks(ctx context.Context, api EBSAPI, first, second string, volume io.WriterAt, workers int) (written int64, err error) {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	input := &ListChangedBlocksInput{
+		SecondSnapshotId: aws.String(second),
+		MaxResults:       aws.Int32(maxResults),
+	}
+	if first != "" {
+		input.FirstSnapshotId = aws.String(first)
+	}
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(workers)
+	var total atomic.Int64
+	listErr := func() (err error) {
+		paginator := NewListChangedBlocksPaginator(api, input)
+		for paginator.HasMorePages() {
+			var page *ListChangedBlocksOutput
+			page, err = paginator.NextPage(groupCtx)
+			if err != nil {
+				err = liberr.Wrap(err, "first", first, "second", second)
+				return
+			}
+			blockSize := int64(aws.ToInt32(page.BlockSize))
+			if blockSize <= 0 && len(page.ChangedBlocks) > 0 {
+				err = liberr.New("invalid block size", "second", second)
+				return
+			}
+			for _, block := range page.ChangedBlocks {
+				group.Go(func() (err error) {
+					n, err := copyBlock(groupCtx, api, second, block, blockSize, volume)
+					total.Add(n)
+					return
+				})
+			}
+		}
+		return
+	}()
+	// The error of a failed block cancels the listing,
+	// so it is reported rather than the cancellation.
+	err = group.Wait()
+	if err == nil {
+		err = listErr
+	}
+	written = total.Load()
+	return
+}
+
+// Write a changed block at its offset in the volume.
+func copyBlock(ctx context.Context, api EBSAPI, snapshot string, block ChangedBlock, blockSize int64, volume io.WriterAt) (n int64, err error) {
+	index := aws.ToInt32(block.BlockIndex)
+	offset := int64(index) * blockSize
+	if block.SecondBlockToken == nil {
+		_, err = volume.WriteAt(make([]byte, blockSize), offset)
+		if err != nil {
+			err = liberr.Wrap(err, "block", index)
+			return
+		}
+		n = blockSize
+		return
+	}
+	output, err := api.GetSnapshotBlock(
+		ctx,
+		&GetSnapshotBlockInput{
+			SnapshotId: aws.String(snapshot),
+			BlockIndex: block.BlockIndex,
+			BlockToken: block.SecondBlockToken,
+		})
+	if err != nil {
+		err = liberr.Wrap(err, "snapshot", snapshot, "block", index)
+		return
+	}
+	defer output.BlockData.Close()
+	data, err := io.ReadAll(output.BlockData)
+	if err != nil {
+		err = liberr.Wrap(err, "snapshot", snapshot, "block", index)
+		return
+	}
+	if output.DataLength != nil && int(*output.DataLength) != len(data) {
+		err = liberr.New(
+			fmt.Sprintf("block length %d does not match the expected length %d", len(data), *output.DataLength),
+			"snapshot", snapshot,
+			"block", index)
+		return
+	}
+	err = verifyChecksum(data, output)
+	if err != nil {
+		err = liberr.Wrap(err, "snapshot", snapshot, "block", index)
+		return
+	}
+	_, err = volume.WriteAt(data, offset)
+	if err != nil {
+		err = liberr.Wrap(err, "block", index)
+		return
+	}
+	n = int64(len(data))
+	return
+}
+
+// Verify the checksum of the block data when one is returned.
+func verifyChecksum(data []byte, output *GetSnapshotBlockOutput) (err error) {
+	checksum := aws.ToString(output.Checksum)
+	if checksum == "" {
+		return
+	}
+	if output.ChecksumAlgorithm != "" && output.ChecksumAlgorithm != ChecksumAlgorithmSHA256 {
+		err = liberr.New(fmt.Sprintf("unsupported checksum algorithm %s", output.ChecksumAlgorithm))
+		return
+	}
+	if Checksum(data) != checksum {
+		err = liberr.New("block checksum mismatch")
+	}
+	return
+}
+
+// Checksum returns the base64 encoded SHA256 checksum of block data.
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
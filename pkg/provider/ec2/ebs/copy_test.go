@@ -0,0 +1,110 @@
+package ebs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kubev2v/forklift/pkg/provider/ec2/ebs"
+	"github.com/kubev2v/forklift/pkg/provider/ec2/testutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEBS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "EC2 EBS Direct API Suite")
+}
+
+// Block size of the test snapshots.
+const blockSize = 4
+
+// volume is an in-memory volume.
+type volume struct {
+	mutex sync.Mutex
+	data  []byte
+}
+
+// WriteAt implements io.WriterAt, growing the volume as needed.
+func (r *volume) WriteAt(p []byte, off int64) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if end := int(off) + len(p); end > len(r.data) {
+		r.data = append(r.data, make([]byte, end-len(r.data))...)
+	}
+	return copy(r.data[off:], p), nil
+}
+
+var _ = Describe("CopyChangedBlocks", func() {
+	var api *testutil.FakeEBSAPI
+	var target *volume
+
+	BeforeEach(func() {
+		api = testutil.NewFakeEBSAPI(blockSize)
+		api.AddSnapshot("snap-base", map[int32][]byte{
+			0: []byte("aaaa"),
+			1: []byte("bbbb"),
+			3: []byte("dddd"),
+		})
+		api.AddSnapshot("snap-next", map[int32][]byte{
+			0: []byte("aaaa"),
+			1: []byte("BBBB"),
+			2: []byte("cccc"),
+		})
+		target = &volume{}
+	})
+
+	It("should copy all the blocks without a first snapshot", func() {
+		written, err := ebs.CopyChangedBlocks(context.Background(), api, "", "snap-base", target, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(Equal(int64(12)))
+		Expect(target.data).To(Equal([]byte("aaaabbbb\x00\x00\x00\x00dddd")))
+	})
+
+	It("should copy only the changed blocks and zero the removed ones", func() {
+		_, err := ebs.CopyChangedBlocks(context.Background(), api, "", "snap-base", target, 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		written, err := ebs.CopyChangedBlocks(context.Background(), api, "snap-base", "snap-next", target, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(Equal(int64(12)))
+		Expect(target.data).To(Equal([]byte("aaaaBBBBcccc\x00\x00\x00\x00")))
+		// Block 0 did not change
+		Expect(api.CallCount(testutil.MethodGetSnapshotBlock)).To(Equal(3 + 2))
+	})
+
+	It("should follow the pages of changed blocks", func() {
+		blocks := make(map[int32][]byte)
+		for i := int32(0); i < 10001; i++ {
+			blocks[i] = []byte("xxxx")
+		}
+		api.AddSnapshot("snap-large", blocks)
+
+		written, err := ebs.CopyChangedBlocks(context.Background(), api, "", "snap-large", target, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(Equal(int64(10001 * blockSize)))
+		Expect(api.CallCount(testutil.MethodListChangedBlocks)).To(Equal(2))
+		Expect(bytes.Count(target.data, []byte("xxxx"))).To(Equal(10001))
+	})
+
+	It("should fail on a checksum mismatch", func() {
+		api.CorruptChecksum = true
+		_, err := ebs.CopyChangedBlocks(context.Background(), api, "", "snap-base", target, 0)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("checksum mismatch"))
+	})
+
+	It("should return the errors of the API", func() {
+		api.Errors[testutil.MethodGetSnapshotBlock] = errors.New("AccessDeniedException")
+		_, err := ebs.CopyChangedBlocks(context.Background(), api, "", "snap-base", target, 0)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("AccessDeniedException"))
+	})
+
+	It("should fail when the snapshot does not exist", func() {
+		_, err := ebs.CopyChangedBlocks(context.Background(), api, "", "snap-missing", target, 0)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -25,6 +25,8 @@ func (h *NetworkHandler) AddRoutes(e *gin.Engine) {
 //   - name: Filter by network name (e.g., ?name=my-vpc)
 //   - label.*: Filter by AWS tags (e.g., ?label.env=production&label.team=platform)
 //
+// The filter, sort and fields query parameters are supported.
+//
 // WebSocket watch supported via X-Watch header.
 func (h *NetworkHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
@@ -40,6 +42,7 @@ func (h *NetworkHandler) List(ctx *gin.Context) {
 
 	// Build list options with label filtering
 	listOptions := h.ListOptionsWithLabels(ctx)
+	h.Pushdown(&listOptions, &model.Network{})
 
 	db := h.Collector.DB()
 	var list []model.Network
@@ -64,7 +67,7 @@ func (h *NetworkHandler) List(ctx *gin.Context) {
 		result = append(result, r)
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	h.ReplyList(ctx, result)
 }
 
 // Get network
@@ -27,6 +27,8 @@ func (h *StorageHandler) AddRoutes(e *gin.Engine) {
 // Note: Storage types are static EBS volume type definitions and don't have AWS tags,
 // so label filtering is not applicable.
 //
+// The filter, sort and fields query parameters are supported.
+//
 // WebSocket watch supported via X-Watch header.
 func (h *StorageHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
@@ -40,9 +42,12 @@ func (h *StorageHandler) List(ctx *gin.Context) {
 		return
 	}
 
+	listOptions := h.ListOptions(ctx)
+	h.Pushdown(&listOptions, &model.Storage{})
+
 	db := h.Collector.DB()
 	var list []model.Storage
-	err = db.List(&list, h.ListOptions(ctx))
+	err = db.List(&list, listOptions)
 	if err != nil {
 		log.Error(err, "Failed to list storage types")
 		ctx.Status(http.StatusInternalServerError)
@@ -63,7 +68,7 @@ func (h *StorageHandler) List(ctx *gin.Context) {
 		result = append(result, r)
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	h.ReplyList(ctx, result)
 }
 
 // Get storage type
@@ -11,9 +11,10 @@ import (
 
 // Routes
 const (
-	VMParam = "vm"
-	VMsRoot = ProviderRoot + "/vms"
-	VMRoot  = VMsRoot + "/:" + VMParam
+	VMParam       = "vm"
+	VMsRoot       = ProviderRoot + "/vms"
+	VMRoot        = VMsRoot + "/:" + VMParam
+	VMHistoryRoot = VMRoot + "/" + base.HistoryCollection
 )
 
 // VM handler
@@ -26,6 +27,7 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 	e.GET(VMsRoot, h.List)
 	e.GET(VMsRoot+"/", h.List)
 	e.GET(VMRoot, h.Get)
+	e.GET(VMHistoryRoot, h.History)
 }
 
 // List VMs
@@ -33,6 +35,8 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 //   - name: Filter by instance name (e.g., ?name=my-instance)
 //   - label.*: Filter by AWS tags (e.g., ?label.env=production&label.team=platform)
 //
+// The filter, sort and fields query parameters are supported.
+//
 // WebSocket watch supported via X-Watch header.
 func (h *VMHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
@@ -48,6 +52,7 @@ func (h *VMHandler) List(ctx *gin.Context) {
 
 	// Build list options with label filtering
 	listOptions := h.ListOptionsWithLabels(ctx)
+	h.Pushdown(&listOptions, &model.Instance{})
 
 	db := h.Collector.DB()
 	var list []model.Instance
@@ -73,7 +78,7 @@ func (h *VMHandler) List(ctx *gin.Context) {
 		result = append(result, r)
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	h.ReplyList(ctx, result)
 }
 
 // Get VM
@@ -111,6 +116,19 @@ func (h *VMHandler) Get(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, r)
 }
 
+// Get the change history of a specific VM.
+func (h *VMHandler) History(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	m := &model.Instance{}
+	m.UID = ctx.Param(VMParam)
+	h.ReplyHistory(ctx, m)
+}
+
 // Watch VMs via WebSocket.
 // Clients can connect with the X-Watch header to receive real-time updates
 // when instances are created, updated, or deleted in the inventory.
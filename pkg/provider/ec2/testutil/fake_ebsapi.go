@@ -0,0 +1,212 @@
+// Package testutil provides test utilities for EC2 provider unit tests.
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kubev2v/forklift/pkg/provider/ec2/ebs"
+)
+
+// EBSMethod represents an EBS direct API method name for error injection.
+type EBSMethod string
+
+// EBS direct API method constants for type-safe error injection.
+const (
+	MethodListChangedBlocks EBSMethod = "ListChangedBlocks"
+	MethodGetSnapshotBlock  EBSMethod = "GetSnapshotBlock"
+)
+
+// DefaultEBSBlockSize is the block size of the EBS snapshots (512 KiB).
+const DefaultEBSBlockSize = 512 * 1024
+
+// FakeEBSAPI is a fake implementation of the EBS direct API for testing.
+// It implements the ebs.EBSAPI interface.
+// Snapshots are stored as sparse maps of block index to block data.
+type FakeEBSAPI struct {
+	mu sync.Mutex
+
+	// Size of the snapshot blocks.
+	BlockSize int32
+
+	// Snapshot blocks: snapshotID -> block index -> data
+	Blocks map[string]map[int32][]byte
+
+	// Error injection - map of method to error
+	Errors map[EBSMethod]error
+
+	// Corrupt the checksum returned by GetSnapshotBlock.
+	CorruptChecksum bool
+
+	// Call tracking
+	Calls []EBSAPICall
+}
+
+// EBSAPICall records a call to the fake EBS API for verification in tests.
+type EBSAPICall struct {
+	Method EBSMethod
+	Input  interface{}
+}
+
+// NewFakeEBSAPI creates a new FakeEBSAPI with empty state.
+func NewFakeEBSAPI(blockSize int32) *FakeEBSAPI {
+	return &FakeEBSAPI{
+		BlockSize: blockSize,
+		Blocks:    make(map[string]map[int32][]byte),
+		Errors:    make(map[EBSMethod]error),
+		Calls:     []EBSAPICall{},
+	}
+}
+
+// Compile-time check to ensure FakeEBSAPI implements EBSAPI
+var _ ebs.EBSAPI = (*FakeEBSAPI)(nil)
+
+// AddSnapshot adds a snapshot with the given blocks to the fake state.
+func (f *FakeEBSAPI) AddSnapshot(snapshotID string, blocks map[int32][]byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	snapshot := make(map[int32][]byte, len(blocks))
+	for index, data := range blocks {
+		snapshot[index] = data
+	}
+	f.Blocks[snapshotID] = snapshot
+}
+
+// CallCount returns the number of calls to a method.
+func (f *FakeEBSAPI) CallCount(method EBSMethod) (count int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, call := range f.Calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return
+}
+
+// ListChangedBlocks implements EBSAPI.
+// The blocks are listed in index order, one page holds at most MaxResults blocks.
+func (f *FakeEBSAPI) ListChangedBlocks(ctx context.Context, params *ebs.ListChangedBlocksInput) (*ebs.ListChangedBlocksOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recordCall(MethodListChangedBlocks, params)
+
+	if err := f.Errors[MethodListChangedBlocks]; err != nil {
+		return nil, err
+	}
+
+	secondID := aws.ToString(params.SecondSnapshotId)
+	second, ok := f.Blocks[secondID]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: The snapshot '%s' does not exist", secondID)
+	}
+	first := map[int32][]byte{}
+	firstID := aws.ToString(params.FirstSnapshotId)
+	if firstID != "" {
+		first, ok = f.Blocks[firstID]
+		if !ok {
+			return nil, fmt.Errorf("ResourceNotFoundException: The snapshot '%s' does not exist", firstID)
+		}
+	}
+
+	indexes := map[int32]bool{}
+	for index := range first {
+		indexes[index] = true
+	}
+	for index := range second {
+		indexes[index] = true
+	}
+	sorted := make([]int32, 0, len(indexes))
+	for index := range indexes {
+		sorted = append(sorted, index)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	start := aws.ToInt32(params.StartingBlockIndex)
+	if token := aws.ToString(params.NextToken); token != "" {
+		n, err := strconv.ParseInt(token, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("ValidationException: invalid token '%s'", token)
+		}
+		start = int32(n)
+	}
+	limit := int(aws.ToInt32(params.MaxResults))
+
+	output := &ebs.ListChangedBlocksOutput{
+		BlockSize: aws.Int32(f.BlockSize),
+	}
+	for _, index := range sorted {
+		if index < start {
+			continue
+		}
+		firstData, inFirst := first[index]
+		secondData, inSecond := second[index]
+		if inFirst && inSecond && bytes.Equal(firstData, secondData) {
+			continue
+		}
+		if limit > 0 && len(output.ChangedBlocks) == limit {
+			output.NextToken = aws.String(strconv.Itoa(int(index)))
+			break
+		}
+		block := ebs.ChangedBlock{BlockIndex: aws.Int32(index)}
+		if inFirst {
+			block.FirstBlockToken = aws.String(blockToken(firstID, index))
+		}
+		if inSecond {
+			block.SecondBlockToken = aws.String(blockToken(secondID, index))
+		}
+		output.ChangedBlocks = append(output.ChangedBlocks, block)
+	}
+
+	return output, nil
+}
+
+// GetSnapshotBlock implements EBSAPI.
+func (f *FakeEBSAPI) GetSnapshotBlock(ctx context.Context, params *ebs.GetSnapshotBlockInput) (*ebs.GetSnapshotBlockOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recordCall(MethodGetSnapshotBlock, params)
+
+	if err := f.Errors[MethodGetSnapshotBlock]; err != nil {
+		return nil, err
+	}
+
+	snapshotID := aws.ToString(params.SnapshotId)
+	index := aws.ToInt32(params.BlockIndex)
+	if aws.ToString(params.BlockToken) != blockToken(snapshotID, index) {
+		return nil, fmt.Errorf("ValidationException: invalid block token for block %d", index)
+	}
+	data, ok := f.Blocks[snapshotID][index]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: block %d not found in snapshot '%s'", index, snapshotID)
+	}
+
+	checksum := ebs.Checksum(data)
+	if f.CorruptChecksum {
+		checksum = ebs.Checksum(append([]byte{0}, data...))
+	}
+
+	return &ebs.GetSnapshotBlockOutput{
+		BlockData:         io.NopCloser(bytes.NewReader(data)),
+		Checksum:          aws.String(checksum),
+		ChecksumAlgorithm: ebs.ChecksumAlgorithmSHA256,
+		DataLength:        aws.Int32(int32(len(data))),
+	}, nil
+}
+
+// recordCall records an API call for later verification.
+// Must be called while holding f.mu lock.
+func (f *FakeEBSAPI) recordCall(method EBSMethod, input interface{}) {
+	f.Calls = append(f.Calls, EBSAPICall{Method: method, Input: input})
+}
+
+// blockToken returns the token of a block of a snapshot.
+func blockToken(snapshotID string, index int32) string {
+	return fmt.Sprintf("%s:%d", snapshotID, index)
+}
@@ -29,6 +29,9 @@ const (
 	TLSCertificate = "API_TLS_CERTIFICATE"
 	TLSKey         = "API_TLS_KEY"
 	TLSCa          = "API_TLS_CA"
+	Persistent     = "INVENTORY_PERSISTENT"
+	History        = "INVENTORY_HISTORY"
+	HistoryHours   = "INVENTORY_HISTORY_RETENTION_HOURS"
 )
 
 // CORS
@@ -43,6 +46,16 @@ type Inventory struct {
 	CORS CORS
 	// DB working directory.
 	WorkingDir string
+	// Keep the DB across restarts.
+	Persistent bool
+	// VM change history.
+	History struct {
+		// Enabled.
+		Enabled bool
+		// Retention (hours).
+		// 0 = never purged.
+		Retention int
+	}
 	// Authorization required.
 	AuthRequired bool
 	// Host.
@@ -65,7 +78,7 @@ type Inventory struct {
 }
 
 // Load settings.
-func (r *Inventory) Load() error {
+func (r *Inventory) Load() (err error) {
 	r.CORS = CORS{
 		AllowedOrigins: []string{},
 	}
@@ -84,6 +97,14 @@ func (r *Inventory) Load() error {
 	} else {
 		r.WorkingDir = os.TempDir()
 	}
+	// Persistent
+	r.Persistent = getEnvBool(Persistent, false)
+	// History
+	r.History.Enabled = getEnvBool(History, false)
+	r.History.Retention, err = getNonNegativeEnvLimit(HistoryHours, 168)
+	if err != nil {
+		return err
+	}
 	// Auth
 	r.AuthRequired = getEnvBool(AuthRequired, true)
 	// Host
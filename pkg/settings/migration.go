@@ -17,6 +17,8 @@ const (
 	ImporterRetry                    = "IMPORTER_RETRY"
 	VirtV2vImage                     = "VIRT_V2V_IMAGE"
 	vddkImage                        = "VDDK_IMAGE"
+	OpenstackPopulatorImage          = "OPENSTACK_POPULATOR_IMAGE"
+	EC2DeltaCopyImage                = "EC2_DELTA_COPY_IMAGE"
 	PrecopyInterval                  = "PRECOPY_INTERVAL"
 	VirtV2vDontRequestKVM            = "VIRT_V2V_DONT_REQUEST_KVM"
 	SnapshotRemovalTimeout           = "SNAPSHOT_REMOVAL_TIMEOUT"
@@ -58,6 +60,7 @@ const (
 	MaxParentBackingRetries          = "MAX_PARENT_BACKING_RETRIES"
 	HostLeaseNamespace               = "HOST_LEASE_NAMESPACE"
 	HostLeaseDurationSeconds         = "HOST_LEASE_DURATION_SECONDS"
+	ServiceAccount                   = "SERVICE_ACCOUNT"
 )
 
 // Default values for populator container resources
@@ -128,6 +131,10 @@ type Migration struct {
 	PopulatorContainerRequestsMemory resource.Quantity
 	// VDDK image for guest conversion
 	VddkImage string
+	// OpenStack populator image for applying warm migration precopies
+	OpenstackPopulatorImage string
+	// EC2 delta copy image for applying warm migration precopies
+	EC2DeltaCopyImage string
 	// TlsConnectionTimeout is the timeout for TLS connections in seconds
 	TlsConnectionTimeout int
 	// MaxConcurrentReconciles is the limit of how many reconciles can run at once
@@ -138,6 +145,9 @@ type Migration struct {
 	HostLeaseNamespace string
 	// HostLeaseDurationSeconds is the host lease duration in seconds used in copy offload
 	HostLeaseDurationSeconds string
+	// ServiceAccount of the controller.
+	// Granted the exec into the virt-launcher pods of the VMs running guest scripts.
+	ServiceAccount string
 }
 
 // Load settings.
@@ -183,6 +193,14 @@ func (r *Migration) Load() (err error) {
 		r.VddkImage = vddkImage
 	}
 
+	// OpenStack populator image for applying warm migration precopies
+	if openstackPopulatorImage, ok := os.LookupEnv(OpenstackPopulatorImage); ok {
+		r.OpenstackPopulatorImage = openstackPopulatorImage
+	}
+	if ec2DeltaCopyImage, ok := os.LookupEnv(EC2DeltaCopyImage); ok {
+		r.EC2DeltaCopyImage = ec2DeltaCopyImage
+	}
+
 	// Set timeout to 12 hours instead of the default 2
 	if r.CDIExportTokenTTL, err = getPositiveEnvLimit(CDIExportTokenTTL, 720); err != nil {
 		return liberr.Wrap(err)
@@ -336,5 +354,6 @@ func (r *Migration) Load() (err error) {
 	// Host lease settings for copy offload
 	r.HostLeaseNamespace = Lookup(HostLeaseNamespace, "openshift-mtv")
 	r.HostLeaseDurationSeconds = Lookup(HostLeaseDurationSeconds, "10")
+	r.ServiceAccount = Lookup(ServiceAccount, "forklift-controller")
 	return
 }
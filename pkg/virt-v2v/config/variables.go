@@ -32,6 +32,7 @@ const (
 	EnvMultipleIpsPerNicName      = "V2V_multipleIPsPerNic"
 	EnvRemoteInspection           = "V2V_remoteInspection"
 	EnvRemoteInspectionDisk       = "V2V_remoteInspectDisk_"
+	EnvTransferRateLimitName      = "V2V_transferRateLimit"
 )
 
 const (
@@ -102,6 +103,8 @@ type AppConfig struct {
 
 	// V2V_multipleIPsPerNic
 	MultipleIpsPerNicName string
+	// V2V_transferRateLimit
+	TransferRateLimit int
 	// Paths
 	VddkConfFile         string
 	InspectionOutputFile string
@@ -140,6 +143,7 @@ func (s *AppConfig) Load() (err error) {
 	flag.StringVar(&s.HostName, "hostname", os.Getenv(EnvHostName), "Hostname of the vm")
 	flag.StringVar(&s.MultipleIpsPerNicName, "multiple-ips-per-nic", os.Getenv(EnvMultipleIpsPerNicName), "Multiple IPs per NIC")
 	flag.BoolVar(&s.IsRemoteInspection, "remote-inspection", s.getEnvBool(EnvRemoteInspection, false), "Run virt-v2v-inspection on remote disks")
+	flag.IntVar(&s.TransferRateLimit, "transfer-rate-limit", s.getEnvInt(EnvTransferRateLimitName, 0), "Maximum disk transfer rate in MB/s")
 	s.RemoteInspectionDisks = s.getRemoteInspectionDisks()
 	flag.Parse()
 
@@ -195,6 +199,17 @@ func (s *AppConfig) getEnvBool(name string, def bool) bool {
 	return def
 }
 
+// Get integer.
+func (s *AppConfig) getEnvInt(name string, def int) int {
+	if s, found := os.LookupEnv(name); found {
+		parsed, err := strconv.Atoi(s)
+		if err == nil {
+			return parsed
+		}
+	}
+	return def
+}
+
 func (s *AppConfig) envMissingError(env string) error {
 	return fmt.Errorf("the env variable '%s' is needed for the migration", env)
 }